@@ -3,26 +3,21 @@ package main
 import (
 	"log/slog"
 	"os"
+	"ozinshe/internal/config"
 	"ozinshe/internal/config/lib/logger/handlers/slogpretty"
 )
 
-const (
-	EnvLocal = "local"
-	EnvProd  = "prod"
-	EnvDev   = "Dev"
-)
-
 func setupLogger(env string) *slog.Logger {
 	var log *slog.Logger
 
 	switch env {
-	case EnvLocal:
+	case config.EnvLocal:
 		log = setupPrettyLogger()
-	case EnvProd:
+	case config.EnvProd:
 		log = slog.New(
 			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}),
 		)
-	case EnvDev:
+	case config.EnvDev:
 		log = slog.New(
 			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
 		)
@@ -2,16 +2,23 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"os"
-	"os/signal"
 	"ozinshe/cmd/server"
+	"ozinshe/internal/cache"
 	"ozinshe/internal/config"
+	"ozinshe/internal/config/lib/logger/sl"
 	"ozinshe/internal/handler"
+	"ozinshe/internal/helper"
+	"ozinshe/internal/lifecycle"
 	_ "ozinshe/internal/models"
 	"ozinshe/internal/service"
 	storage "ozinshe/internal/storage/postgresql"
-	"syscall"
+	"ozinshe/internal/tracing"
+	"ozinshe/util"
+	"strconv"
+	"time"
 )
 
 // @title Ozinshe API
@@ -19,7 +26,7 @@ import (
 // description API Server for Ozinshe Application
 
 // @host localhost:8080
-// @BasePath /
+// @BasePath /api/v1
 
 // @securityDefinitions.cookie CookieAuth
 // @in cookie
@@ -27,36 +34,227 @@ import (
 // @description Type "JWT" token received from the server
 
 func main() {
+	// "ozinshe worker" only runs the background jobs.
+	worker := len(os.Args) > 1 && os.Args[1] == "worker"
+	if worker {
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+	}
+
+	// "ozinshe config print" prints the effective config, secrets redacted,
+	// and fails when it is not valid.
+	if len(os.Args) > 2 && os.Args[1] == "config" && os.Args[2] == "print" {
+		os.Args = append(os.Args[:1], os.Args[3:]...)
+		os.Exit(printConfig())
+	}
+
 	cfg := config.MustLoad()
 	log := setupLogger(cfg.Env)
 
+	util.SetupTokens(cfg.Auth)
+	helper.SetUploadDir(cfg.Uploads.Dir)
+
+	app := lifecycle.New(cfg.Shutdown, log)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		panic(err)
+	}
+	app.Close("tracing", shutdownTracing)
+
 	db, err := storage.NewPostgres(cfg)
 	if err != nil {
 		panic(err)
 	}
+	app.Close("postgres", func(context.Context) error { return db.Close() })
+
+	ctx := context.Background()
+
+	store, closeCache, err := cache.Open(cfg.Cache)
+	if err != nil {
+		panic(err)
+	}
+	app.Close("cache", closeCache)
 
 	storage := storage.New(db)
-	service := service.New(storage)
-	handler := handler.New(service, log)
+	service := service.New(storage, store)
+	if err := service.Job.SyncSchedules(ctx); err != nil {
+		panic(err)
+	}
 
-	srv := new(server.Server)
-	err = srv.Run(cfg.Port, handler.InitRoutes())
+	if !worker {
+		handler := handler.New(service, cfg, log)
 
+		srv := server.New(cfg.Port, handler.InitRoutes(), cfg.HTTP)
+		app.Serve("http", srv.Run, srv.Shutdown)
+		app.OnDrain(service.Health.Drain)
+		app.OnDrain(handler.Drain)
+
+		app.Go("recommendations", func(ctx context.Context) {
+			recomputeRecommendations(ctx, service.Recommendation, service.Health, cfg.Recommendations.Interval, log)
+		})
+		app.Go("events.relay", func(ctx context.Context) {
+			relayEvents(ctx, service.Event, service.Health, cfg.Events.RelayInterval, log)
+		})
+		app.Go("webhooks.delivery", func(ctx context.Context) {
+			deliverWebhooks(ctx, service.Webhook, service.Health, cfg.Webhooks.PollInterval, log)
+		})
+		app.Go("notifications.listen", func(ctx context.Context) {
+			listenNotifications(ctx, service.Notification, log)
+		})
+	}
+	if worker || cfg.Jobs.InServer {
+		startJobs(app, service.Job, service.Health, cfg.Jobs, log)
+	}
+
+	if err := app.Run(ctx); err != nil {
+		log.Error("application failed", sl.Err(err))
+		os.Exit(1)
+	}
+}
+
+// printConfig prints the effective config and returns the exit code.
+func printConfig() int {
+	cfg, err := config.Load()
 	if err != nil {
-		log.Error("error while running http server", err)
-		return
+		fmt.Fprintln(os.Stderr, err)
+		return 1
 	}
 
-	log.Info("server started")
+	if err := cfg.Print(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
 
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
 
-	sign := <-stop
+	return 0
+}
+
+// every runs round right away and then every interval until ctx is done.
+// Rounds that report more work pending are followed by the next one right
+// away. A round in progress when ctx is done is finished first.
+func every(ctx context.Context, interval time.Duration, round func(ctx context.Context) (more bool)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	work := context.WithoutCancel(ctx)
+	for ctx.Err() == nil {
+		if round(work) {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		}
+	}
+}
+
+// recomputeRecommendations refreshes the cached similarity scores on start
+// and then every interval.
+func recomputeRecommendations(ctx context.Context, recommendation service.Recommendation, health service.Health, interval time.Duration, log *slog.Logger) {
+	health.Watch("recommendations", interval)
+
+	every(ctx, interval, func(ctx context.Context) bool {
+		err := recommendation.Recompute(ctx)
+		health.Beat("recommendations", err)
+		if err != nil {
+			log.Error("recommendations recompute failed", sl.Err(err))
+		}
+		return false
+	})
+}
+
+// relayEvents dispatches the events recorded in the outbox every interval.
+// Rounds that relayed anything are followed by the next one right away.
+func relayEvents(ctx context.Context, event service.Event, health service.Health, interval time.Duration, log *slog.Logger) {
+	health.Watch("events.relay", interval)
+
+	every(ctx, interval, func(ctx context.Context) bool {
+		relayed, err := event.RelayPending(ctx)
+		health.Beat("events.relay", err)
+		if err != nil {
+			log.Error("event relay failed", sl.Err(err))
+		}
+		return relayed > 0 && err == nil
+	})
+}
+
+// deliverWebhooks sends due webhook deliveries every interval. Rounds that
+// sent anything are followed by the next one right away.
+func deliverWebhooks(ctx context.Context, webhook service.Webhook, health service.Health, interval time.Duration, log *slog.Logger) {
+	health.Watch("webhooks.delivery", interval)
 
-	log.Info("Stopping application", slog.String("signal", sign.String()))
+	every(ctx, interval, func(ctx context.Context) bool {
+		sent, err := webhook.DeliverDue(ctx)
+		health.Beat("webhooks.delivery", err)
+		if err != nil {
+			log.Error("webhook delivery failed", sl.Err(err))
+		}
+		return sent > 0 && err == nil
+	})
+}
+
+// listenNotifications streams the notifications created by every process to
+// the subscribers of this one, listening again a while after it fails.
+func listenNotifications(ctx context.Context, notification service.Notification, log *slog.Logger) {
+	const retry = 5 * time.Second
+
+	for ctx.Err() == nil {
+		if err := notification.Listen(ctx); err != nil {
+			log.Error("notification listen failed", sl.Err(err))
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(retry):
+		}
+	}
+}
+
+// startJobs adds the scheduler and cfg.Workers job workers to app. Jobs that
+// are running when a worker is killed are claimed again once their lease
+// expires.
+func startJobs(app *lifecycle.Manager, job service.Job, health service.Health, cfg config.JobsConfig, log *slog.Logger) {
+	app.Go("jobs.scheduler", func(ctx context.Context) {
+		scheduleJobs(ctx, job, health, cfg.PollInterval, log)
+	})
+	for i := 0; i < cfg.Workers; i++ {
+		name := "jobs.worker-" + strconv.Itoa(i+1)
+		app.Go(name, func(ctx context.Context) {
+			runJobs(ctx, job, health, name, cfg.PollInterval, log)
+		})
+	}
+}
+
+// scheduleJobs queues the runs of due job schedules every interval.
+func scheduleJobs(ctx context.Context, job service.Job, health service.Health, interval time.Duration, log *slog.Logger) {
+	health.Watch("jobs.scheduler", interval)
+
+	every(ctx, interval, func(ctx context.Context) bool {
+		_, err := job.EnqueueScheduled(ctx)
+		health.Beat("jobs.scheduler", err)
+		if err != nil {
+			log.Error("job scheduling failed", sl.Err(err))
+		}
+		return false
+	})
+}
 
-	srv.MustShutdown(context.Background())
+// runJobs runs due jobs every interval as the worker called name. Rounds that
+// ran anything are followed by the next one right away.
+func runJobs(ctx context.Context, job service.Job, health service.Health, name string, interval time.Duration, log *slog.Logger) {
+	health.Watch(name, interval)
 
-	log.Info("Application stopped")
+	every(ctx, interval, func(ctx context.Context) bool {
+		ran, err := job.RunDue(ctx)
+		health.Beat(name, err)
+		if err != nil {
+			log.Error("running jobs failed", sl.Err(err))
+		}
+		return ran > 0 && err == nil
+	})
 }
@@ -3,32 +3,34 @@ package server
 import (
 	"context"
 	"net/http"
-	"time"
+	"ozinshe/internal/config"
 )
 
 type Server struct {
 	httpServer *http.Server
 }
 
-func (s *Server) Run(port string, handler http.Handler) error {
-
-	s.httpServer = &http.Server{
-		Addr:           ":" + port,
-		Handler:        handler,
-		MaxHeaderBytes: 1 << 20,
-		WriteTimeout:   5 * time.Second,
-		ReadTimeout:    5 * time.Second,
+func New(port string, handler http.Handler, cfg config.HTTPConfig) *Server {
+	return &Server{
+		httpServer: &http.Server{
+			Addr:           ":" + port,
+			Handler:        handler,
+			MaxHeaderBytes: cfg.MaxHeaderBytes,
+			WriteTimeout:   cfg.WriteTimeout,
+			ReadTimeout:    cfg.ReadTimeout,
+			IdleTimeout:    cfg.IdleTimeout,
+		},
 	}
+}
 
+// Run serves until the server fails or is shut down, in which case it
+// returns http.ErrServerClosed.
+func (s *Server) Run() error {
 	return s.httpServer.ListenAndServe()
 }
 
+// Shutdown stops accepting connections and waits for the requests in flight
+// until ctx is done.
 func (s *Server) Shutdown(ctx context.Context) error {
 	return s.httpServer.Shutdown(ctx)
 }
-
-func (s *Server) MustShutdown(ctx context.Context) {
-	if err := s.Shutdown(ctx); err != nil {
-		panic(err)
-	}
-}
@@ -0,0 +1,56 @@
+// Command webhook-receiver is a local HTTP receiver for testing webhooks. It
+// verifies the signature of every delivery and prints it.
+//
+//	go run ./cmd/webhook-receiver -secret <webhook secret> -addr :9090
+//
+// Register http://localhost:9090/ as a webhook and ping it from
+// POST /admin/webhooks/{id}/ping. Use -fail to answer with a 500 and watch
+// the retries in the delivery log.
+package main
+
+import (
+	"flag"
+	"io"
+	"log"
+	"net/http"
+	"ozinshe/internal/webhook"
+	"time"
+)
+
+func main() {
+	addr := flag.String("addr", ":9090", "address to listen on")
+	secret := flag.String("secret", "", "webhook secret")
+	fail := flag.Bool("fail", false, "answer every delivery with 500")
+	flag.Parse()
+
+	if *secret == "" {
+		log.Fatal("-secret is required")
+	}
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		err = webhook.Verify(*secret, r.Header.Get(webhook.HeaderTimestamp), r.Header.Get(webhook.HeaderSignature), body, 5*time.Minute)
+		if err != nil {
+			log.Printf("delivery %s rejected: %v", r.Header.Get(webhook.HeaderDelivery), err)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		log.Printf("delivery %s %s: %s", r.Header.Get(webhook.HeaderDelivery), r.Header.Get(webhook.HeaderEvent), body)
+
+		if *fail {
+			http.Error(w, "failing on purpose", http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	log.Printf("listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
@@ -1,4 +1,5 @@
-// Package docs Code generated by swaggo/swag. DO NOT EDIT
+// Code generated by swaggo/swag. DO NOT EDIT.
+
 package docs
 
 import "github.com/swaggo/swag"
@@ -15,183 +16,259 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/ages": {
+        "/admin/audit": {
             "get": {
-                "description": "Retrieves a list of all age categories in the system.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "age categories"
-                ],
-                "summary": "Get a list of all age categories",
-                "responses": {
-                    "200": {
-                        "description": "List of age categories",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.AgeCategory"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Error retrieving age categories",
-                        "schema": {}
-                    }
-                }
-            },
-            "post": {
                 "security": [
                     {
                         "CookieAuth": []
                     }
                 ],
-                "description": "Creates a new age category based on the provided age range. Requires admin authorization.",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                "description": "Retrieves the changes made by admins, newest first, with snapshots of the target before and after each change. Requires admin authorization.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "age categories"
+                    "audit"
                 ],
-                "summary": "Create a new age category",
+                "summary": "Get the audit log",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the admin who made the change",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Age range (e.g., '18-30')",
-                        "name": "age",
-                        "in": "formData",
-                        "required": true
+                        "description": "Action, e.g. movie.update",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target type, e.g. movie",
+                        "name": "target_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target ID",
+                        "name": "target_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest time (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time before which changes were made (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 10, max 50)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Age category created"
+                        "description": "Audit log entries",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AuditEntry"
+                            }
+                        }
                     },
                     "400": {
-                        "description": "Error creating age category",
-                        "schema": {}
+                        "description": "Error getting audit log",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
                     }
                 }
             }
         },
-        "/ages/{id}": {
+        "/admin/audit/export": {
             "get": {
-                "description": "Retrieves details of an age category based on the provided ID.",
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Downloads up to 10000 audit log entries matching the filters as CSV, newest first. Requires admin authorization.",
                 "produces": [
-                    "application/json"
+                    "text/csv"
                 ],
                 "tags": [
-                    "age categories"
+                    "audit"
                 ],
-                "summary": "Get details of a specific age category",
+                "summary": "Export the audit log",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Age category ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "ID of the admin who made the change",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Action, e.g. movie.update",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target type, e.g. movie",
+                        "name": "target_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target ID",
+                        "name": "target_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest time (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time before which changes were made (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Age category retrieved successfully",
+                        "description": "CSV file",
                         "schema": {
-                            "$ref": "#/definitions/models.AgeCategory"
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "Error retrieving age category",
-                        "schema": {}
+                        "description": "Error exporting audit log",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/job-schedules": {
+            "get": {
                 "security": [
                     {
                         "CookieAuth": []
                     }
                 ],
-                "description": "Deletes an existing age category based on the provided ID.Requires admin authorization.",
+                "description": "Retrieves the recurring job schedules with their next run. Requires admin authorization.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "age categories"
-                ],
-                "summary": "Delete an existing age category",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Age category ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "jobs"
                 ],
+                "summary": "Get job schedules",
                 "responses": {
                     "200": {
-                        "description": "Age category deleted successfully"
+                        "description": "Job schedules",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.JobSchedule"
+                            }
+                        }
                     },
                     "400": {
-                        "description": "Error deleting age category",
-                        "schema": {}
+                        "description": "Error getting schedules",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
                     }
                 }
             }
         },
-        "/change-password": {
-            "post": {
+        "/admin/jobs": {
+            "get": {
                 "security": [
                     {
                         "CookieAuth": []
                     }
                 ],
-                "description": "Allows the currently authenticated user to change their password.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieves background jobs, newest first, optionally filtered by status and kind. Requires admin authorization.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "jobs"
                 ],
-                "summary": "Change password",
+                "summary": "Get a list of jobs",
                 "parameters": [
                     {
-                        "description": "New password information",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.NewPassword"
-                        }
+                        "type": "string",
+                        "description": "Job status (queued, running, succeeded or dead)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Job kind",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 10, max 50)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Password changed successfully"
+                        "description": "List of jobs",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Job"
+                            }
+                        }
                     },
                     "400": {
-                        "description": "Error changing password",
-                        "schema": {}
+                        "description": "Error getting jobs",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
                     }
                 }
-            }
-        },
-        "/change-profile": {
+            },
             "post": {
                 "security": [
                     {
                         "CookieAuth": []
                     }
                 ],
-                "description": "Allows the currently authenticated user to change their profile information.",
+                "description": "Queues a job of a registered kind to run at run_at, or right away when it is omitted. Requires admin authorization.",
                 "consumes": [
                     "application/json"
                 ],
@@ -199,176 +276,223 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "jobs"
                 ],
-                "summary": "Change profile",
+                "summary": "Enqueue a job",
                 "parameters": [
                     {
-                        "description": "New profile information",
-                        "name": "request",
+                        "description": "Job",
+                        "name": "job",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.ProfileData"
+                            "$ref": "#/definitions/handler.jobForm"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Profile changed successfully"
+                        "description": "Job queued"
                     },
                     "400": {
-                        "description": "Error changing profile",
-                        "schema": {}
+                        "description": "Error queuing job",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
                     }
                 }
             }
         },
-        "/favorites": {
+        "/admin/jobs/kinds": {
             "get": {
                 "security": [
                     {
                         "CookieAuth": []
                     }
                 ],
-                "description": "Retrieves a list of all projects (movies and series) favorited by the current user.",
+                "description": "Retrieves the kinds of job the workers can run. Requires admin authorization.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "favorites"
+                    "jobs"
                 ],
-                "summary": "Get a list of all favorited projects (movies and series) for the current user",
+                "summary": "Get job kinds",
                 "responses": {
                     "200": {
-                        "description": "List of favorited movies and series",
+                        "description": "Job kinds",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/handler.Contents"
+                                "type": "string"
                             }
                         }
-                    },
-                    "400": {
-                        "description": "Error getting favorites",
-                        "schema": {}
                     }
                 }
             }
         },
-        "/genres": {
+        "/admin/jobs/{id}": {
             "get": {
-                "description": "Retrieves a list of all genres.",
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Retrieves a background job with its last error. Requires admin authorization.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "genres"
+                    "jobs"
+                ],
+                "summary": "Get a job",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get a list of all genres",
                 "responses": {
                     "200": {
-                        "description": "List of genres",
+                        "description": "Job",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Genre"
-                            }
+                            "$ref": "#/definitions/models.Job"
                         }
                     },
                     "400": {
-                        "description": "Error getting genres",
-                        "schema": {}
+                        "description": "Error getting job",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
                     }
                 }
-            },
+            }
+        },
+        "/admin/jobs/{id}/retry": {
             "post": {
                 "security": [
                     {
                         "CookieAuth": []
                     }
                 ],
-                "description": "Creates a new genre with the provided name. Requires admin authorization.",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                "description": "Queues a job that is not running to run again right away with a fresh set of attempts. Requires admin authorization.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "genres"
+                    "jobs"
                 ],
-                "summary": "Create a new genre",
+                "summary": "Retry a job",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Genre name",
-                        "name": "genre",
-                        "in": "formData",
+                        "type": "integer",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Genre created"
+                        "description": "Job queued"
                     },
                     "400": {
-                        "description": "Error creating genre",
-                        "schema": {}
+                        "description": "Error retrying job",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found or running",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
                     }
                 }
             }
         },
-        "/genres/{id}": {
+        "/admin/projects": {
             "get": {
-                "description": "Retrieves details of a genre based on the provided ID.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "genres"
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
                 ],
-                "summary": "Get details of a specific genre",
+                "description": "Retrieves the cards of all projects, published or not, with their status and publish window, newest first. Requires admin authorization.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get projects by publication status",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Status (draft, review, scheduled or published)",
+                        "name": "status",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Genre ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 10, max 50)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Genre details",
+                        "description": "Projects",
                         "schema": {
-                            "$ref": "#/definitions/models.Genre"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PublicationCard"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Error getting genre",
-                        "schema": {}
+                        "description": "Error getting projects",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/webhook-deliveries/{id}/redeliver": {
+            "post": {
                 "security": [
                     {
                         "CookieAuth": []
                     }
                 ],
-                "description": "Deletes an existing genre based on the provided ID. Requires admin authorization.",
+                "description": "Queues a delivery to be sent again right away with a fresh set of retries. Requires admin authorization.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "genres"
+                    "webhooks"
                 ],
-                "summary": "Delete an existing genre",
+                "summary": "Redeliver a webhook delivery",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Genre ID",
+                        "description": "Delivery ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -376,92 +500,119 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Genre deleted successfully"
+                        "description": "Redelivery queued"
                     },
                     "400": {
-                        "description": "Error deleting genre",
-                        "schema": {}
+                        "description": "Error queuing redelivery",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
                     }
                 }
             }
         },
-        "/movies": {
+        "/admin/webhooks": {
             "get": {
-                "description": "Retrieves a list of all movies.",
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Retrieves the registered webhooks without their secrets. Requires admin authorization.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "movies"
+                    "webhooks"
                 ],
-                "summary": "Get a list of all movies",
+                "summary": "Get a list of all webhooks",
                 "responses": {
                     "200": {
-                        "description": "List of movies",
+                        "description": "List of webhooks",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Movie"
+                                "$ref": "#/definitions/models.Webhook"
                             }
                         }
                     },
                     "400": {
-                        "description": "Error getting movies",
-                        "schema": {}
+                        "description": "Error getting webhooks",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
                     }
                 }
-            }
-        },
-        "/movies/{id}": {
-            "get": {
-                "description": "Retrieves details of a movie based on the provided ID.",
+            },
+            "post": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Registers an endpoint receiving the subscribed events. Deliveries are signed with the secret, which is generated when omitted and only returned here. Requires admin authorization.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "movies"
+                    "webhooks"
                 ],
-                "summary": "Get details of a specific movie",
+                "summary": "Register a webhook",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Movie ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Webhook",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.webhookForm"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Movie details",
+                        "description": "Registered webhook",
                         "schema": {
-                            "$ref": "#/definitions/models.Movie"
+                            "$ref": "#/definitions/models.Webhook"
                         }
                     },
                     "400": {
-                        "description": "Error getting movie",
-                        "schema": {}
+                        "description": "Error registering webhook",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
                     }
                 }
-            },
+            }
+        },
+        "/admin/webhooks/{id}": {
             "delete": {
                 "security": [
                     {
                         "CookieAuth": []
                     }
                 ],
-                "description": "Deletes an existing movie based on the provided ID. Requires admin authorization.",
+                "description": "Deletes a webhook with its delivery log. Requires admin authorization.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "movies"
+                    "webhooks"
                 ],
-                "summary": "Delete an existing movie",
+                "summary": "Delete a webhook",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Movie ID",
+                        "description": "Webhook ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -469,362 +620,290 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Movie deleted successfully"
+                        "description": "Webhook deleted"
                     },
-                    "500": {
-                        "description": "Error deleting movie",
-                        "schema": {}
+                    "400": {
+                        "description": "Error deleting webhook",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
                     }
                 }
             }
         },
-        "/projects": {
+        "/admin/webhooks/{id}/deliveries": {
             "get": {
-                "description": "Retrieves a list of all projects, including both movies and series.",
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Retrieves the delivery log of a webhook, newest first. Requires admin authorization.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "projects"
+                    "webhooks"
+                ],
+                "summary": "Get deliveries of a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 10, max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get a list of all projects (movies and series)",
                 "responses": {
                     "200": {
-                        "description": "List of movies and series",
+                        "description": "Deliveries",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/handler.Contents"
+                                "$ref": "#/definitions/models.WebhookDelivery"
                             }
                         }
                     },
                     "400": {
-                        "description": "Error getting projects",
-                        "schema": {}
+                        "description": "Error getting deliveries",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
                     }
                 }
             }
         },
-        "/projects/create-project": {
+        "/admin/webhooks/{id}/ping": {
             "post": {
                 "security": [
                     {
-                        "CookieAuthc": []
+                        "CookieAuth": []
                     }
                 ],
-                "description": "Creates a new project based on the provided project type and form data. Requires admin authorization.",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                "description": "Queues a signed ping event to the webhook to test its receiver. Requires admin authorization.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "projects"
+                    "webhooks"
                 ],
-                "summary": "Create a new project (movie or series)",
+                "summary": "Ping a webhook",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Project type (movie or series)",
-                        "name": "project_type",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "Project cover",
-                        "name": "cover",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "Project screenshots",
-                        "name": "screenshots",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "name": "movie_age_category",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "name": "movie_description",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "name": "movie_director",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "integer",
-                        "name": "movie_duration",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "csv",
-                        "name": "movie_genres",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "csv",
-                        "name": "movie_keywords",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "name": "movie_link",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "name": "movie_producer",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "name": "movie_title",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "integer",
-                        "name": "movie_year",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "name": "series_age_category",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "name": "series_description",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "name": "series_director",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "integer",
-                        "name": "series_duration",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "csv",
-                        "name": "series_genres",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "csv",
-                        "description": "Consider an array for keywords",
-                        "name": "series_keywords",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "name": "series_producer",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "name": "series_title",
-                        "in": "formData"
-                    },
                     {
                         "type": "integer",
-                        "name": "series_year",
-                        "in": "formData"
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Project created successfully",
+                        "description": "Ping queued"
+                    },
+                    "400": {
+                        "description": "Error pinging webhook",
                         "schema": {
-                            "$ref": "#/definitions/models.Project"
+                            "$ref": "#/definitions/handler.Problem"
                         }
                     },
-                    "400": {
-                        "description": "Error creating project",
+                    "404": {
+                        "description": "Webhook not found",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorData"
+                            "$ref": "#/definitions/handler.Problem"
                         }
                     }
                 }
             }
         },
-        "/projects/search": {
+        "/ages": {
             "get": {
-                "description": "Retrieves a list of projects (movies or series) matching the provided filter parameters.",
+                "description": "Retrieves a list of all age categories in the system. fields limits the fields of the age categories sent.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "projects"
+                    "age categories"
                 ],
-                "summary": "Get a filtered list of projects (movies or series) based on search criteria",
+                "summary": "Get a list of all age categories",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Starting year for filter",
-                        "name": "year_start",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Ending year for filter",
-                        "name": "year_end",
+                        "type": "string",
+                        "description": "Comma separated fields to send, e.g. min_age,max_age",
+                        "name": "fields",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Project type (movie or series)",
-                        "name": "project_type",
-                        "in": "query"
+                        "description": "ETag of a cached copy",
+                        "name": "If-None-Match",
+                        "in": "header"
                     },
                     {
                         "type": "string",
-                        "description": "Popularity order (asc or desc)",
-                        "name": "popularity_order",
-                        "in": "query"
+                        "description": "Last-Modified of a cached copy",
+                        "name": "If-Modified-Since",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Filtered list of movies and series",
+                        "description": "List of age categories",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handler.Contents"
+                            "type": "object",
+                            "properties": {
+                                "age_categories": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.AgeCategory"
+                                    }
+                                },
+                                "message": {
+                                    "type": "string"
+                                }
+                            }
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Hash of the response"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "When the resource was last modified"
                             }
                         }
                     },
+                    "304": {
+                        "description": "The cached copy is current"
+                    },
                     "400": {
-                        "description": "Error getting filtered projects",
-                        "schema": {}
+                        "description": "Error retrieving age categories",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
                     }
                 }
-            }
-        },
-        "/projects/{id}": {
-            "get": {
-                "description": "Retrieves details of a project based on the provided ID.",
+            },
+            "post": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Creates a new age category based on the provided age range. Requires admin authorization.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "projects"
+                    "age categories"
                 ],
-                "summary": "Get details of a specific project (movie or series)",
+                "summary": "Create a new age category",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Project ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Age range (e.g., '18-30')",
+                        "name": "age",
+                        "in": "formData",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Project details (series)",
-                        "schema": {
-                            "$ref": "#/definitions/models.Series"
-                        }
+                        "description": "Age category created"
                     },
                     "400": {
-                        "description": "Error getting project",
-                        "schema": {}
+                        "description": "Error creating age category",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "CookieAuth": []
-                    }
-                ],
-                "description": "Updates an existing project based on the provided ID and form data. Requires admin authorization.",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+            }
+        },
+        "/ages/{id}": {
+            "get": {
+                "description": "Retrieves details of an age category based on the provided ID. fields limits the fields of the age category sent.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "projects"
+                    "age categories"
                 ],
-                "summary": "Update an existing project (movie or series)",
+                "summary": "Get details of a specific age category",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Project ID",
+                        "description": "Age category ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "file",
-                        "description": "Project cover",
-                        "name": "cover",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "Project screenshots",
-                        "name": "screenshots",
-                        "in": "formData",
-                        "required": true
+                        "type": "string",
+                        "description": "Comma separated fields to send, e.g. min_age,max_age",
+                        "name": "fields",
+                        "in": "query"
                     },
                     {
-                        "description": "Project data (JSON)",
-                        "name": "movie_data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.movieForm"
-                        }
+                        "type": "string",
+                        "description": "ETag of a cached copy",
+                        "name": "If-None-Match",
+                        "in": "header"
                     },
                     {
-                        "description": "Project data (JSON)",
-                        "name": "series_data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.Series"
-                        }
+                        "type": "string",
+                        "description": "Last-Modified of a cached copy",
+                        "name": "If-Modified-Since",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Project updated successfully"
+                        "description": "Age category retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.AgeCategory"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Hash of the response"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "When the resource was last modified"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "The cached copy is current"
                     },
                     "400": {
-                        "description": "Error updating project",
+                        "description": "Error retrieving age category",
                         "schema": {
-                            "$ref": "#/definitions/handler.ErrorData"
+                            "$ref": "#/definitions/handler.Problem"
                         }
                     }
                 }
@@ -835,18 +914,18 @@ const docTemplate = `{
                         "CookieAuth": []
                     }
                 ],
-                "description": "Deletes an existing project based on the provided ID. Requires admin authorization.",
+                "description": "Deletes an existing age category based on the provided ID.Requires admin authorization.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "projects"
+                    "age categories"
                 ],
-                "summary": "Delete an existing project (movie or series)",
+                "summary": "Delete an existing age category",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Project ID",
+                        "description": "Age category ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -854,439 +933,510 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Project deleted successfully"
+                        "description": "Age category deleted successfully"
                     },
                     "400": {
-                        "description": "Error deleting project",
-                        "schema": {}
+                        "description": "Error deleting age category",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
                     }
                 }
             }
         },
-        "/projects/{id}/favorites": {
+        "/change-password": {
             "post": {
                 "security": [
                     {
                         "CookieAuth": []
                     }
                 ],
-                "description": "Allows the currently authenticated user to add a project (movie or series) to their favorites list.",
+                "description": "Allows the currently authenticated user to change their password.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "favorites"
+                    "users"
                 ],
-                "summary": "Add a project to favorites",
+                "summary": "Change password",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Project ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "New password information",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.NewPassword"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Favorite added successfully"
+                        "description": "Password changed successfully"
                     },
                     "400": {
-                        "description": "Error adding to favorites",
-                        "schema": {}
+                        "description": "Error changing password",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/change-profile": {
+            "post": {
                 "security": [
                     {
                         "CookieAuth": []
                     }
                 ],
-                "description": "Allows the currently authenticated user to delete a project (movie or series) from their favorites list.",
+                "description": "Allows the currently authenticated user to change their profile information.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "favorites"
+                    "users"
                 ],
-                "summary": "Delete a project from favorites",
+                "summary": "Change profile",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Project ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "New profile information",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProfileData"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Favorite added successfully"
+                        "description": "Profile changed successfully"
                     },
                     "400": {
-                        "description": "Error adding to favorites",
-                        "schema": {}
+                        "description": "Error changing profile",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
                     }
                 }
             }
         },
-        "/series": {
+        "/collections": {
             "get": {
-                "description": "Retrieves a list of all series.",
+                "description": "Retrieves curated collections in the order they appear on the home screen.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "series"
+                    "collections"
                 ],
-                "summary": "Get a list of all series",
+                "summary": "Get a list of all collections",
                 "responses": {
                     "200": {
-                        "description": "List of series",
+                        "description": "List of collections",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Series"
+                                "$ref": "#/definitions/models.Collection"
                             }
                         }
                     },
                     "400": {
-                        "description": "Error getting series",
-                        "schema": {}
+                        "description": "Error getting collections",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
                     }
                 }
-            }
-        },
-        "/series/{id}": {
-            "delete": {
+            },
+            "post": {
                 "security": [
                     {
-                        "ApiKeyAuth": []
+                        "CookieAuth": []
                     }
                 ],
-                "description": "Deletes a series based on the provided ID.",
+                "description": "Creates a curated collection with an optional artwork banner. Requires admin authorization.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "series"
+                    "collections"
                 ],
-                "summary": "Delete a series",
+                "summary": "Create a new collection",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Series ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Collection title",
+                        "name": "title",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Collection slug",
+                        "name": "slug",
+                        "in": "formData",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Position on the home screen",
+                        "name": "position",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Collection banner",
+                        "name": "banner",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Series deleted successfully"
+                        "description": "Collection created"
                     },
                     "400": {
-                        "description": "Invalid parameters",
-                        "schema": {}
-                    },
-                    "500": {
-                        "description": "Error deleting series",
-                        "schema": {}
+                        "description": "Error creating collection",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
                     }
                 }
             }
         },
-        "/series/{seriesID}": {
+        "/collections/{slug}": {
             "get": {
-                "description": "Retrieves details of a series based on the provided ID.",
+                "description": "Retrieves a curated collection with one page of its ordered projects.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "series"
+                    "collections"
                 ],
-                "summary": "Get details of a specific series",
+                "summary": "Get a page of a collection",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Series ID",
-                        "name": "seriesID",
+                        "description": "Collection slug",
+                        "name": "slug",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 10, max 50)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Series details",
+                        "description": "Collection page",
                         "schema": {
-                            "$ref": "#/definitions/models.Series"
+                            "$ref": "#/definitions/models.HomeRow"
                         }
                     },
                     "400": {
-                        "description": "Error getting series",
-                        "schema": {}
-                    }
-                }
-            }
-        },
-        "/series/{seriesID}/seasons/{seasonNumber}/episodes": {
-            "get": {
-                "description": "Retrieves episodes of a specific season of a series based on the provided series ID and season number.",
+                        "description": "Error getting collection",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Collection not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Deletes a curated collection based on the provided slug. Requires admin authorization.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "series"
+                    "collections"
                 ],
-                "summary": "Get episodes of a specific season of a series",
+                "summary": "Delete a collection",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Series ID",
-                        "name": "seriesID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Season Number",
-                        "name": "seasonNumber",
+                        "description": "Collection slug",
+                        "name": "slug",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of episodes",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Episode"
-                            }
-                        }
+                        "description": "Collection deleted"
                     },
                     "400": {
-                        "description": "Invalid parameters",
-                        "schema": {}
-                    },
-                    "404": {
-                        "description": "Season not found",
-                        "schema": {}
-                    },
-                    "500": {
-                        "description": "Error getting episodes",
-                        "schema": {}
+                        "description": "Error deleting collection",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
                     }
                 }
             }
         },
-        "/series/{seriesID}/seasons/{seasonNumber}/episodes/{episodeID}": {
-            "get": {
-                "description": "Retrieves details of a specific episode of a series based on the provided series ID, season number, and episode ID.",
+        "/collections/{slug}/projects": {
+            "put": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Replaces the ordered list of projects in a collection. Requires admin authorization.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "series"
+                    "collections"
                 ],
-                "summary": "Get details of a specific episode of a series",
+                "summary": "Replace projects of a collection",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Series ID",
-                        "name": "seriesID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Season Number",
-                        "name": "seasonNumber",
+                        "description": "Collection slug",
+                        "name": "slug",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Episode ID",
-                        "name": "episodeID",
-                        "in": "path",
-                        "required": true
+                        "description": "Ordered project IDs",
+                        "name": "projects",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "integer"
+                            }
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Episode details",
-                        "schema": {
-                            "$ref": "#/definitions/models.Episode"
-                        }
+                        "description": "Collection updated"
                     },
                     "400": {
-                        "description": "Invalid parameters",
-                        "schema": {}
-                    },
-                    "500": {
-                        "description": "Error getting episode",
-                        "schema": {}
+                        "description": "Error updating collection",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
                     }
                 }
             }
         },
-        "/signin": {
-            "post": {
-                "description": "Logs in an existing user with the provided email and password.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/genres": {
+            "get": {
+                "description": "Retrieves a list of all genres. fields limits the fields sent.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "authentication"
+                    "genres"
                 ],
-                "summary": "User sign in",
+                "summary": "Get a list of all genres",
                 "parameters": [
                     {
-                        "description": "User email and password",
-                        "name": "entry",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.entryForm"
-                        }
+                        "type": "string",
+                        "description": "Comma separated fields to send, e.g. name",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified of a cached copy",
+                        "name": "If-Modified-Since",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User logged in successfully",
+                        "description": "List of genres",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "type": "string"
+                                "$ref": "#/definitions/models.Genre"
+                            }
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Hash of the response"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "When the resource was last modified"
                             }
                         }
                     },
+                    "304": {
+                        "description": "The cached copy is current"
+                    },
                     "400": {
-                        "description": "Error signing in: user loggin in failed",
+                        "description": "Error getting genres",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.Problem"
                         }
                     }
                 }
-            }
-        },
-        "/signout": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "CookieAuth": []
                     }
                 ],
-                "description": "Logs out the currently authenticated user.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "authentication"
-                ],
-                "summary": "User sign out",
-                "responses": {
-                    "200": {
-                        "description": "Logout successful"
-                    },
-                    "400": {
-                        "description": "Error logging out user",
-                        "schema": {}
-                    }
-                }
-            }
-        },
-        "/signup": {
-            "post": {
-                "description": "Registers a new user with the provided email and password.",
+                "description": "Creates a new genre with the provided name. Requires admin authorization.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "authentication"
+                    "genres"
                 ],
-                "summary": "User sign up",
+                "summary": "Create a new genre",
                 "parameters": [
                     {
-                        "description": "User email and password",
-                        "name": "entry",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.entryForm"
-                        }
+                        "type": "string",
+                        "description": "Genre name",
+                        "name": "genre",
+                        "in": "formData",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User registered successfully"
+                        "description": "Genre created"
                     },
                     "400": {
-                        "description": "Error signing up: user registration failed",
+                        "description": "Error creating genre",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.Problem"
                         }
                     }
                 }
             }
         },
-        "/user/{id}": {
+        "/genres/{id}": {
             "get": {
-                "security": [
-                    {
-                        "CookieAuth": []
-                    }
-                ],
-                "description": "Retrieves details of a user based on the provided ID.",
+                "description": "Retrieves details of a genre based on the provided ID. fields limits the fields sent.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "genres"
                 ],
-                "summary": "Get details of a specific user",
+                "summary": "Get details of a specific genre",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Genre ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to send, e.g. name",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified of a cached copy",
+                        "name": "If-Modified-Since",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User details",
+                        "description": "Genre details",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.Genre"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Hash of the response"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "When the resource was last modified"
+                            }
                         }
                     },
+                    "304": {
+                        "description": "The cached copy is current"
+                    },
                     "400": {
-                        "description": "Error getting user",
-                        "schema": {}
+                        "description": "Error getting genre",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
                     }
                 }
             },
             "delete": {
                 "security": [
                     {
-                        "ApiKeyAuth": []
+                        "CookieAuth": []
                     }
                 ],
-                "description": "Deletes a user based on the provided ID. Requires admin authorization.",
+                "description": "Deletes an existing genre based on the provided ID. Requires admin authorization.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "genres"
                 ],
-                "summary": "Delete user",
+                "summary": "Delete an existing genre",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Genre ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1294,288 +1444,3041 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "User deleted successfully"
+                        "description": "Genre deleted successfully"
                     },
                     "400": {
-                        "description": "Error deleting user",
-                        "schema": {}
+                        "description": "Error deleting genre",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
                     }
                 }
             }
         },
-        "/users": {
+        "/home": {
             "get": {
-                "security": [
-                    {
-                        "CookieAuth": []
-                    }
-                ],
-                "description": "Retrieves a list of all users in the system. Requires admin authorization.",
+                "description": "Assembles the home screen from curated collections and algorithmic rows (continue watching, trending, new releases, favorite genre). Each row holds its first page of items.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "home"
+                ],
+                "summary": "Get the home feed",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Items per row (default 10, max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get a list of all users",
                 "responses": {
                     "200": {
-                        "description": "List of users",
+                        "description": "Home rows",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.User"
+                                "$ref": "#/definitions/models.HomeRow"
                             }
                         }
                     },
                     "400": {
-                        "description": "Error getting users",
-                        "schema": {}
+                        "description": "Error getting home feed",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handler.Contents": {
-            "type": "object",
-            "properties": {
-                "movies": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Movie"
-                    }
-                },
-                "series": {
-                    "type": "array",
-                    "items": {
+        },
+        "/home/rows/{row}": {
+            "get": {
+                "description": "Retrieves the next items of a home row. Curated collections are paged through /collections/{slug}.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "home"
+                ],
+                "summary": "Get a page of an algorithmic home row",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Row key (trending, new-releases, continue-watching, favorite-genre)",
+                        "name": "row",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 10, max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Home row page",
+                        "schema": {
+                            "$ref": "#/definitions/models.HomeRow"
+                        }
+                    },
+                    "400": {
+                        "description": "Error getting home row",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown row",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/lists/shared/{token}": {
+            "get": {
+                "description": "Retrieves a public list by its sharing link with one page of its ordered items.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Get a shared list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sharing token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 10, max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List",
+                        "schema": {
+                            "$ref": "#/definitions/models.List"
+                        }
+                    },
+                    "404": {
+                        "description": "List not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/lists": {
+            "get": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Retrieves the built-in Favorites and Watch Later lists followed by the custom lists of the current user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Get lists of the current user",
+                "responses": {
+                    "200": {
+                        "description": "Lists",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.List"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Error getting lists",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Creates a custom list for the current user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Create a list",
+                "parameters": [
+                    {
+                        "description": "List",
+                        "name": "list",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.listForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List created"
+                    },
+                    "400": {
+                        "description": "Error creating list",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/lists/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Retrieves a list with one page of its ordered items.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Get a list of the current user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "List ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 10, max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List",
+                        "schema": {
+                            "$ref": "#/definitions/models.List"
+                        }
+                    },
+                    "400": {
+                        "description": "Error getting list",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "List not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Renames a custom list and changes the visibility of any list. Public lists can be opened by their sharing link.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Update a list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "List ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "List",
+                        "name": "list",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.listForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List updated"
+                    },
+                    "400": {
+                        "description": "Error updating list",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "List not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Deletes a custom list of the current user. Built-in lists can not be deleted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Delete a list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "List ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List deleted"
+                    },
+                    "400": {
+                        "description": "Error deleting list",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "List not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/lists/{id}/items": {
+            "post": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Appends a project with an optional note to the end of a list.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Add a project to a list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "List ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "List item",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.listItemForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project added"
+                    },
+                    "400": {
+                        "description": "Error adding project",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "List not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/lists/{id}/items/{projectID}": {
+            "put": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Moves a project to another position of a list and/or changes its note.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Update a list item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "List ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "projectID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Changes",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.listItemUpdateForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List item updated"
+                    },
+                    "400": {
+                        "description": "Error updating list item",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "List or item not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Removes a project from a list of the current user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Remove a project from a list",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "List ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "projectID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project removed"
+                    },
+                    "400": {
+                        "description": "Error removing project",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "List or item not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/notification-preferences": {
+            "get": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Retrieves which notifications the current user receives.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Get notification preferences",
+                "responses": {
+                    "200": {
+                        "description": "Preferences",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationPreferences"
+                        }
+                    },
+                    "400": {
+                        "description": "Error getting preferences",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Sets which notifications the current user receives.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Update notification preferences",
+                "parameters": [
+                    {
+                        "description": "Preferences",
+                        "name": "preferences",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationPreferences"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Preferences updated"
+                    },
+                    "400": {
+                        "description": "Error updating preferences",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Retrieves one page of the current user's notifications, newest first, with the number of unread ones.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Get notifications of the current user",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only unread notifications",
+                        "name": "unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 10, max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Notifications",
+                        "schema": {
+                            "$ref": "#/definitions/models.Inbox"
+                        }
+                    },
+                    "400": {
+                        "description": "Error getting notifications",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/notifications/read": {
+            "post": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Mark all notifications as read",
+                "responses": {
+                    "200": {
+                        "description": "Notifications marked as read"
+                    },
+                    "400": {
+                        "description": "Error marking notifications",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/notifications/stream": {
+            "get": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Server-Sent Events stream delivering the current user's new notifications as \"notification\" events. A \"ping\" event is sent while idle.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Stream notifications of the current user",
+                "responses": {
+                    "200": {
+                        "description": "Notification events",
+                        "schema": {
+                            "$ref": "#/definitions/models.Notification"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/notifications/{id}/read": {
+            "post": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Mark a notification as read",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Notification ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Notification marked as read"
+                    },
+                    "400": {
+                        "description": "Error marking notification",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Notification not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/recommendations": {
+            "get": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Recommends projects similar to the current user's favorites by genres, keywords, people and what other users favorited.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "Get recommendations for the current user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 10, max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recommended projects",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ProjectCard"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Error getting recommendations",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies": {
+            "get": {
+                "description": "Retrieves a list of all movies. fields and expand shape them as for a single movie, except that credits are never sent, and only the parts sent are loaded. With view=card, the compact cards of the movies' projects are sent instead, which fields can limit too.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Get a list of all movies",
+                "parameters": [
+                    {
+                        "enum": [
+                            "card"
+                        ],
+                        "type": "string",
+                        "description": "card for project cards",
+                        "name": "view",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to send, e.g. title,release_year,cover",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated parts to send: genres, keywords, age_categories, cover, screenshots",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified of a cached copy",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of movies, or of models.ProjectCard with view=card",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Movie"
+                            }
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Hash of the response"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "When the resource was last modified"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "The cached copy is current"
+                    },
+                    "400": {
+                        "description": "Error getting movies",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/movies/{id}": {
+            "get": {
+                "description": "Retrieves details of a published movie based on the provided ID. Admins can preview unpublished movies with preview=true. fields limits the response to the listed fields and the ID; expand alone leaves out every part but the listed ones, which fields can also ask for.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "movies"
+                ],
+                "summary": "Get details of a specific movie",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Movie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Preview an unpublished movie (admins only)",
+                        "name": "preview",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to send, e.g. title,release_year,cover",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated parts to send: genres, keywords, age_categories, cover, screenshots, credits",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified of a cached copy",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Movie details",
+                        "schema": {
+                            "$ref": "#/definitions/models.Movie"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Hash of the response"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "When the resource was last modified"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "The cached copy is current"
+                    },
+                    "400": {
+                        "description": "Error getting movie",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Movie not published",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/people": {
+            "get": {
+                "description": "Retrieves a list of people (cast and crew), optionally filtered by name.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "people"
+                ],
+                "summary": "Get a list of people",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Part of the person's name",
+                        "name": "name",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of people",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Person"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Error getting people",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Creates a new person with the provided name, bio and photo. Requires admin authorization.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "people"
+                ],
+                "summary": "Create a new person",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Person name",
+                        "name": "name",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Person bio",
+                        "name": "bio",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Person photo",
+                        "name": "photo",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Person created"
+                    },
+                    "400": {
+                        "description": "Error creating person",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/people/{id}": {
+            "get": {
+                "description": "Retrieves a person with photo, bio and filmography based on the provided ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "people"
+                ],
+                "summary": "Get details of a specific person",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Person details with filmography",
+                        "schema": {
+                            "$ref": "#/definitions/models.Person"
+                        }
+                    },
+                    "400": {
+                        "description": "Error getting person",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Deletes a person and all of their credits based on the provided ID. Requires admin authorization.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "people"
+                ],
+                "summary": "Delete an existing person",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Person ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Person deleted successfully"
+                    },
+                    "400": {
+                        "description": "Error deleting person",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects": {
+            "get": {
+                "description": "Retrieves a list of all published projects, including both movies and series. fields and expand shape them as for the lists of movies and of series, and only the parts sent are loaded. With view=card, the compact cards of the projects are sent instead, which fields can limit too.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get a list of all projects (movies and series)",
+                "parameters": [
+                    {
+                        "enum": [
+                            "card"
+                        ],
+                        "type": "string",
+                        "description": "card for project cards",
+                        "name": "view",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to send, e.g. title,release_year,cover",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated parts to send: genres, keywords, age_categories, cover, screenshots, seasons",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of movies and series, or of models.ProjectCard with view=card",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.Contents"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Error getting projects",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/create-project": {
+            "post": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Creates a new project based on the provided project type and form data. New projects are drafts, hidden from the public until published. Requires admin authorization.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Create a new project (movie or series)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project type (movie or series)",
+                        "name": "project_type",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Project cover",
+                        "name": "cover",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Project screenshots",
+                        "name": "screenshots",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Movie data, a handler.movieForm as JSON (movies only)",
+                        "name": "movie_data",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Series data, a handler.Series as JSON (series only)",
+                        "name": "series_data",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.Project"
+                        }
+                    },
+                    "400": {
+                        "description": "Error creating project",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/favorites": {
+            "get": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Retrieves a list of all projects (movies and series) favorited by the current user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "favorites"
+                ],
+                "summary": "Get a list of all favorited projects (movies and series) for the current user",
+                "responses": {
+                    "200": {
+                        "description": "List of favorited movies and series",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.Contents"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Error getting favorites",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/search": {
+            "get": {
+                "description": "Retrieves a list of projects (movies or series) matching the provided filter parameters.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get a filtered list of projects (movies or series) based on search criteria",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Starting year for filter",
+                        "name": "year_start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ending year for filter",
+                        "name": "year_end",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Project type (movie or series)",
+                        "name": "project_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Popularity order (asc or desc)",
+                        "name": "popularity_order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only projects crediting this person",
+                        "name": "person_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Filtered list of movies and series",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.Contents"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Error getting filtered projects",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}": {
+            "get": {
+                "description": "Retrieves details of a published project based on the provided ID. Admins can preview unpublished projects with preview=true. fields and expand shape it as for a single movie or series.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get details of a specific project (movie or series)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Preview an unpublished project (admins only)",
+                        "name": "preview",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to send, e.g. title,release_year,cover",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated parts to send: genres, keywords, age_categories, cover, screenshots, credits, and seasons for series",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project details (series)",
+                        "schema": {
+                            "$ref": "#/definitions/models.Series"
+                        }
+                    },
+                    "400": {
+                        "description": "Error getting project",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found or not published",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Updates an existing project based on the provided ID and form data. Requires admin authorization.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Update an existing project (movie or series)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "New project cover",
+                        "name": "cover",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "New project screenshots",
+                        "name": "screenshots",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Movie data, a handler.movieForm as JSON (movies only)",
+                        "name": "movie_data",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Series data, a handler.Series as JSON (series only)",
+                        "name": "series_data",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project updated successfully"
+                    },
+                    "400": {
+                        "description": "Error updating project",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Deletes an existing project based on the provided ID. Requires admin authorization.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Delete an existing project (movie or series)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project deleted successfully"
+                    },
+                    "400": {
+                        "description": "Error deleting project",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/credits": {
+            "get": {
+                "description": "Retrieves the ordered cast and crew of a project (movie or series).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "people"
+                ],
+                "summary": "Get credits of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of credits",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Credit"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Error getting credits",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Replaces the cast and crew of a project. The order of the list is used as billing order. Requires admin authorization.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "people"
+                ],
+                "summary": "Replace credits of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Ordered credits (role is director, producer, actor or writer)",
+                        "name": "credits",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.creditForm"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Credits updated"
+                    },
+                    "400": {
+                        "description": "Error updating credits",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/favorite": {
+            "post": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Allows the currently authenticated user to add a project (movie or series) to their built-in Favorites list.",
+                "tags": [
+                    "favorites"
+                ],
+                "summary": "Add a project to favorites",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Favorite added successfully"
+                    },
+                    "400": {
+                        "description": "Error adding to favorites",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Allows the currently authenticated user to delete a project (movie or series) from their built-in Favorites list.",
+                "tags": [
+                    "favorites"
+                ],
+                "summary": "Delete a project from favorites",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Favorite removed successfully"
+                    },
+                    "400": {
+                        "description": "Error removing from favorites",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/progress": {
+            "put": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Stores how far the current user has watched a project. Unfinished projects show up in the continue watching row.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Save watch progress",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Watch progress",
+                        "name": "progress",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.progressForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Progress saved"
+                    },
+                    "400": {
+                        "description": "Error saving progress",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/similar": {
+            "get": {
+                "description": "Retrieves projects similar to the provided project, most similar first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "Get projects similar to a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 10, max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Similar projects",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ProjectCard"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Error getting similar projects",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{id}/status": {
+            "put": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Moves a project through the publishing workflow: draft, review, scheduled or published. Scheduled projects need a publish_at in the future and go live then. An optional unpublish_at hides the project again, such as when its license expires. Requires admin authorization.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Set the status of a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Status and publish window",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.statusForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Status updated"
+                    },
+                    "400": {
+                        "description": "Invalid status or publish window",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Status transition not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/series": {
+            "get": {
+                "description": "Retrieves a list of all series. fields and expand shape them as for a single series, except that credits are never sent, and only the parts sent are loaded. With view=card, the compact cards of the series' projects are sent instead, which fields can limit too.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "series"
+                ],
+                "summary": "Get a list of all series",
+                "parameters": [
+                    {
+                        "enum": [
+                            "card"
+                        ],
+                        "type": "string",
+                        "description": "card for project cards",
+                        "name": "view",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to send, e.g. title,release_year,cover",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated parts to send: genres, keywords, age_categories, cover, screenshots, seasons",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified of a cached copy",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of series, or of models.ProjectCard with view=card",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Series"
+                            }
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Hash of the response"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "When the resource was last modified"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "The cached copy is current"
+                    },
+                    "400": {
+                        "description": "Error getting series",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{seriesID}": {
+            "get": {
+                "description": "Retrieves details of a published series based on the provided ID. Admins can preview unpublished series with preview=true. fields limits the response to the listed fields and the ID; expand alone leaves out every part but the listed ones, which fields can also ask for.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "series"
+                ],
+                "summary": "Get details of a specific series",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Series ID",
+                        "name": "seriesID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Preview an unpublished series (admins only)",
+                        "name": "preview",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields to send, e.g. title,release_year,cover",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated parts to send: genres, keywords, age_categories, cover, screenshots, seasons, credits",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified of a cached copy",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Series details",
+                        "schema": {
+                            "$ref": "#/definitions/models.Series"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Hash of the response"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "When the resource was last modified"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "The cached copy is current"
+                    },
+                    "400": {
+                        "description": "Error getting series",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Series not published",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{seriesID}/seasons/{seasonNumber}/episodes": {
+            "get": {
+                "description": "Retrieves episodes of a specific season of a series based on the provided series ID and season number.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "series"
+                ],
+                "summary": "Get episodes of a specific season of a series",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Series ID",
+                        "name": "seriesID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Season Number",
+                        "name": "seasonNumber",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Preview an unpublished series (admins only)",
+                        "name": "preview",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of episodes",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Episode"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Season not found or series not published",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Error getting episodes",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{seriesID}/seasons/{seasonNumber}/episodes/{episodeID}": {
+            "get": {
+                "description": "Retrieves details of a specific episode of a series based on the provided series ID, season number, and episode ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "series"
+                ],
+                "summary": "Get details of a specific episode of a series",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Series ID",
+                        "name": "seriesID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Season Number",
+                        "name": "seasonNumber",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Episode ID",
+                        "name": "episodeID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Preview an unpublished series (admins only)",
+                        "name": "preview",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Episode details",
+                        "schema": {
+                            "$ref": "#/definitions/models.Episode"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Series not published",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Error getting episode",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/signin": {
+            "post": {
+                "description": "Logs in an existing user with the provided email and password.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "User sign in",
+                "parameters": [
+                    {
+                        "description": "User email and password",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.entryForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Access and refresh tokens",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Request body is not JSON or passwords don't match",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Wrong email or password",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Email or password is not valid",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/signout": {
+            "get": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Logs out the currently authenticated user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "User sign out",
+                "responses": {
+                    "200": {
+                        "description": "Logout successful"
+                    },
+                    "400": {
+                        "description": "Error logging out user",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/signup": {
+            "post": {
+                "description": "Registers a new user with the provided email and password.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "User sign up",
+                "parameters": [
+                    {
+                        "description": "User email and password",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.entryForm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User registered successfully"
+                    },
+                    "400": {
+                        "description": "Request body is not JSON",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "User already exists",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Email or password is not valid",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/sync": {
+            "get": {
+                "description": "Returns the projects, genres and age categories created, updated and deleted since the sync token, and the favorites of the current user added and removed, for clients keeping an offline copy. Omit since for a full sync. Store the returned token for the next sync, and sync again right away while has_more is true.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sync"
+                ],
+                "summary": "Get the changes since the last sync",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token returned by the previous sync",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Changes to read at most (default 500, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Changes since the token",
+                        "schema": {
+                            "$ref": "#/definitions/models.Sync"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid sync token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Error getting changes",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Retrieves details of a user based on the provided ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get details of a specific user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User details",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Error getting user",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Deletes a user based on the provided ID. Requires admin authorization.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User deleted successfully"
+                    },
+                    "400": {
+                        "description": "Error deleting user",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "CookieAuth": []
+                    }
+                ],
+                "description": "Retrieves a list of all users in the system. Requires admin authorization.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get a list of all users",
+                "responses": {
+                    "200": {
+                        "description": "List of users",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.User"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Error getting users",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handler.Contents": {
+            "type": "object",
+            "properties": {
+                "movies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Movie"
+                    }
+                },
+                "series": {
+                    "type": "array",
+                    "items": {
                         "$ref": "#/definitions/models.Series"
                     }
                 }
             }
         },
-        "handler.Episode": {
+        "handler.NewPassword": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "new_password": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ProfileData": {
+            "type": "object",
+            "required": [
+                "date_of_birth",
+                "name",
+                "number"
+            ],
+            "properties": {
+                "date_of_birth": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 2
+                },
+                "number": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.creditForm": {
+            "type": "object",
+            "required": [
+                "person_id",
+                "role"
+            ],
+            "properties": {
+                "character": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "person_id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "director",
+                        "producer",
+                        "actor",
+                        "writer"
+                    ]
+                }
+            }
+        },
+        "handler.entryForm": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "confirm_password": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.jobForm": {
             "type": "object",
+            "required": [
+                "kind"
+            ],
             "properties": {
-                "episode_link": {
+                "kind": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "run_at": {
                     "type": "string"
                 }
             }
         },
-        "handler.ErrorData": {
+        "handler.listForm": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
-                "message": {
-                    "type": "string"
+                "is_public": {
+                    "type": "boolean"
                 },
-                "status": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "handler.listItemForm": {
+            "type": "object",
+            "required": [
+                "project_id"
+            ],
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "project_id": {
                     "type": "integer"
                 }
             }
         },
-        "handler.NewPassword": {
+        "handler.listItemUpdateForm": {
             "type": "object",
             "properties": {
-                "current_password": {
-                    "type": "string"
+                "note": {
+                    "type": "string",
+                    "maxLength": 500
                 },
-                "new_password": {
-                    "type": "string"
+                "position": {
+                    "type": "integer",
+                    "minimum": 0
                 }
             }
         },
-        "handler.ProfileData": {
+        "handler.progressForm": {
             "type": "object",
             "properties": {
-                "date_of_birth": {
-                    "type": "string"
+                "finished": {
+                    "type": "boolean"
                 },
-                "name": {
+                "position_seconds": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "handler.statusForm": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "publish_at": {
                     "type": "string"
                 },
-                "number": {
-                    "type": "string"
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "review",
+                        "scheduled",
+                        "published"
+                    ]
                 },
-                "password": {
+                "unpublish_at": {
                     "type": "string"
                 }
             }
         },
-        "handler.Season": {
+        "handler.webhookForm": {
             "type": "object",
+            "required": [
+                "events",
+                "url"
+            ],
             "properties": {
-                "season_episodes": {
+                "events": {
                     "type": "array",
+                    "minItems": 1,
                     "items": {
-                        "$ref": "#/definitions/handler.Episode"
+                        "type": "string"
                     }
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
-        "handler.Series": {
+        "models.AgeCategory": {
             "type": "object",
             "properties": {
-                "series_age_category": {
-                    "type": "string"
-                },
-                "series_description": {
-                    "type": "string"
+                "id": {
+                    "type": "integer"
                 },
-                "series_director": {
-                    "type": "string"
+                "max_age": {
+                    "type": "integer"
                 },
-                "series_duration": {
+                "min_age": {
                     "type": "integer"
+                }
+            }
+        },
+        "models.AgeCategoryChanges": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AgeCategory"
+                    }
                 },
-                "series_genres": {
+                "deleted": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "type": "integer"
                     }
                 },
-                "series_keywords": {
-                    "description": "Consider an array for keywords",
+                "updated": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/models.AgeCategory"
                     }
+                }
+            }
+        },
+        "models.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor_email": {
+                    "type": "string"
+                },
+                "actor_id": {
+                    "type": "integer"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "string"
                 },
-                "series_producer": {
+                "target_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Collection": {
+            "type": "object",
+            "properties": {
+                "banner": {
                     "type": "string"
                 },
-                "series_seasons": {
+                "id": {
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "projects": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handler.Season"
+                        "$ref": "#/definitions/models.ProjectCard"
                     }
                 },
-                "series_title": {
+                "slug": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Cover": {
+            "type": "object",
+            "properties": {
+                "filename": {
                     "type": "string"
                 },
-                "series_year": {
+                "id": {
+                    "type": "integer"
+                },
+                "project_id": {
                     "type": "integer"
                 }
             }
         },
-        "handler.entryForm": {
+        "models.Credit": {
             "type": "object",
             "properties": {
-                "confirm_password": {
+                "character": {
                     "type": "string"
                 },
-                "email": {
+                "id": {
+                    "type": "integer"
+                },
+                "person_id": {
+                    "type": "integer"
+                },
+                "person_name": {
                     "type": "string"
                 },
-                "password": {
+                "person_photo": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Episode": {
+            "type": "object",
+            "properties": {
+                "episode_number": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "link": {
                     "type": "string"
+                },
+                "season_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FavoriteChanges": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "deleted": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 }
             }
         },
-        "handler.movieForm": {
+        "models.Filmography": {
             "type": "object",
             "properties": {
-                "movie_age_category": {
+                "character": {
                     "type": "string"
                 },
-                "movie_description": {
+                "project_id": {
+                    "type": "integer"
+                },
+                "project_type": {
                     "type": "string"
                 },
-                "movie_director": {
+                "release_year": {
+                    "type": "integer"
+                },
+                "role": {
                     "type": "string"
                 },
-                "movie_duration": {
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Genre": {
+            "type": "object",
+            "properties": {
+                "id": {
                     "type": "integer"
                 },
-                "movie_genres": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.GenreChanges": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Genre"
+                    }
+                },
+                "deleted": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "type": "integer"
                     }
                 },
-                "movie_keywords": {
+                "updated": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/models.Genre"
+                    }
+                }
+            }
+        },
+        "models.HomeRow": {
+            "type": "object",
+            "properties": {
+                "banner": {
+                    "type": "string"
+                },
+                "has_more": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProjectCard"
                     }
                 },
-                "movie_link": {
+                "key": {
                     "type": "string"
                 },
-                "movie_producer": {
+                "kind": {
                     "type": "string"
                 },
-                "movie_title": {
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "title": {
                     "type": "string"
+                }
+            }
+        },
+        "models.Inbox": {
+            "type": "object",
+            "properties": {
+                "notifications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Notification"
+                    }
                 },
-                "movie_year": {
+                "unread": {
                     "type": "integer"
                 }
             }
         },
-        "models.AgeCategory": {
+        "models.Job": {
             "type": "object",
             "properties": {
-                "id": {
+                "attempts": {
                     "type": "integer"
                 },
-                "max_age": {
+                "created_at": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "min_age": {
+                "kind": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "max_attempts": {
                     "type": "integer"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "run_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
                 }
             }
         },
-        "models.Cover": {
+        "models.JobSchedule": {
             "type": "object",
             "properties": {
-                "filename": {
+                "kind": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "last_run_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
                 },
-                "project_id": {
-                    "type": "integer"
+                "spec": {
+                    "type": "string"
                 }
             }
         },
-        "models.Episode": {
+        "models.Keyword": {
             "type": "object",
             "properties": {
-                "episode_number": {
-                    "type": "integer"
-                },
                 "id": {
                     "type": "integer"
                 },
-                "link": {
+                "name": {
                     "type": "string"
-                },
-                "season_id": {
-                    "type": "integer"
                 }
             }
         },
-        "models.Genre": {
+        "models.List": {
             "type": "object",
             "properties": {
+                "created_at": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
+                "is_public": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ListItem"
+                    }
+                },
+                "kind": {
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
+                },
+                "share_token": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
-        "models.Keyword": {
+        "models.ListItem": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "integer"
+                "added_at": {
+                    "type": "string"
                 },
-                "name": {
+                "note": {
                     "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "project": {
+                    "$ref": "#/definitions/models.ProjectCard"
+                },
+                "project_id": {
+                    "type": "integer"
                 }
             }
         },
@@ -1591,6 +4494,12 @@ const docTemplate = `{
                 "cover": {
                     "$ref": "#/definitions/models.Cover"
                 },
+                "credits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Credit"
+                    }
+                },
                 "description": {
                     "type": "string"
                 },
@@ -1638,6 +4547,69 @@ const docTemplate = `{
                 }
             }
         },
+        "models.Notification": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "read_at": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.NotificationPreferences": {
+            "type": "object",
+            "properties": {
+                "new_episodes": {
+                    "type": "boolean"
+                },
+                "new_releases": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.Person": {
+            "type": "object",
+            "properties": {
+                "bio": {
+                    "type": "string"
+                },
+                "filmography": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Filmography"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "photo": {
+                    "type": "string"
+                }
+            }
+        },
         "models.Project": {
             "type": "object",
             "properties": {
@@ -1656,11 +4628,98 @@ const docTemplate = `{
                 "project_type": {
                     "type": "string"
                 },
+                "publish_at": {
+                    "type": "string"
+                },
                 "series": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/models.Series"
                     }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "unpublish_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProjectCard": {
+            "type": "object",
+            "properties": {
+                "cover": {
+                    "type": "string"
+                },
+                "popularity": {
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "project_type": {
+                    "type": "string"
+                },
+                "release_year": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProjectChanges": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SyncProject"
+                    }
+                },
+                "deleted": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "updated": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SyncProject"
+                    }
+                }
+            }
+        },
+        "models.PublicationCard": {
+            "type": "object",
+            "properties": {
+                "cover": {
+                    "type": "string"
+                },
+                "popularity": {
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "project_type": {
+                    "type": "string"
+                },
+                "publish_at": {
+                    "type": "string"
+                },
+                "release_year": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "unpublish_at": {
+                    "type": "string"
                 }
             }
         },
@@ -1710,6 +4769,12 @@ const docTemplate = `{
                 "cover": {
                     "$ref": "#/definitions/models.Cover"
                 },
+                "credits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Credit"
+                    }
+                },
                 "description": {
                     "type": "string"
                 },
@@ -1760,16 +4825,48 @@ const docTemplate = `{
                 }
             }
         },
+        "models.Sync": {
+            "type": "object",
+            "properties": {
+                "age_categories": {
+                    "$ref": "#/definitions/models.AgeCategoryChanges"
+                },
+                "favorites": {
+                    "$ref": "#/definitions/models.FavoriteChanges"
+                },
+                "genres": {
+                    "$ref": "#/definitions/models.GenreChanges"
+                },
+                "has_more": {
+                    "type": "boolean"
+                },
+                "projects": {
+                    "$ref": "#/definitions/models.ProjectChanges"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SyncProject": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "movie": {
+                    "$ref": "#/definitions/models.Movie"
+                },
+                "series": {
+                    "$ref": "#/definitions/models.Series"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
         "models.User": {
             "type": "object",
-            "required": [
-                "date_of_birth",
-                "email",
-                "name",
-                "number",
-                "password",
-                "user_type"
-            ],
             "properties": {
                 "created_at": {
                     "type": "string"
@@ -1784,16 +4881,13 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "name": {
-                    "type": "string",
-                    "maxLength": 50,
-                    "minLength": 2
+                    "type": "string"
                 },
                 "number": {
                     "type": "string"
                 },
                 "password": {
-                    "type": "string",
-                    "minLength": 6
+                    "type": "string"
                 },
                 "refresh_token": {
                     "type": "string"
@@ -1808,6 +4902,70 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "models.Webhook": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_status_code": {
+                    "type": "integer"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "webhook_id": {
+                    "type": "integer"
+                }
+            }
         }
     }
 }`
@@ -1816,7 +4974,7 @@ const docTemplate = `{
 var SwaggerInfo = &swag.Spec{
 	Version:          "1.0",
 	Host:             "localhost:8080",
-	BasePath:         "/",
+	BasePath:         "/api/v1",
 	Schemes:          []string{},
 	Title:            "Ozinshe API",
 	Description:      "Type \"JWT\" token received from the server",
@@ -0,0 +1,196 @@
+// Package apispec reads the OpenAPI (Swagger 2.0) document of the API, the
+// one swag generates into the docs package, and checks the router and the
+// traffic of the server against it.
+package apispec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Spec struct {
+	BasePath    string                           `json:"basePath"`
+	Paths       map[string]map[string]*Operation `json:"paths"`
+	Definitions map[string]*Schema               `json:"definitions"`
+}
+
+type Operation struct {
+	Consumes   []string            `json:"consumes"`
+	Parameters []Parameter         `json:"parameters"`
+	Responses  map[string]Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Type     string  `json:"type"`
+	Schema   *Schema `json:"schema"`
+}
+
+type Response struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is the part of a JSON schema the documents swag generates use.
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	Items                *Schema            `json:"items"`
+	AdditionalProperties *Schema            `json:"additionalProperties"`
+	AllOf                []*Schema          `json:"allOf"`
+	Enum                 []any              `json:"enum"`
+	MinLength            *int               `json:"minLength"`
+	MaxLength            *int               `json:"maxLength"`
+	MinItems             *int               `json:"minItems"`
+	MaxItems             *int               `json:"maxItems"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+}
+
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	// additionalProperties may be a boolean rather than a schema, which
+	// allows any value.
+	if b := bytes.TrimSpace(data); bytes.Equal(b, []byte("true")) || bytes.Equal(b, []byte("false")) {
+		*s = Schema{}
+		return nil
+	}
+
+	type schema Schema
+	return json.Unmarshal(data, (*schema)(s))
+}
+
+// Load parses the JSON document doc.
+func Load(doc string) (*Spec, error) {
+	const op = "apispec.Load"
+
+	var spec Spec
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &spec, nil
+}
+
+// Operation returns the operation documented for method and route, a gin
+// path pattern such as "/api/v1/projects/:id", or nil when there is none.
+// Routes outside the base path are looked up as they are, so that the
+// unversioned aliases of the routes find their operations too.
+func (s *Spec) Operation(method, route string) *Operation {
+	return s.Paths[s.path(route)][strings.ToLower(method)]
+}
+
+// Check compares the spec with the routes of the router: every documented
+// operation must be routed under the base path, and every route under the
+// base path documented. It also reports operations whose parameters do not
+// match their path or can not be sent together. The problems are sorted.
+func (s *Spec) Check(routes gin.RoutesInfo) []string {
+	var problems []string
+
+	routed := map[string]bool{}
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Path, s.prefix()+"/") {
+			continue
+		}
+
+		routed[route.Method+" "+s.path(route.Path)] = true
+		if s.Operation(route.Method, route.Path) == nil {
+			problems = append(problems, fmt.Sprintf("%s %s is routed but not documented", route.Method, route.Path))
+		}
+	}
+
+	for path, ops := range s.Paths {
+		for method, op := range ops {
+			name := strings.ToUpper(method) + " " + s.prefix() + path
+			if !routed[strings.ToUpper(method)+" "+path] {
+				problems = append(problems, name+" is documented but not routed")
+			}
+			for _, problem := range op.check(path) {
+				problems = append(problems, name+": "+problem)
+			}
+		}
+	}
+
+	sort.Strings(problems)
+	return problems
+}
+
+func (op *Operation) check(path string) []string {
+	var problems []string
+
+	declared := map[string]bool{}
+	body, formData := 0, 0
+	for _, param := range op.Parameters {
+		switch param.In {
+		case "path":
+			declared[param.Name] = true
+			if !strings.Contains(path, "{"+param.Name+"}") {
+				problems = append(problems, fmt.Sprintf("path parameter %q is not in the path", param.Name))
+			}
+		case "body":
+			body++
+		case "formData":
+			formData++
+		}
+	}
+
+	for _, segment := range strings.Split(path, "/") {
+		if name, ok := strings.CutPrefix(segment, "{"); ok {
+			name = strings.TrimSuffix(name, "}")
+			if !declared[name] {
+				problems = append(problems, fmt.Sprintf("path parameter %q is not declared", name))
+			}
+		}
+	}
+
+	switch {
+	case body > 1:
+		problems = append(problems, "more than one body parameter")
+	case body > 0 && formData > 0:
+		problems = append(problems, "body and formData parameters can not be sent together")
+	case body > 0 && op.consumes("multipart/form-data"):
+		problems = append(problems, "body parameter in a multipart/form-data request, declare it as formData")
+	}
+
+	return problems
+}
+
+func (op *Operation) consumes(mime string) bool {
+	for _, consumes := range op.Consumes {
+		if consumes == mime {
+			return true
+		}
+	}
+	return false
+}
+
+// prefix is the base path without its trailing slash.
+func (s *Spec) prefix() string {
+	return strings.TrimSuffix(s.BasePath, "/")
+}
+
+// path turns route, a gin path pattern, into the path of the spec it is
+// documented under: "/api/v1/projects/:id" becomes "/projects/{id}".
+func (s *Spec) path(route string) string {
+	if s.prefix() != "" {
+		if rest, ok := strings.CutPrefix(route, s.prefix()); ok && (rest == "" || rest[0] == '/') {
+			route = rest
+		}
+	}
+
+	segments := strings.Split(route, "/")
+	for i, segment := range segments {
+		if segment != "" && (segment[0] == ':' || segment[0] == '*') {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,213 @@
+package apispec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidateRequest checks the query parameters of r and its JSON body, read
+// beforehand, against op. It returns what does not match, sorted.
+func (s *Spec) ValidateRequest(op *Operation, r *http.Request, body []byte) []string {
+	var problems []string
+
+	query := r.URL.Query()
+	for _, param := range op.Parameters {
+		switch param.In {
+		case "query":
+			value, ok := query[param.Name]
+			if !ok {
+				if param.Required {
+					problems = append(problems, fmt.Sprintf("query parameter %q is required", param.Name))
+				}
+				continue
+			}
+			if !validParam(param.Type, value[0]) {
+				problems = append(problems, fmt.Sprintf("query parameter %q must be of type %s", param.Name, param.Type))
+			}
+		case "body":
+			if !IsJSON(r.Header.Get("Content-Type")) {
+				problems = append(problems, "body must be application/json")
+				continue
+			}
+			if len(bytes.TrimSpace(body)) == 0 {
+				if param.Required {
+					problems = append(problems, "body is required")
+				}
+				continue
+			}
+			problems = append(problems, s.validateJSON(param.Schema, body, "body")...)
+		}
+	}
+
+	sort.Strings(problems)
+	return problems
+}
+
+// ValidateResponse checks a response with status, header and body against
+// op. Statuses the operation does not document are only reported for
+// successful responses, since any request may fail in ways not worth
+// documenting for each operation.
+func (s *Spec) ValidateResponse(op *Operation, status int, header http.Header, body []byte) []string {
+	resp, ok := op.Responses[strconv.Itoa(status)]
+	if !ok {
+		resp, ok = op.Responses["default"]
+	}
+	if !ok {
+		if status >= 200 && status < 300 {
+			return []string{fmt.Sprintf("status %d is not documented", status)}
+		}
+		return nil
+	}
+
+	if resp.Schema == nil || len(bytes.TrimSpace(body)) == 0 || !IsJSON(header.Get("Content-Type")) {
+		return nil
+	}
+
+	problems := s.validateJSON(resp.Schema, body, "response")
+	sort.Strings(problems)
+	return problems
+}
+
+// IsJSON reports whether contentType is JSON, such as application/json or
+// application/problem+json.
+func IsJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+func validParam(typ, value string) bool {
+	var err error
+	switch typ {
+	case "integer":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "number":
+		_, err = strconv.ParseFloat(value, 64)
+	case "boolean":
+		_, err = strconv.ParseBool(value)
+	}
+	return err == nil
+}
+
+func (s *Spec) validateJSON(schema *Schema, data []byte, at string) []string {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return []string{at + ": not valid JSON: " + err.Error()}
+	}
+
+	return s.validate(schema, v, at)
+}
+
+// validate checks v, decoded with numbers as json.Number, against schema.
+// Go encodes nil slices, maps and pointers as null, which Swagger 2.0 has no
+// way to allow, so null is accepted for any value.
+func (s *Spec) validate(schema *Schema, v any, at string) []string {
+	if schema == nil || v == nil {
+		return nil
+	}
+
+	if schema.Ref != "" {
+		def, ok := s.Definitions[strings.TrimPrefix(schema.Ref, "#/definitions/")]
+		if !ok {
+			return []string{fmt.Sprintf("%s: unknown schema %s", at, schema.Ref)}
+		}
+		return s.validate(def, v, at)
+	}
+
+	var problems []string
+	for _, sub := range schema.AllOf {
+		problems = append(problems, s.validate(sub, v, at)...)
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, v) {
+		problems = append(problems, fmt.Sprintf("%s: must be one of %v", at, schema.Enum))
+	}
+
+	switch schema.Type {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return append(problems, at+": must be an object")
+		}
+		for _, name := range schema.Required {
+			if _, ok := obj[name]; !ok {
+				problems = append(problems, fmt.Sprintf("%s.%s: is required", at, name))
+			}
+		}
+		for name, value := range obj {
+			prop, ok := schema.Properties[name]
+			if !ok {
+				prop = schema.AdditionalProperties
+			}
+			problems = append(problems, s.validate(prop, value, at+"."+name)...)
+		}
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			return append(problems, at+": must be an array")
+		}
+		if schema.MinItems != nil && len(arr) < *schema.MinItems {
+			problems = append(problems, fmt.Sprintf("%s: must have at least %d items", at, *schema.MinItems))
+		}
+		if schema.MaxItems != nil && len(arr) > *schema.MaxItems {
+			problems = append(problems, fmt.Sprintf("%s: must have at most %d items", at, *schema.MaxItems))
+		}
+		for i, item := range arr {
+			problems = append(problems, s.validate(schema.Items, item, fmt.Sprintf("%s[%d]", at, i))...)
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return append(problems, at+": must be a string")
+		}
+		n := utf8.RuneCountInString(str)
+		if schema.MinLength != nil && n < *schema.MinLength {
+			problems = append(problems, fmt.Sprintf("%s: must be at least %d characters", at, *schema.MinLength))
+		}
+		if schema.MaxLength != nil && n > *schema.MaxLength {
+			problems = append(problems, fmt.Sprintf("%s: must be at most %d characters", at, *schema.MaxLength))
+		}
+	case "integer", "number":
+		num, ok := v.(json.Number)
+		if _, err := num.Int64(); ok && schema.Type == "integer" && err != nil {
+			ok = false
+		}
+		if !ok && schema.Type == "integer" {
+			return append(problems, at+": must be an integer")
+		}
+		if !ok {
+			return append(problems, at+": must be a number")
+		}
+		f, _ := num.Float64()
+		if schema.Minimum != nil && f < *schema.Minimum {
+			problems = append(problems, fmt.Sprintf("%s: must be at least %v", at, *schema.Minimum))
+		}
+		if schema.Maximum != nil && f > *schema.Maximum {
+			problems = append(problems, fmt.Sprintf("%s: must be at most %v", at, *schema.Maximum))
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			problems = append(problems, at+": must be a boolean")
+		}
+	}
+
+	return problems
+}
+
+func inEnum(enum []any, v any) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == fmt.Sprint(v) {
+			return true
+		}
+	}
+	return false
+}
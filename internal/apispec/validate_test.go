@@ -0,0 +1,245 @@
+package apispec
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const testDoc = `{
+	"basePath": "/api/v1",
+	"paths": {
+		"/movies": {
+			"get": {
+				"parameters": [
+					{"name": "limit", "in": "query", "type": "integer"},
+					{"name": "order", "in": "query", "type": "string", "required": true}
+				],
+				"responses": {
+					"200": {"schema": {"type": "array", "items": {"$ref": "#/definitions/Movie"}}},
+					"404": {"schema": {"$ref": "#/definitions/Problem"}}
+				}
+			},
+			"post": {
+				"parameters": [
+					{"name": "movie", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Movie"}}
+				],
+				"responses": {"200": {}}
+			}
+		}
+	},
+	"definitions": {
+		"Movie": {
+			"type": "object",
+			"required": ["id", "title"],
+			"properties": {
+				"id": {"type": "integer"},
+				"title": {"type": "string", "minLength": 1},
+				"rating": {"type": "number", "minimum": 0, "maximum": 10},
+				"status": {"type": "string", "enum": ["draft", "published"]},
+				"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2},
+				"free": {"type": "boolean"}
+			}
+		},
+		"Problem": {
+			"type": "object",
+			"properties": {"status": {"type": "integer"}, "title": {"type": "string"}}
+		}
+	}
+}`
+
+func loadTestSpec(t *testing.T) *Spec {
+	t.Helper()
+
+	spec, err := Load(testDoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
+
+func TestValidateResponse(t *testing.T) {
+	spec := loadTestSpec(t)
+	op := spec.Operation(http.MethodGet, "/api/v1/movies")
+	header := http.Header{"Content-Type": {"application/json; charset=utf-8"}}
+
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   []string
+	}{
+		{
+			name:   "valid",
+			status: http.StatusOK,
+			body:   `[{"id": 1, "title": "Up", "rating": 8.5, "status": "draft", "tags": ["a"], "free": true}]`,
+		},
+		{
+			name:   "null values",
+			status: http.StatusOK,
+			body:   `[{"id": 1, "title": "Up", "tags": null, "status": null}]`,
+		},
+		{
+			name:   "required",
+			status: http.StatusOK,
+			body:   `[{"id": 1}]`,
+			want:   []string{"response[0].title: is required"},
+		},
+		{
+			name:   "types",
+			status: http.StatusOK,
+			body:   `[{"id": 1.5, "title": 2, "rating": "high", "tags": "a", "free": "yes"}]`,
+			want: []string{
+				"response[0].free: must be a boolean",
+				"response[0].id: must be an integer",
+				"response[0].rating: must be a number",
+				"response[0].tags: must be an array",
+				"response[0].title: must be a string",
+			},
+		},
+		{
+			name:   "not an array",
+			status: http.StatusOK,
+			body:   `{"id": 1, "title": "Up"}`,
+			want:   []string{"response: must be an array"},
+		},
+		{
+			name:   "enum",
+			status: http.StatusOK,
+			body:   `[{"id": 1, "title": "Up", "status": "deleted"}]`,
+			want:   []string{"response[0].status: must be one of [draft published]"},
+		},
+		{
+			name:   "bounds",
+			status: http.StatusOK,
+			body:   `[{"id": 1, "title": "", "rating": 11, "tags": ["a", "b", "c"]}]`,
+			want: []string{
+				"response[0].rating: must be at most 10",
+				"response[0].tags: must have at most 2 items",
+				"response[0].title: must be at least 1 characters",
+			},
+		},
+		{
+			name:   "documented error",
+			status: http.StatusNotFound,
+			body:   `{"status": "404"}`,
+			want:   []string{"response.status: must be an integer"},
+		},
+		{
+			name:   "undocumented error",
+			status: http.StatusInternalServerError,
+			body:   `{"anything": true}`,
+		},
+		{
+			name:   "undocumented success",
+			status: http.StatusCreated,
+			body:   `[]`,
+			want:   []string{"status 201 is not documented"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := spec.ValidateResponse(op, tt.status, header, []byte(tt.body))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ValidateResponse() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateResponseSkipsOtherContent(t *testing.T) {
+	spec := loadTestSpec(t)
+	op := spec.Operation(http.MethodGet, "/api/v1/movies")
+
+	header := http.Header{"Content-Type": {"text/event-stream"}}
+	if got := spec.ValidateResponse(op, http.StatusOK, header, []byte("data: {}\n\n")); got != nil {
+		t.Errorf("ValidateResponse() of an event stream = %q, want nothing", got)
+	}
+}
+
+func TestValidateRequest(t *testing.T) {
+	spec := loadTestSpec(t)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		want   []string
+	}{
+		{
+			name:   "valid query",
+			method: http.MethodGet,
+			target: "/api/v1/movies?order=asc&limit=10",
+		},
+		{
+			name:   "query types and required",
+			method: http.MethodGet,
+			target: "/api/v1/movies?limit=ten",
+			want: []string{
+				`query parameter "limit" must be of type integer`,
+				`query parameter "order" is required`,
+			},
+		},
+		{
+			name:   "valid body",
+			method: http.MethodPost,
+			target: "/api/v1/movies",
+			body:   `{"id": 1, "title": "Up", "status": "published"}`,
+		},
+		{
+			name:   "invalid body",
+			method: http.MethodPost,
+			target: "/api/v1/movies",
+			body:   `{"title": "Up", "status": "gone"}`,
+			want: []string{
+				"body.id: is required",
+				"body.status: must be one of [draft published]",
+			},
+		},
+		{
+			name:   "missing body",
+			method: http.MethodPost,
+			target: "/api/v1/movies",
+			want:   []string{"body is required"},
+		},
+		{
+			name:   "malformed body",
+			method: http.MethodPost,
+			target: "/api/v1/movies",
+			body:   `{"id":`,
+			want:   []string{"body: not valid JSON: unexpected EOF"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", "application/json")
+
+			got := spec.ValidateRequest(spec.Operation(tt.method, "/api/v1/movies"), r, []byte(tt.body))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ValidateRequest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsJSON(t *testing.T) {
+	tests := map[string]bool{
+		"application/json":                true,
+		"application/json; charset=utf-8": true,
+		"application/problem+json":        true,
+		"text/plain":                      false,
+		"":                                false,
+	}
+
+	for contentType, want := range tests {
+		if got := IsJSON(contentType); got != want {
+			t.Errorf("IsJSON(%q) = %v, want %v", contentType, got, want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+// Package audit carries who is making a change from the handler down to the
+// storage transaction that records it in the audit log.
+package audit
+
+import "context"
+
+// Actor is the admin behind a change and the request it was made with.
+type Actor struct {
+	UserID    int
+	Email     string
+	IP        string
+	RequestID string
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx carrying the actor.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor carried by ctx, if any. Changes made by jobs
+// and other background work have none.
+func ActorFrom(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	return actor, ok
+}
@@ -0,0 +1,210 @@
+// Package cache keeps the results of reads that are expensive and rarely
+// change, such as the catalog, in memory or in Redis. Values are stored as
+// JSON, so that cached values are never shared with, or changed by, their
+// callers.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ozinshe/internal/config"
+	"ozinshe/internal/metrics"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrMiss is returned by Get for keys that are not cached.
+var ErrMiss = errors.New("cache: miss")
+
+// Cache is where cached values are kept.
+type Cache interface {
+	// Get returns the value of key, or ErrMiss when there is none.
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	// DeletePrefix deletes every key starting with prefix.
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// Store reads through a Cache. Concurrent misses of a key are loaded once,
+// so that an expired or invalidated key does not send every request waiting
+// for it to the database at the same time. A value loaded while its key was
+// invalidated is returned to the requests that were waiting for it but not
+// cached, since it may predate the write that invalidated it; requests made
+// after the write load the key anew.
+//
+// Errors of the cache itself are not returned: a cache that is down only
+// makes reads slower. They are counted in metrics.CacheErrors.
+type Store struct {
+	cache Cache
+	name  string
+	ttl   time.Duration
+
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+// call is a load in flight. It is stale once its key is invalidated.
+type call struct {
+	done  chan struct{}
+	value []byte
+	err   error
+	stale bool
+}
+
+// NewStore returns a store keeping values in cache for ttl. name labels its
+// metrics, such as "memory" or "redis".
+func NewStore(cache Cache, name string, ttl time.Duration) *Store {
+	return &Store{
+		cache: cache,
+		name:  name,
+		ttl:   ttl,
+		calls: make(map[string]*call),
+	}
+}
+
+// Open returns the store cfg describes, or nil when caching is off, along
+// with the function closing its connections.
+func Open(cfg config.CacheConfig) (*Store, func(context.Context) error, error) {
+	const op = "cache.Open"
+
+	noop := func(context.Context) error { return nil }
+
+	switch cfg.Driver {
+	case "none":
+		return nil, noop, nil
+	case "memory":
+		return NewStore(NewLRU(cfg.Size), cfg.Driver, cfg.TTL), noop, nil
+	case "redis":
+		r := NewRedis(redis.NewClient(&redis.Options{
+			Addr:     cfg.Redis.Addr,
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.DB,
+		}), cfg.Redis.Prefix)
+		return NewStore(r, cfg.Driver, cfg.TTL), func(context.Context) error { return r.Close() }, nil
+	default:
+		return nil, nil, fmt.Errorf("%s: unknown driver %q", op, cfg.Driver)
+	}
+}
+
+// Get returns the cached value of key, loading and caching it with load on
+// a miss. A nil store always loads.
+func Get[T any](ctx context.Context, s *Store, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	var value T
+	if s == nil {
+		return load(ctx)
+	}
+
+	data, err := s.cache.Get(ctx, key)
+	if err == nil {
+		if err := json.Unmarshal(data, &value); err == nil {
+			metrics.CacheRequests.WithLabelValues(s.name, "hit").Inc()
+			return value, nil
+		}
+	} else if !errors.Is(err, ErrMiss) {
+		metrics.CacheErrors.WithLabelValues(s.name, "get").Inc()
+	}
+	metrics.CacheRequests.WithLabelValues(s.name, "miss").Inc()
+
+	data, err = s.load(ctx, key, func(ctx context.Context) ([]byte, error) {
+		value, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(value)
+	})
+	if err != nil {
+		return value, err
+	}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, fmt.Errorf("cache: decode %s: %w", key, err)
+	}
+
+	return value, nil
+}
+
+// load runs load for key unless a load of key is already running, in which
+// case it waits for that one instead, and caches the result.
+func (s *Store) load(ctx context.Context, key string, load func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	s.mu.Lock()
+	if c, ok := s.calls[key]; ok {
+		s.mu.Unlock()
+
+		select {
+		case <-c.done:
+			return c.value, c.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	c := &call{done: make(chan struct{})}
+	s.calls[key] = c
+	s.mu.Unlock()
+
+	// The load is shared, so it must not be cut short when the request
+	// that happened to start it is.
+	ctx = context.WithoutCancel(ctx)
+	c.value, c.err = load(ctx)
+
+	s.mu.Lock()
+	if s.calls[key] == c {
+		delete(s.calls, key)
+	}
+	stale := c.stale
+	s.mu.Unlock()
+	close(c.done)
+
+	if c.err == nil && !stale {
+		if err := s.cache.Set(ctx, key, c.value, s.ttl); err != nil {
+			metrics.CacheErrors.WithLabelValues(s.name, "set").Inc()
+		}
+	}
+
+	return c.value, c.err
+}
+
+// Invalidate deletes keys, and every key starting with one of them that ends
+// in ":", such as "movie:".
+func (s *Store) Invalidate(ctx context.Context, keys ...string) {
+	if s == nil {
+		return
+	}
+
+	// Invalidating follows a write that is done, so it must not be cut
+	// short when the request that made the write is.
+	ctx = context.WithoutCancel(ctx)
+
+	s.mu.Lock()
+	for key, c := range s.calls {
+		for _, invalidated := range keys {
+			if key == invalidated || strings.HasSuffix(invalidated, ":") && strings.HasPrefix(key, invalidated) {
+				c.stale = true
+				delete(s.calls, key)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	var exact []string
+	for _, key := range keys {
+		if !strings.HasSuffix(key, ":") {
+			exact = append(exact, key)
+			continue
+		}
+		if err := s.cache.DeletePrefix(ctx, key); err != nil {
+			metrics.CacheErrors.WithLabelValues(s.name, "delete").Inc()
+		}
+	}
+	if len(exact) > 0 {
+		if err := s.cache.Delete(ctx, exact...); err != nil {
+			metrics.CacheErrors.WithLabelValues(s.name, "delete").Inc()
+		}
+	}
+}
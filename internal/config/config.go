@@ -2,44 +2,252 @@ package config
 
 import (
 	"flag"
+	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
+const (
+	EnvLocal = "local"
+	EnvProd  = "prod"
+	EnvDev   = "Dev"
+)
+
+// Config is read from the YAML file given by -config or CONFIG_PATH. Every
+// value can be overridden by the environment variable named in its env tag,
+// prefixed by the env-prefix of the sections it is in, such as DB_PASSWORD.
 type Config struct {
-	Env      string   `yaml:"env"`
-	Host     string   `yaml:"host"`
-	Port     string   `yaml:"port"`
-	TokenTTL string   `yaml:"token_ttl"`
-	DB       DbConfig `yaml:"db"`
+	Env  string   `yaml:"env" env:"ENV" env-default:"local"`
+	Host string   `yaml:"host" env:"HOST" env-default:"localhost"`
+	Port string   `yaml:"port" env:"PORT" env-default:"8080"`
+	DB   DbConfig `yaml:"db" env-prefix:"DB_"`
+
+	Auth            AuthConfig            `yaml:"auth" env-prefix:"AUTH_"`
+	Cookie          CookieConfig          `yaml:"cookie" env-prefix:"COOKIE_"`
+	CORS            CORSConfig            `yaml:"cors" env-prefix:"CORS_"`
+	Uploads         UploadsConfig         `yaml:"uploads" env-prefix:"UPLOADS_"`
+	HTTP            HTTPConfig            `yaml:"http" env-prefix:"HTTP_"`
+	Shutdown        ShutdownConfig        `yaml:"shutdown" env-prefix:"SHUTDOWN_"`
+	Metrics         MetricsConfig         `yaml:"metrics" env-prefix:"METRICS_"`
+	Tracing         TracingConfig         `yaml:"tracing" env-prefix:"TRACING_"`
+	Recommendations RecommendationsConfig `yaml:"recommendations" env-prefix:"RECOMMENDATIONS_"`
+	Webhooks        WebhooksConfig        `yaml:"webhooks" env-prefix:"WEBHOOKS_"`
+	Events          EventsConfig          `yaml:"events" env-prefix:"EVENTS_"`
+	Jobs            JobsConfig            `yaml:"jobs" env-prefix:"JOBS_"`
+	Cache           CacheConfig           `yaml:"cache" env-prefix:"CACHE_"`
 }
 
 type DbConfig struct {
-	Port     string `yaml:"port"`
-	User     string `yaml:"user"`
-	Password string `yaml:"password"`
-	Dbname   string `yaml:"dbname"`
-	Sslmode  string `yaml:"sslmode"`
+	Port         string `yaml:"port" env:"PORT" env-default:"5432"`
+	User         string `yaml:"user" env:"USER"`
+	Password     string `yaml:"password" env:"PASSWORD"`
+	PasswordFile string `yaml:"password_file" env:"PASSWORD_FILE"`
+	Dbname       string `yaml:"dbname" env:"NAME"`
+	Sslmode      string `yaml:"sslmode" env:"SSLMODE" env-default:"disable"`
+}
+
+// AuthConfig signs the JWTs. SECRET_KEY is still read for the secret when
+// it is not set otherwise.
+type AuthConfig struct {
+	Secret     string        `yaml:"secret" env:"SECRET"`
+	SecretFile string        `yaml:"secret_file" env:"SECRET_FILE"`
+	TokenTTL   time.Duration `yaml:"token_ttl" env:"TOKEN_TTL" env-default:"24h"`
+	RefreshTTL time.Duration `yaml:"refresh_ttl" env:"REFRESH_TTL" env-default:"169h"`
+}
+
+// CookieConfig sets the attributes of the token cookie. SameSite is "lax",
+// "strict" or "none", which browsers only accept on Secure cookies.
+type CookieConfig struct {
+	Domain   string `yaml:"domain" env:"DOMAIN"`
+	Path     string `yaml:"path" env:"PATH" env-default:"/"`
+	Secure   bool   `yaml:"secure" env:"SECURE"`
+	HTTPOnly bool   `yaml:"http_only" env:"HTTP_ONLY" env-default:"true"`
+	SameSite string `yaml:"same_site" env:"SAME_SITE" env-default:"lax"`
+}
+
+// CORSConfig lists the origins browsers may call the API from, such as
+// "https://ozinshe.kz", or "*" for any. Without origins, cross-origin
+// requests are left to the browser's same-origin policy.
+type CORSConfig struct {
+	AllowedOrigins []string      `yaml:"allowed_origins" env:"ALLOWED_ORIGINS"`
+	MaxAge         time.Duration `yaml:"max_age" env:"MAX_AGE" env-default:"12h"`
+}
+
+// UploadsConfig sets where uploaded images are kept, relative to the working
+// directory unless absolute, and how large an image may be.
+type UploadsConfig struct {
+	Dir          string `yaml:"dir" env:"DIR" env-default:"internal/uploads"`
+	MaxImageSize int64  `yaml:"max_image_size" env:"MAX_IMAGE_SIZE" env-default:"15728640"`
+}
+
+// HTTPConfig sets the deadlines of requests. RouteTimeouts override
+// RequestTimeout for routes keyed by method and path pattern without the
+// /api/v1 prefix, such as "GET /admin/audit/export"; a zero timeout means no
+// deadline. The server timeouts bound reading a request and writing its
+// response; for requests with a deadline, WriteTimeout is counted from the
+// deadline instead, so that a route is never cut off before its timeout.
+// CatalogMaxAge is how long clients and CDNs may reuse the public
+// catalog responses before revalidating them.
+type HTTPConfig struct {
+	RequestTimeout time.Duration            `yaml:"request_timeout" env:"REQUEST_TIMEOUT" env-default:"10s"`
+	RouteTimeouts  map[string]time.Duration `yaml:"route_timeouts"`
+	ReadTimeout    time.Duration            `yaml:"read_timeout" env:"READ_TIMEOUT" env-default:"5s"`
+	WriteTimeout   time.Duration            `yaml:"write_timeout" env:"WRITE_TIMEOUT" env-default:"5s"`
+	IdleTimeout    time.Duration            `yaml:"idle_timeout" env:"IDLE_TIMEOUT" env-default:"1m"`
+	MaxHeaderBytes int                      `yaml:"max_header_bytes" env:"MAX_HEADER_BYTES" env-default:"1048576"`
+	CatalogMaxAge  time.Duration            `yaml:"catalog_max_age" env:"CATALOG_MAX_AGE" env-default:"1m"`
 }
 
+// ShutdownConfig bounds the graceful shutdown. DrainDelay is how long the
+// server keeps serving while reporting not ready, for load balancers to
+// notice; Timeout then bounds finishing the requests in flight, stopping
+// the workers and closing the database.
+type ShutdownConfig struct {
+	DrainDelay time.Duration `yaml:"drain_delay" env:"DRAIN_DELAY" env-default:"0s"`
+	Timeout    time.Duration `yaml:"timeout" env:"TIMEOUT" env-default:"30s"`
+}
+
+// MetricsConfig guards the Prometheus endpoint. A scrape is let through
+// when it connects from one of AllowedNetworks or sends
+// "Authorization: Bearer <Token>"; behind a proxy, use the token.
+type MetricsConfig struct {
+	Enabled         bool     `yaml:"enabled" env:"ENABLED" env-default:"true"`
+	Path            string   `yaml:"path" env:"PATH" env-default:"/metrics"`
+	Token           string   `yaml:"token" env:"TOKEN"`
+	TokenFile       string   `yaml:"token_file" env:"TOKEN_FILE"`
+	AllowedNetworks []string `yaml:"allowed_networks" env:"ALLOWED_NETWORKS" env-default:"127.0.0.1/32,::1/128"`
+}
+
+// TracingConfig selects where spans go: "otlp" sends them over OTLP/HTTP to
+// Endpoint, "stdout" prints them and "none" turns tracing off. SampleRatio
+// is the share of new traces recorded; requests that arrive with a sampled
+// trace are always recorded.
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter" env:"EXPORTER" env-default:"none"`
+	Endpoint    string  `yaml:"endpoint" env:"ENDPOINT" env-default:"localhost:4318"`
+	Insecure    bool    `yaml:"insecure" env:"INSECURE"`
+	ServiceName string  `yaml:"service_name" env:"SERVICE_NAME" env-default:"ozinshe"`
+	SampleRatio float64 `yaml:"sample_ratio" env:"SAMPLE_RATIO" env-default:"1"`
+}
+
+type RecommendationsConfig struct {
+	Interval time.Duration `yaml:"interval" env:"INTERVAL" env-default:"1h"`
+}
+
+type WebhooksConfig struct {
+	PollInterval time.Duration `yaml:"poll_interval" env:"POLL_INTERVAL" env-default:"5s"`
+}
+
+type EventsConfig struct {
+	RelayInterval time.Duration `yaml:"relay_interval" env:"RELAY_INTERVAL" env-default:"1s"`
+}
+
+// JobsConfig controls the job workers. With InServer unset, jobs only run
+// in a separate "ozinshe worker" process.
+type JobsConfig struct {
+	InServer     bool          `yaml:"in_server" env:"IN_SERVER" env-default:"true"`
+	Workers      int           `yaml:"workers" env:"WORKERS" env-default:"2"`
+	PollInterval time.Duration `yaml:"poll_interval" env:"POLL_INTERVAL" env-default:"1s"`
+}
+
+// CacheConfig sets where the catalog is cached: "memory" keeps at most Size
+// entries in each server process, "redis" shares them between processes
+// through Redis, and "none" turns the cache off. The memory cache is for a
+// single server only: the writes made through a server, and the events
+// relayed by it, only invalidate its own memory. Use redis with more than
+// one server. A separate worker needs no redis, since the server relays the
+// events of the changes the worker makes. Entries expire after TTL even
+// when no write invalidates them, such as when the publish window of a
+// project ends.
+type CacheConfig struct {
+	Driver string        `yaml:"driver" env:"DRIVER" env-default:"memory"`
+	TTL    time.Duration `yaml:"ttl" env:"TTL" env-default:"5m"`
+	Size   int           `yaml:"size" env:"SIZE" env-default:"1000"`
+	Redis  RedisConfig   `yaml:"redis" env-prefix:"REDIS_"`
+}
+
+// RedisConfig connects to Redis. Prefix is put before every key, so that
+// the cache can share a database.
+type RedisConfig struct {
+	Addr         string `yaml:"addr" env:"ADDR" env-default:"localhost:6379"`
+	Password     string `yaml:"password" env:"PASSWORD"`
+	PasswordFile string `yaml:"password_file" env:"PASSWORD_FILE"`
+	DB           int    `yaml:"db" env:"DB"`
+	Prefix       string `yaml:"prefix" env:"PREFIX" env-default:"ozinshe:"`
+}
+
+// MustLoad reads the config and panics when it can not be read or is not
+// valid, so that a misconfigured server never starts.
 func MustLoad() *Config {
+	cfg, err := Load()
+	if err != nil {
+		panic(err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		panic(err)
+	}
+
+	return cfg
+}
+
+// Load reads the config file, applies the environment over it and reads
+// the secrets kept in files. It does not validate the result.
+func Load() (*Config, error) {
+	const op = "config.Load"
+
 	path := fetchConfigPath()
 
 	if path == "" {
-		panic("no config path provided")
+		return nil, fmt.Errorf("%s: no config path provided", op)
 	}
 
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		panic("file does not exist at path " + path)
+		return nil, fmt.Errorf("%s: file does not exist at path %s", op, path)
 	}
 
 	var cfg Config
 	if err := cleanenv.ReadConfig(path, &cfg); err != nil {
-		panic(err)
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if cfg.Auth.Secret == "" {
+		cfg.Auth.Secret = os.Getenv("SECRET_KEY")
+	}
+
+	for _, s := range cfg.secrets() {
+		if s.file == "" {
+			continue
+		}
+
+		value, err := os.ReadFile(s.file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", op, s.name, err)
+		}
+		*s.value = strings.TrimRight(string(value), "\r\n")
 	}
 
-	return &cfg
+	return &cfg, nil
+}
+
+type secret struct {
+	name  string
+	value *string
+	file  string
+}
+
+// secrets returns the secret values of cfg, which are never printed, along
+// with the files they are read from when set.
+func (cfg *Config) secrets() []secret {
+	return []secret{
+		{name: "db.password", value: &cfg.DB.Password, file: cfg.DB.PasswordFile},
+		{name: "auth.secret", value: &cfg.Auth.Secret, file: cfg.Auth.SecretFile},
+		{name: "metrics.token", value: &cfg.Metrics.Token, file: cfg.Metrics.TokenFile},
+		{name: "cache.redis.password", value: &cfg.Cache.Redis.Password, file: cfg.Cache.Redis.PasswordFile},
+	}
 }
 
 func fetchConfigPath() string {
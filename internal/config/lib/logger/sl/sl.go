@@ -1,6 +1,9 @@
 package sl
 
-import "log/slog"
+import (
+	"context"
+	"log/slog"
+)
 
 func Err(err error) slog.Attr {
 	return slog.Attr{
@@ -8,3 +11,19 @@ func Err(err error) slog.Attr {
 		Value: slog.StringValue(err.Error()),
 	}
 }
+
+type loggerKey struct{}
+
+// WithLogger returns a copy of ctx carrying log.
+func WithLogger(ctx context.Context, log *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, log)
+}
+
+// FromContext returns the logger carried by ctx, or fallback when there is
+// none.
+func FromContext(ctx context.Context, fallback *slog.Logger) *slog.Logger {
+	if log, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return log
+	}
+	return fallback
+}
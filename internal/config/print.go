@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const redacted = "[redacted]"
+
+// Print writes the effective config to w as YAML, with the secrets that are
+// set replaced by "[redacted]".
+func (cfg *Config) Print(w io.Writer) error {
+	shown := *cfg
+	for _, s := range shown.secrets() {
+		if *s.value != "" {
+			*s.value = redacted
+		}
+	}
+
+	var b strings.Builder
+	printStruct(&b, reflect.ValueOf(shown), 0)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func printStruct(b *strings.Builder, v reflect.Value, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	for i := 0; i < v.NumField(); i++ {
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		field := v.Field(i)
+		switch field.Kind() {
+		case reflect.Struct:
+			fmt.Fprintf(b, "%s%s:\n", indent, name)
+			printStruct(b, field, depth+1)
+		case reflect.Map:
+			if field.Len() == 0 {
+				fmt.Fprintf(b, "%s%s: {}\n", indent, name)
+				continue
+			}
+			fmt.Fprintf(b, "%s%s:\n", indent, name)
+			keys := field.MapKeys()
+			sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+			for _, key := range keys {
+				fmt.Fprintf(b, "%s  %s: %s\n", indent, strconv.Quote(key.String()), scalar(field.MapIndex(key)))
+			}
+		case reflect.Slice:
+			if field.Len() == 0 {
+				fmt.Fprintf(b, "%s%s: []\n", indent, name)
+				continue
+			}
+			fmt.Fprintf(b, "%s%s:\n", indent, name)
+			for j := 0; j < field.Len(); j++ {
+				fmt.Fprintf(b, "%s  - %s\n", indent, scalar(field.Index(j)))
+			}
+		default:
+			fmt.Fprintf(b, "%s%s: %s\n", indent, name, scalar(field))
+		}
+	}
+}
+
+func scalar(v reflect.Value) string {
+	switch value := v.Interface().(type) {
+	case string:
+		return strconv.Quote(value)
+	case time.Duration:
+		return value.String()
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Validate reports every invalid value of cfg at once.
+func (cfg *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	positive := func(name string, d time.Duration) {
+		check(d > 0, "%s must be positive, got %s", name, d)
+	}
+	notNegative := func(name string, d time.Duration) {
+		check(d >= 0, "%s must not be negative, got %s", name, d)
+	}
+
+	check(cfg.Env == EnvLocal || cfg.Env == EnvDev || cfg.Env == EnvProd, "env must be %q, %q or %q, got %q", EnvLocal, EnvDev, EnvProd, cfg.Env)
+	check(validPort(cfg.Port), "port must be a TCP port, got %q", cfg.Port)
+
+	check(cfg.Host != "", "host is required")
+	check(validPort(cfg.DB.Port), "db.port must be a TCP port, got %q", cfg.DB.Port)
+	check(cfg.DB.User != "", "db.user is required")
+	check(cfg.DB.Dbname != "", "db.dbname is required")
+
+	check(cfg.Auth.Secret != "", "auth.secret is required")
+	positive("auth.token_ttl", cfg.Auth.TokenTTL)
+	check(cfg.Auth.RefreshTTL >= cfg.Auth.TokenTTL, "auth.refresh_ttl must not be shorter than auth.token_ttl")
+
+	switch strings.ToLower(cfg.Cookie.SameSite) {
+	case "lax", "strict":
+	case "none":
+		check(cfg.Cookie.Secure, "cookie.same_site none requires cookie.secure")
+	default:
+		check(false, "cookie.same_site must be lax, strict or none, got %q", cfg.Cookie.SameSite)
+	}
+
+	for _, origin := range cfg.CORS.AllowedOrigins {
+		check(validOrigin(origin), "cors.allowed_origins: %q is not an origin such as https://example.com", origin)
+	}
+	notNegative("cors.max_age", cfg.CORS.MaxAge)
+
+	check(cfg.Uploads.Dir != "", "uploads.dir is required")
+	check(cfg.Uploads.MaxImageSize > 0, "uploads.max_image_size must be positive, got %d", cfg.Uploads.MaxImageSize)
+
+	notNegative("http.request_timeout", cfg.HTTP.RequestTimeout)
+	for route, timeout := range cfg.HTTP.RouteTimeouts {
+		method, path, ok := strings.Cut(route, " ")
+		check(ok && method == strings.ToUpper(method) && strings.HasPrefix(path, "/"), "http.route_timeouts: %q is not a route such as \"GET /movies/:id\"", route)
+		notNegative("http.route_timeouts "+route, timeout)
+	}
+	notNegative("http.read_timeout", cfg.HTTP.ReadTimeout)
+	notNegative("http.write_timeout", cfg.HTTP.WriteTimeout)
+	notNegative("http.idle_timeout", cfg.HTTP.IdleTimeout)
+	check(cfg.HTTP.MaxHeaderBytes > 0, "http.max_header_bytes must be positive, got %d", cfg.HTTP.MaxHeaderBytes)
+	notNegative("http.catalog_max_age", cfg.HTTP.CatalogMaxAge)
+
+	notNegative("shutdown.drain_delay", cfg.Shutdown.DrainDelay)
+	positive("shutdown.timeout", cfg.Shutdown.Timeout)
+
+	if cfg.Metrics.Enabled {
+		check(strings.HasPrefix(cfg.Metrics.Path, "/"), "metrics.path must start with /, got %q", cfg.Metrics.Path)
+		for _, network := range cfg.Metrics.AllowedNetworks {
+			_, _, err := net.ParseCIDR(network)
+			check(err == nil, "metrics.allowed_networks: %q is not a CIDR network", network)
+		}
+	}
+
+	switch cfg.Tracing.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		check(cfg.Tracing.Endpoint != "", "tracing.endpoint is required by the otlp exporter")
+	default:
+		check(false, "tracing.exporter must be none, stdout or otlp, got %q", cfg.Tracing.Exporter)
+	}
+	check(cfg.Tracing.SampleRatio >= 0 && cfg.Tracing.SampleRatio <= 1, "tracing.sample_ratio must be between 0 and 1, got %v", cfg.Tracing.SampleRatio)
+
+	positive("recommendations.interval", cfg.Recommendations.Interval)
+	positive("webhooks.poll_interval", cfg.Webhooks.PollInterval)
+	positive("events.relay_interval", cfg.Events.RelayInterval)
+	check(cfg.Jobs.Workers >= 0, "jobs.workers must not be negative, got %d", cfg.Jobs.Workers)
+	positive("jobs.poll_interval", cfg.Jobs.PollInterval)
+
+	switch cfg.Cache.Driver {
+	case "none":
+	case "memory":
+		check(cfg.Cache.Size > 0, "cache.size must be positive, got %d", cfg.Cache.Size)
+	case "redis":
+		check(cfg.Cache.Redis.Addr != "", "cache.redis.addr is required by the redis cache")
+		check(cfg.Cache.Redis.DB >= 0, "cache.redis.db must not be negative, got %d", cfg.Cache.Redis.DB)
+	default:
+		check(false, "cache.driver must be none, memory or redis, got %q", cfg.Cache.Driver)
+	}
+	if cfg.Cache.Driver != "none" {
+		positive("cache.ttl", cfg.Cache.TTL)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("invalid config:\n%w", err)
+	}
+
+	return nil
+}
+
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n < 1<<16
+}
+
+func validOrigin(origin string) bool {
+	if origin == "*" {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && u.Path == "" && u.RawQuery == ""
+}
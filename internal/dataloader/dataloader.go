@@ -0,0 +1,149 @@
+// Package dataloader batches the loads of single keys made at about the same
+// time, such as by the resolvers of the items of a GraphQL list, into one
+// call for all of them, and caches what it loaded.
+package dataloader
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BatchFunc loads the values of keys. Keys it returns no value for load as
+// the zero value; an error fails the load of every key of the batch.
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader loads values by key through a BatchFunc. Loads are collected for
+// Wait after the first of a batch, or until MaxBatch keys are waiting, and
+// then loaded together. A loader caches every value, and error, for as long
+// as it lives, so it is meant to serve a single request.
+type Loader[K comparable, V any] struct {
+	fetch    BatchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	cache   map[K]*result[V]
+	pending *batch[K, V]
+}
+
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type batch[K comparable, V any] struct {
+	ctx     context.Context
+	keys    []K
+	results []*result[V]
+	timer   *time.Timer
+}
+
+// New returns a loader batching the loads made within wait of each other, up
+// to maxBatch keys at a time.
+func New[K comparable, V any](fetch BatchFunc[K, V], wait time.Duration, maxBatch int) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		cache:    make(map[K]*result[V]),
+	}
+}
+
+// Load returns the value of key, waiting for the batch it joins to load. The
+// batch is loaded with the context of its first load.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	res, ok := l.cache[key]
+	if !ok {
+		res = &result[V]{done: make(chan struct{})}
+		l.cache[key] = res
+		l.add(ctx, key, res)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-res.done:
+		return res.value, res.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// LoadMany returns the values of keys, in order, queueing them all into the
+// same batches rather than waiting for each in turn.
+func (l *Loader[K, V]) LoadMany(ctx context.Context, keys []K) ([]V, error) {
+	results := make([]*result[V], len(keys))
+
+	l.mu.Lock()
+	for i, key := range keys {
+		res, ok := l.cache[key]
+		if !ok {
+			res = &result[V]{done: make(chan struct{})}
+			l.cache[key] = res
+			l.add(ctx, key, res)
+		}
+		results[i] = res
+	}
+	l.mu.Unlock()
+
+	values := make([]V, len(keys))
+	for i, res := range results {
+		select {
+		case <-res.done:
+			if res.err != nil {
+				return nil, res.err
+			}
+			values[i] = res.value
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return values, nil
+}
+
+// add queues key into the pending batch, starting one when there is none.
+// l.mu must be held.
+func (l *Loader[K, V]) add(ctx context.Context, key K, res *result[V]) {
+	if l.pending == nil {
+		b := &batch[K, V]{ctx: ctx}
+		b.timer = time.AfterFunc(l.wait, func() { l.flush(b) })
+		l.pending = b
+	}
+
+	b := l.pending
+	b.keys = append(b.keys, key)
+	b.results = append(b.results, res)
+
+	if len(b.keys) >= l.maxBatch {
+		b.timer.Stop()
+		l.pending = nil
+		go l.run(b)
+	}
+}
+
+// flush loads b once its wait is over, unless it filled up before.
+func (l *Loader[K, V]) flush(b *batch[K, V]) {
+	l.mu.Lock()
+	if l.pending != b {
+		l.mu.Unlock()
+		return
+	}
+	l.pending = nil
+	l.mu.Unlock()
+
+	l.run(b)
+}
+
+func (l *Loader[K, V]) run(b *batch[K, V]) {
+	values, err := l.fetch(b.ctx, b.keys)
+
+	for i, key := range b.keys {
+		res := b.results[i]
+		res.value, res.err = values[key], err
+		close(res.done)
+	}
+}
@@ -0,0 +1,71 @@
+// Package events routes domain events read from the outbox to in-process
+// subscribers.
+//
+// Events are recorded in the outbox in the same transaction as the change
+// they describe and relayed to the bus afterwards. An event is relayed again
+// to the subscribers that failed on it until each of them handles it without
+// error. Subscribers see each event at least once and, when relaying stops
+// before it is recorded that they handled it, more than once.
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ozinshe/internal/models"
+	"slices"
+	"sync"
+)
+
+// All subscribes a handler to every event type.
+const All = "*"
+
+type Handler func(ctx context.Context, event models.Event) error
+
+type subscriber struct {
+	name    string
+	handler Handler
+}
+
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[string][]subscriber
+}
+
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[string][]subscriber)}
+}
+
+// On subscribes a handler to an event type, or to every type with All. The
+// name identifies the subscriber in errors and in what Dispatch reports, so
+// it must be unique among the subscribers of an event.
+func (b *Bus) On(eventType, name string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscribers[eventType] = append(b.subscribers[eventType], subscriber{name: name, handler: handler})
+}
+
+// Dispatch calls every subscriber of the event but the handled ones, even
+// after one of them failed. It returns the names of the subscribers that
+// handled the event now and the joined errors of the others.
+func (b *Bus) Dispatch(ctx context.Context, event models.Event, handled []string) ([]string, error) {
+	b.mu.RLock()
+	subscribers := append(append([]subscriber(nil), b.subscribers[event.Type]...), b.subscribers[All]...)
+	b.mu.RUnlock()
+
+	var succeeded []string
+	var errs []error
+	for _, s := range subscribers {
+		if slices.Contains(handled, s.name) {
+			continue
+		}
+		if err := s.handler(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+			continue
+		}
+		succeeded = append(succeeded, s.name)
+	}
+
+	return succeeded, errors.Join(errs...)
+}
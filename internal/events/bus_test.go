@@ -0,0 +1,63 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"ozinshe/internal/models"
+	"slices"
+	"testing"
+)
+
+func TestDispatchSkipsHandledSubscribers(t *testing.T) {
+	calls := make(map[string]int)
+	failing := true
+
+	bus := NewBus()
+	bus.On(models.EventProjectPublished, "notifications", func(ctx context.Context, event models.Event) error {
+		calls["notifications"]++
+		return nil
+	})
+	bus.On(All, "webhooks", func(ctx context.Context, event models.Event) error {
+		calls["webhooks"]++
+		if failing {
+			return errors.New("unreachable")
+		}
+		return nil
+	})
+
+	event := models.Event{ID: 1, Type: models.EventProjectPublished}
+
+	succeeded, err := bus.Dispatch(context.Background(), event, nil)
+	if err == nil {
+		t.Fatal("Dispatch() error = nil, want the webhooks error")
+	}
+	if !slices.Equal(succeeded, []string{"notifications"}) {
+		t.Errorf("Dispatch() succeeded = %v, want [notifications]", succeeded)
+	}
+
+	failing = false
+	succeeded, err = bus.Dispatch(context.Background(), event, succeeded)
+	if err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+	if !slices.Equal(succeeded, []string{"webhooks"}) {
+		t.Errorf("Dispatch() succeeded = %v, want [webhooks]", succeeded)
+	}
+
+	if calls["notifications"] != 1 || calls["webhooks"] != 2 {
+		t.Errorf("calls = %v, want notifications once and webhooks twice", calls)
+	}
+}
+
+func TestDispatchIgnoresOtherEventTypes(t *testing.T) {
+	bus := NewBus()
+	bus.On(models.EventProjectPublished, "notifications", func(ctx context.Context, event models.Event) error {
+		t.Error("notifications called for another event type")
+		return nil
+	})
+
+	succeeded, err := bus.Dispatch(context.Background(), models.Event{Type: models.EventUserRegistered}, nil)
+	if err != nil || len(succeeded) != 0 {
+		t.Errorf("Dispatch() = %v, %v, want no subscribers", succeeded, err)
+	}
+}
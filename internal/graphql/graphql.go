@@ -0,0 +1,147 @@
+// Package graphql serves the catalog over GraphQL: projects, movies, series
+// with their seasons and episodes, genres, age categories and the favorites
+// of the signed in user. The parts of movies and series are loaded through
+// per-request dataloaders, so that a list of N projects costs a query per
+// kind of part asked for rather than N of them.
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"log/slog"
+	"ozinshe/internal/apperr"
+	"ozinshe/internal/config/lib/logger/sl"
+	"ozinshe/internal/models"
+	"ozinshe/internal/service"
+	"runtime/debug"
+
+	gql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/graph-gophers/graphql-go/trace/otel"
+	gootel "go.opentelemetry.io/otel"
+)
+
+//go:embed schema.graphql
+var Schema string
+
+const (
+	// maxDepth bounds how deeply queries may nest, deep enough for
+	// me.favorites.series.seasons.episodes with room to spare.
+	maxDepth = 10
+
+	// maxParallelism is how many resolvers of a query run at once. It is as
+	// large as a batch so that the items of a list all join the same one.
+	maxParallelism = maxBatch
+)
+
+// Request is a GraphQL request as clients post it.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Viewer is the user a request is made by.
+type Viewer struct {
+	User    models.User
+	IsAdmin bool
+}
+
+type Server struct {
+	schema  *gql.Schema
+	service *service.Service
+	log     *slog.Logger
+}
+
+// New parses the schema against its resolvers, failing when they do not
+// implement it.
+func New(service *service.Service, log *slog.Logger) (*Server, error) {
+	const op = "graphql.New"
+
+	schema, err := gql.ParseSchema(Schema, &resolver{service: service},
+		gql.MaxDepth(maxDepth),
+		gql.MaxParallelism(maxParallelism),
+		gql.Tracer(&otel.Tracer{Tracer: gootel.Tracer("ozinshe/graphql")}),
+		gql.Logger(panics{log: log}),
+		gql.PanicHandler(panics{log: log}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Server{schema: schema, service: service, log: log}, nil
+}
+
+// Exec runs req for viewer, which is nil for anonymous requests.
+func (s *Server) Exec(ctx context.Context, viewer *Viewer, req Request) *gql.Response {
+	ctx = context.WithValue(ctx, requestKey{}, &request{
+		viewer:  viewer,
+		loaders: newLoaders(s.service.Catalog),
+		log:     sl.FromContext(ctx, s.log),
+	})
+
+	return s.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+}
+
+type requestKey struct{}
+
+// request is what the resolvers of a request share.
+type request struct {
+	viewer  *Viewer
+	loaders *loaders
+	log     *slog.Logger
+}
+
+func requestFrom(ctx context.Context) *request {
+	return ctx.Value(requestKey{}).(*request)
+}
+
+var errSignInRequired = apperr.Unauthorized("sign_in_required", "sign in required")
+
+// queryError is an error as clients are shown it, with its code in the
+// extensions of the error.
+type queryError struct {
+	err *apperr.Error
+}
+
+func (e queryError) Error() string {
+	return e.err.Message
+}
+
+func (e queryError) Extensions() map[string]any {
+	extensions := map[string]any{"code": e.err.Code}
+	if len(e.err.Fields) > 0 {
+		extensions["fields"] = e.err.Fields
+	}
+	return extensions
+}
+
+// fail turns err into the error its resolver returns, logging it when it
+// is not the client's doing.
+func fail(ctx context.Context, err error) error {
+	e := apperr.From(err)
+	if e.Kind == apperr.KindInternal || e.Kind == apperr.KindUnavailable {
+		requestFrom(ctx).log.Error("graphql resolver failed", sl.Err(err))
+	}
+	return queryError{e}
+}
+
+// panics handles the panics of resolvers, which the executor recovers from
+// before the Recover middleware could see them. They are logged like the
+// panics of handlers, and clients are told of an internal error only.
+type panics struct {
+	log *slog.Logger
+}
+
+func (p panics) LogPanic(ctx context.Context, value any) {
+	sl.FromContext(ctx, p.log).Error("panic recovered", slog.Any("panic", value), slog.String("stack", string(debug.Stack())))
+}
+
+func (p panics) MakePanicError(ctx context.Context, value any) *gqlerrors.QueryError {
+	e := queryError{apperr.Internal(nil)}
+
+	err := gqlerrors.Errorf("%s", e.Error())
+	err.Extensions = e.Extensions()
+	return err
+}
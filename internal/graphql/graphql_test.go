@@ -0,0 +1,326 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"ozinshe/internal/models"
+	"ozinshe/internal/service"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+)
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// The resolvers must implement the schema, field for field.
+func TestSchemaMatchesResolvers(t *testing.T) {
+	if _, err := New(&service.Service{}, discard); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// catalog is a fake catalog of movies 1-20, projects 1-20, and series 1-2,
+// projects 21-22, which records the batches it is asked for.
+type catalog struct {
+	service.Catalog
+
+	mu    sync.Mutex
+	calls map[string][][]int
+}
+
+func newCatalog() *catalog {
+	return &catalog{calls: map[string][][]int{}}
+}
+
+func (c *catalog) record(name string, ids []int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ids = slices.Clone(ids)
+	slices.Sort(ids)
+	c.calls[name] = append(c.calls[name], ids)
+}
+
+// batches returns the batches name was called with.
+func (c *catalog) batches(name string) [][]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.calls[name]
+}
+
+func project(id int) models.Project {
+	if id > 20 {
+		return models.Project{Id: id, Project_type: "series", Project_id: id - 20}
+	}
+	return models.Project{Id: id, Project_type: "movie", Project_id: id}
+}
+
+// each returns the value of every ID known to exist, up to last.
+func each[V any](ids []int, last int, value func(id int) V) map[int]V {
+	values := make(map[int]V, len(ids))
+	for _, id := range ids {
+		if id >= 1 && id <= last {
+			values[id] = value(id)
+		}
+	}
+	return values
+}
+
+func (c *catalog) GetPublished(ctx context.Context, projectType string, offset, limit int) ([]models.Project, error) {
+	var projects []models.Project
+	for id := 1; id <= 22; id++ {
+		if p := project(id); projectType == "" || p.Project_type == projectType {
+			projects = append(projects, p)
+		}
+	}
+	return projects[min(offset, len(projects)):min(offset+limit, len(projects))], nil
+}
+
+func (c *catalog) GetProjects(ctx context.Context, ids []int) (map[int]models.Project, error) {
+	c.record("GetProjects", ids)
+	return each(ids, 22, project), nil
+}
+
+func (c *catalog) GetMovies(ctx context.Context, ids []int) (map[int]models.Movie, error) {
+	c.record("GetMovies", ids)
+	return each(ids, 20, func(id int) models.Movie {
+		return models.Movie{ID: id, Title: fmt.Sprintf("Movie %d", id), ReleaseYear: 2000 + id}
+	}), nil
+}
+
+func (c *catalog) GetSeries(ctx context.Context, ids []int) (map[int]models.Series, error) {
+	c.record("GetSeries", ids)
+	return each(ids, 2, func(id int) models.Series {
+		return models.Series{ID: id, Title: fmt.Sprintf("Series %d", id)}
+	}), nil
+}
+
+func (c *catalog) GetGenres(ctx context.Context, projectType string, ids []int) (map[int][]models.Genre, error) {
+	c.record("GetGenres "+projectType, ids)
+	return each(ids, 20, func(id int) []models.Genre {
+		return []models.Genre{{ID: 1, Name: "Drama"}}
+	}), nil
+}
+
+func (c *catalog) GetKeywords(ctx context.Context, projectType string, ids []int) (map[int][]models.Keyword, error) {
+	c.record("GetKeywords "+projectType, ids)
+	return nil, nil
+}
+
+func (c *catalog) GetCovers(ctx context.Context, projectType string, ids []int) (map[int]models.Cover, error) {
+	c.record("GetCovers "+projectType, ids)
+	if projectType != "movie" {
+		return nil, nil
+	}
+	return each(ids, 20, func(id int) models.Cover {
+		return models.Cover{ID: 100 + id, ProjectID: id, Filename: fmt.Sprintf("%d.jpg", id)}
+	}), nil
+}
+
+// Series 1 has seasons 11 and 12, series 2 season 21; every season has a
+// single episode.
+func (c *catalog) GetSeasons(ctx context.Context, seriesIDs []int) (map[int][]models.Season, error) {
+	c.record("GetSeasons", seriesIDs)
+	return each(seriesIDs, 2, func(id int) []models.Season {
+		if id == 1 {
+			return []models.Season{{ID: 11, SeriesID: 1, SeasonNumber: 1}, {ID: 12, SeriesID: 1, SeasonNumber: 2}}
+		}
+		return []models.Season{{ID: 21, SeriesID: 2, SeasonNumber: 1}}
+	}), nil
+}
+
+func (c *catalog) GetEpisodes(ctx context.Context, seasonIDs []int) (map[int][]models.Episode, error) {
+	c.record("GetEpisodes", seasonIDs)
+	values := make(map[int][]models.Episode, len(seasonIDs))
+	for _, id := range seasonIDs {
+		values[id] = []models.Episode{{ID: 10 * id, SeasonID: id, EpisodeNumber: 1, Link: fmt.Sprintf("https://youtu.be/%d", id)}}
+	}
+	return values, nil
+}
+
+// projects is a fake project service, whose movies and series are all
+// published but movie 20.
+type projects struct {
+	service.Project
+}
+
+func (projects) IsPublished(ctx context.Context, projectType string, contentID int) (bool, error) {
+	return projectType != "movie" || contentID != 20, nil
+}
+
+func newTestServer(t *testing.T) (*Server, *catalog) {
+	t.Helper()
+
+	catalog := newCatalog()
+	server, err := New(&service.Service{Catalog: catalog, Project: projects{}}, discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server, catalog
+}
+
+// exec runs query and returns its data, failing on errors.
+func exec(t *testing.T, server *Server, query string) map[string]any {
+	t.Helper()
+
+	resp := server.Exec(context.Background(), nil, Request{Query: query})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("errors: %v", resp.Errors)
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// equalJSON reports whether got holds the JSON want.
+func equalJSON(t *testing.T, got any, want string) bool {
+	t.Helper()
+
+	var w any
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatal(err)
+	}
+	return reflect.DeepEqual(got, w)
+}
+
+func TestResolvers(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			"movie",
+			`{ movie(id: "3") { id title releaseYear cover { filename } genres { name } keywords } }`,
+			`{"movie": {"id": "3", "title": "Movie 3", "releaseYear": 2003, "cover": {"filename": "3.jpg"}, "genres": [{"name": "Drama"}], "keywords": []}}`,
+		},
+		{
+			"unpublished movie",
+			`{ movie(id: "20") { title } }`,
+			`{"movie": null}`,
+		},
+		{
+			"missing movie",
+			`{ movie(id: "99") { title } }`,
+			`{"movie": null}`,
+		},
+		{
+			"series without a cover",
+			`{ series(id: "1") { title cover { filename } season(number: 2) { id episodes { number link } } } }`,
+			`{"series": {"title": "Series 1", "cover": null, "season": {"id": "12", "episodes": [{"number": 1, "link": "https://youtu.be/12"}]}}}`,
+		},
+		{
+			"projects of a type",
+			`{ projects(type: SERIES) { id type title movie { id } series { id } } }`,
+			`{"projects": [
+				{"id": "21", "type": "SERIES", "title": "Series 1", "movie": null, "series": {"id": "1"}},
+				{"id": "22", "type": "SERIES", "title": "Series 2", "movie": null, "series": {"id": "2"}}
+			]}`,
+		},
+		{
+			"page of projects",
+			`{ projects(offset: 1, limit: 2) { id title } }`,
+			`{"projects": [{"id": "2", "title": "Movie 2"}, {"id": "3", "title": "Movie 3"}]}`,
+		},
+		{
+			"anonymous viewer",
+			`{ me { id } }`,
+			`{"me": null}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := newTestServer(t)
+
+			if data := exec(t, server, tt.query); !equalJSON(t, data, tt.want) {
+				got, _ := json.Marshal(data)
+				t.Errorf("data = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvalidPage(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	resp := server.Exec(context.Background(), nil, Request{Query: `{ projects(limit: 0) { id } }`})
+	if len(resp.Errors) != 1 {
+		t.Fatalf("errors = %v, want one", resp.Errors)
+	}
+	if code := resp.Errors[0].Extensions["code"]; code != "invalid_page" {
+		t.Errorf("error code = %v, want invalid_page", code)
+	}
+}
+
+// A list of projects costs a few calls per kind of part asked for, and
+// project type, rather than one per project: the items of a list join the
+// batches open when they are resolved, each loaded once.
+func TestLoadersBatch(t *testing.T) {
+	server, catalog := newTestServer(t)
+
+	data := exec(t, server, `{
+		projects(limit: 50) {
+			title
+			movie { title cover { filename } genres { name } keywords }
+			series { title genres { name } seasons { number episodes { link } } }
+		}
+	}`)
+	if n := len(data["projects"].([]any)); n != 22 {
+		t.Fatalf("%d projects, want 22", n)
+	}
+
+	var movies []int
+	for id := 1; id <= 20; id++ {
+		movies = append(movies, id)
+	}
+	series := []int{1, 2}
+
+	for name, want := range map[string][]int{
+		"GetMovies":          movies,
+		"GetSeries":          series,
+		"GetCovers movie":    movies,
+		"GetGenres movie":    movies,
+		"GetKeywords movie":  movies,
+		"GetGenres series":   series,
+		"GetSeasons":         series,
+		"GetEpisodes":        {11, 12, 21},
+		"GetCovers series":   nil,
+		"GetKeywords series": nil,
+	} {
+		batches := catalog.batches(name)
+
+		var loaded []int
+		for _, batch := range batches {
+			loaded = append(loaded, batch...)
+		}
+		slices.Sort(loaded)
+
+		if !slices.Equal(loaded, want) {
+			t.Errorf("%s loaded %v, want %v each once", name, batches, want)
+		}
+		// A slow scheduler may resolve the items across more than one wait
+		// of the loaders, but never one at a time.
+		if len(want) == len(movies) && len(batches) > len(want)/2 {
+			t.Errorf("%s called %d times for %d movies, want them batched", name, len(batches), len(want))
+		}
+	}
+}
+
+// A project asked for twice in a request is loaded once.
+func TestLoadersCache(t *testing.T) {
+	server, catalog := newTestServer(t)
+
+	exec(t, server, `{ a: movie(id: "1") { title } b: movie(id: "1") { title } c: project(id: "1") { title } }`)
+
+	if batches := catalog.batches("GetMovies"); len(batches) != 1 || !slices.Equal(batches[0], []int{1}) {
+		t.Errorf("GetMovies called with %v, want once with [1]", batches)
+	}
+}
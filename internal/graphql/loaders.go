@@ -0,0 +1,98 @@
+package graphql
+
+import (
+	"context"
+	"ozinshe/internal/dataloader"
+	"ozinshe/internal/models"
+	"ozinshe/internal/service"
+	"time"
+)
+
+const (
+	// wait is how long a loader collects loads after the first of a batch.
+	// The items of a list are resolved at about the same time, so a short
+	// wait is enough for all of them to join.
+	wait = 2 * time.Millisecond
+
+	maxBatch = 100
+)
+
+// content identifies a movie or series, whose parts are loaded together
+// for both kinds.
+type content struct {
+	Type string
+	ID   int
+}
+
+// loaders load the catalog for a single request. Projects, movies, series
+// and seasons are keyed by their IDs; episodes by the ID of their season.
+type loaders struct {
+	projects      *dataloader.Loader[int, *models.Project]
+	movies        *dataloader.Loader[int, *models.Movie]
+	series        *dataloader.Loader[int, *models.Series]
+	genres        *dataloader.Loader[content, []models.Genre]
+	ageCategories *dataloader.Loader[content, []models.AgeCategory]
+	keywords      *dataloader.Loader[content, []models.Keyword]
+	covers        *dataloader.Loader[content, models.Cover]
+	screenshots   *dataloader.Loader[content, []models.Screenshot]
+	seasons       *dataloader.Loader[int, []models.Season]
+	episodes      *dataloader.Loader[int, []models.Episode]
+}
+
+func newLoaders(catalog service.Catalog) *loaders {
+	return &loaders{
+		projects:      dataloader.New(byPointer(catalog.GetProjects), wait, maxBatch),
+		movies:        dataloader.New(byPointer(catalog.GetMovies), wait, maxBatch),
+		series:        dataloader.New(byPointer(catalog.GetSeries), wait, maxBatch),
+		genres:        dataloader.New(byContent(catalog.GetGenres), wait, maxBatch),
+		ageCategories: dataloader.New(byContent(catalog.GetAgeCategories), wait, maxBatch),
+		keywords:      dataloader.New(byContent(catalog.GetKeywords), wait, maxBatch),
+		covers:        dataloader.New(byContent(catalog.GetCovers), wait, maxBatch),
+		screenshots:   dataloader.New(byContent(catalog.GetScreenshots), wait, maxBatch),
+		seasons:       dataloader.New(catalog.GetSeasons, wait, maxBatch),
+		episodes:      dataloader.New(catalog.GetEpisodes, wait, maxBatch),
+	}
+}
+
+// byPointer loads pointers to the values fetch loads, so that the ones it
+// finds none for load as nil.
+func byPointer[V any](fetch func(context.Context, []int) (map[int]V, error)) dataloader.BatchFunc[int, *V] {
+	return func(ctx context.Context, ids []int) (map[int]*V, error) {
+		values, err := fetch(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		pointers := make(map[int]*V, len(values))
+		for id := range values {
+			v := values[id]
+			pointers[id] = &v
+		}
+
+		return pointers, nil
+	}
+}
+
+// byContent loads the parts of movies and series, calling fetch once for
+// each project type in the batch.
+func byContent[V any](fetch func(context.Context, string, []int) (map[int]V, error)) dataloader.BatchFunc[content, V] {
+	return func(ctx context.Context, keys []content) (map[content]V, error) {
+		ids := map[string][]int{}
+		for _, key := range keys {
+			ids[key.Type] = append(ids[key.Type], key.ID)
+		}
+
+		values := make(map[content]V, len(keys))
+		for projectType, ids := range ids {
+			parts, err := fetch(ctx, projectType, ids)
+			if err != nil {
+				return nil, err
+			}
+			for id, part := range parts {
+				values[content{Type: projectType, ID: id}] = part
+			}
+		}
+
+		return values, nil
+	}
+}
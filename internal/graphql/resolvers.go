@@ -0,0 +1,239 @@
+package graphql
+
+import (
+	"context"
+	"ozinshe/internal/apperr"
+	"ozinshe/internal/models"
+	"ozinshe/internal/service"
+	"ozinshe/internal/storage"
+	"strconv"
+	"strings"
+	"time"
+
+	gql "github.com/graph-gophers/graphql-go"
+)
+
+const maxPageLimit = 50
+
+// resolver resolves the fields of Query and Mutation.
+type resolver struct {
+	service *service.Service
+}
+
+func (r *resolver) Projects(ctx context.Context, args struct {
+	Type   *string
+	Offset int32
+	Limit  int32
+}) ([]*projectResolver, error) {
+	if args.Offset < 0 || args.Limit < 1 || args.Limit > maxPageLimit {
+		return nil, queryError{apperr.Validation("invalid_page", "offset must not be negative and limit between 1 and "+strconv.Itoa(maxPageLimit), nil)}
+	}
+
+	projectType := ""
+	if args.Type != nil {
+		projectType = strings.ToLower(*args.Type)
+	}
+
+	projects, err := r.service.Catalog.GetPublished(ctx, projectType, int(args.Offset), int(args.Limit))
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	resolvers := make([]*projectResolver, len(projects))
+	for i := range projects {
+		resolvers[i] = &projectResolver{project: &projects[i]}
+	}
+
+	return resolvers, nil
+}
+
+func (r *resolver) Project(ctx context.Context, args struct{ ID gql.ID }) (*projectResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := visibleProject(ctx, id)
+	if err != nil || project == nil {
+		return nil, err
+	}
+
+	return &projectResolver{project: project}, nil
+}
+
+func (r *resolver) Movie(ctx context.Context, args struct{ ID gql.ID }) (*movieResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	movie, err := requestFrom(ctx).loaders.movies.Load(ctx, id)
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+	if movie == nil {
+		return nil, nil
+	}
+
+	visible, err := r.visible(ctx, "movie", id)
+	if err != nil || !visible {
+		return nil, err
+	}
+
+	return newMovieResolver(movie), nil
+}
+
+func (r *resolver) Series(ctx context.Context, args struct{ ID gql.ID }) (*seriesResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	series, err := requestFrom(ctx).loaders.series.Load(ctx, id)
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+	if series == nil {
+		return nil, nil
+	}
+
+	visible, err := r.visible(ctx, "series", id)
+	if err != nil || !visible {
+		return nil, err
+	}
+
+	return newSeriesResolver(series), nil
+}
+
+// visible reports whether the viewer may see the movie or series: admins
+// see all of them, everyone else only published ones.
+func (r *resolver) visible(ctx context.Context, projectType string, id int) (bool, error) {
+	if viewer := requestFrom(ctx).viewer; viewer != nil && viewer.IsAdmin {
+		return true, nil
+	}
+
+	published, err := r.service.Project.IsPublished(ctx, projectType, id)
+	if err != nil {
+		return false, fail(ctx, err)
+	}
+
+	return published, nil
+}
+
+func (r *resolver) Genres(ctx context.Context) ([]*genreResolver, error) {
+	genres, err := r.service.Genre.GetAll(ctx)
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	return genreResolvers(genres), nil
+}
+
+func (r *resolver) Genre(ctx context.Context, args struct{ ID gql.ID }) (*genreResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	genre, err := r.service.Genre.GetById(ctx, id)
+	if apperr.IsKind(err, apperr.KindNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	return &genreResolver{genre: genre}, nil
+}
+
+func (r *resolver) AgeCategories(ctx context.Context) ([]*ageCategoryResolver, error) {
+	ageCategories, err := r.service.AgeCategory.GetAll(ctx)
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	return ageCategoryResolvers(ageCategories), nil
+}
+
+func (r *resolver) Me(ctx context.Context) *viewerResolver {
+	viewer := requestFrom(ctx).viewer
+	if viewer == nil {
+		return nil
+	}
+
+	return &viewerResolver{viewer: viewer, service: r.service}
+}
+
+func (r *resolver) AddFavorite(ctx context.Context, args struct{ ProjectID gql.ID }) (*projectResolver, error) {
+	viewer := requestFrom(ctx).viewer
+	if viewer == nil {
+		return nil, queryError{errSignInRequired}
+	}
+
+	id, err := parseID(args.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := visibleProject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, queryError{storage.ErrProjectNotFound}
+	}
+
+	err = r.service.List.AddToFavorites(ctx, viewer.User.ID, id)
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	return &projectResolver{project: project}, nil
+}
+
+func (r *resolver) RemoveFavorite(ctx context.Context, args struct{ ProjectID gql.ID }) (bool, error) {
+	viewer := requestFrom(ctx).viewer
+	if viewer == nil {
+		return false, queryError{errSignInRequired}
+	}
+
+	id, err := parseID(args.ProjectID)
+	if err != nil {
+		return false, err
+	}
+
+	err = r.service.List.RemoveFromFavorites(ctx, viewer.User.ID, id)
+	if err != nil {
+		return false, fail(ctx, err)
+	}
+
+	return true, nil
+}
+
+// visibleProject loads the project with id, or nil when there is none or
+// the viewer may not see it.
+func visibleProject(ctx context.Context, id int) (*models.Project, error) {
+	req := requestFrom(ctx)
+
+	project, err := req.loaders.projects.Load(ctx, id)
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+	if project == nil {
+		return nil, nil
+	}
+
+	if !project.Visible(time.Now()) && (req.viewer == nil || !req.viewer.IsAdmin) {
+		return nil, nil
+	}
+
+	return project, nil
+}
+
+func parseID(id gql.ID) (int, error) {
+	n, err := strconv.Atoi(string(id))
+	if err != nil || n < 1 {
+		return 0, queryError{apperr.Validation("invalid_id", "invalid id "+strconv.Quote(string(id)), nil)}
+	}
+	return n, nil
+}
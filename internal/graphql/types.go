@@ -0,0 +1,321 @@
+package graphql
+
+import (
+	"context"
+	"ozinshe/internal/models"
+	"ozinshe/internal/service"
+	"strconv"
+	"strings"
+
+	gql "github.com/graph-gophers/graphql-go"
+)
+
+func toID(id int) gql.ID {
+	return gql.ID(strconv.Itoa(id))
+}
+
+type viewerResolver struct {
+	viewer  *Viewer
+	service *service.Service
+}
+
+func (v *viewerResolver) ID() gql.ID {
+	return toID(v.viewer.User.ID)
+}
+
+func (v *viewerResolver) Name() string {
+	return v.viewer.User.Name
+}
+
+func (v *viewerResolver) Email() string {
+	return v.viewer.User.Email
+}
+
+func (v *viewerResolver) Favorites(ctx context.Context) ([]*projectResolver, error) {
+	ids, err := v.service.Catalog.GetFavoriteIds(ctx, v.viewer.User.ID)
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	projects, err := requestFrom(ctx).loaders.projects.LoadMany(ctx, ids)
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	resolvers := make([]*projectResolver, 0, len(projects))
+	for _, project := range projects {
+		if project != nil {
+			resolvers = append(resolvers, &projectResolver{project: project})
+		}
+	}
+
+	return resolvers, nil
+}
+
+type projectResolver struct {
+	project *models.Project
+}
+
+func (p *projectResolver) ID() gql.ID {
+	return toID(p.project.Id)
+}
+
+func (p *projectResolver) Type() string {
+	return strings.ToUpper(p.project.Project_type)
+}
+
+// Title is the title of the movie or series of the project.
+func (p *projectResolver) Title(ctx context.Context) (string, error) {
+	loaders := requestFrom(ctx).loaders
+
+	switch p.project.Project_type {
+	case "movie":
+		movie, err := loaders.movies.Load(ctx, p.project.Project_id)
+		if err != nil || movie == nil {
+			return "", failIf(ctx, err)
+		}
+		return movie.Title, nil
+	default:
+		series, err := loaders.series.Load(ctx, p.project.Project_id)
+		if err != nil || series == nil {
+			return "", failIf(ctx, err)
+		}
+		return series.Title, nil
+	}
+}
+
+func (p *projectResolver) Movie(ctx context.Context) (*movieResolver, error) {
+	if p.project.Project_type != "movie" {
+		return nil, nil
+	}
+
+	movie, err := requestFrom(ctx).loaders.movies.Load(ctx, p.project.Project_id)
+	if err != nil || movie == nil {
+		return nil, failIf(ctx, err)
+	}
+
+	return newMovieResolver(movie), nil
+}
+
+func (p *projectResolver) Series(ctx context.Context) (*seriesResolver, error) {
+	if p.project.Project_type != "series" {
+		return nil, nil
+	}
+
+	series, err := requestFrom(ctx).loaders.series.Load(ctx, p.project.Project_id)
+	if err != nil || series == nil {
+		return nil, failIf(ctx, err)
+	}
+
+	return newSeriesResolver(series), nil
+}
+
+// failIf is fail for errors that may be nil.
+func failIf(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fail(ctx, err)
+}
+
+// partsResolver resolves the fields movies and series share, their parts
+// loaded for content.
+type partsResolver struct {
+	content content
+}
+
+func (p partsResolver) Cover(ctx context.Context) (*imageResolver, error) {
+	cover, err := requestFrom(ctx).loaders.covers.Load(ctx, p.content)
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+	if cover.ID == 0 {
+		return nil, nil
+	}
+
+	return &imageResolver{id: cover.ID, filename: cover.Filename}, nil
+}
+
+func (p partsResolver) Screenshots(ctx context.Context, args struct{ First *int32 }) ([]*imageResolver, error) {
+	screenshots, err := requestFrom(ctx).loaders.screenshots.Load(ctx, p.content)
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	if args.First != nil && int(*args.First) < len(screenshots) {
+		screenshots = screenshots[:max(*args.First, 0)]
+	}
+
+	resolvers := make([]*imageResolver, len(screenshots))
+	for i, screenshot := range screenshots {
+		resolvers[i] = &imageResolver{id: screenshot.ID, filename: screenshot.Filename}
+	}
+
+	return resolvers, nil
+}
+
+func (p partsResolver) Genres(ctx context.Context) ([]*genreResolver, error) {
+	genres, err := requestFrom(ctx).loaders.genres.Load(ctx, p.content)
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	return genreResolvers(genres), nil
+}
+
+func (p partsResolver) AgeCategories(ctx context.Context) ([]*ageCategoryResolver, error) {
+	ageCategories, err := requestFrom(ctx).loaders.ageCategories.Load(ctx, p.content)
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	return ageCategoryResolvers(ageCategories), nil
+}
+
+func (p partsResolver) Keywords(ctx context.Context) ([]string, error) {
+	keywords, err := requestFrom(ctx).loaders.keywords.Load(ctx, p.content)
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	names := make([]string, len(keywords))
+	for i, keyword := range keywords {
+		names[i] = keyword.Name
+	}
+
+	return names, nil
+}
+
+type movieResolver struct {
+	partsResolver
+	movie *models.Movie
+}
+
+func newMovieResolver(movie *models.Movie) *movieResolver {
+	return &movieResolver{partsResolver: partsResolver{content{Type: "movie", ID: movie.ID}}, movie: movie}
+}
+
+func (m *movieResolver) ID() gql.ID          { return toID(m.movie.ID) }
+func (m *movieResolver) Title() string       { return m.movie.Title }
+func (m *movieResolver) ReleaseYear() int32  { return int32(m.movie.ReleaseYear) }
+func (m *movieResolver) Description() string { return m.movie.Description }
+func (m *movieResolver) Popularity() int32   { return int32(m.movie.Popularity) }
+func (m *movieResolver) YoutubeID() string   { return m.movie.YoutubeID }
+func (m *movieResolver) Duration() int32     { return int32(m.movie.Duration) }
+func (m *movieResolver) Director() string    { return m.movie.Director }
+func (m *movieResolver) Producer() string    { return m.movie.Producer }
+
+type seriesResolver struct {
+	partsResolver
+	series *models.Series
+}
+
+func newSeriesResolver(series *models.Series) *seriesResolver {
+	return &seriesResolver{partsResolver: partsResolver{content{Type: "series", ID: series.ID}}, series: series}
+}
+
+func (s *seriesResolver) ID() gql.ID          { return toID(s.series.ID) }
+func (s *seriesResolver) Title() string       { return s.series.Title }
+func (s *seriesResolver) ReleaseYear() int32  { return int32(s.series.ReleaseYear) }
+func (s *seriesResolver) Description() string { return s.series.Description }
+func (s *seriesResolver) Popularity() int32   { return int32(s.series.Popularity) }
+func (s *seriesResolver) Duration() int32     { return int32(s.series.Duration) }
+func (s *seriesResolver) Director() string    { return s.series.Director }
+func (s *seriesResolver) Producer() string    { return s.series.Producer }
+
+func (s *seriesResolver) Seasons(ctx context.Context) ([]*seasonResolver, error) {
+	seasons, err := requestFrom(ctx).loaders.seasons.Load(ctx, s.series.ID)
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	resolvers := make([]*seasonResolver, len(seasons))
+	for i, season := range seasons {
+		resolvers[i] = &seasonResolver{season: season}
+	}
+
+	return resolvers, nil
+}
+
+func (s *seriesResolver) Season(ctx context.Context, args struct{ Number int32 }) (*seasonResolver, error) {
+	seasons, err := requestFrom(ctx).loaders.seasons.Load(ctx, s.series.ID)
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	for _, season := range seasons {
+		if season.SeasonNumber == int(args.Number) {
+			return &seasonResolver{season: season}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+type seasonResolver struct {
+	season models.Season
+}
+
+func (s *seasonResolver) ID() gql.ID    { return toID(s.season.ID) }
+func (s *seasonResolver) Number() int32 { return int32(s.season.SeasonNumber) }
+
+func (s *seasonResolver) Episodes(ctx context.Context) ([]*episodeResolver, error) {
+	episodes, err := requestFrom(ctx).loaders.episodes.Load(ctx, s.season.ID)
+	if err != nil {
+		return nil, fail(ctx, err)
+	}
+
+	resolvers := make([]*episodeResolver, len(episodes))
+	for i, episode := range episodes {
+		resolvers[i] = &episodeResolver{episode: episode}
+	}
+
+	return resolvers, nil
+}
+
+type episodeResolver struct {
+	episode models.Episode
+}
+
+func (e *episodeResolver) ID() gql.ID    { return toID(e.episode.ID) }
+func (e *episodeResolver) Number() int32 { return int32(e.episode.EpisodeNumber) }
+func (e *episodeResolver) Link() string  { return e.episode.Link }
+
+type imageResolver struct {
+	id       int
+	filename string
+}
+
+func (i *imageResolver) ID() gql.ID       { return toID(i.id) }
+func (i *imageResolver) Filename() string { return i.filename }
+
+type genreResolver struct {
+	genre models.Genre
+}
+
+func genreResolvers(genres []models.Genre) []*genreResolver {
+	resolvers := make([]*genreResolver, len(genres))
+	for i, genre := range genres {
+		resolvers[i] = &genreResolver{genre: genre}
+	}
+	return resolvers
+}
+
+func (g *genreResolver) ID() gql.ID   { return toID(g.genre.ID) }
+func (g *genreResolver) Name() string { return g.genre.Name }
+
+type ageCategoryResolver struct {
+	ageCategory models.AgeCategory
+}
+
+func ageCategoryResolvers(ageCategories []models.AgeCategory) []*ageCategoryResolver {
+	resolvers := make([]*ageCategoryResolver, len(ageCategories))
+	for i, ageCategory := range ageCategories {
+		resolvers[i] = &ageCategoryResolver{ageCategory: ageCategory}
+	}
+	return resolvers
+}
+
+func (a *ageCategoryResolver) ID() gql.ID    { return toID(a.ageCategory.ID) }
+func (a *ageCategoryResolver) MinAge() int32 { return int32(a.ageCategory.MinAge) }
+func (a *ageCategoryResolver) MaxAge() int32 { return int32(a.ageCategory.MaxAge) }
@@ -3,12 +3,16 @@ package handler
 import (
 	"net/http"
 	"ozinshe/internal/models"
+	"ozinshe/internal/validation"
 	"strconv"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
+type ageCategoryForm struct {
+	Age string `form:"age" validate:"required,age_range"`
+}
+
 // @Summary Create a new age category
 // @Description Creates a new age category based on the provided age range. Requires admin authorization.
 // @Tags age categories
@@ -17,30 +21,26 @@ import (
 // @Security CookieAuth
 // @Param age formData string true "Age range (e.g., '18-30')"
 // @Success 200 "Age category created"
-// @Failure 400 {object} error "Error creating age category"
+// @Failure 400 {object} Problem "Error creating age category"
 // @Router /ages [post]
 func (h *Handler) CreateAgeCategory(c *gin.Context) {
 
-	form, err := c.MultipartForm()
-	if err != nil {
+	var form ageCategoryForm
+	if err := c.ShouldBind(&form); err != nil {
 		h.errorpage(c, http.StatusBadRequest, err, "binding multipart form in create age category")
 		return
 	}
 
-	ages := form.Value["age"]
-	minAgeString, maxAgeString := strings.Split(ages[0], "-")[0], strings.Split(ages[0], "-")[1]
-
-	minAge, _ := strconv.Atoi(minAgeString)
-	maxAge, _ := strconv.Atoi(maxAgeString)
+	minAge, maxAge, _ := validation.ParseAgeRange(form.Age)
 
 	ageCategory := models.AgeCategory{
 		MinAge: minAge,
 		MaxAge: maxAge,
 	}
 
-	err = h.Service.AgeCategory.Add(append([]models.AgeCategory{}, ageCategory))
+	err := h.Service.AgeCategory.Add(c.Request.Context(), append([]models.AgeCategory{}, ageCategory))
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "age category creation failed")
+		h.errorpage(c, http.StatusInternalServerError, err, "age category creation failed")
 		return
 	}
 
@@ -48,23 +48,47 @@ func (h *Handler) CreateAgeCategory(c *gin.Context) {
 }
 
 // @Summary Get details of a specific age category
-// @Description Retrieves details of an age category based on the provided ID.
+// @Description Retrieves details of an age category based on the provided ID. fields limits the fields of the age category sent.
 // @Tags age categories
 // @Produce json
 // @Param id path int true "Age category ID"
+// @Param fields query string false "Comma separated fields to send, e.g. min_age,max_age"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Param If-Modified-Since header string false "Last-Modified of a cached copy"
 // @Success 200 {object} models.AgeCategory "Age category retrieved successfully"
-// @Failure 400 {object} error "Error retrieving age category"
+// @Header 200 {string} ETag "Hash of the response"
+// @Header 200 {string} Last-Modified "When the resource was last modified"
+// @Success 304 "The cached copy is current"
+// @Failure 400 {object} Problem "Error retrieving age category"
 // @Router /ages/{id} [get]
 func (h *Handler) GetAgeCategory(c *gin.Context) {
 
 	id, _ := strconv.Atoi(c.Param("id"))
-	ageCategory, err := h.Service.AgeCategory.GetById(id)
+	p, err := parseProjection(c, jsonFields(models.AgeCategory{}), nil)
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid projection")
+		return
+	}
+
+	modified, err := h.Service.Catalog.ModifiedAt(c.Request.Context(), models.ResourceAgeCategories, id)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "age category modification time getting failed")
+		return
+	}
+
+	ageCategory, err := h.Service.AgeCategory.GetById(c.Request.Context(), id)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "age category getting failed")
+		return
+	}
+
+	body, err := p.apply(ageCategory)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "age category getting failed")
+		h.errorpage(c, http.StatusInternalServerError, err, "encoding response")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Age category gotten  ", "age_category": ageCategory})
+	h.catalogJSON(c, modified, gin.H{"message": "Age category gotten  ", "age_category": body})
 }
 
 // @Summary Delete an existing age category
@@ -74,33 +98,57 @@ func (h *Handler) GetAgeCategory(c *gin.Context) {
 // @Security CookieAuth
 // @Param id path int true "Age category ID"
 // @Success 200 "Age category deleted successfully"
-// @Failure 400 {object} error "Error deleting age category"
+// @Failure 400 {object} Problem "Error deleting age category"
 // @Router /ages/{id} [delete]
 func (h *Handler) DeleteAgeCategory(c *gin.Context) {
 
 	id, _ := strconv.Atoi(c.Param("id"))
-	err := h.Service.AgeCategory.Remove(id)
+	err := h.Service.AgeCategory.Remove(c.Request.Context(), id)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "age category deleting failed")
+		h.errorpage(c, http.StatusInternalServerError, err, "age category deleting failed")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Age category deleted"})
 }
 
 // @Summary Get a list of all age categories
-// @Description Retrieves a list of all age categories in the system.
+// @Description Retrieves a list of all age categories in the system. fields limits the fields of the age categories sent.
 // @Tags age categories
 // @Produce json
-// @Success 200 {array} models.AgeCategory "List of age categories"
-// @Failure 400 {object} error "Error retrieving age categories"
+// @Param fields query string false "Comma separated fields to send, e.g. min_age,max_age"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Param If-Modified-Since header string false "Last-Modified of a cached copy"
+// @Success 200 {object} object{message=string,age_categories=[]models.AgeCategory} "List of age categories"
+// @Header 200 {string} ETag "Hash of the response"
+// @Header 200 {string} Last-Modified "When the resource was last modified"
+// @Success 304 "The cached copy is current"
+// @Failure 400 {object} Problem "Error retrieving age categories"
 // @Router /ages [get]
 func (h *Handler) GetAllAgeCategories(c *gin.Context) {
 
-	ageCategories, err := h.Service.AgeCategory.GetAll()
+	p, err := parseProjection(c, jsonFields(models.AgeCategory{}), nil)
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid projection")
+		return
+	}
+
+	modified, err := h.Service.Catalog.ListModifiedAt(c.Request.Context(), models.ResourceAgeCategories)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "age categories modification time getting failed")
+		return
+	}
+
+	ageCategories, err := h.Service.AgeCategory.GetAll(c.Request.Context())
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "age categories getting failed")
+		return
+	}
+
+	body, err := p.apply(ageCategories)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "age categories getting failed")
+		h.errorpage(c, http.StatusInternalServerError, err, "encoding response")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Age categories gotten", "age_categories": ageCategories})
+	h.catalogJSON(c, modified, gin.H{"message": "Age categories gotten", "age_categories": body})
 }
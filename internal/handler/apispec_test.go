@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"io"
+	"log/slog"
+	"ozinshe/docs"
+	"ozinshe/internal/apispec"
+	"ozinshe/internal/config"
+	"ozinshe/internal/service"
+	"testing"
+)
+
+// The API spec generated from the annotations must document every route of
+// the router, and nothing else. Run "make swagger" after changing either.
+func TestAPISpecMatchesRoutes(t *testing.T) {
+	spec, err := apispec.Load(docs.SwaggerInfo.ReadDoc())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Routing needs neither a database nor a config.
+	routes := New(&service.Service{}, &config.Config{}, slog.New(slog.NewTextHandler(io.Discard, nil))).InitRoutes().Routes()
+	if len(routes) == 0 {
+		t.Fatal("the router has no routes")
+	}
+
+	for _, problem := range spec.Check(routes) {
+		t.Error(problem)
+	}
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"ozinshe/internal/models"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxAuditExport caps the number of entries in a CSV export.
+const maxAuditExport = 10000
+
+// parseAuditFilter reads the audit log filters shared by the list and the
+// export from the query string.
+func parseAuditFilter(c *gin.Context) (models.AuditFilter, error) {
+	filter := models.AuditFilter{
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+		TargetID:   c.Query("target_id"),
+	}
+
+	var err error
+	if actorID := c.Query("actor_id"); actorID != "" {
+		if filter.ActorID, err = strconv.Atoi(actorID); err != nil {
+			return models.AuditFilter{}, err
+		}
+	}
+	if from := c.Query("from"); from != "" {
+		if filter.From, err = time.Parse(time.RFC3339, from); err != nil {
+			return models.AuditFilter{}, err
+		}
+	}
+	if to := c.Query("to"); to != "" {
+		if filter.To, err = time.Parse(time.RFC3339, to); err != nil {
+			return models.AuditFilter{}, err
+		}
+	}
+
+	return filter, nil
+}
+
+// @Summary Get the audit log
+// @Description Retrieves the changes made by admins, newest first, with snapshots of the target before and after each change. Requires admin authorization.
+// @Tags audit
+// @Security CookieAuth
+// @Produce json
+// @Param actor_id query int false "ID of the admin who made the change"
+// @Param action query string false "Action, e.g. movie.update"
+// @Param target_type query string false "Target type, e.g. movie"
+// @Param target_id query string false "Target ID"
+// @Param from query string false "Earliest time (RFC 3339)"
+// @Param to query string false "Time before which changes were made (RFC 3339)"
+// @Param offset query int false "Items to skip"
+// @Param limit query int false "Items per page (default 10, max 50)"
+// @Success 200 {array} models.AuditEntry "Audit log entries"
+// @Failure 400 {object} Problem "Error getting audit log"
+// @Router /admin/audit [get]
+func (h *Handler) GetAuditLog(c *gin.Context) {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid audit log filter")
+		return
+	}
+	filter.Offset, filter.Limit = parsePage(c)
+
+	entries, err := h.Service.Audit.GetAll(c.Request.Context(), filter)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "audit log getting failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// @Summary Export the audit log
+// @Description Downloads up to 10000 audit log entries matching the filters as CSV, newest first. Requires admin authorization.
+// @Tags audit
+// @Security CookieAuth
+// @Produce text/csv
+// @Param actor_id query int false "ID of the admin who made the change"
+// @Param action query string false "Action, e.g. movie.update"
+// @Param target_type query string false "Target type, e.g. movie"
+// @Param target_id query string false "Target ID"
+// @Param from query string false "Earliest time (RFC 3339)"
+// @Param to query string false "Time before which changes were made (RFC 3339)"
+// @Success 200 {file} file "CSV file"
+// @Failure 400 {object} Problem "Error exporting audit log"
+// @Router /admin/audit/export [get]
+func (h *Handler) ExportAuditLog(c *gin.Context) {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid audit log filter")
+		return
+	}
+	filter.Limit = maxAuditExport
+
+	entries, err := h.Service.Audit.GetAll(c.Request.Context(), filter)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "audit log exporting failed")
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="audit-log.csv"`)
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"id", "created_at", "actor_id", "actor_email", "action", "target_type", "target_id",
+		"before", "after", "ip", "request_id"})
+	for _, e := range entries {
+		actorID := ""
+		if e.ActorID != nil {
+			actorID = strconv.Itoa(*e.ActorID)
+		}
+		w.Write([]string{
+			strconv.FormatInt(e.ID, 10), e.CreatedAt.Format(time.RFC3339), actorID, e.ActorEmail, e.Action,
+			e.TargetType, e.TargetID, string(e.Before), string(e.After), e.IP, e.RequestID,
+		})
+	}
+	w.Flush()
+
+	if err := w.Error(); err != nil {
+		h.logger(c).Error(fmt.Sprintf("audit log export interrupted: %v", err))
+	}
+}
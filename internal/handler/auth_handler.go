@@ -4,14 +4,13 @@ import (
 	"errors"
 	"net/http"
 	"ozinshe/internal/models"
-	"ozinshe/internal/validation"
 
 	"github.com/gin-gonic/gin"
 )
 
 type entryForm struct {
-	Email            string `form:"email" json:"email"`
-	Password         string `form:"password" json:"password"`
+	Email            string `form:"email" json:"email" validate:"required,email"`
+	Password         string `form:"password" json:"password" validate:"required,password"`
 	Confirm_password string `form:"confirm_password" json:"confirm_password"`
 }
 
@@ -22,9 +21,9 @@ type entryForm struct {
 // @Produce json
 // @Param entry body entryForm true "User email and password"
 // @Success 200  "User registered successfully"
-// @Failure 400 {object} string "Error signing up: email or password is empty"
-// @Failure 400 {object} string "Error signing up: sign up failed"
-// @Failure 400 {object} string "Error signing up: user registration failed"
+// @Failure 400 {object} Problem "Request body is not JSON"
+// @Failure 409 {object} Problem "User already exists"
+// @Failure 422 {object} Problem "Email or password is not valid"
 // @Router /signup [post]
 func (h *Handler) SignUp(c *gin.Context) {
 	var form entryForm
@@ -39,21 +38,10 @@ func (h *Handler) SignUp(c *gin.Context) {
 		Password: form.Password,
 	}
 
-	if user.Email == "" || user.Password == "" {
-		h.errorpage(c, http.StatusBadRequest, errors.New("email or password is empty"), "sign up failed")
-		return
-	}
-
-	err := validation.GetErrMsg(user)
-	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "sign up failed")
-		return
-	}
-
-	err = h.Service.Register(user)
+	err := h.Service.Register(c.Request.Context(), user)
 
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "user registration failed")
+		h.errorpage(c, http.StatusInternalServerError, err, "user registration failed")
 		return
 	}
 
@@ -66,10 +54,10 @@ func (h *Handler) SignUp(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param entry body entryForm true "User email and password"
-// @Success 200 {array} string "User logged in successfully"
-// @Failure 400 {object} string "Error signing in: email or password is empty"
-// @Failure 400 {object} string "Error signing in: sign up failed"
-// @Failure 400 {object} string "Error signing in: user loggin in failed"
+// @Success 200 {array} string "Access and refresh tokens"
+// @Failure 400 {object} Problem "Request body is not JSON or passwords don't match"
+// @Failure 401 {object} Problem "Wrong email or password"
+// @Failure 422 {object} Problem "Email or password is not valid"
 // @Router /signin [post]
 func (h *Handler) SignIn(c *gin.Context) {
 	var form entryForm
@@ -79,38 +67,22 @@ func (h *Handler) SignIn(c *gin.Context) {
 		return
 	}
 
-	user := models.User{
-		Email:    form.Email,
-		Password: form.Password,
-	}
-
-	if user.Email == "" || user.Password == "" {
-		h.errorpage(c, http.StatusBadRequest, errors.New("email or password is empty"), "sign up failed")
-		return
-	}
-
-	err := validation.GetErrMsg(user)
-	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "sign up failed")
-		return
-	}
-
 	if form.Password != form.Confirm_password {
 		h.errorpage(c, http.StatusBadRequest, errors.New("passwords don't match"), "sign in failed")
 		return
 	}
 
-	token, refresh_token, err := h.Service.Login(models.User{
+	token, refresh_token, err := h.Service.Login(c.Request.Context(), models.User{
 		Email:    form.Email,
 		Password: form.Password,
 	})
 
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "user login failed")
+		h.errorpage(c, http.StatusInternalServerError, err, "user login failed")
 		return
 	}
 
-	c.SetCookie("token", token, 3600, "/", "", false, true)
+	h.setTokenCookie(c, "token", token)
 
 	c.JSON(http.StatusOK, []string{token, refresh_token})
 }
@@ -121,7 +93,7 @@ func (h *Handler) SignIn(c *gin.Context) {
 // @Security CookieAuth
 // @Produce json
 // @Success 200 "Logout successful"
-// @Failure 400 {object} error "Error logging out user"
+// @Failure 400 {object} Problem "Error logging out user"
 // @Router /signout [get]
 func (h *Handler) Signout(c *gin.Context) {
 	data := c.MustGet("data").(*Data)
@@ -129,14 +101,14 @@ func (h *Handler) Signout(c *gin.Context) {
 		return
 	}
 
-	err := h.Service.DeleteTokensByEmail(data.User.Email)
+	err := h.Service.DeleteTokensByEmail(c.Request.Context(), data.User.Email)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "user logout failed")
+		h.errorpage(c, http.StatusInternalServerError, err, "user logout failed")
 		return
 	}
 
-	c.SetCookie("token", "", -1, "/", "", false, true)
-	c.SetCookie("refresh_token", "", -1, "/", "", false, true)
+	h.setTokenCookie(c, "token", "")
+	h.setTokenCookie(c, "refresh_token", "")
 
 	c.JSON(http.StatusOK, gin.H{"message": "Logout successful"})
 }
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"net/http"
+	"ozinshe/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type collectionForm struct {
+	Title    string `form:"title" validate:"required,max=255"`
+	Slug     string `form:"slug" validate:"required,max=100"`
+	Position int    `form:"position" validate:"gte=0"`
+}
+
+// @Summary Get a list of all collections
+// @Description Retrieves curated collections in the order they appear on the home screen.
+// @Tags collections
+// @Produce json
+// @Success 200 {array} models.Collection "List of collections"
+// @Failure 400 {object} Problem "Error getting collections"
+// @Router /collections [get]
+func (h *Handler) GetAllCollections(c *gin.Context) {
+	collections, err := h.Service.Collection.GetAll(c.Request.Context())
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "collections getting failed")
+		return
+	}
+	c.JSON(http.StatusOK, collections)
+}
+
+// @Summary Get a page of a collection
+// @Description Retrieves a curated collection with one page of its ordered projects.
+// @Tags collections
+// @Produce json
+// @Param slug path string true "Collection slug"
+// @Param offset query int false "Items to skip"
+// @Param limit query int false "Items per page (default 10, max 50)"
+// @Success 200 {object} models.HomeRow "Collection page"
+// @Failure 400 {object} Problem "Error getting collection"
+// @Failure 404 {object} Problem "Collection not found"
+// @Router /collections/{slug} [get]
+func (h *Handler) GetCollection(c *gin.Context) {
+	offset, limit := parsePage(c)
+
+	row, err := h.Service.Home.CollectionRow(c.Request.Context(), c.Param("slug"), offset, limit)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "collection getting failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, row)
+}
+
+// @Summary Create a new collection
+// @Description Creates a curated collection with an optional artwork banner. Requires admin authorization.
+// @Tags collections
+// @Accept multipart/form-data
+// @Produce json
+// @Security CookieAuth
+// @Param title formData string true "Collection title"
+// @Param slug formData string true "Collection slug"
+// @Param position formData int false "Position on the home screen"
+// @Param banner formData file false "Collection banner"
+// @Success 200 "Collection created"
+// @Failure 400 {object} Problem "Error creating collection"
+// @Router /collections [post]
+func (h *Handler) CreateCollection(c *gin.Context) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "binding multipart form in create collection")
+		return
+	}
+
+	var fields collectionForm
+	if err := c.ShouldBind(&fields); err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "collection creation failed")
+		return
+	}
+
+	collection := models.Collection{
+		Title:    fields.Title,
+		Slug:     fields.Slug,
+		Position: fields.Position,
+	}
+
+	images_data, err := h.ProcessSavePhoto(form, "collections")
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "processing collection banner")
+		return
+	}
+
+	id, err := h.Service.Collection.Add(c.Request.Context(), collection, images_data)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "collection creation failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Collection created", "id": id})
+}
+
+// @Summary Replace projects of a collection
+// @Description Replaces the ordered list of projects in a collection. Requires admin authorization.
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Security CookieAuth
+// @Param slug path string true "Collection slug"
+// @Param projects body []int true "Ordered project IDs"
+// @Success 200 "Collection updated"
+// @Failure 400 {object} Problem "Error updating collection"
+// @Router /collections/{slug}/projects [put]
+func (h *Handler) SetCollectionProjects(c *gin.Context) {
+	var projectIDs []int
+	if err := c.ShouldBindJSON(&projectIDs); err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "binding json in set collection projects")
+		return
+	}
+
+	err := h.Service.Collection.SetProjects(c.Request.Context(), c.Param("slug"), projectIDs)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "collection updating failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Collection updated"})
+}
+
+// @Summary Delete a collection
+// @Description Deletes a curated collection based on the provided slug. Requires admin authorization.
+// @Tags collections
+// @Produce json
+// @Security CookieAuth
+// @Param slug path string true "Collection slug"
+// @Success 200 "Collection deleted"
+// @Failure 400 {object} Problem "Error deleting collection"
+// @Router /collections/{slug} [delete]
+func (h *Handler) DeleteCollection(c *gin.Context) {
+	err := h.Service.Collection.Remove(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "collection deleting failed")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Collection deleted"})
+}
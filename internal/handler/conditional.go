@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// catalogJSON writes body, a catalog resource last modified at modified, as
+// JSON along with its validators and caching policy, or 304 Not Modified
+// when the copy the client asks to validate is still current.
+//
+// The ETag is a hash of the body, so it changes exactly when the body does.
+// Last-Modified only has whole seconds, so clients should prefer the ETag.
+// modified must be read before body is loaded, so that body is never older
+// than the Last-Modified sent with it, which would have clients keep it as
+// current.
+func (h *Handler) catalogJSON(c *gin.Context, modified time.Time, body any) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "encoding response")
+		return
+	}
+
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	modified = modified.UTC().Truncate(time.Second)
+
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", modified.Format(http.TimeFormat))
+	c.Header("Cache-Control", h.catalogCacheControl(c))
+
+	if notModified(c.Request, etag, modified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
+
+// catalogCacheControl lets clients and shared caches such as CDNs reuse the
+// public catalog for HTTP.CatalogMaxAge. Admin previews of unpublished
+// projects are not stored.
+func (h *Handler) catalogCacheControl(c *gin.Context) string {
+	if previewing(c) {
+		return "private, no-store"
+	}
+	return "public, max-age=" + strconv.Itoa(int(h.HTTP.CatalogMaxAge.Seconds()))
+}
+
+// notModified evaluates the preconditions of a GET as RFC 9110 orders them:
+// If-Modified-Since is only looked at without an If-None-Match.
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		return etagMatches(match, etag)
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	return !modified.After(since)
+}
+
+// etagMatches reports whether the If-None-Match header value matches etag.
+// The comparison is weak, as it must be for If-None-Match, so that the
+// W/ proxies add when they compress a response does not defeat it.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
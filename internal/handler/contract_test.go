@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"database/sql/driver"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"ozinshe/docs"
+	"ozinshe/internal/apispec"
+	"ozinshe/internal/config"
+	"ozinshe/internal/service"
+	psql "ozinshe/internal/storage/postgresql"
+	"ozinshe/internal/storage/storagetest"
+	"ozinshe/util"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// signIn returns the token cookie of a user of the given type.
+func signIn(t *testing.T, userType string) *http.Cookie {
+	t.Helper()
+
+	util.SetupTokens(config.AuthConfig{Secret: "test", TokenTTL: time.Hour, RefreshTTL: time.Hour})
+	token, _, err := util.GenerateAllTokens("user@example.com", "User", userType, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &http.Cookie{Name: "token", Value: token}
+}
+
+// The responses of the handlers must match the API spec, errors included.
+// Each request is sent through the whole router to the fake database and
+// its response checked against the operation of its route.
+func TestResponsesMatchAPISpec(t *testing.T) {
+	spec, err := apispec.Load(docs.SwaggerInfo.ReadDoc())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Errors an operation does not document are problem details all the same.
+	problemOp := &apispec.Operation{Responses: map[string]apispec.Response{
+		"default": {Schema: &apispec.Schema{Ref: "#/definitions/handler.Problem"}},
+	}}
+
+	tests := []struct {
+		name   string
+		method string
+		route  string
+		target string
+		body   string
+		user   string
+		db     *storagetest.DB
+		status int
+	}{
+		{
+			name:   "genres",
+			method: http.MethodGet,
+			route:  "/api/v1/genres",
+			target: "/api/v1/genres",
+			db:     storagetest.New().On("FROM genres", []string{"id", "name"}, []driver.Value{int64(1), "Drama"}, []driver.Value{int64(2), "Comedy"}),
+			status: http.StatusOK,
+		},
+		{
+			name:   "genre not found",
+			method: http.MethodGet,
+			route:  "/api/v1/genres/:id",
+			target: "/api/v1/genres/7",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "age categories",
+			method: http.MethodGet,
+			route:  "/api/v1/ages",
+			target: "/api/v1/ages",
+			db:     storagetest.New().On("FROM age_categories", []string{"id", "min_age", "max_age"}, []driver.Value{int64(1), int64(12), int64(16)}),
+			status: http.StatusOK,
+		},
+		{
+			name:   "movie",
+			method: http.MethodGet,
+			route:  "/api/v1/movies/:id",
+			target: "/api/v1/movies/1",
+			db: storagetest.New().
+				On("SELECT g.id, g.name", []string{"id", "name"}, []driver.Value{int64(3), "Drama"}).
+				On("FROM movie_covers", []string{"filename"}, []driver.Value{"cover.jpg"}).
+				On("FROM movie_screenshots", []string{"filename"}, []driver.Value{"1.jpg"}).
+				On("FROM published_projects", []string{"exists"}, []driver.Value{true}).
+				On("SELECT updated_at FROM projects", []string{"updated_at"}, []driver.Value{time.Now()}).
+				On("FROM movies m", movieColumns, []driver.Value{int64(1), "Title", int64(2020), "About", int64(5), "yt", int64(90), "Director", "Producer"}),
+			status: http.StatusOK,
+		},
+		{
+			name:   "movie fields",
+			method: http.MethodGet,
+			route:  "/api/v1/movies/:id",
+			target: "/api/v1/movies/1?fields=title,release_year&expand=genres",
+			db:     movieDB(),
+			status: http.StatusOK,
+		},
+		{
+			name:   "unpublished movie",
+			method: http.MethodGet,
+			route:  "/api/v1/movies/:id",
+			target: "/api/v1/movies/1",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "invalid search",
+			method: http.MethodGet,
+			route:  "/api/v1/projects/search",
+			target: "/api/v1/projects/search?project_type=cartoon",
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "invalid sign in",
+			method: http.MethodPost,
+			route:  "/api/v1/signin",
+			target: "/api/v1/signin",
+			body:   `{"email": "not an email"}`,
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "lists signed out",
+			method: http.MethodGet,
+			route:  "/api/v1/me/lists",
+			target: "/api/v1/me/lists",
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "jobs as a user",
+			method: http.MethodGet,
+			route:  "/api/v1/admin/jobs/kinds",
+			target: "/api/v1/admin/jobs/kinds",
+			user:   "user",
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "job kinds",
+			method: http.MethodGet,
+			route:  "/api/v1/admin/jobs/kinds",
+			target: "/api/v1/admin/jobs/kinds",
+			user:   "admin",
+			status: http.StatusOK,
+		},
+		{
+			name:   "webhooks",
+			method: http.MethodGet,
+			route:  "/api/v1/admin/webhooks",
+			target: "/api/v1/admin/webhooks",
+			user:   "admin",
+			status: http.StatusOK,
+		},
+		{
+			name:   "people",
+			method: http.MethodGet,
+			route:  "/api/v1/people",
+			target: "/api/v1/people",
+			status: http.StatusOK,
+		},
+		{
+			name:   "collections",
+			method: http.MethodGet,
+			route:  "/api/v1/collections",
+			target: "/api/v1/collections",
+			status: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := spec.Operation(tt.method, tt.route)
+			if op == nil {
+				t.Fatalf("%s %s is not documented", tt.method, tt.route)
+			}
+
+			db := tt.db
+			if db == nil {
+				db = storagetest.New()
+			}
+			// Whatever a case does not serve is unpublished and unchanged.
+			db.On("FROM catalog_changes", []string{"greatest"}, []driver.Value{time.Now()}).
+				On("FROM published_projects", []string{"exists"}, []driver.Value{false})
+			h := New(service.New(psql.New(psql.Open(db)), nil), &config.Config{Env: config.EnvProd}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			if tt.user != "" {
+				req.AddCookie(signIn(t, tt.user))
+			}
+
+			rec := httptest.NewRecorder()
+			h.InitRoutes().ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d; body %s", rec.Code, tt.status, rec.Body)
+			}
+
+			if !apispec.IsJSON(rec.Header().Get("Content-Type")) {
+				t.Fatalf("Content-Type = %q, want JSON", rec.Header().Get("Content-Type"))
+			}
+
+			problems := spec.ValidateResponse(op, rec.Code, rec.Header(), rec.Body.Bytes())
+			if _, ok := op.Responses[strconv.Itoa(rec.Code)]; !ok && rec.Code >= 400 {
+				problems = append(problems, spec.ValidateResponse(problemOp, rec.Code, rec.Header(), rec.Body.Bytes())...)
+			}
+			for _, problem := range problems {
+				t.Errorf("%s; body %s", problem, rec.Body)
+			}
+		})
+	}
+}
@@ -9,41 +9,81 @@ import (
 )
 
 // @Summary Get a list of all genres
-// @Description Retrieves a list of all genres.
+// @Description Retrieves a list of all genres. fields limits the fields sent.
 // @Tags genres
 // @Produce json
+// @Param fields query string false "Comma separated fields to send, e.g. name"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Param If-Modified-Since header string false "Last-Modified of a cached copy"
 // @Success 200 {array} models.Genre "List of genres"
-// @Failure 400 {object} error "Error getting genres"
+// @Header 200 {string} ETag "Hash of the response"
+// @Header 200 {string} Last-Modified "When the resource was last modified"
+// @Success 304 "The cached copy is current"
+// @Failure 400 {object} Problem "Error getting genres"
 // @Router /genres [get]
 func (h *Handler) GetAllGenres(c *gin.Context) {
-	genres, err := h.Service.Genre.GetAll()
+	p, err := parseProjection(c, jsonFields(models.Genre{}), nil)
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid projection")
+		return
+	}
 
+	modified, err := h.Service.Catalog.ListModifiedAt(c.Request.Context(), models.ResourceGenres)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "genres getting failed")
+		h.errorpage(c, http.StatusInternalServerError, err, "genres modification time getting failed")
 		return
 	}
 
-	c.JSON(http.StatusOK, genres)
+	genres, err := h.Service.Genre.GetAll(c.Request.Context())
+
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "genres getting failed")
+		return
+	}
+
+	h.projectedJSON(c, modified, p, genres)
 }
 
 // @Summary Get details of a specific genre
-// @Description Retrieves details of a genre based on the provided ID.
+// @Description Retrieves details of a genre based on the provided ID. fields limits the fields sent.
 // @Tags genres
 // @Produce json
 // @Param id path int true "Genre ID"
+// @Param fields query string false "Comma separated fields to send, e.g. name"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Param If-Modified-Since header string false "Last-Modified of a cached copy"
 // @Success 200 {object} models.Genre "Genre details"
-// @Failure 400 {object} error "Error getting genre"
+// @Header 200 {string} ETag "Hash of the response"
+// @Header 200 {string} Last-Modified "When the resource was last modified"
+// @Success 304 "The cached copy is current"
+// @Failure 400 {object} Problem "Error getting genre"
 // @Router /genres/{id} [get]
 func (h *Handler) GetGenre(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
-	genre, err := h.Service.Genre.GetById(id)
+	p, err := parseProjection(c, jsonFields(models.Genre{}), nil)
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid projection")
+		return
+	}
 
+	modified, err := h.Service.Catalog.ModifiedAt(c.Request.Context(), models.ResourceGenres, id)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "genre getting failed")
+		h.errorpage(c, http.StatusInternalServerError, err, "genre modification time getting failed")
 		return
 	}
 
-	c.JSON(http.StatusOK, genre)
+	genre, err := h.Service.Genre.GetById(c.Request.Context(), id)
+
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "genre getting failed")
+		return
+	}
+
+	h.projectedJSON(c, modified, p, genre)
+}
+
+type genreForm struct {
+	Name string `form:"genre" validate:"required,max=50"`
 }
 
 // @Summary Create a new genre
@@ -54,23 +94,23 @@ func (h *Handler) GetGenre(c *gin.Context) {
 // @Security CookieAuth
 // @Param genre formData string true "Genre name"
 // @Success 200 "Genre created"
-// @Failure 400 {object} error "Error creating genre"
+// @Failure 400 {object} Problem "Error creating genre"
 // @Router /genres [post]
 func (h *Handler) CreateGenre(c *gin.Context) {
 
-	form, err := c.MultipartForm()
-	if err != nil {
+	var form genreForm
+	if err := c.ShouldBind(&form); err != nil {
 		h.errorpage(c, http.StatusBadRequest, err, "binding multipart form in create genre")
 		return
 	}
 
-	genres := form.Value["genre"]
-
-	err = h.Service.Genre.Add(append([]models.Genre{}, models.Genre{Name: genres[0]}))
+	err := h.Service.Genre.Add(c.Request.Context(), append([]models.Genre{}, models.Genre{Name: form.Name}))
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "genre creation failed")
+		h.errorpage(c, http.StatusInternalServerError, err, "genre creation failed")
 		return
 	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Genre created"})
 }
 
 // @Summary Delete an existing genre
@@ -80,13 +120,13 @@ func (h *Handler) CreateGenre(c *gin.Context) {
 // @Security CookieAuth
 // @Param id path int true "Genre ID"
 // @Success 200 "Genre deleted successfully"
-// @Failure 400 {object} error "Error deleting genre"
+// @Failure 400 {object} Problem "Error deleting genre"
 // @Router /genres/{id} [delete]
 func (h *Handler) DeleteGenre(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
-	err := h.Service.Genre.Remove(id)
+	err := h.Service.Genre.Remove(c.Request.Context(), id)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "genre deleting failed")
+		h.errorpage(c, http.StatusInternalServerError, err, "genre deleting failed")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Genre deleted"})
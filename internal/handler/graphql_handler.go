@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"ozinshe/internal/graphql"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+var errNotJSON = errors.New("graphql requests must be application/json")
+
+// GraphQL runs a GraphQL query or mutation against the catalog, as the
+// signed in user if there is one. Only JSON requests are accepted, which
+// browsers do not send cross-origin without a preflight, so that other
+// sites can not run mutations with the cookie of the user.
+func (h *Handler) GraphQL(c *gin.Context) {
+	if c.ContentType() != binding.MIMEJSON {
+		h.errorpage(c, http.StatusUnsupportedMediaType, errNotJSON, "invalid graphql request")
+		return
+	}
+
+	var req graphql.Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid graphql request")
+		return
+	}
+
+	data := c.MustGet("data").(*Data)
+
+	var viewer *graphql.Viewer
+	if data.IsAuthorized {
+		viewer = &graphql.Viewer{User: data.User, IsAdmin: data.IsAdmin}
+	}
+
+	c.JSON(http.StatusOK, h.graphQL.Exec(c.Request.Context(), viewer, req))
+}
@@ -3,40 +3,135 @@ package handler
 import (
 	"html/template"
 	"log/slog"
+	"ozinshe/docs"
+	"ozinshe/internal/apispec"
+	"ozinshe/internal/config"
+	"ozinshe/internal/config/lib/logger/sl"
+	"ozinshe/internal/graphql"
 	"ozinshe/internal/service"
+	"ozinshe/internal/validation"
+	"sync"
+	"time"
 
-	_ "ozinshe/docs"
 	_ "ozinshe/internal/models"
 
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 type Handler struct {
 	Service   *service.Service
+	HTTP      config.HTTPConfig
+	Metrics   config.MetricsConfig
+	Cookie    config.CookieConfig
+	CORS      config.CORSConfig
+	Uploads   config.UploadsConfig
+	TokenTTL  time.Duration
 	Log       *slog.Logger
 	TempCache *template.Template
+
+	// Spec is the API spec requests and responses are checked against, or
+	// nil in production, where they are not.
+	Spec *apispec.Spec
+
+	graphQL   *graphql.Server
+	draining  chan struct{}
+	drainOnce sync.Once
+}
+
+// structValidator has gin check the requests it binds with the validation
+// package, so that binding fails with the fields that are not valid.
+type structValidator struct{}
+
+func (structValidator) ValidateStruct(obj any) error {
+	return validation.Struct(obj)
+}
+
+func (structValidator) Engine() any {
+	return validation.Validate
 }
 
-func New(service *service.Service, log *slog.Logger) *Handler {
-	return &Handler{
-		Service: service,
-		Log:     log,
+func New(service *service.Service, cfg *config.Config, log *slog.Logger) *Handler {
+	binding.Validator = structValidator{}
+
+	h := &Handler{
+		Service:  service,
+		HTTP:     cfg.HTTP,
+		Metrics:  cfg.Metrics,
+		Cookie:   cfg.Cookie,
+		CORS:     cfg.CORS,
+		Uploads:  cfg.Uploads,
+		TokenTTL: cfg.Auth.TokenTTL,
+		Log:      log,
+
+		draining: make(chan struct{}),
+	}
+
+	// The schema is embedded, so it only fails to parse when the resolvers
+	// do not implement it, which the tests of graphql report before it gets here.
+	graphQL, err := graphql.New(service, log)
+	if err != nil {
+		panic(err)
+	}
+	h.graphQL = graphQL
+
+	if cfg.Env != config.EnvProd {
+		spec, err := apispec.Load(docs.SwaggerInfo.ReadDoc())
+		if err != nil {
+			log.Warn("requests are not checked against the api spec", sl.Err(err))
+		}
+		h.Spec = spec
 	}
+
+	return h
+}
+
+// Drain ends the notification streams, whose clients would otherwise hold
+// the server open until the shutdown timeout. They reconnect elsewhere.
+func (h *Handler) Drain() {
+	h.drainOnce.Do(func() { close(h.draining) })
 }
 
+// APIPrefix is the path the routes of the current version of the API are
+// served under.
+const APIPrefix = "/api/v1"
+
 func (h *Handler) InitRoutes() *gin.Engine {
 	router := gin.New()
+	router.Use(h.RequestID, h.Trace, h.AccessLog, h.Instrument, h.Recover, h.CrossOrigin, h.Deadline)
+	if h.Spec != nil {
+		router.Use(h.CheckContract)
+	}
+
+	if h.Metrics.Enabled {
+		router.GET(h.Metrics.Path, h.metricsHandler())
+	}
+
+	router.GET("/healthz", h.Healthz)
+	router.GET("/readyz", h.Readyz)
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	router.GET("/", h.HomePage)
-	router.POST("/signup", h.SignUp)
-	router.POST("/signin", h.SignIn)
+	router.GET("/", h.Middleware, h.HomePage)
+	router.POST("/graphql", h.Middleware, h.GraphQL)
+
+	h.apiRoutes(router.Group(APIPrefix))
 
-	authGroup := router.Group("/", h.Middleware)
+	// The routes from before the API was versioned stay as aliases of their
+	// /api/v1 successors until clients have moved over.
+	h.apiRoutes(router.Group("/", h.Deprecated))
+
+	return router
+}
+
+func (h *Handler) apiRoutes(api *gin.RouterGroup) {
+	api.POST("/signup", h.SignUp)
+	api.POST("/signin", h.SignIn)
+
+	authGroup := api.Group("/", h.Middleware)
 	{
 		authGroup.GET("/signout", h.MustBeAuthorizedMiddleware, h.Signout)
 
@@ -48,7 +143,7 @@ func (h *Handler) InitRoutes() *gin.Engine {
 
 		projectGroup := authGroup.Group("/projects")
 		{
-			projectGroup.GET("/", h.GetAllProjects)
+			projectGroup.GET("", h.GetAllProjects)
 			projectGroup.GET("/:id", h.GetProject)
 			projectGroup.GET("/search", h.GetFilteredProjects)
 			projectGroup.GET("/favorites", h.MustBeAuthorizedMiddleware, h.GetAllFavorites)
@@ -57,47 +152,112 @@ func (h *Handler) InitRoutes() *gin.Engine {
 			projectGroup.POST("/create-project", h.IsAdminMiddlware, h.CreateProject)
 			projectGroup.DELETE("/:id", h.IsAdminMiddlware, h.DeleteProject)
 			projectGroup.PUT("/:id", h.IsAdminMiddlware, h.UpdateProject)
+			projectGroup.PUT("/:id/status", h.IsAdminMiddlware, h.SetProjectStatus)
+			projectGroup.GET("/:id/credits", h.GetProjectCredits)
+			projectGroup.PUT("/:id/credits", h.IsAdminMiddlware, h.SetProjectCredits)
+			projectGroup.PUT("/:id/progress", h.MustBeAuthorizedMiddleware, h.SaveProgress)
+			projectGroup.GET("/:id/similar", h.GetSimilarProjects)
+		}
+
+		meGroup := authGroup.Group("/me", h.MustBeAuthorizedMiddleware)
+		{
+			meGroup.GET("/recommendations", h.GetRecommendations)
+			meGroup.GET("/lists", h.GetAllLists)
+			meGroup.POST("/lists", h.CreateList)
+			meGroup.GET("/lists/:id", h.GetList)
+			meGroup.PUT("/lists/:id", h.UpdateList)
+			meGroup.DELETE("/lists/:id", h.DeleteList)
+			meGroup.POST("/lists/:id/items", h.AddListItem)
+			meGroup.PUT("/lists/:id/items/:projectID", h.UpdateListItem)
+			meGroup.DELETE("/lists/:id/items/:projectID", h.RemoveListItem)
+			meGroup.GET("/notifications", h.GetNotifications)
+			meGroup.GET("/notifications/stream", h.StreamNotifications)
+			meGroup.POST("/notifications/read", h.MarkAllNotificationsRead)
+			meGroup.POST("/notifications/:id/read", h.MarkNotificationRead)
+			meGroup.GET("/notification-preferences", h.GetNotificationPreferences)
+			meGroup.PUT("/notification-preferences", h.SetNotificationPreferences)
+		}
+
+		authGroup.GET("/lists/shared/:token", h.GetSharedList)
+		authGroup.GET("/sync", h.GetSync)
+
+		adminGroup := authGroup.Group("/admin", h.IsAdminMiddlware)
+		{
+			adminGroup.GET("/projects", h.GetPublications)
+
+			adminGroup.GET("/webhooks", h.GetAllWebhooks)
+			adminGroup.POST("/webhooks", h.CreateWebhook)
+			adminGroup.DELETE("/webhooks/:id", h.DeleteWebhook)
+			adminGroup.POST("/webhooks/:id/ping", h.PingWebhook)
+			adminGroup.GET("/webhooks/:id/deliveries", h.GetWebhookDeliveries)
+			adminGroup.POST("/webhook-deliveries/:id/redeliver", h.RedeliverWebhook)
+
+			adminGroup.GET("/jobs", h.GetAllJobs)
+			adminGroup.POST("/jobs", h.CreateJob)
+			adminGroup.GET("/jobs/kinds", h.GetJobKinds)
+			adminGroup.GET("/jobs/:id", h.GetJob)
+			adminGroup.POST("/jobs/:id/retry", h.RetryJob)
+			adminGroup.GET("/job-schedules", h.GetJobSchedules)
+
+			adminGroup.GET("/audit", h.GetAuditLog)
+			adminGroup.GET("/audit/export", h.ExportAuditLog)
+		}
+
+		homeGroup := authGroup.Group("/home")
+		{
+			homeGroup.GET("", h.GetHome)
+			homeGroup.GET("/rows/:row", h.GetHomeRow)
+		}
+
+		collectionGroup := authGroup.Group("/collections")
+		{
+			collectionGroup.GET("", h.GetAllCollections)
+			collectionGroup.GET("/:slug", h.GetCollection)
+			collectionGroup.POST("", h.IsAdminMiddlware, h.CreateCollection)
+			collectionGroup.PUT("/:slug/projects", h.IsAdminMiddlware, h.SetCollectionProjects)
+			collectionGroup.DELETE("/:slug", h.IsAdminMiddlware, h.DeleteCollection)
+		}
+
+		peopleGroup := authGroup.Group("/people")
+		{
+			peopleGroup.GET("", h.GetAllPeople)
+			peopleGroup.GET("/:id", h.GetPerson)
+			peopleGroup.POST("", h.IsAdminMiddlware, h.CreatePerson)
+			peopleGroup.DELETE("/:id", h.IsAdminMiddlware, h.DeletePerson)
 		}
 
 		movieGroup := authGroup.Group("/movies")
 		{
-			movieGroup.GET("/", h.GetAllMovies)
+			movieGroup.GET("", h.GetAllMovies)
 			movieGroup.GET("/:id", h.GetMovie)
 		}
 
 		seriesGroup := authGroup.Group("/series")
 		{
-			seriesGroup.GET("/", h.GetAllSeries)
+			seriesGroup.GET("", h.GetAllSeries)
 			seriesGroup.GET("/:seriesID", h.GetSeries)
 
-			seasonGroup := seriesGroup.Group("/seasons")
+			seasonGroup := seriesGroup.Group("/:seriesID/seasons/:seasonNumber")
 			{
-				seasonGroup.GET("/:seasonNumber", h.GetSeason)
-
-				episodeGroup := seriesGroup.Group("/episodes")
-				{
-					episodeGroup.GET("/:episodeID", h.GetEpisode)
-				}
+				seasonGroup.GET("/episodes", h.GetSeason)
+				seasonGroup.GET("/episodes/:episodeID", h.GetEpisode)
 			}
-
 		}
 
 		genreGroup := authGroup.Group("/genres")
 		{
-			genreGroup.GET("/", h.GetAllGenres)
+			genreGroup.GET("", h.GetAllGenres)
 			genreGroup.GET("/:id", h.GetGenre)
-			genreGroup.POST("/", h.IsAdminMiddlware, h.CreateGenre)
+			genreGroup.POST("", h.IsAdminMiddlware, h.CreateGenre)
 			genreGroup.DELETE("/:id", h.IsAdminMiddlware, h.DeleteGenre)
 		}
 
 		ageGroup := authGroup.Group("/ages")
 		{
-			ageGroup.GET("/", h.GetAllAgeCategories)
+			ageGroup.GET("", h.GetAllAgeCategories)
 			ageGroup.GET("/:id", h.GetAgeCategory)
-			ageGroup.POST("/", h.IsAdminMiddlware, h.CreateAgeCategory)
+			ageGroup.POST("", h.IsAdminMiddlware, h.CreateAgeCategory)
 			ageGroup.DELETE("/:id", h.IsAdminMiddlware, h.DeleteAgeCategory)
 		}
 	}
-
-	return router
 }
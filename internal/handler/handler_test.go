@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"ozinshe/internal/config"
+	"ozinshe/internal/service"
+	psql "ozinshe/internal/storage/postgresql"
+	"ozinshe/internal/storage/storagetest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+// newTestHandler returns a handler serving the whole stack of services and
+// storage on top of db, without a cache, with the given HTTP settings.
+func newTestHandler(t *testing.T, db *storagetest.DB, http config.HTTPConfig) *Handler {
+	t.Helper()
+
+	cfg := &config.Config{Env: config.EnvProd, HTTP: http}
+	return New(service.New(psql.New(psql.Open(db)), nil), cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
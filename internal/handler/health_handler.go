@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"net/http"
+	"ozinshe/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Healthz is the liveness probe. It responds as long as the process is up
+// and does not check any dependency.
+func (h *Handler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz is the readiness probe. It checks the database connection, pending
+// migrations, the uploads directory and the background workers, and
+// responds with 503 when a check fails or the server is shutting down.
+func (h *Handler) Readyz(c *gin.Context) {
+	readiness := h.Service.Health.Ready(c.Request.Context())
+
+	status := http.StatusOK
+	if readiness.Status != models.Ready {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, readiness)
+}
@@ -2,12 +2,19 @@ package handler
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
-	"os"
+	"ozinshe/internal/apperr"
+	"ozinshe/internal/config/lib/logger/sl"
+	"ozinshe/internal/helper"
 	"ozinshe/internal/models"
-	"regexp"
+	"ozinshe/internal/requestid"
+	"ozinshe/internal/storage"
+	"ozinshe/internal/validation"
 	"strconv"
 	"strings"
 
@@ -22,9 +29,18 @@ type Data struct {
 	ErrMsgs      map[string]string
 }
 
-type ErrorData struct {
-	Status  int
-	Message string
+// Problem is an RFC 7807 problem details response. Code identifies the kind
+// of problem and is stable, unlike Detail; Errors names what is wrong with
+// each field of the request when it was not valid.
+type Problem struct {
+	Type      string            `json:"type"`
+	Title     string            `json:"title"`
+	Status    int               `json:"status"`
+	Detail    string            `json:"detail,omitempty"`
+	Instance  string            `json:"instance,omitempty"`
+	Code      string            `json:"code"`
+	RequestID string            `json:"request_id,omitempty"`
+	Errors    map[string]string `json:"errors,omitempty"`
 }
 
 func (h *Handler) render(c *gin.Context, status int, page string, data any) {
@@ -46,62 +62,152 @@ func (h *Handler) render(c *gin.Context, status int, page string, data any) {
 	c.Status(status)
 }
 
+// errorpage responds with the problem of err and aborts the request. Errors
+// of the apperr package carry their own status, code and message; status is
+// used for any other error, with errortype as its detail. Other errors are
+// logged with the request ID, never shown.
 func (h *Handler) errorpage(c *gin.Context, status int, err error, errortype string) {
-	if err != nil {
-		h.Log.Error(fmt.Sprintf("%s: %v", errortype, err))
+	problem := Problem{
+		Type:      "about:blank",
+		Status:    status,
+		Instance:  c.Request.URL.Path,
+		Code:      statusCode(status),
+		RequestID: requestid.From(c.Request.Context()),
+	}
+
+	var e *apperr.Error
+	switch {
+	case errors.As(err, &e):
+	case status >= http.StatusInternalServerError:
+		e = apperr.From(err)
 	}
 
-	errdata := ErrorData{
-		Status:  status,
-		Message: getLastMessageFromError(err),
+	if e != nil {
+		problem.Status = e.Kind.Status()
+		problem.Code = e.Code
+		problem.Detail = e.Message
+		problem.Errors = e.Fields
+	} else {
+		problem.Detail = errortype
 	}
 
-	if status == http.StatusInternalServerError {
-		errdata.Message = "Something went wrong. Please try again later."
+	if problem.Status >= http.StatusInternalServerError {
+		if err != nil {
+			h.logger(c).Error(errortype, sl.Err(err))
+		}
+		if e == nil || e.Kind == apperr.KindInternal {
+			problem.Detail = "Something went wrong. Please try again later."
+		}
+	} else if err != nil {
+		h.logger(c).Info(errortype, sl.Err(err))
 	}
 
-	c.JSON(status, errdata)
-	c.Abort()
+	problem.Title = http.StatusText(problem.Status)
+
+	c.Header("Content-Type", "application/problem+json")
+	c.AbortWithStatusJSON(problem.Status, problem)
 }
 
-func getLastMessageFromError(err error) string {
-	if err == nil {
-		return ""
+// statusCode returns the code of problems that only have a status.
+func statusCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "bad_request"
+	case http.StatusUnauthorized:
+		return "unauthorized"
+	case http.StatusForbidden:
+		return "forbidden"
+	case http.StatusNotFound:
+		return "not_found"
+	case http.StatusConflict:
+		return "conflict"
+	case http.StatusServiceUnavailable:
+		return "unavailable"
+	default:
+		if status >= http.StatusInternalServerError {
+			return "internal"
+		}
+		return "error"
 	}
-	// Convert the error to a string
-	errStr := err.Error()
+}
 
-	// Define a regular expression pattern to match the last message after the last colon
-	re := regexp.MustCompile(`[^:]+:\s*([^:]+)$`)
+// logger returns the logger of the request, which tags every line with the
+// request ID.
+func (h *Handler) logger(c *gin.Context) *slog.Logger {
+	return sl.FromContext(c.Request.Context(), h.Log)
+}
+
+const (
+	defaultPageLimit = 10
+	maxPageLimit     = 50
+)
 
-	// Find the last match of the pattern in the error string
-	matches := re.FindStringSubmatch(errStr)
+// parsePage reads offset and limit query parameters, falling back to the
+// first page of defaultPageLimit items.
+func parsePage(c *gin.Context) (int, int) {
+	offset, err := strconv.Atoi(c.Query("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
 
-	// If there is a match, return the captured group (message)
-	if len(matches) > 1 {
-		return matches[1]
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultPageLimit
 	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+
+	return offset, limit
+}
 
-	// If no match found, return the entire error string
-	return errStr
+// previewing reports whether an admin asked to see unpublished projects
+// with ?preview=true.
+func previewing(c *gin.Context) bool {
+	data := c.MustGet("data").(*Data)
+	return data.IsAdmin && c.Query("preview") == "true"
 }
 
-func ProcessSavePhoto(form *multipart.Form, dst string) (models.SavePhoto, error) {
-	currentDir, err := os.Getwd()
+// published reports whether a movie or series may be shown, writing a 404
+// when it is not published and the request isn't an admin preview.
+func (h *Handler) published(c *gin.Context, projectType string, contentID int) bool {
+	if previewing(c) {
+		return true
+	}
+
+	ok, err := h.Service.Project.IsPublished(c.Request.Context(), projectType, contentID)
 	if err != nil {
-		return models.SavePhoto{}, err
+		h.errorpage(c, http.StatusInternalServerError, err, "publication check failed")
+		return false
 	}
-	path := currentDir + "/internal/uploads/" + dst + "/"
+	if !ok {
+		h.errorpage(c, http.StatusNotFound, storage.ErrProjectNotFound, "project not published")
+		return false
+	}
+
+	return true
+}
+
+func (h *Handler) ProcessSavePhoto(form *multipart.Form, dst string) (models.SavePhoto, error) {
+	path := helper.UploadPath(dst) + "/"
 
 	images_data := models.SavePhoto{
 		File_form:    form,
 		UploadPath:   path,
-		MaxImageSize: maxImageSize,
+		MaxImageSize: h.Uploads.MaxImageSize,
 	}
 
 	return images_data, nil
 }
 
+// parseJSON decodes the JSON sent in a form field into v and validates it.
+func parseJSON(data string, v any) error {
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		return err
+	}
+	return validation.Struct(v)
+}
+
 func ProcessParsing(genres []string, ageCategory string, keywords []string) ([]models.Genre, []models.AgeCategory, []models.Keyword) {
 	var Keywords []models.Keyword
 	for _, keyword := range keywords {
@@ -111,15 +217,16 @@ func ProcessParsing(genres []string, ageCategory string, keywords []string) ([]m
 		Keywords = append(Keywords, keyword)
 	}
 
+	// Forms are validated before they are parsed, so the age category is
+	// only skipped when it is not valid.
 	var AgeCategories []models.AgeCategory
-	ages := strings.Split(ageCategory, "-")
-	min_age, _ := strconv.Atoi(ages[0])
-	max_age, _ := strconv.Atoi(ages[1])
-	age_category := models.AgeCategory{
-		MinAge: min_age,
-		MaxAge: max_age,
+	if min_age, max_age, ok := validation.ParseAgeRange(ageCategory); ok {
+		age_category := models.AgeCategory{
+			MinAge: min_age,
+			MaxAge: max_age,
+		}
+		AgeCategories = append(AgeCategories, age_category)
 	}
-	AgeCategories = append(AgeCategories, age_category)
 
 	var Genres []models.Genre
 	for _, genre := range genres {
@@ -156,3 +263,22 @@ func ProcessParsingSeasons(seasons []Season) []models.Season {
 
 	return Seasons
 }
+
+// setTokenCookie sets a token cookie with the configured attributes, living
+// as long as the access token. An empty token clears the cookie.
+func (h *Handler) setTokenCookie(c *gin.Context, name, token string) {
+	maxAge := int(h.TokenTTL.Seconds())
+	if token == "" {
+		maxAge = -1
+	}
+
+	switch strings.ToLower(h.Cookie.SameSite) {
+	case "strict":
+		c.SetSameSite(http.SameSiteStrictMode)
+	case "none":
+		c.SetSameSite(http.SameSiteNoneMode)
+	default:
+		c.SetSameSite(http.SameSiteLaxMode)
+	}
+	c.SetCookie(name, token, maxAge, h.Cookie.Path, h.Cookie.Domain, h.Cookie.Secure, h.Cookie.HTTPOnly)
+}
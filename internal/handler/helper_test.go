@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"ozinshe/internal/config"
+	"ozinshe/internal/requestid"
+	"ozinshe/internal/service"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestErrorpageHidesCauses(t *testing.T) {
+	var logs bytes.Buffer
+	h := New(&service.Service{}, &config.Config{}, slog.New(slog.NewTextHandler(&logs, nil)))
+
+	cause := errors.New(`pq: invalid input syntax for type integer: "x"`)
+	router := gin.New()
+	router.GET("/", h.RequestID, func(c *gin.Context) {
+		h.errorpage(c, http.StatusBadRequest, cause, "binding query failed")
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	var problem Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+		t.Fatal(err)
+	}
+	if problem.Detail != "binding query failed" {
+		t.Errorf("detail = %q, want the generic %q", problem.Detail, "binding query failed")
+	}
+	if strings.Contains(rec.Body.String(), "pq:") {
+		t.Errorf("body shows the cause: %s", rec.Body)
+	}
+
+	id := rec.Header().Get(requestid.Header)
+	if !strings.Contains(logs.String(), "request_id="+id) || !strings.Contains(logs.String(), "pq: invalid input syntax") {
+		t.Errorf("log = %q, want the cause tagged with request %s", logs.String(), id)
+	}
+}
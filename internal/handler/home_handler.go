@@ -10,3 +10,48 @@ func (h *Handler) HomePage(c *gin.Context) {
 	data := c.MustGet("data").(*Data)
 	h.render(c, http.StatusOK, "index.html", data)
 }
+
+// @Summary Get the home feed
+// @Description Assembles the home screen from curated collections and algorithmic rows (continue watching, trending, new releases, favorite genre). Each row holds its first page of items.
+// @Tags home
+// @Produce json
+// @Param limit query int false "Items per row (default 10, max 50)"
+// @Success 200 {array} models.HomeRow "Home rows"
+// @Failure 400 {object} Problem "Error getting home feed"
+// @Router /home [get]
+func (h *Handler) GetHome(c *gin.Context) {
+	data := c.MustGet("data").(*Data)
+	_, limit := parsePage(c)
+
+	feed, err := h.Service.Home.Feed(c.Request.Context(), data.User.ID, limit)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "home feed getting failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, feed)
+}
+
+// @Summary Get a page of an algorithmic home row
+// @Description Retrieves the next items of a home row. Curated collections are paged through /collections/{slug}.
+// @Tags home
+// @Produce json
+// @Param row path string true "Row key (trending, new-releases, continue-watching, favorite-genre)"
+// @Param offset query int false "Items to skip"
+// @Param limit query int false "Items per page (default 10, max 50)"
+// @Success 200 {object} models.HomeRow "Home row page"
+// @Failure 400 {object} Problem "Error getting home row"
+// @Failure 404 {object} Problem "Unknown row"
+// @Router /home/rows/{row} [get]
+func (h *Handler) GetHomeRow(c *gin.Context) {
+	data := c.MustGet("data").(*Data)
+	offset, limit := parsePage(c)
+
+	row, err := h.Service.Home.Row(c.Request.Context(), c.Param("row"), data.User.ID, offset, limit)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "home row getting failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, row)
+}
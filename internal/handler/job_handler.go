@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"ozinshe/internal/models"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type jobForm struct {
+	Kind    string          `json:"kind" validate:"required"`
+	Payload json.RawMessage `json:"payload" swaggertype:"object"`
+	RunAt   time.Time       `json:"run_at"`
+}
+
+// @Summary Get a list of jobs
+// @Description Retrieves background jobs, newest first, optionally filtered by status and kind. Requires admin authorization.
+// @Tags jobs
+// @Security CookieAuth
+// @Produce json
+// @Param status query string false "Job status (queued, running, succeeded or dead)"
+// @Param kind query string false "Job kind"
+// @Param offset query int false "Items to skip"
+// @Param limit query int false "Items per page (default 10, max 50)"
+// @Success 200 {array} models.Job "List of jobs"
+// @Failure 400 {object} Problem "Error getting jobs"
+// @Router /admin/jobs [get]
+func (h *Handler) GetAllJobs(c *gin.Context) {
+	offset, limit := parsePage(c)
+
+	jobs, err := h.Service.Job.GetAll(c.Request.Context(), models.JobFilter{
+		Status: c.Query("status"),
+		Kind:   c.Query("kind"),
+		Offset: offset,
+		Limit:  limit,
+	})
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "jobs getting failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, jobs)
+}
+
+// @Summary Enqueue a job
+// @Description Queues a job of a registered kind to run at run_at, or right away when it is omitted. Requires admin authorization.
+// @Tags jobs
+// @Security CookieAuth
+// @Accept json
+// @Produce json
+// @Param job body jobForm true "Job"
+// @Success 200 "Job queued"
+// @Failure 400 {object} Problem "Error queuing job"
+// @Router /admin/jobs [post]
+func (h *Handler) CreateJob(c *gin.Context) {
+	var form jobForm
+	if err := c.ShouldBindJSON(&form); err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "binding json in create job")
+		return
+	}
+
+	var payload any = form.Payload
+	if len(form.Payload) == 0 {
+		payload = struct{}{}
+	}
+
+	id, err := h.Service.Job.Enqueue(c.Request.Context(), form.Kind, payload, form.RunAt)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "job queuing failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Job queued", "job_id": id})
+}
+
+// @Summary Get job kinds
+// @Description Retrieves the kinds of job the workers can run. Requires admin authorization.
+// @Tags jobs
+// @Security CookieAuth
+// @Produce json
+// @Success 200 {array} string "Job kinds"
+// @Router /admin/jobs/kinds [get]
+func (h *Handler) GetJobKinds(c *gin.Context) {
+	c.JSON(http.StatusOK, h.Service.Job.Kinds())
+}
+
+// @Summary Get a job
+// @Description Retrieves a background job with its last error. Requires admin authorization.
+// @Tags jobs
+// @Security CookieAuth
+// @Produce json
+// @Param id path int true "Job ID"
+// @Success 200 {object} models.Job "Job"
+// @Failure 400 {object} Problem "Error getting job"
+// @Failure 404 {object} Problem "Job not found"
+// @Router /admin/jobs/{id} [get]
+func (h *Handler) GetJob(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid job id")
+		return
+	}
+
+	job, err := h.Service.Job.GetById(c.Request.Context(), id)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "job getting failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// @Summary Retry a job
+// @Description Queues a job that is not running to run again right away with a fresh set of attempts. Requires admin authorization.
+// @Tags jobs
+// @Security CookieAuth
+// @Produce json
+// @Param id path int true "Job ID"
+// @Success 200 "Job queued"
+// @Failure 400 {object} Problem "Error retrying job"
+// @Failure 404 {object} Problem "Job not found or running"
+// @Router /admin/jobs/{id}/retry [post]
+func (h *Handler) RetryJob(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid job id")
+		return
+	}
+
+	err = h.Service.Job.Retry(c.Request.Context(), id)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "job retrying failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Job queued"})
+}
+
+// @Summary Get job schedules
+// @Description Retrieves the recurring job schedules with their next run. Requires admin authorization.
+// @Tags jobs
+// @Security CookieAuth
+// @Produce json
+// @Success 200 {array} models.JobSchedule "Job schedules"
+// @Failure 400 {object} Problem "Error getting schedules"
+// @Router /admin/job-schedules [get]
+func (h *Handler) GetJobSchedules(c *gin.Context) {
+	schedules, err := h.Service.Job.Schedules(c.Request.Context())
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "job schedules getting failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, schedules)
+}
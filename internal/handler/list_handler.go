@@ -0,0 +1,318 @@
+package handler
+
+import (
+	"net/http"
+	"ozinshe/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type listForm struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	IsPublic bool   `json:"is_public"`
+}
+
+type listItemForm struct {
+	ProjectID int    `json:"project_id" validate:"required,gt=0"`
+	Note      string `json:"note" validate:"max=500"`
+}
+
+type listItemUpdateForm struct {
+	Position *int    `json:"position" validate:"omitempty,gte=0"`
+	Note     *string `json:"note" validate:"omitempty,max=500"`
+}
+
+// @Summary Get lists of the current user
+// @Description Retrieves the built-in Favorites and Watch Later lists followed by the custom lists of the current user.
+// @Tags lists
+// @Security CookieAuth
+// @Produce json
+// @Success 200 {array} models.List "Lists"
+// @Failure 400 {object} Problem "Error getting lists"
+// @Router /me/lists [get]
+func (h *Handler) GetAllLists(c *gin.Context) {
+	data := c.MustGet("data").(*Data)
+
+	lists, err := h.Service.List.GetAll(c.Request.Context(), data.User.ID)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "lists getting failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, lists)
+}
+
+// @Summary Create a list
+// @Description Creates a custom list for the current user.
+// @Tags lists
+// @Security CookieAuth
+// @Accept json
+// @Produce json
+// @Param list body listForm true "List"
+// @Success 200 "List created"
+// @Failure 400 {object} Problem "Error creating list"
+// @Router /me/lists [post]
+func (h *Handler) CreateList(c *gin.Context) {
+	data := c.MustGet("data").(*Data)
+
+	var form listForm
+	if err := c.ShouldBindJSON(&form); err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "binding json in create list")
+		return
+	}
+
+	id, err := h.Service.List.Create(c.Request.Context(), models.List{
+		UserID:   data.User.ID,
+		Name:     form.Name,
+		IsPublic: form.IsPublic,
+	})
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "list creation failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "List created", "id": id})
+}
+
+// @Summary Get a list of the current user
+// @Description Retrieves a list with one page of its ordered items.
+// @Tags lists
+// @Security CookieAuth
+// @Produce json
+// @Param id path int true "List ID"
+// @Param offset query int false "Items to skip"
+// @Param limit query int false "Items per page (default 10, max 50)"
+// @Success 200 {object} models.List "List"
+// @Failure 400 {object} Problem "Error getting list"
+// @Failure 404 {object} Problem "List not found"
+// @Router /me/lists/{id} [get]
+func (h *Handler) GetList(c *gin.Context) {
+	data := c.MustGet("data").(*Data)
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid list id")
+		return
+	}
+	offset, limit := parsePage(c)
+
+	list, err := h.Service.List.Get(c.Request.Context(), data.User.ID, id, offset, limit)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "list getting failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// @Summary Update a list
+// @Description Renames a custom list and changes the visibility of any list. Public lists can be opened by their sharing link.
+// @Tags lists
+// @Security CookieAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "List ID"
+// @Param list body listForm true "List"
+// @Success 200 "List updated"
+// @Failure 400 {object} Problem "Error updating list"
+// @Failure 404 {object} Problem "List not found"
+// @Router /me/lists/{id} [put]
+func (h *Handler) UpdateList(c *gin.Context) {
+	data := c.MustGet("data").(*Data)
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid list id")
+		return
+	}
+
+	var form listForm
+	if err := c.ShouldBindJSON(&form); err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "binding json in update list")
+		return
+	}
+
+	err = h.Service.List.Update(c.Request.Context(), data.User.ID, id, form.Name, form.IsPublic)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "list updating failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "List updated"})
+}
+
+// @Summary Delete a list
+// @Description Deletes a custom list of the current user. Built-in lists can not be deleted.
+// @Tags lists
+// @Security CookieAuth
+// @Produce json
+// @Param id path int true "List ID"
+// @Success 200 "List deleted"
+// @Failure 400 {object} Problem "Error deleting list"
+// @Failure 404 {object} Problem "List not found"
+// @Router /me/lists/{id} [delete]
+func (h *Handler) DeleteList(c *gin.Context) {
+	data := c.MustGet("data").(*Data)
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid list id")
+		return
+	}
+
+	err = h.Service.List.Remove(c.Request.Context(), data.User.ID, id)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "list deleting failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "List deleted"})
+}
+
+// @Summary Add a project to a list
+// @Description Appends a project with an optional note to the end of a list.
+// @Tags lists
+// @Security CookieAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "List ID"
+// @Param item body listItemForm true "List item"
+// @Success 200 "Project added"
+// @Failure 400 {object} Problem "Error adding project"
+// @Failure 404 {object} Problem "List not found"
+// @Router /me/lists/{id}/items [post]
+func (h *Handler) AddListItem(c *gin.Context) {
+	data := c.MustGet("data").(*Data)
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid list id")
+		return
+	}
+
+	var form listItemForm
+	if err := c.ShouldBindJSON(&form); err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "binding json in add list item")
+		return
+	}
+
+	err = h.Service.List.AddItem(c.Request.Context(), data.User.ID, id, form.ProjectID, form.Note)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "adding to list failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "added to list"})
+}
+
+// @Summary Update a list item
+// @Description Moves a project to another position of a list and/or changes its note.
+// @Tags lists
+// @Security CookieAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "List ID"
+// @Param projectID path int true "Project ID"
+// @Param item body listItemUpdateForm true "Changes"
+// @Success 200 "List item updated"
+// @Failure 400 {object} Problem "Error updating list item"
+// @Failure 404 {object} Problem "List or item not found"
+// @Router /me/lists/{id}/items/{projectID} [put]
+func (h *Handler) UpdateListItem(c *gin.Context) {
+	data := c.MustGet("data").(*Data)
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid list id")
+		return
+	}
+	projectID, err := strconv.Atoi(c.Param("projectID"))
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid project id")
+		return
+	}
+
+	var form listItemUpdateForm
+	if err := c.ShouldBindJSON(&form); err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "binding json in update list item")
+		return
+	}
+
+	if form.Note != nil {
+		err = h.Service.List.SetItemNote(c.Request.Context(), data.User.ID, id, projectID, *form.Note)
+		if err != nil {
+			h.errorpage(c, http.StatusInternalServerError, err, "list item updating failed")
+			return
+		}
+	}
+
+	if form.Position != nil {
+		err = h.Service.List.MoveItem(c.Request.Context(), data.User.ID, id, projectID, *form.Position)
+		if err != nil {
+			h.errorpage(c, http.StatusInternalServerError, err, "list item moving failed")
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "List item updated"})
+}
+
+// @Summary Remove a project from a list
+// @Description Removes a project from a list of the current user.
+// @Tags lists
+// @Security CookieAuth
+// @Produce json
+// @Param id path int true "List ID"
+// @Param projectID path int true "Project ID"
+// @Success 200 "Project removed"
+// @Failure 400 {object} Problem "Error removing project"
+// @Failure 404 {object} Problem "List or item not found"
+// @Router /me/lists/{id}/items/{projectID} [delete]
+func (h *Handler) RemoveListItem(c *gin.Context) {
+	data := c.MustGet("data").(*Data)
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid list id")
+		return
+	}
+	projectID, err := strconv.Atoi(c.Param("projectID"))
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid project id")
+		return
+	}
+
+	err = h.Service.List.RemoveItem(c.Request.Context(), data.User.ID, id, projectID)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "removing from list failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "removed from list"})
+}
+
+// @Summary Get a shared list
+// @Description Retrieves a public list by its sharing link with one page of its ordered items.
+// @Tags lists
+// @Produce json
+// @Param token path string true "Sharing token"
+// @Param offset query int false "Items to skip"
+// @Param limit query int false "Items per page (default 10, max 50)"
+// @Success 200 {object} models.List "List"
+// @Failure 404 {object} Problem "List not found"
+// @Router /lists/shared/{token} [get]
+func (h *Handler) GetSharedList(c *gin.Context) {
+	offset, limit := parsePage(c)
+
+	list, err := h.Service.List.GetShared(c.Request.Context(), c.Param("token"), offset, limit)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "shared list getting failed")
+		return
+	}
+
+	// The token is the secret of the owner, viewers have no use for it.
+	list.ShareToken = ""
+
+	c.JSON(http.StatusOK, list)
+}
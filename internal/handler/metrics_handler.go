@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"ozinshe/internal/metrics"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Instrument records the duration of every request by its route pattern,
+// so that "/movies/1" and "/movies/2" share one series.
+func (h *Handler) Instrument(c *gin.Context) {
+	start := time.Now()
+
+	c.Next()
+
+	route := c.FullPath()
+	if route == "" {
+		route = "unmatched"
+	}
+
+	metrics.HTTPRequestDuration.
+		WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+		Observe(time.Since(start).Seconds())
+}
+
+// metricsHandler serves the metrics to the clients let through by the
+// metrics config. It panics on a malformed allowed network.
+func (h *Handler) metricsHandler() gin.HandlerFunc {
+	var networks []*net.IPNet
+	for _, cidr := range h.Metrics.AllowedNetworks {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic("metrics: allowed network " + cidr + ": " + err.Error())
+		}
+		networks = append(networks, network)
+	}
+
+	serve := promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})
+
+	return func(c *gin.Context) {
+		if !h.metricsAllowed(c, networks) {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+
+		serve.ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+// metricsAllowed checks the address of the peer itself rather than
+// X-Forwarded-For, which any client can set.
+func (h *Handler) metricsAllowed(c *gin.Context, networks []*net.IPNet) bool {
+	if h.Metrics.Token != "" {
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if ok && subtle.ConstantTimeCompare([]byte(token), []byte(h.Metrics.Token)) == 1 {
+			return true
+		}
+	}
+
+	ip := net.ParseIP(c.RemoteIP())
+	if ip == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,13 +1,321 @@
 package handler
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"ozinshe/internal/apispec"
+	"ozinshe/internal/apperr"
+	"ozinshe/internal/audit"
+	"ozinshe/internal/config/lib/logger/sl"
 	"ozinshe/internal/models"
+	"ozinshe/internal/requestid"
+	"ozinshe/internal/tracing"
 	"ozinshe/util"
+	"runtime/debug"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestID assigns the request an ID, or keeps the one sent in the
+// X-Request-ID header, and echoes it in the response. The request context
+// carries the ID and a logger tagging every line with it.
+func (h *Handler) RequestID(c *gin.Context) {
+	id := c.GetHeader(requestid.Header)
+	if !requestid.Valid(id) {
+		id = requestid.New()
+	}
+
+	c.Header(requestid.Header, id)
+
+	ctx := requestid.With(c.Request.Context(), id)
+	ctx = sl.WithLogger(ctx, h.Log.With(slog.String("request_id", id)))
+	c.Request = c.Request.WithContext(ctx)
+
+	c.Next()
+}
+
+// AccessLog logs one line per request once it is handled.
+func (h *Handler) AccessLog(c *gin.Context) {
+	start := time.Now()
+
+	c.Next()
+
+	status := c.Writer.Status()
+	attrs := []slog.Attr{
+		slog.String("method", c.Request.Method),
+		slog.String("path", c.Request.URL.Path),
+		slog.String("route", c.FullPath()),
+		slog.Int("status", status),
+		slog.Duration("latency", time.Since(start)),
+		slog.Int("bytes", max(c.Writer.Size(), 0)),
+		slog.String("ip", c.ClientIP()),
+	}
+	if data, ok := c.Get("data"); ok && data.(*Data).IsAuthorized {
+		attrs = append(attrs, slog.Int("user_id", data.(*Data).User.ID))
+	}
+
+	level := slog.LevelInfo
+	if status >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+
+	h.logger(c).LogAttrs(c.Request.Context(), level, "request", attrs...)
+}
+
+// Trace starts the server span of the request, continuing the trace of the
+// caller when it sent a traceparent header. The spans of the service and
+// storage calls the request makes become its children.
+func (h *Handler) Trace(c *gin.Context) {
+	route := c.FullPath()
+	name := c.Request.Method + " " + route
+	if route == "" {
+		name = c.Request.Method
+	}
+
+	ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+	ctx, span := tracing.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.HTTPMethod(c.Request.Method),
+			semconv.HTTPRoute(route),
+			semconv.URLPath(c.Request.URL.Path),
+		),
+	)
+	defer span.End()
+
+	if sc := span.SpanContext(); sc.IsValid() {
+		log := sl.FromContext(ctx, h.Log).With(slog.String("trace_id", sc.TraceID().String()))
+		ctx = sl.WithLogger(ctx, log)
+	}
+	c.Request = c.Request.WithContext(ctx)
+
+	c.Next()
+
+	status := c.Writer.Status()
+	span.SetAttributes(semconv.HTTPStatusCode(status))
+	if status >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+}
+
+// Recover turns a panic in a handler into a 500 response and logs it with
+// its stack.
+func (h *Handler) Recover(c *gin.Context) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		if r == http.ErrAbortHandler {
+			panic(r)
+		}
+
+		h.logger(c).Error("panic recovered", slog.Any("panic", r), slog.String("stack", string(debug.Stack())))
+
+		if c.Writer.Written() {
+			c.Abort()
+			return
+		}
+		h.errorpage(c, http.StatusInternalServerError, nil, "panic recovered")
+	}()
+
+	c.Next()
+}
+
+// CrossOrigin lets pages of the allowed origins call the API and answers
+// their preflight requests. Credentials, such as the token cookie, are only
+// allowed for origins listed by name, never for "*".
+func (h *Handler) CrossOrigin(c *gin.Context) {
+	if len(h.CORS.AllowedOrigins) == 0 {
+		c.Next()
+		return
+	}
+
+	c.Writer.Header().Add("Vary", "Origin")
+
+	origin := c.GetHeader("Origin")
+	credentials, ok := h.allowedOrigin(origin)
+	if origin == "" || !ok {
+		c.Next()
+		return
+	}
+
+	c.Header("Access-Control-Allow-Origin", origin)
+	if credentials {
+		c.Header("Access-Control-Allow-Credentials", "true")
+	}
+
+	if c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != "" {
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE")
+		c.Header("Access-Control-Allow-Headers", c.GetHeader("Access-Control-Request-Headers"))
+		c.Header("Access-Control-Max-Age", strconv.Itoa(int(h.CORS.MaxAge.Seconds())))
+		c.AbortWithStatus(http.StatusNoContent)
+		return
+	}
+
+	c.Next()
+}
+
+// allowedOrigin reports whether origin may call the API, and whether it may
+// do so with credentials.
+func (h *Handler) allowedOrigin(origin string) (credentials, ok bool) {
+	for _, allowed := range h.CORS.AllowedOrigins {
+		if allowed == origin {
+			return true, true
+		}
+		if allowed == "*" {
+			ok = true
+		}
+	}
+	return false, ok
+}
+
+// streamingRoutes hold the connection open for as long as the client
+// listens, so they get no deadline unless one is configured.
+var streamingRoutes = map[string]bool{
+	"GET /me/notifications/stream": true,
+}
+
+// Deadline bounds the request context by the timeout of the route. Queries
+// of requests that run past it, or whose client goes away, are cancelled.
+// Routes are keyed without the API prefix, so that a timeout applies to a
+// route and its unversioned alias alike.
+//
+// The server write timeout would cut off routes with a longer timeout, so
+// the connection gets until HTTP.WriteTimeout past the deadline to write the
+// response, or no write deadline for routes without one.
+func (h *Handler) Deadline(c *gin.Context) {
+	route := c.Request.Method + " " + strings.TrimPrefix(c.FullPath(), APIPrefix)
+
+	timeout, ok := h.HTTP.RouteTimeouts[route]
+	if !ok && !streamingRoutes[route] {
+		timeout = h.HTTP.RequestTimeout
+	}
+
+	var writeDeadline time.Time
+	if timeout > 0 {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		writeDeadline = time.Now().Add(timeout + h.HTTP.WriteTimeout)
+	}
+
+	// Writers without a connection, such as test recorders, keep none.
+	err := http.NewResponseController(c.Writer).SetWriteDeadline(writeDeadline)
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		h.logger(c).Warn("setting write deadline failed", sl.Err(err))
+	}
+
+	c.Next()
+}
+
+// Deprecated marks the responses of the unversioned routes as deprecated
+// and links them to the route under /api/v1 that replaces them.
+func (h *Handler) Deprecated(c *gin.Context) {
+	c.Header("Deprecation", "true")
+	c.Header("Link", fmt.Sprintf("<%s%s>; rel=\"successor-version\"", APIPrefix, c.Request.URL.RequestURI()))
+
+	c.Next()
+}
+
+// maxCheckedBody bounds the responses CheckContract buffers to check.
+const maxCheckedBody = 1 << 20
+
+// CheckContract checks requests and their responses against the API spec and
+// logs where they do not match it, so that the spec and the handlers can not
+// drift apart unnoticed. Neither is changed. Only JSON bodies are checked.
+func (h *Handler) CheckContract(c *gin.Context) {
+	route := c.FullPath()
+	op := h.Spec.Operation(c.Request.Method, route)
+	if op == nil {
+		c.Next()
+		return
+	}
+
+	log := h.logger(c).With(slog.String("route", c.Request.Method+" "+route))
+
+	var body []byte
+	if c.Request.Body != nil && apispec.IsJSON(c.GetHeader("Content-Type")) {
+		var err error
+		body, err = io.ReadAll(c.Request.Body)
+		if err != nil {
+			h.errorpage(c, http.StatusBadRequest, err, "reading request body")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	for _, problem := range h.Spec.ValidateRequest(op, c.Request, body) {
+		log.Warn("request does not match the api spec", slog.String("problem", problem))
+	}
+
+	w := &contractWriter{ResponseWriter: c.Writer}
+	c.Writer = w
+
+	c.Next()
+
+	if w.skipped {
+		return
+	}
+	for _, problem := range h.Spec.ValidateResponse(op, w.Status(), w.Header(), w.body.Bytes()) {
+		log.Warn("response does not match the api spec", slog.String("problem", problem))
+	}
+}
+
+// contractWriter keeps a copy of the JSON responses it writes for
+// CheckContract. Other responses, such as event streams, and responses too
+// large to buffer are skipped.
+type contractWriter struct {
+	gin.ResponseWriter
+	body    bytes.Buffer
+	skipped bool
+}
+
+// Unwrap lets http.ResponseController reach the connection, for the write
+// deadlines of Deadline and the notification stream.
+func (w *contractWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *contractWriter) Write(b []byte) (int, error) {
+	w.keep(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *contractWriter) WriteString(s string) (int, error) {
+	w.keep([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *contractWriter) keep(b []byte) {
+	if w.skipped {
+		return
+	}
+	if !apispec.IsJSON(w.Header().Get("Content-Type")) || w.body.Len()+len(b) > maxCheckedBody {
+		w.skipped = true
+		w.body.Reset()
+		return
+	}
+	w.body.Write(b)
+}
+
+var (
+	errInvalidToken   = apperr.Unauthorized("invalid_token", "invalid or expired token")
+	errSignInRequired = apperr.Unauthorized("sign_in_required", "sign in required")
+	errAdminRequired  = apperr.Forbidden("admin_required", "admin access required")
 )
 
 func (h *Handler) Middleware(c *gin.Context) {
@@ -22,7 +330,8 @@ func (h *Handler) Middleware(c *gin.Context) {
 	case nil:
 		validToken, err := util.ValidateToken(token)
 		if err != nil {
-			h.errorpage(c, http.StatusBadRequest, err, "token validation failed")
+			h.errorpage(c, http.StatusUnauthorized, errInvalidToken.Wrap(err), "token validation failed")
+			return
 		}
 
 		id, _ := strconv.Atoi(validToken["uid"].(string))
@@ -40,11 +349,19 @@ func (h *Handler) IsAdminMiddlware(c *gin.Context) {
 	data := c.MustGet("data").(*Data)
 
 	if !data.IsAdmin {
-		h.errorpage(c, http.StatusForbidden, errors.New("admin access required"), "forbidden")
-		c.Abort()
+		h.errorpage(c, http.StatusForbidden, errAdminRequired, "forbidden")
 		return
 	}
 
+	// Changes made by admins are recorded in the audit log along with who
+	// made them.
+	c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), audit.Actor{
+		UserID:    data.User.ID,
+		Email:     data.User.Email,
+		IP:        c.ClientIP(),
+		RequestID: requestid.From(c.Request.Context()),
+	}))
+
 	c.Next()
 }
 
@@ -52,7 +369,7 @@ func (h *Handler) MustBeAuthorizedMiddleware(c *gin.Context) {
 	data := c.MustGet("data").(*Data)
 
 	if !data.IsAuthorized {
-		h.errorpage(c, http.StatusForbidden, nil, "forbidden")
+		h.errorpage(c, http.StatusUnauthorized, errSignInRequired, "unauthorized")
 		return
 	}
 
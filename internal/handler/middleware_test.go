@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"ozinshe/internal/config"
+	"ozinshe/internal/storage/storagetest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// timedOut checks that rec is the problem of a request whose query was
+// cancelled, and that the query did run.
+func timedOut(t *testing.T, db *storagetest.DB, status int, body []byte) {
+	t.Helper()
+
+	if status != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d; body %s", status, http.StatusServiceUnavailable, body)
+	}
+
+	var problem Problem
+	if err := json.Unmarshal(body, &problem); err != nil {
+		t.Fatalf("decoding problem: %v", err)
+	}
+	if problem.Code != "timeout" {
+		t.Errorf("problem code = %q, want %q", problem.Code, "timeout")
+	}
+
+	if len(db.Queries()) == 0 {
+		t.Error("no query was run")
+	}
+}
+
+func TestDeadlineCancelsQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		http config.HTTPConfig
+		path string
+	}{
+		{"request timeout", config.HTTPConfig{RequestTimeout: 50 * time.Millisecond}, "/api/v1/genres"},
+		{"route timeout", config.HTTPConfig{RequestTimeout: time.Hour, RouteTimeouts: map[string]time.Duration{"GET /genres": 50 * time.Millisecond}}, "/api/v1/genres"},
+		{"route timeout of the unversioned alias", config.HTTPConfig{RequestTimeout: time.Hour, RouteTimeouts: map[string]time.Duration{"GET /genres": 50 * time.Millisecond}}, "/genres"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := storagetest.New().Block()
+			router := newTestHandler(t, db, tt.http).InitRoutes()
+
+			start := time.Now()
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			timedOut(t, db, rec.Code, rec.Body.Bytes())
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("request took %v, want it cut at its timeout", elapsed)
+			}
+		})
+	}
+}
+
+func TestClientGoneCancelsQuery(t *testing.T) {
+	db := storagetest.New().Block()
+	router := newTestHandler(t, db, config.HTTPConfig{RequestTimeout: time.Hour}).InitRoutes()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/movies/1", nil).WithContext(ctx))
+
+	timedOut(t, db, rec.Code, rec.Body.Bytes())
+}
+
+// A route may run longer than the server write timeout; its response must
+// still reach the client rather than the connection being cut.
+func TestDeadlineOutlivesWriteTimeout(t *testing.T) {
+	db := storagetest.New().Block()
+	router := newTestHandler(t, db, config.HTTPConfig{
+		RequestTimeout: 300 * time.Millisecond,
+		WriteTimeout:   50 * time.Millisecond,
+	}).InitRoutes()
+
+	srv := httptest.NewUnstartedServer(router)
+	srv.Config.WriteTimeout = 50 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/v1/genres")
+	if err != nil {
+		t.Fatalf("the connection was cut before the route timed out: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	timedOut(t, db, resp.StatusCode, body)
+}
+
+// recordSpans installs a tracer provider recording every span, and the W3C
+// propagator, for the duration of the test.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	})
+
+	return recorder
+}
+
+// spanTree indexes recorded spans by name, and their parents by span ID.
+type spanTree struct {
+	byName map[string][]sdktrace.ReadOnlySpan
+	byID   map[trace.SpanID]sdktrace.ReadOnlySpan
+}
+
+func newSpanTree(spans []sdktrace.ReadOnlySpan) spanTree {
+	tree := spanTree{byName: map[string][]sdktrace.ReadOnlySpan{}, byID: map[trace.SpanID]sdktrace.ReadOnlySpan{}}
+	for _, span := range spans {
+		tree.byName[span.Name()] = append(tree.byName[span.Name()], span)
+		tree.byID[span.SpanContext().SpanID()] = span
+	}
+	return tree
+}
+
+// one returns the only span named name.
+func (tree spanTree) one(t *testing.T, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+
+	spans := tree.byName[name]
+	if len(spans) != 1 {
+		t.Fatalf("%d spans named %q, want 1", len(spans), name)
+	}
+	return spans[0]
+}
+
+// parent returns the name of the parent of span, or "" for a root.
+func (tree spanTree) parent(span sdktrace.ReadOnlySpan) string {
+	parent, ok := tree.byID[span.Parent().SpanID()]
+	if !ok {
+		return ""
+	}
+	return parent.Name()
+}
+
+func attr(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestTraceSpanTree(t *testing.T) {
+	recorder := recordSpans(t)
+
+	db := storagetest.New().On("FROM catalog_changes", []string{"greatest"}, []driver.Value{time.Now()})
+	router := newTestHandler(t, db, config.HTTPConfig{RequestTimeout: time.Minute}).InitRoutes()
+
+	const (
+		traceID  = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentID = "00f067aa0ba902b7"
+	)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/movies", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-"+parentID+"-01")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body %s", rec.Code, http.StatusOK, rec.Body)
+	}
+
+	spans := recorder.Ended()
+	tree := newSpanTree(spans)
+
+	for _, span := range spans {
+		if got := span.SpanContext().TraceID().String(); got != traceID {
+			t.Errorf("span %q has trace %s, want the incoming %s", span.Name(), got, traceID)
+		}
+	}
+
+	server := tree.one(t, "GET /api/v1/movies")
+	if server.SpanKind() != trace.SpanKindServer {
+		t.Errorf("server span kind = %v, want %v", server.SpanKind(), trace.SpanKindServer)
+	}
+	if got := server.Parent().SpanID().String(); got != parentID || !server.Parent().IsRemote() {
+		t.Errorf("server span parent = %s (remote %v), want the remote %s", got, server.Parent().IsRemote(), parentID)
+	}
+	for key, want := range map[attribute.Key]attribute.Value{
+		"http.method":      attribute.StringValue(http.MethodGet),
+		"http.route":       attribute.StringValue("/api/v1/movies"),
+		"url.path":         attribute.StringValue("/api/v1/movies"),
+		"http.status_code": attribute.IntValue(http.StatusOK),
+	} {
+		if got := attr(server, key); got != want {
+			t.Errorf("server span %s = %v, want %v", key, got.Emit(), want.Emit())
+		}
+	}
+
+	// handler -> service -> storage -> SQL
+	for child, parent := range map[string]string{
+		"service.catalog.ListModifiedAt": "GET /api/v1/movies",
+		"storage.catalog.ListModifiedAt": "service.catalog.ListModifiedAt",
+		"service.movie.GetAll":           "GET /api/v1/movies",
+		"storage.movie.GetAll":           "service.movie.GetAll",
+		"storage.movie.FetchMovieData":   "storage.movie.GetAll",
+	} {
+		if got := tree.parent(tree.one(t, child)); got != parent {
+			t.Errorf("parent of %q = %q, want %q", child, got, parent)
+		}
+	}
+
+	queries := tree.byName["sql.Query"]
+	if len(queries) != 2 {
+		t.Fatalf("%d sql.Query spans, want 2", len(queries))
+	}
+	for _, query := range queries {
+		if query.SpanKind() != trace.SpanKindClient {
+			t.Errorf("query span kind = %v, want %v", query.SpanKind(), trace.SpanKindClient)
+		}
+		if got := attr(query, "db.system").AsString(); got != "postgresql" {
+			t.Errorf("query db.system = %q, want postgresql", got)
+		}
+
+		statement := attr(query, "db.statement").AsString()
+		want := "storage.movie.FetchMovieData"
+		if strings.Contains(statement, "catalog_changes") {
+			want = "storage.catalog.ListModifiedAt"
+		}
+		if got := tree.parent(query); got != want {
+			t.Errorf("parent of query %q = %q, want %q", statement, got, want)
+		}
+	}
+}
+
+func TestTraceFailedRequest(t *testing.T) {
+	recorder := recordSpans(t)
+
+	db := storagetest.New().Block()
+	router := newTestHandler(t, db, config.HTTPConfig{RequestTimeout: 50 * time.Millisecond}).InitRoutes()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/movies", nil))
+
+	tree := newSpanTree(recorder.Ended())
+
+	server := tree.one(t, "GET /api/v1/movies")
+	if server.Status().Code != codes.Error {
+		t.Errorf("server span status = %v, want %v", server.Status().Code, codes.Error)
+	}
+	if got := attr(server, "http.status_code").AsInt64(); got != http.StatusServiceUnavailable {
+		t.Errorf("server span http.status_code = %d, want %d", got, http.StatusServiceUnavailable)
+	}
+
+	query := tree.one(t, "sql.Query")
+	if query.Status().Code != codes.Error || len(query.Events()) == 0 {
+		t.Errorf("query span status = %v with %d events, want the error recorded", query.Status(), len(query.Events()))
+	}
+}
@@ -1,9 +1,6 @@
 package handler
 
 import (
-	"encoding/json"
-	"errors"
-	"fmt"
 	"mime/multipart"
 	"net/http"
 	"ozinshe/internal/models"
@@ -13,32 +10,26 @@ import (
 )
 
 type movieForm struct {
-	Link        string   `json:"movie_link"`
-	Title       string   `json:"movie_title"`
-	Genres      []string `json:"movie_genres"`
-	Year        int      `json:"movie_year"`
-	Keywords    []string `json:"movie_keywords"`
-	Duration    int      `json:"movie_duration"`
-	Producer    string   `json:"movie_producer"`
-	Director    string   `json:"movie_director"`
+	Link        string   `json:"movie_link" validate:"required,youtube_id"`
+	Title       string   `json:"movie_title" validate:"required,max=100"`
+	Genres      []string `json:"movie_genres" validate:"dive,required"`
+	Year        int      `json:"movie_year" validate:"release_year"`
+	Keywords    []string `json:"movie_keywords" validate:"dive,required"`
+	Duration    int      `json:"movie_duration" validate:"gt=0"`
 	Description string   `json:"movie_description"`
-	AgeCategory string   `json:"movie_age_category"`
+	AgeCategory string   `json:"movie_age_category" validate:"required,age_range"`
+	// Credits are the ordered cast and crew, directors and producers
+	// included. Updates leave them as they are when they are left out.
+	Credits []creditForm `json:"movie_credits" validate:"dive"`
 }
 
-const maxImageSize = 15 * 1024 * 1024 // 15 MB limit
-
-func (h *Handler) CreateMovie(c *gin.Context, form *multipart.Form, project *models.Project) {
-	fmt.Println(c.Request)
+// CreateMovie adds the movie of a new project, and returns its credits, which
+// are set once the project exists.
+func (h *Handler) CreateMovie(c *gin.Context, form *multipart.Form, project *models.Project) []models.Credit {
 	var movie movieForm
-	if err := json.NewDecoder(c.Request.Body).Decode(&movie); err != nil {
+	if err := parseJSON(c.PostForm("movie_data"), &movie); err != nil {
 		h.errorpage(c, http.StatusBadRequest, err, "error parsing movie JSON")
-		return
-	}
-
-	fmt.Println(movie)
-	if movie.Link == "" {
-		h.errorpage(c, http.StatusBadRequest, errors.New("empty link"), "empty link")
-		return
+		return nil
 	}
 
 	Genres, AgeCategories, Keywords := ProcessParsing(movie.Genres, movie.AgeCategory, movie.Keywords)
@@ -48,36 +39,37 @@ func (h *Handler) CreateMovie(c *gin.Context, form *multipart.Form, project *mod
 		ReleaseYear:   movie.Year,
 		Duration:      movie.Duration,
 		Description:   movie.Description,
-		Producer:      movie.Producer,
-		Director:      movie.Director,
 		YoutubeID:     movie.Link,
 		Genres:        Genres,
 		Keywords:      Keywords,
 		AgeCategories: AgeCategories,
 	}
 
-	images_data, err := ProcessSavePhoto(form, "movies")
+	images_data, err := h.ProcessSavePhoto(form, "movies")
 	if err != nil {
 		h.errorpage(c, http.StatusInternalServerError, err, "adding movie")
-		return
+		return nil
 	}
 
-	id, err := h.Service.Movie.Add(movie_data, images_data)
+	id, err := h.Service.Movie.Add(c.Request.Context(), movie_data, images_data)
 	if err != nil {
 		h.errorpage(c, http.StatusInternalServerError, err, "adding movie")
-		return
+		return nil
 	}
 
 	project.Project_type = "movie"
 	project.Project_id = id
+
+	return toCredits(movie.Credits)
 }
 
-func (h *Handler) UpdateMovie(c *gin.Context, form *multipart.Form, movieID int, updated *bool) {
-	movieJSON := c.PostForm("movie_data")
+func (h *Handler) UpdateMovie(c *gin.Context, form *multipart.Form, project models.Project, updated *bool) {
+	movieID := project.Project_id
+
 	var movie movieForm
-	err := json.Unmarshal([]byte(movieJSON), &movie)
+	err := parseJSON(c.PostForm("movie_data"), &movie)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "error parsing series JSON")
+		h.errorpage(c, http.StatusBadRequest, err, "error parsing movie JSON")
 		return
 	}
 
@@ -88,22 +80,21 @@ func (h *Handler) UpdateMovie(c *gin.Context, form *multipart.Form, movieID int,
 		ReleaseYear:   movie.Year,
 		Duration:      movie.Duration,
 		Description:   movie.Description,
-		Producer:      movie.Producer,
-		Director:      movie.Director,
 		YoutubeID:     movie.Link,
 		Genres:        Genres,
 		Keywords:      Keywords,
 		AgeCategories: AgeCategories,
+		Credits:       toCredits(movie.Credits),
 	}
 
-	images_data, err := ProcessSavePhoto(form, "movies")
+	images_data, err := h.ProcessSavePhoto(form, "movies")
 	if err != nil {
 		h.errorpage(c, http.StatusInternalServerError, err, "adding movie")
 		return
 	}
 
 	if form.File["cover"] != nil {
-		err := h.Service.Movie.UpdateCover(movieID, images_data)
+		err := h.Service.Movie.UpdateCover(c.Request.Context(), movieID, images_data)
 		if err != nil {
 			h.errorpage(c, http.StatusInternalServerError, err, "updating movie cover")
 			return
@@ -111,14 +102,14 @@ func (h *Handler) UpdateMovie(c *gin.Context, form *multipart.Form, movieID int,
 	}
 
 	if form.File["screenshots"] != nil {
-		err := h.Service.Movie.UpdateScreenshots(movieID, images_data)
+		err := h.Service.Movie.UpdateScreenshots(c.Request.Context(), movieID, images_data)
 		if err != nil {
 			h.errorpage(c, http.StatusInternalServerError, err, "updating movie Screenshots")
 			return
 		}
 	}
 
-	err = h.Service.Movie.Update(movieID, movie_data)
+	err = h.Service.Movie.Update(c.Request.Context(), movieID, movie_data)
 	if err != nil {
 		h.errorpage(c, http.StatusInternalServerError, err, "updating movie")
 		return
@@ -130,54 +121,88 @@ func (h *Handler) UpdateMovie(c *gin.Context, form *multipart.Form, movieID int,
 }
 
 // @Summary Get details of a specific movie
-// @Description Retrieves details of a movie based on the provided ID.
+// @Description Retrieves details of a published movie based on the provided ID. Admins can preview unpublished movies with preview=true. fields limits the response to the listed fields and the ID; expand alone leaves out every part but the listed ones, which fields can also ask for.
 // @Tags movies
 // @Produce json
 // @Param id path int true "Movie ID"
+// @Param preview query bool false "Preview an unpublished movie (admins only)"
+// @Param fields query string false "Comma separated fields to send, e.g. title,release_year,cover"
+// @Param expand query string false "Comma separated parts to send: genres, keywords, age_categories, cover, screenshots, credits"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Param If-Modified-Since header string false "Last-Modified of a cached copy"
 // @Success 200 {object} models.Movie "Movie details"
-// @Failure 400 {object} error "Error getting movie"
+// @Header 200 {string} ETag "Hash of the response"
+// @Header 200 {string} Last-Modified "When the resource was last modified"
+// @Success 304 "The cached copy is current"
+// @Failure 400 {object} Problem "Error getting movie"
+// @Failure 404 {object} Problem "Movie not published"
 // @Router /movies/{id} [get]
 func (h *Handler) GetMovie(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
-	movie, err := h.Service.Movie.GetById(id)
+	if !h.published(c, "movie", id) {
+		return
+	}
+
+	p, err := parseProjection(c, jsonFields(models.Movie{}), movieDetailParts)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "movie getting failed")
+		h.errorpage(c, http.StatusBadRequest, err, "invalid projection")
 		return
 	}
-	c.JSON(http.StatusOK, movie)
-}
 
-// @Summary Delete an existing movie
-// @Description Deletes an existing movie based on the provided ID. Requires admin authorization.
-// @Tags movies
-// @Produce json
-// @Security CookieAuth
-// @Param id path int true "Movie ID"
-// @Success 200 "Movie deleted successfully"
-// @Failure 500 {object} error "Error deleting movie"
-// @Router /movies/{id} [delete]
-func (h *Handler) DeleteMovie(c *gin.Context) {
-	id, _ := strconv.Atoi(c.Param("id"))
-	err := h.Service.Movie.Remove(id)
+	modified, err := h.Service.Catalog.ModifiedAt(c.Request.Context(), models.ResourceMovies, id)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "movie modification time getting failed")
+		return
+	}
+
+	movie, err := h.loadMovie(c.Request.Context(), id, p)
 	if err != nil {
-		h.errorpage(c, http.StatusInternalServerError, err, "deleting movie")
+		h.errorpage(c, http.StatusInternalServerError, err, "movie getting failed")
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Movie deleted"})
+	h.projectedJSON(c, modified, p, movie)
 }
 
 // @Summary Get a list of all movies
-// @Description Retrieves a list of all movies.
+// @Description Retrieves a list of all movies. fields and expand shape them as for a single movie, except that credits are never sent, and only the parts sent are loaded. With view=card, the compact cards of the movies' projects are sent instead, which fields can limit too.
 // @Tags movies
 // @Produce json
-// @Success 200 {array} models.Movie "List of movies"
-// @Failure 400 {object} error "Error getting movies"
+// @Param view query string false "card for project cards" Enums(card)
+// @Param fields query string false "Comma separated fields to send, e.g. title,release_year,cover"
+// @Param expand query string false "Comma separated parts to send: genres, keywords, age_categories, cover, screenshots"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Param If-Modified-Since header string false "Last-Modified of a cached copy"
+// @Success 200 {array} models.Movie "List of movies, or of models.ProjectCard with view=card"
+// @Header 200 {string} ETag "Hash of the response"
+// @Header 200 {string} Last-Modified "When the resource was last modified"
+// @Success 304 "The cached copy is current"
+// @Failure 400 {object} Problem "Error getting movies"
 // @Router /movies [get]
 func (h *Handler) GetAllMovies(c *gin.Context) {
-	movies, err := h.Service.Movie.GetAll()
+	cards, p, err := parseListProjection(c, jsonFields(models.Movie{}), movieParts)
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid projection")
+		return
+	}
+
+	modified, err := h.Service.Catalog.ListModifiedAt(c.Request.Context(), models.ResourceMovies)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "movies modification time getting failed")
+		return
+	}
+
+	var movies any
+	switch {
+	case cards:
+		movies, err = h.Service.Catalog.GetCards(c.Request.Context(), "movie")
+	case p.parts == nil:
+		movies, err = h.Service.Movie.GetAll(c.Request.Context())
+	default:
+		movies, err = h.Service.Catalog.ListMovies(c.Request.Context(), p.parts)
+	}
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "movies getting failed")
+		h.errorpage(c, http.StatusInternalServerError, err, "movies getting failed")
 		return
 	}
-	c.JSON(http.StatusOK, movies)
+	h.projectedJSON(c, modified, p, movies)
 }
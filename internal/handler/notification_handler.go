@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"ozinshe/internal/models"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamKeepAlive is how often an idle notification stream sends a ping so
+// proxies don't close it.
+const streamKeepAlive = 30 * time.Second
+
+// @Summary Get notifications of the current user
+// @Description Retrieves one page of the current user's notifications, newest first, with the number of unread ones.
+// @Tags notifications
+// @Security CookieAuth
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Param offset query int false "Items to skip"
+// @Param limit query int false "Items per page (default 10, max 50)"
+// @Success 200 {object} models.Inbox "Notifications"
+// @Failure 400 {object} Problem "Error getting notifications"
+// @Router /me/notifications [get]
+func (h *Handler) GetNotifications(c *gin.Context) {
+	data := c.MustGet("data").(*Data)
+	offset, limit := parsePage(c)
+	unreadOnly, _ := strconv.ParseBool(c.Query("unread"))
+
+	inbox, err := h.Service.Notification.Inbox(c.Request.Context(), data.User.ID, unreadOnly, offset, limit)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "notifications getting failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, inbox)
+}
+
+// @Summary Stream notifications of the current user
+// @Description Server-Sent Events stream delivering the current user's new notifications as "notification" events. A "ping" event is sent while idle.
+// @Tags notifications
+// @Security CookieAuth
+// @Produce text/event-stream
+// @Success 200 {object} models.Notification "Notification events"
+// @Router /me/notifications/stream [get]
+func (h *Handler) StreamNotifications(c *gin.Context) {
+	data := c.MustGet("data").(*Data)
+
+	// The server write timeout would cut the stream short.
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "notification stream failed")
+		return
+	}
+
+	notifications, unsubscribe := h.Service.Notification.Subscribe(data.User.ID)
+	defer unsubscribe()
+
+	ticker := time.NewTicker(streamKeepAlive)
+	defer ticker.Stop()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.SSEvent("ping", "")
+	c.Writer.Flush()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case notification := <-notifications:
+			c.SSEvent("notification", notification)
+		case <-ticker.C:
+			c.SSEvent("ping", "")
+		case <-c.Request.Context().Done():
+			return false
+		case <-h.draining:
+			return false
+		}
+		return true
+	})
+}
+
+// @Summary Mark a notification as read
+// @Tags notifications
+// @Security CookieAuth
+// @Produce json
+// @Param id path int true "Notification ID"
+// @Success 200 "Notification marked as read"
+// @Failure 400 {object} Problem "Error marking notification"
+// @Failure 404 {object} Problem "Notification not found"
+// @Router /me/notifications/{id}/read [post]
+func (h *Handler) MarkNotificationRead(c *gin.Context) {
+	data := c.MustGet("data").(*Data)
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid notification id")
+		return
+	}
+
+	err = h.Service.Notification.MarkRead(c.Request.Context(), data.User.ID, id)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "notification marking failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+}
+
+// @Summary Mark all notifications as read
+// @Tags notifications
+// @Security CookieAuth
+// @Produce json
+// @Success 200 "Notifications marked as read"
+// @Failure 400 {object} Problem "Error marking notifications"
+// @Router /me/notifications/read [post]
+func (h *Handler) MarkAllNotificationsRead(c *gin.Context) {
+	data := c.MustGet("data").(*Data)
+
+	err := h.Service.Notification.MarkAllRead(c.Request.Context(), data.User.ID)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "notifications marking failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notifications marked as read"})
+}
+
+// @Summary Get notification preferences
+// @Description Retrieves which notifications the current user receives.
+// @Tags notifications
+// @Security CookieAuth
+// @Produce json
+// @Success 200 {object} models.NotificationPreferences "Preferences"
+// @Failure 400 {object} Problem "Error getting preferences"
+// @Router /me/notification-preferences [get]
+func (h *Handler) GetNotificationPreferences(c *gin.Context) {
+	data := c.MustGet("data").(*Data)
+
+	prefs, err := h.Service.Notification.GetPreferences(c.Request.Context(), data.User.ID)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "notification preferences getting failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// @Summary Update notification preferences
+// @Description Sets which notifications the current user receives.
+// @Tags notifications
+// @Security CookieAuth
+// @Accept json
+// @Produce json
+// @Param preferences body models.NotificationPreferences true "Preferences"
+// @Success 200 "Preferences updated"
+// @Failure 400 {object} Problem "Error updating preferences"
+// @Router /me/notification-preferences [put]
+func (h *Handler) SetNotificationPreferences(c *gin.Context) {
+	data := c.MustGet("data").(*Data)
+
+	var prefs models.NotificationPreferences
+	if err := c.ShouldBindJSON(&prefs); err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "binding json in set notification preferences")
+		return
+	}
+
+	err := h.Service.Notification.SetPreferences(c.Request.Context(), data.User.ID, prefs)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "notification preferences updating failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Preferences updated"})
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"net/http"
+	"ozinshe/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type personForm struct {
+	Name string `form:"name" validate:"required,max=255"`
+	Bio  string `form:"bio"`
+}
+
+type creditForm struct {
+	PersonID  int    `json:"person_id" validate:"required,gt=0"`
+	Role      string `json:"role" validate:"required,oneof=director producer actor writer"`
+	Character string `json:"character" validate:"max=255"`
+}
+
+// toCredits returns the credits of form in order, or nil when form is nil,
+// such as when a movie or series form leaves them out.
+func toCredits(form []creditForm) []models.Credit {
+	if form == nil {
+		return nil
+	}
+
+	credits := make([]models.Credit, 0, len(form))
+	for _, credit := range form {
+		credits = append(credits, models.Credit{
+			PersonID:  credit.PersonID,
+			Role:      credit.Role,
+			Character: credit.Character,
+		})
+	}
+	return credits
+}
+
+// @Summary Get a list of people
+// @Description Retrieves a list of people (cast and crew), optionally filtered by name.
+// @Tags people
+// @Produce json
+// @Param name query string false "Part of the person's name"
+// @Success 200 {array} models.Person "List of people"
+// @Failure 400 {object} Problem "Error getting people"
+// @Router /people [get]
+func (h *Handler) GetAllPeople(c *gin.Context) {
+	people, err := h.Service.Person.GetAll(c.Request.Context(), c.Query("name"))
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "people getting failed")
+		return
+	}
+	c.JSON(http.StatusOK, people)
+}
+
+// @Summary Get details of a specific person
+// @Description Retrieves a person with photo, bio and filmography based on the provided ID.
+// @Tags people
+// @Produce json
+// @Param id path int true "Person ID"
+// @Success 200 {object} models.Person "Person details with filmography"
+// @Failure 400 {object} Problem "Error getting person"
+// @Router /people/{id} [get]
+func (h *Handler) GetPerson(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid person id")
+		return
+	}
+
+	person, err := h.Service.Person.GetById(c.Request.Context(), id)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "person getting failed")
+		return
+	}
+	c.JSON(http.StatusOK, person)
+}
+
+// @Summary Create a new person
+// @Description Creates a new person with the provided name, bio and photo. Requires admin authorization.
+// @Tags people
+// @Accept multipart/form-data
+// @Produce json
+// @Security CookieAuth
+// @Param name formData string true "Person name"
+// @Param bio formData string false "Person bio"
+// @Param photo formData file false "Person photo"
+// @Success 200 "Person created"
+// @Failure 400 {object} Problem "Error creating person"
+// @Router /people [post]
+func (h *Handler) CreatePerson(c *gin.Context) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "binding multipart form in create person")
+		return
+	}
+
+	var fields personForm
+	if err := c.ShouldBind(&fields); err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "person creation failed")
+		return
+	}
+
+	person := models.Person{
+		Name: fields.Name,
+		Bio:  fields.Bio,
+	}
+
+	images_data, err := h.ProcessSavePhoto(form, "people")
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "processing person photo")
+		return
+	}
+
+	id, err := h.Service.Person.Add(c.Request.Context(), person, images_data)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "person creation failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Person created", "id": id})
+}
+
+// @Summary Delete an existing person
+// @Description Deletes a person and all of their credits based on the provided ID. Requires admin authorization.
+// @Tags people
+// @Produce json
+// @Security CookieAuth
+// @Param id path int true "Person ID"
+// @Success 200 "Person deleted successfully"
+// @Failure 400 {object} Problem "Error deleting person"
+// @Router /people/{id} [delete]
+func (h *Handler) DeletePerson(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid person id")
+		return
+	}
+
+	err = h.Service.Person.Remove(c.Request.Context(), id)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "person deleting failed")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Person deleted"})
+}
+
+// @Summary Get credits of a project
+// @Description Retrieves the ordered cast and crew of a project (movie or series).
+// @Tags people
+// @Produce json
+// @Param id path int true "Project ID"
+// @Success 200 {array} models.Credit "List of credits"
+// @Failure 400 {object} Problem "Error getting credits"
+// @Router /projects/{id}/credits [get]
+func (h *Handler) GetProjectCredits(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid project id")
+		return
+	}
+
+	credits, err := h.Service.Person.GetCredits(c.Request.Context(), id)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "credits getting failed")
+		return
+	}
+	c.JSON(http.StatusOK, credits)
+}
+
+// @Summary Replace credits of a project
+// @Description Replaces the cast and crew of a project. The order of the list is used as billing order. Requires admin authorization.
+// @Tags people
+// @Accept json
+// @Produce json
+// @Security CookieAuth
+// @Param id path int true "Project ID"
+// @Param credits body []creditForm true "Ordered credits (role is director, producer, actor or writer)"
+// @Success 200 "Credits updated"
+// @Failure 400 {object} Problem "Error updating credits"
+// @Router /projects/{id}/credits [put]
+func (h *Handler) SetProjectCredits(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid project id")
+		return
+	}
+
+	var form []creditForm
+	if err := c.ShouldBindJSON(&form); err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "binding json in set credits")
+		return
+	}
+
+	if _, err := h.Service.Project.GetById(c.Request.Context(), id); err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "project getting failed")
+		return
+	}
+
+	err = h.Service.Person.SetCredits(c.Request.Context(), id, toCredits(form))
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "credits updating failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Credits updated"})
+}
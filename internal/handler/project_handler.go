@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"ozinshe/internal/models"
+	"ozinshe/internal/storage"
 	"sort"
 	"strconv"
 	"time"
@@ -17,18 +18,18 @@ type Contents struct {
 }
 
 // @Summary Create a new project (movie or series)
-// @Description Creates a new project based on the provided project type and form data. Requires admin authorization.
+// @Description Creates a new project based on the provided project type and form data. New projects are drafts, hidden from the public until published. Requires admin authorization.
 // @Tags projects
-// @Accept mpfd
+// @Accept multipart/form-data
 // @Produce json
-// @Security CookieAuthc
+// @Security CookieAuth
 // @Param project_type formData string true "Project type (movie or series)"
 // @Param cover formData file true "Project cover"
 // @Param screenshots formData file true "Project screenshots"
-// @Param movie_data formData movieForm true "Movie data (JSON)"
-// @Param series_data formData Series true "Series data (JSON)"
+// @Param movie_data formData string false "Movie data, a handler.movieForm as JSON (movies only)"
+// @Param series_data formData string false "Series data, a handler.Series as JSON (series only)"
 // @Success 200 {object} models.Project "Project created successfully"
-// @Failure 400 {object} ErrorData "Error creating project"
+// @Failure 400 {object} Problem "Error creating project"
 // @Router /projects/create-project [post]
 func (h *Handler) CreateProject(c *gin.Context) {
 
@@ -39,35 +40,38 @@ func (h *Handler) CreateProject(c *gin.Context) {
 	}
 
 	project := models.Project{}
+	var credits []models.Credit
 	switch c.PostForm("project_type") {
 	case "movie":
-		h.CreateMovie(c, form, &project)
+		credits = h.CreateMovie(c, form, &project)
 	case "series":
-		h.CreateSeries(c, form, &project)
+		credits = h.CreateSeries(c, form, &project)
 	}
 
 	if project.Project_type == "" {
 		return
 	}
 
-	id, err := h.Service.Project.Add(project)
+	// Credits refer to the project, so they are written along with it.
+	project.Credits = credits
+	id, err := h.Service.Project.Add(c.Request.Context(), project)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "project creation failed")
+		h.errorpage(c, http.StatusInternalServerError, err, "project creation failed")
 		return
 	}
 
 	if project.Project_type == "movie" {
-		movie_data, err := h.Service.Movie.GetById(project.Project_id)
+		movie_data, err := h.Service.Movie.GetById(c.Request.Context(), project.Project_id)
 		if err != nil {
-			h.errorpage(c, http.StatusBadRequest, err, "movie getting failed")
+			h.errorpage(c, http.StatusInternalServerError, err, "movie getting failed")
 			return
 		}
 		movie_data.ID = id
 		c.JSON(http.StatusOK, movie_data)
 	} else if project.Project_type == "series" {
-		series_data, err := h.Service.Series.GetById(project.Project_id)
+		series_data, err := h.Service.Series.GetById(c.Request.Context(), project.Project_id)
 		if err != nil {
-			h.errorpage(c, http.StatusBadRequest, err, "series getting failed")
+			h.errorpage(c, http.StatusInternalServerError, err, "series getting failed")
 			return
 		}
 		series_data.ID = id
@@ -82,12 +86,12 @@ func (h *Handler) CreateProject(c *gin.Context) {
 // @Produce json
 // @Security CookieAuth
 // @Param id path int true "Project ID"
-// @Param cover formData file true "Project cover"
-// @Param screenshots formData file true "Project screenshots"
-// @Param movie_data body movieForm true "Project data (JSON)"
-// @Param series_data body Series true "Project data (JSON)"
+// @Param cover formData file false "New project cover"
+// @Param screenshots formData file false "New project screenshots"
+// @Param movie_data formData string false "Movie data, a handler.movieForm as JSON (movies only)"
+// @Param series_data formData string false "Series data, a handler.Series as JSON (series only)"
 // @Success 200 "Project updated successfully"
-// @Failure 400 {object} ErrorData "Error updating project"
+// @Failure 400 {object} Problem "Error updating project"
 // @Router /projects/{id} [put]
 func (h *Handler) UpdateProject(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -96,9 +100,9 @@ func (h *Handler) UpdateProject(c *gin.Context) {
 		return
 	}
 
-	project, err := h.Service.Project.GetById(id)
+	project, err := h.Service.Project.GetById(c.Request.Context(), id)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "project getting failed")
+		h.errorpage(c, http.StatusInternalServerError, err, "project getting failed")
 		return
 	}
 
@@ -111,9 +115,9 @@ func (h *Handler) UpdateProject(c *gin.Context) {
 	updated := false
 	switch project.Project_type {
 	case "movie":
-		h.UpdateMovie(c, form, project.Project_id, &updated)
+		h.UpdateMovie(c, form, project, &updated)
 	case "series":
-		h.UpdateSeries(c, form, project.Project_id, &updated)
+		h.UpdateSeries(c, form, project, &updated)
 	}
 
 	if !updated {
@@ -130,100 +134,147 @@ func (h *Handler) UpdateProject(c *gin.Context) {
 // @Security CookieAuth
 // @Param id path int true "Project ID"
 // @Success 200 "Project deleted successfully"
-// @Failure 400 {object} error "Error deleting project"
+// @Failure 400 {object} Problem "Error deleting project"
 // @Router /projects/{id} [delete]
 func (h *Handler) DeleteProject(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
 
-	project, err := h.Service.Project.GetById(id)
+	project, err := h.Service.Project.GetById(c.Request.Context(), id)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "project getting failed")
+		h.errorpage(c, http.StatusInternalServerError, err, "project getting failed")
 		return
 	}
 
 	if project.Project_type == "movie" {
-		err := h.Service.Movie.Remove(project.Project_id)
+		err := h.Service.Movie.Remove(c.Request.Context(), project.Project_id)
 		if err != nil {
-			h.errorpage(c, http.StatusBadRequest, err, "movie removing failed")
+			h.errorpage(c, http.StatusInternalServerError, err, "movie removing failed")
 			return
 		}
 	} else if project.Project_type == "series" {
-		err := h.Service.Series.Remove(project.Project_id)
+		err := h.Service.Series.Remove(c.Request.Context(), project.Project_id)
 		if err != nil {
-			h.errorpage(c, http.StatusBadRequest, err, "series removing failed")
+			h.errorpage(c, http.StatusInternalServerError, err, "series removing failed")
 			return
 		}
 	}
 
-	err = h.Service.Project.Remove(id)
+	err = h.Service.Project.Remove(c.Request.Context(), id)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "project deleting failed")
+		h.errorpage(c, http.StatusInternalServerError, err, "project deleting failed")
 		return
 	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Project deleted"})
 }
 
 // @Summary Get details of a specific project (movie or series)
-// @Description Retrieves details of a project based on the provided ID.
+// @Description Retrieves details of a published project based on the provided ID. Admins can preview unpublished projects with preview=true. fields and expand shape it as for a single movie or series.
 // @Tags projects
 // @Produce json
 // @Param id path int true "Project ID"
+// @Param preview query bool false "Preview an unpublished project (admins only)"
+// @Param fields query string false "Comma separated fields to send, e.g. title,release_year,cover"
+// @Param expand query string false "Comma separated parts to send: genres, keywords, age_categories, cover, screenshots, credits, and seasons for series"
 // @Success 200 {object} models.Movie "Project details (movie)"
 // @Success 200 {object} models.Series "Project details (series)"
-// @Failure 400 {object} error "Error getting project"
+// @Failure 400 {object} Problem "Error getting project"
+// @Failure 404 {object} Problem "Project not found or not published"
 // @Router /projects/{id} [get]
 func (h *Handler) GetProject(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
-	project, err := h.Service.Project.GetById(id)
+	project, err := h.Service.Project.GetById(c.Request.Context(), id)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "project getting failed")
+		h.errorpage(c, http.StatusInternalServerError, err, "project getting failed")
 		return
 	}
 
+	if !project.Visible(time.Now()) && !previewing(c) {
+		h.errorpage(c, http.StatusNotFound, storage.ErrProjectNotFound, "project not published")
+		return
+	}
+
+	var (
+		p        projection
+		resource any
+	)
 	switch project.Project_type {
 	case "movie":
-		movie, err := h.Service.Movie.GetById(project.Project_id)
-		if err != nil {
-			h.errorpage(c, http.StatusBadRequest, err, "movie getting failed")
+		if p, err = parseProjection(c, jsonFields(models.Movie{}), movieDetailParts); err != nil {
+			h.errorpage(c, http.StatusBadRequest, err, "invalid projection")
+			return
+		}
+		if resource, err = h.loadMovie(c.Request.Context(), project.Project_id, p); err != nil {
+			h.errorpage(c, http.StatusInternalServerError, err, "movie getting failed")
 			return
 		}
-		c.JSON(http.StatusOK, movie)
 
 	case "series":
-		series, err := h.Service.Series.GetById(project.Project_id)
-		if err != nil {
-			h.errorpage(c, http.StatusBadRequest, err, "series getting failed")
+		if p, err = parseProjection(c, jsonFields(models.Series{}), seriesDetailParts); err != nil {
+			h.errorpage(c, http.StatusBadRequest, err, "invalid projection")
+			return
+		}
+		if resource, err = h.loadSeries(c.Request.Context(), project.Project_id, p); err != nil {
+			h.errorpage(c, http.StatusInternalServerError, err, "series getting failed")
 			return
 		}
-		c.JSON(http.StatusOK, series)
 	}
+
+	body, err := p.apply(resource)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "encoding response")
+		return
+	}
+
+	c.JSON(http.StatusOK, body)
 }
 
 // @Summary Get a list of all projects (movies and series)
-// @Description Retrieves a list of all projects, including both movies and series.
+// @Description Retrieves a list of all published projects, including both movies and series. fields and expand shape them as for the lists of movies and of series, and only the parts sent are loaded. With view=card, the compact cards of the projects are sent instead, which fields can limit too.
 // @Tags projects
 // @Produce json
-// @Success 200 {array} Contents "List of movies and series"
-// @Failure 400 {object} error "Error getting projects"
+// @Param view query string false "card for project cards" Enums(card)
+// @Param fields query string false "Comma separated fields to send, e.g. title,release_year,cover"
+// @Param expand query string false "Comma separated parts to send: genres, keywords, age_categories, cover, screenshots, seasons"
+// @Success 200 {array} Contents "List of movies and series, or of models.ProjectCard with view=card"
+// @Failure 400 {object} Problem "Error getting projects"
 // @Router /projects [get]
 func (h *Handler) GetAllProjects(c *gin.Context) {
-	movies, err := h.Service.Movie.GetAll()
+	cards, p, err := parseListProjection(c, jsonFields(models.Movie{}, models.Series{}), seriesParts)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "(movie)projects getting failed")
+		h.errorpage(c, http.StatusBadRequest, err, "invalid projection")
 		return
 	}
-	var contents Contents
 
-	series, err := h.Service.Series.GetAll()
+	var movies, series any
+	switch {
+	case cards:
+		if movies, err = h.Service.Catalog.GetCards(c.Request.Context(), "movie"); err == nil {
+			series, err = h.Service.Catalog.GetCards(c.Request.Context(), "series")
+		}
+	case p.parts == nil:
+		if movies, err = h.Service.Movie.GetAll(c.Request.Context()); err == nil {
+			series, err = h.Service.Series.GetAll(c.Request.Context())
+		}
+	default:
+		if movies, err = h.Service.Catalog.ListMovies(c.Request.Context(), p.parts); err == nil {
+			series, err = h.Service.Catalog.ListSeries(c.Request.Context(), p.parts)
+		}
+	}
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "(series)projects getting failed")
+		h.errorpage(c, http.StatusInternalServerError, err, "projects getting failed")
 		return
 	}
 
-	contents.Series = series
-	contents.Movies = movies
+	if movies, err = p.apply(movies); err == nil {
+		series, err = p.apply(series)
+	}
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "encoding response")
+		return
+	}
 
-	c.JSON(http.StatusOK, contents)
+	c.JSON(http.StatusOK, gin.H{"Movies": movies, "Series": series})
 }
 
 // @Summary Get a list of all favorited projects (movies and series) for the current user
@@ -232,22 +283,22 @@ func (h *Handler) GetAllProjects(c *gin.Context) {
 // @Security CookieAuth
 // @Produce json
 // @Success 200 {array} Contents "List of favorited movies and series"
-// @Failure 400 {object} error "Error getting favorites"
-// @Router /favorites [get]
+// @Failure 400 {object} Problem "Error getting favorites"
+// @Router /projects/favorites [get]
 func (h *Handler) GetAllFavorites(c *gin.Context) {
 	data := c.MustGet("data").(*Data)
 
 	var content Contents
-	movies, err := h.Service.Movie.GetFavorites(data.User.ID)
+	movies, err := h.Service.Movie.GetFavorites(c.Request.Context(), data.User.ID)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "getting favorites failed")
+		h.errorpage(c, http.StatusInternalServerError, err, "getting favorites failed")
 		return
 	}
 	content.Movies = movies
 
-	series, err := h.Service.Series.GetFavorites(data.User.ID)
+	series, err := h.Service.Series.GetFavorites(c.Request.Context(), data.User.ID)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "getting favorites failed")
+		h.errorpage(c, http.StatusInternalServerError, err, "getting favorites failed")
 		return
 	}
 	content.Series = series
@@ -263,8 +314,9 @@ func (h *Handler) GetAllFavorites(c *gin.Context) {
 // @Param year_end query int false "Ending year for filter"
 // @Param project_type query string false "Project type (movie or series)"
 // @Param popularity_order query string false "Popularity order (asc or desc)"
+// @Param person_id query int false "Only projects crediting this person"
 // @Success 200 {array} Contents "Filtered list of movies and series"
-// @Failure 400 {object} error "Error getting filtered projects"
+// @Failure 400 {object} Problem "Error getting filtered projects"
 // @Router /projects/search [get]
 func (h *Handler) GetFilteredProjects(c *gin.Context) {
 	var filter models.FilterParams
@@ -283,9 +335,9 @@ func (h *Handler) GetFilteredProjects(c *gin.Context) {
 
 	switch filter.Project_type {
 	case "movie":
-		movies, err := h.Service.Movie.GetFiltered(filter)
+		movies, err := h.Service.Movie.GetFiltered(c.Request.Context(), filter)
 		if err != nil {
-			h.errorpage(c, http.StatusBadRequest, err, "movie filtering failed")
+			h.errorpage(c, http.StatusInternalServerError, err, "movie filtering failed")
 			return
 		}
 		if filter.PopularityOrder == "asc" {
@@ -301,9 +353,9 @@ func (h *Handler) GetFilteredProjects(c *gin.Context) {
 		c.JSON(http.StatusOK, movies)
 
 	case "series":
-		series, err := h.Service.Series.GetFiltered(filter)
+		series, err := h.Service.Series.GetFiltered(c.Request.Context(), filter)
 		if err != nil {
-			h.errorpage(c, http.StatusBadRequest, err, "series filtering failed")
+			h.errorpage(c, http.StatusInternalServerError, err, "series filtering failed")
 			return
 		}
 
@@ -319,15 +371,15 @@ func (h *Handler) GetFilteredProjects(c *gin.Context) {
 		c.JSON(http.StatusOK, series)
 
 	default:
-		movies, err := h.Service.Movie.GetFiltered(filter)
+		movies, err := h.Service.Movie.GetFiltered(c.Request.Context(), filter)
 		if err != nil {
-			h.errorpage(c, http.StatusBadRequest, err, "movie filtering failed")
+			h.errorpage(c, http.StatusInternalServerError, err, "movie filtering failed")
 			return
 		}
 
-		series, err := h.Service.Series.GetFiltered(filter)
+		series, err := h.Service.Series.GetFiltered(c.Request.Context(), filter)
 		if err != nil {
-			h.errorpage(c, http.StatusBadRequest, err, "series filtering failed")
+			h.errorpage(c, http.StatusInternalServerError, err, "series filtering failed")
 			return
 		}
 
@@ -360,41 +412,24 @@ func (h *Handler) GetFilteredProjects(c *gin.Context) {
 }
 
 // @Summary Add a project to favorites
-// @Description Allows the currently authenticated user to add a project (movie or series) to their favorites list.
+// @Description Allows the currently authenticated user to add a project (movie or series) to their built-in Favorites list.
 // @Tags favorites
 // @Security CookieAuth
 // @Param id path int true "Project ID"
 // @Success 200 "Favorite added successfully"
-// @Failure 400 {object} error "Error adding to favorites"
-// @Router /projects/{id}/favorites [post]
+// @Failure 400 {object} Problem "Error adding to favorites"
+// @Router /projects/{id}/favorite [post]
 func (h *Handler) MakeFavorite(c *gin.Context) {
-	projectID, _ := strconv.Atoi(c.Param("id"))
-	data := c.MustGet("data").(*Data)
-
-	project, err := h.Service.Project.GetById(projectID)
+	projectID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "project getting failed")
+		h.errorpage(c, http.StatusBadRequest, err, "invalid project id")
 		return
 	}
+	data := c.MustGet("data").(*Data)
 
-	if project.Project_type == "movie" {
-		err := h.Service.Movie.AddToFavorites(project.Project_id, data.User.ID)
-		if err != nil {
-			h.errorpage(c, http.StatusBadRequest, err, "adding to favorites failed")
-			return
-		}
-	} else if project.Project_type == "series" {
-		err := h.Service.Series.AddToFavorites(project.Project_id, data.User.ID)
-		if err != nil {
-			h.errorpage(c, http.StatusBadRequest, err, "adding to favorites failed")
-			return
-		}
-	}
-
-	err = h.Service.Project.AddToFavorites(projectID, data.User.ID)
-
+	err = h.Service.List.AddToFavorites(c.Request.Context(), data.User.ID, projectID)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "adding to favorites failed")
+		h.errorpage(c, http.StatusInternalServerError, err, "adding to favorites failed")
 		return
 	}
 
@@ -402,43 +437,138 @@ func (h *Handler) MakeFavorite(c *gin.Context) {
 }
 
 // @Summary Delete a project from favorites
-// @Description Allows the currently authenticated user to delete a project (movie or series) from their favorites list.
+// @Description Allows the currently authenticated user to delete a project (movie or series) from their built-in Favorites list.
 // @Tags favorites
 // @Security CookieAuth
 // @Param id path int true "Project ID"
-// @Success 200 "Favorite added successfully"
-// @Failure 400 {object} error "Error adding to favorites"
-// @Router /projects/{id}/favorites [delete]
+// @Success 200 "Favorite removed successfully"
+// @Failure 400 {object} Problem "Error removing from favorites"
+// @Router /projects/{id}/favorite [delete]
 func (h *Handler) RemoveFromFavorites(c *gin.Context) {
-	projectID, _ := strconv.Atoi(c.Param("id"))
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid project id")
+		return
+	}
 	data := c.MustGet("data").(*Data)
 
-	project, err := h.Service.Project.GetById(projectID)
+	err = h.Service.List.RemoveFromFavorites(c.Request.Context(), data.User.ID, projectID)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "project getting failed")
+		h.errorpage(c, http.StatusInternalServerError, err, "removing from favorites failed")
 		return
 	}
 
-	if project.Project_type == "movie" {
-		err := h.Service.Movie.RemoveFromFavorites(project.Project_id, data.User.ID)
-		if err != nil {
-			h.errorpage(c, http.StatusBadRequest, err, "removing from favorites failed")
-			return
-		}
-	} else if project.Project_type == "series" {
-		err := h.Service.Series.RemoveFromFavorites(project.Project_id, data.User.ID)
-		if err != nil {
-			h.errorpage(c, http.StatusBadRequest, err, "removing from favorites failed")
-			return
-		}
+	c.JSON(http.StatusOK, gin.H{"message": "removed from favorites"})
+}
+
+type statusForm struct {
+	Status      string     `json:"status" validate:"required,oneof=draft review scheduled published"`
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
+}
+
+// @Summary Set the status of a project
+// @Description Moves a project through the publishing workflow: draft, review, scheduled or published. Scheduled projects need a publish_at in the future and go live then. An optional unpublish_at hides the project again, such as when its license expires. Requires admin authorization.
+// @Tags projects
+// @Security CookieAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param status body statusForm true "Status and publish window"
+// @Success 200 "Status updated"
+// @Failure 400 {object} Problem "Invalid status or publish window"
+// @Failure 404 {object} Problem "Project not found"
+// @Failure 409 {object} Problem "Status transition not allowed"
+// @Router /projects/{id}/status [put]
+func (h *Handler) SetProjectStatus(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid project id")
+		return
 	}
 
-	err = h.Service.Project.RemoveFromFavorites(projectID, data.User.ID)
+	var form statusForm
+	if err := c.ShouldBindJSON(&form); err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "binding json in set project status")
+		return
+	}
 
+	err = h.Service.Project.SetStatus(c.Request.Context(), id, models.Publication{
+		Status:      form.Status,
+		PublishAt:   form.PublishAt,
+		UnpublishAt: form.UnpublishAt,
+	})
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "removing from favorites failed")
+		h.errorpage(c, http.StatusInternalServerError, err, "project status setting failed")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "removed from favorites"})
+	c.JSON(http.StatusOK, gin.H{"message": "Status updated"})
+}
+
+// @Summary Get projects by publication status
+// @Description Retrieves the cards of all projects, published or not, with their status and publish window, newest first. Requires admin authorization.
+// @Tags projects
+// @Security CookieAuth
+// @Produce json
+// @Param status query string false "Status (draft, review, scheduled or published)"
+// @Param offset query int false "Items to skip"
+// @Param limit query int false "Items per page (default 10, max 50)"
+// @Success 200 {array} models.PublicationCard "Projects"
+// @Failure 400 {object} Problem "Error getting projects"
+// @Router /admin/projects [get]
+func (h *Handler) GetPublications(c *gin.Context) {
+	offset, limit := parsePage(c)
+
+	cards, err := h.Service.Project.Publications(c.Request.Context(), c.Query("status"), offset, limit)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "projects getting failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, cards)
+}
+
+type progressForm struct {
+	PositionSeconds int  `json:"position_seconds" validate:"gte=0"`
+	Finished        bool `json:"finished"`
+}
+
+// @Summary Save watch progress
+// @Description Stores how far the current user has watched a project. Unfinished projects show up in the continue watching row.
+// @Tags projects
+// @Security CookieAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param progress body progressForm true "Watch progress"
+// @Success 200 "Progress saved"
+// @Failure 400 {object} Problem "Error saving progress"
+// @Router /projects/{id}/progress [put]
+func (h *Handler) SaveProgress(c *gin.Context) {
+	projectID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid project id")
+		return
+	}
+	data := c.MustGet("data").(*Data)
+
+	var form progressForm
+	if err := c.ShouldBindJSON(&form); err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "binding json in save progress")
+		return
+	}
+
+	err = h.Service.Project.SaveProgress(c.Request.Context(), models.WatchProgress{
+		UserID:          data.User.ID,
+		ProjectID:       projectID,
+		PositionSeconds: form.PositionSeconds,
+		Finished:        form.Finished,
+	})
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "saving progress failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "progress saved"})
 }
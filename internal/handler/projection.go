@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"ozinshe/internal/apperr"
+	"ozinshe/internal/models"
+	"ozinshe/internal/storage"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Parts of movies and series that fields and expand can leave out or ask
+// for. Credits are only loaded for a single movie or series.
+var (
+	movieParts        = []string{models.PartGenres, models.PartKeywords, models.PartAgeCategories, models.PartCover, models.PartScreenshots}
+	seriesParts       = append(slices.Clip(movieParts), models.PartSeasons)
+	movieDetailParts  = append(slices.Clip(movieParts), models.PartCredits)
+	seriesDetailParts = append(slices.Clip(seriesParts), models.PartCredits)
+)
+
+// Views of the catalog lists.
+const (
+	viewFull = ""
+	viewCard = "card"
+)
+
+var errInvalidView = apperr.Validation("invalid_view", "view must be card or left out", map[string]string{"view": "must be card"})
+
+// projection is the shape a catalog GET responds with, set by its fields
+// and expand query parameters. The zero projection is the whole resource.
+type projection struct {
+	// fields are the JSON fields sent, nil for all of them.
+	fields map[string]bool
+	// parts are the parts to load, nil for all of them.
+	parts models.Parts
+}
+
+// parseProjection reads the fields and expand query parameters of a GET of
+// resources with the JSON fields names, the first one their ID, and the
+// given parts. Without either, the whole resource is sent. With fields,
+// only the listed fields are sent, and the ID; with expand alone, every
+// field but the parts. Either way the parts listed in expand are sent too.
+// Only the parts sent are loaded.
+func parseProjection(c *gin.Context, names []string, parts []string) (projection, error) {
+	fields, expand := splitList(c.Query("fields")), splitList(c.Query("expand"))
+	if fields == nil && expand == nil {
+		return projection{}, nil
+	}
+
+	for _, name := range fields {
+		if !slices.Contains(names, name) {
+			return projection{}, invalidListItem("fields", "unknown field", name)
+		}
+	}
+	for _, name := range expand {
+		if !slices.Contains(parts, name) {
+			return projection{}, invalidListItem("expand", "unknown part", name)
+		}
+	}
+
+	p := projection{fields: map[string]bool{names[0]: true}, parts: models.Parts{}}
+	if fields == nil {
+		for _, name := range names {
+			if !slices.Contains(parts, name) {
+				p.fields[name] = true
+			}
+		}
+	}
+	for _, name := range append(fields, expand...) {
+		p.fields[name] = true
+		if slices.Contains(parts, name) {
+			p.parts[name] = true
+		}
+	}
+
+	return p, nil
+}
+
+// parseListProjection is parseProjection for a catalog list, which can
+// also be viewed as project cards with view=card. It reports whether it is.
+func parseListProjection(c *gin.Context, names []string, parts []string) (bool, projection, error) {
+	switch c.Query("view") {
+	case viewFull:
+		p, err := parseProjection(c, names, parts)
+		return false, p, err
+	case viewCard:
+		p, err := parseProjection(c, jsonFields(models.ProjectCard{}), nil)
+		return true, p, err
+	default:
+		return false, projection{}, errInvalidView
+	}
+}
+
+// apply leaves out the fields of body, an object or an array of objects,
+// that p does not send.
+func (p projection) apply(body any) (any, error) {
+	if p.fields == nil {
+		return body, nil
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(string(data), "[") {
+		var items []map[string]json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			p.trim(item)
+		}
+		return items, nil
+	}
+
+	var item map[string]json.RawMessage
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil, err
+	}
+	p.trim(item)
+	return item, nil
+}
+
+func (p projection) trim(item map[string]json.RawMessage) {
+	for name := range item {
+		if !p.fields[name] {
+			delete(item, name)
+		}
+	}
+}
+
+// jsonFields returns the names of the JSON fields of the struct models, in
+// the order they first appear.
+func jsonFields(models ...any) []string {
+	var names []string
+	for _, model := range models {
+		t := reflect.TypeOf(model)
+		for i := 0; i < t.NumField(); i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+			if name != "" && name != "-" && !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// splitList splits a comma separated query parameter, nil when it is empty.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// projectedJSON is catalogJSON for body as p shapes it.
+func (h *Handler) projectedJSON(c *gin.Context, modified time.Time, p projection, body any) {
+	body, err := p.apply(body)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "encoding response")
+		return
+	}
+	h.catalogJSON(c, modified, body)
+}
+
+// loadMovie gets the movie with the given ID with only the parts p sends.
+func (h *Handler) loadMovie(ctx context.Context, id int, p projection) (models.Movie, error) {
+	if p.parts == nil {
+		return h.Service.Movie.GetById(ctx, id)
+	}
+
+	movies, err := h.Service.Catalog.LoadMovies(ctx, []int{id}, p.parts)
+	if err != nil {
+		return models.Movie{}, err
+	}
+	movie, ok := movies[id]
+	if !ok {
+		return models.Movie{}, storage.ErrMovieNotFound
+	}
+	return movie, nil
+}
+
+// loadSeries is loadMovie for series.
+func (h *Handler) loadSeries(ctx context.Context, id int, p projection) (models.Series, error) {
+	if p.parts == nil {
+		return h.Service.Series.GetById(ctx, id)
+	}
+
+	series, err := h.Service.Catalog.LoadSeries(ctx, []int{id}, p.parts)
+	if err != nil {
+		return models.Series{}, err
+	}
+	one, ok := series[id]
+	if !ok {
+		return models.Series{}, storage.ErrSeriesNotFound
+	}
+	return one, nil
+}
+
+func invalidListItem(param, message, item string) error {
+	return apperr.Validation("invalid_"+param, message+" "+strconv.Quote(item), map[string]string{param: message + " " + strconv.Quote(item)})
+}
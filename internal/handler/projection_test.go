@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"ozinshe/internal/config"
+	"ozinshe/internal/storage/storagetest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var movieColumns = []string{"id", "title", "release_year", "description", "popularity", "youtube_id", "duration", "director", "producer"}
+
+// movieDB serves movie 1 with one genre.
+func movieDB() *storagetest.DB {
+	return storagetest.New().
+		On("FROM published_projects", []string{"exists"}, []driver.Value{true}).
+		On("SELECT updated_at FROM projects", []string{"updated_at"}, []driver.Value{time.Now()}).
+		On("FROM movies m", movieColumns, []driver.Value{int64(1), "Title", int64(2020), "About", int64(5), "yt", int64(90), "Director", "Producer"}).
+		On("JOIN movie_genres", []string{"movie_id", "id", "name"}, []driver.Value{int64(1), int64(3), "Drama"})
+}
+
+func getJSON(t *testing.T, router http.Handler, target string) map[string]json.RawMessage {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s: status = %d, want %d; body %s", target, rec.Code, http.StatusOK, rec.Body)
+	}
+
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("GET %s: %v", target, err)
+	}
+	return body
+}
+
+func keys(body map[string]json.RawMessage) []string {
+	names := make([]string, 0, len(body))
+	for name := range body {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestProjectionLoadsOnlyExpandedParts(t *testing.T) {
+	// Read only by the loaders of the parts left out.
+	unexpanded := []string{"movie_key_words", "movie_age_categories", "movie_covers", "movie_screenshots", "pe.photo"}
+
+	for _, target := range []string{"/api/v1/movies/1?fields=title&expand=genres", "/api/v1/movies/1?expand=genres"} {
+		t.Run(target, func(t *testing.T) {
+			db := movieDB()
+			router := newTestHandler(t, db, config.HTTPConfig{RequestTimeout: time.Minute}).InitRoutes()
+
+			body := getJSON(t, router, target)
+			if string(body["genres"]) != `[{"id":3,"name":"Drama"}]` {
+				t.Errorf("genres = %s, want the movie's genre", body["genres"])
+			}
+			for _, part := range []string{"keywords", "age_categories", "cover", "screenshots", "credits"} {
+				if _, ok := body[part]; ok {
+					t.Errorf("body has %s, which was not expanded; fields %v", part, keys(body))
+				}
+			}
+			for _, query := range db.Queries() {
+				for _, table := range unexpanded {
+					if strings.Contains(query, table) {
+						t.Errorf("query reads %s, which was not expanded:\n%s", table, query)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestProjectionShapesProjectsAndAges(t *testing.T) {
+	db := movieDB().
+		On("FROM projects WHERE id = $1", []string{"project_type", "project_id", "status", "publish_at", "unpublish_at"}, []driver.Value{"movie", int64(1), "published", nil, nil}).
+		On("FROM catalog_changes", []string{"greatest"}, []driver.Value{time.Now()}).
+		On("FROM age_categories", []string{"id", "min_age", "max_age"}, []driver.Value{int64(2), int64(12), int64(16)})
+	router := newTestHandler(t, db, config.HTTPConfig{RequestTimeout: time.Minute}).InitRoutes()
+
+	body := getJSON(t, router, "/api/v1/projects/7?fields=title")
+	if got := keys(body); !slices.Equal(got, []string{"id", "title"}) {
+		t.Errorf("project fields = %v, want [id title]", got)
+	}
+
+	body = getJSON(t, router, "/api/v1/ages?fields=min_age")
+	var ages []map[string]json.RawMessage
+	if err := json.Unmarshal(body["age_categories"], &ages); err != nil || len(ages) != 1 {
+		t.Fatalf("age_categories = %s, want one age category", body["age_categories"])
+	}
+	if got := keys(ages[0]); !slices.Equal(got, []string{"id", "min_age"}) {
+		t.Errorf("age category fields = %v, want [id min_age]", got)
+	}
+}
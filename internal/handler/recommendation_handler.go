@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get recommendations for the current user
+// @Description Recommends projects similar to the current user's favorites by genres, keywords, people and what other users favorited.
+// @Tags recommendations
+// @Security CookieAuth
+// @Produce json
+// @Param offset query int false "Items to skip"
+// @Param limit query int false "Items per page (default 10, max 50)"
+// @Success 200 {array} models.ProjectCard "Recommended projects"
+// @Failure 400 {object} Problem "Error getting recommendations"
+// @Router /me/recommendations [get]
+func (h *Handler) GetRecommendations(c *gin.Context) {
+	data := c.MustGet("data").(*Data)
+	offset, limit := parsePage(c)
+
+	cards, err := h.Service.Recommendation.ForUser(c.Request.Context(), data.User.ID, offset, limit)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "recommendations getting failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, cards)
+}
+
+// @Summary Get projects similar to a project
+// @Description Retrieves projects similar to the provided project, most similar first.
+// @Tags recommendations
+// @Produce json
+// @Param id path int true "Project ID"
+// @Param offset query int false "Items to skip"
+// @Param limit query int false "Items per page (default 10, max 50)"
+// @Success 200 {array} models.ProjectCard "Similar projects"
+// @Failure 400 {object} Problem "Error getting similar projects"
+// @Router /projects/{id}/similar [get]
+func (h *Handler) GetSimilarProjects(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid project id")
+		return
+	}
+	offset, limit := parsePage(c)
+
+	cards, err := h.Service.Recommendation.Similar(c.Request.Context(), id, offset, limit)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "similar projects getting failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, cards)
+}
@@ -1,9 +1,6 @@
 package handler
 
 import (
-	"database/sql"
-	"encoding/json"
-	"errors"
 	"mime/multipart"
 	"net/http"
 	"ozinshe/internal/models"
@@ -13,33 +10,35 @@ import (
 )
 
 type Series struct {
-	Title       string   `json:"series_title"`
-	Genre       []string `json:"series_genres"`
-	Year        int      `json:"series_year"`
-	Duration    int      `json:"series_duration"`
-	Keywords    []string `json:"series_keywords"` // Consider an array for keywords
+	Title       string   `json:"series_title" validate:"required,max=100"`
+	Genre       []string `json:"series_genres" validate:"dive,required"`
+	Year        int      `json:"series_year" validate:"release_year"`
+	Duration    int      `json:"series_duration" validate:"gt=0"`
+	Keywords    []string `json:"series_keywords" validate:"dive,required"` // Consider an array for keywords
 	Description string   `json:"series_description"`
-	Producer    string   `json:"series_producer"`
-	Director    string   `json:"series_director"`
-	AgeCategory string   `json:"series_age_category"`
-	Seasons     []Season `json:"series_seasons"`
+	AgeCategory string   `json:"series_age_category" validate:"required,age_range"`
+	Seasons     []Season `json:"series_seasons" validate:"dive"`
+	// Credits are the ordered cast and crew, directors and producers
+	// included. Updates leave them as they are when they are left out.
+	Credits []creditForm `json:"series_credits" validate:"dive"`
 }
 
 type Season struct {
-	Episode []Episode `json:"season_episodes"`
+	Episode []Episode `json:"season_episodes" validate:"dive"`
 }
 
 type Episode struct {
-	Link string `json:"episode_link"`
+	Link string `json:"episode_link" validate:"required,youtube_id"`
 }
 
-func (h *Handler) CreateSeries(c *gin.Context, form *multipart.Form, project *models.Project) {
-	seriesJSON := c.PostForm("series_data")
+// CreateSeries adds the series of a new project, and returns its credits,
+// which are set once the project exists.
+func (h *Handler) CreateSeries(c *gin.Context, form *multipart.Form, project *models.Project) []models.Credit {
 	var series Series
-	err := json.Unmarshal([]byte(seriesJSON), &series)
+	err := parseJSON(c.PostForm("series_data"), &series)
 	if err != nil {
 		h.errorpage(c, http.StatusBadRequest, err, "error parsing series JSON")
-		return
+		return nil
 	}
 
 	Genres, AgeCategories, Keywords := ProcessParsing(series.Genre, series.AgeCategory, series.Keywords)
@@ -52,32 +51,33 @@ func (h *Handler) CreateSeries(c *gin.Context, form *multipart.Form, project *mo
 		Genres:        Genres,
 		Keywords:      Keywords,
 		Description:   series.Description,
-		Producer:      series.Producer,
-		Director:      series.Director,
 		AgeCategories: AgeCategories,
 		Seasons:       Seasons,
 	}
 
-	images_data, err := ProcessSavePhoto(form, "series")
+	images_data, err := h.ProcessSavePhoto(form, "series")
 	if err != nil {
 		h.errorpage(c, http.StatusInternalServerError, err, "processing images in series")
-		return
+		return nil
 	}
 
-	id, err := h.Service.Series.Add(series_data, images_data)
+	id, err := h.Service.Series.Add(c.Request.Context(), series_data, images_data)
 	if err != nil {
 		h.errorpage(c, http.StatusInternalServerError, err, "adding series")
-		return
+		return nil
 	}
 
 	project.Project_type = "series"
 	project.Project_id = id
+
+	return toCredits(series.Credits)
 }
 
-func (h *Handler) UpdateSeries(c *gin.Context, form *multipart.Form, seriesID int, updated *bool) {
-	seriesJSON := c.PostForm("series_data")
+func (h *Handler) UpdateSeries(c *gin.Context, form *multipart.Form, project models.Project, updated *bool) {
+	seriesID := project.Project_id
+
 	var series Series
-	err := json.Unmarshal([]byte(seriesJSON), &series)
+	err := parseJSON(c.PostForm("series_data"), &series)
 	if err != nil {
 		h.errorpage(c, http.StatusBadRequest, err, "error parsing series JSON")
 		return
@@ -94,20 +94,19 @@ func (h *Handler) UpdateSeries(c *gin.Context, form *multipart.Form, seriesID in
 		Genres:        Genres,
 		Keywords:      Keywords,
 		Description:   series.Description,
-		Producer:      series.Producer,
-		Director:      series.Director,
 		AgeCategories: AgeCategories,
 		Seasons:       Seasons,
+		Credits:       toCredits(series.Credits),
 	}
 
-	images_data, err := ProcessSavePhoto(form, "series")
+	images_data, err := h.ProcessSavePhoto(form, "series")
 	if err != nil {
 		h.errorpage(c, http.StatusInternalServerError, err, "processing images in series")
 		return
 	}
 
 	if form.File["cover"] != nil {
-		err := h.Service.Series.UpdateCover(seriesID, images_data)
+		err := h.Service.Series.UpdateCover(c.Request.Context(), seriesID, images_data)
 		if err != nil {
 			h.errorpage(c, http.StatusInternalServerError, err, "updating movie cover")
 			return
@@ -115,14 +114,14 @@ func (h *Handler) UpdateSeries(c *gin.Context, form *multipart.Form, seriesID in
 	}
 
 	if form.File["screenshots"] != nil {
-		err := h.Service.Series.UpdateScreenshots(seriesID, images_data)
+		err := h.Service.Series.UpdateScreenshots(c.Request.Context(), seriesID, images_data)
 		if err != nil {
 			h.errorpage(c, http.StatusInternalServerError, err, "updating movie Screenshots")
 			return
 		}
 	}
 
-	err = h.Service.Series.Update(seriesID, series_data)
+	err = h.Service.Series.Update(c.Request.Context(), seriesID, series_data)
 	if err != nil {
 		h.errorpage(c, http.StatusInternalServerError, err, "adding series")
 		return
@@ -134,63 +133,91 @@ func (h *Handler) UpdateSeries(c *gin.Context, form *multipart.Form, seriesID in
 }
 
 // @Summary Get details of a specific series
-// @Description Retrieves details of a series based on the provided ID.
+// @Description Retrieves details of a published series based on the provided ID. Admins can preview unpublished series with preview=true. fields limits the response to the listed fields and the ID; expand alone leaves out every part but the listed ones, which fields can also ask for.
 // @Tags series
 // @Produce json
 // @Param seriesID path string true "Series ID"
+// @Param preview query bool false "Preview an unpublished series (admins only)"
+// @Param fields query string false "Comma separated fields to send, e.g. title,release_year,cover"
+// @Param expand query string false "Comma separated parts to send: genres, keywords, age_categories, cover, screenshots, seasons, credits"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Param If-Modified-Since header string false "Last-Modified of a cached copy"
 // @Success 200 {object} models.Series "Series details"
-// @Failure 400 {object} error"Error getting series"
+// @Header 200 {string} ETag "Hash of the response"
+// @Header 200 {string} Last-Modified "When the resource was last modified"
+// @Success 304 "The cached copy is current"
+// @Failure 400 {object} Problem "Error getting series"
+// @Failure 404 {object} Problem "Series not published"
 // @Router /series/{seriesID} [get]
 func (h *Handler) GetSeries(c *gin.Context) {
 	id := c.Param("seriesID")
 	seriesID, _ := strconv.Atoi(id)
-	series, err := h.Service.Series.GetById(seriesID)
+	if !h.published(c, "series", seriesID) {
+		return
+	}
+
+	p, err := parseProjection(c, jsonFields(models.Series{}), seriesDetailParts)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "series getting failed")
+		h.errorpage(c, http.StatusBadRequest, err, "invalid projection")
 		return
 	}
-	c.JSON(http.StatusOK, series)
+
+	modified, err := h.Service.Catalog.ModifiedAt(c.Request.Context(), models.ResourceSeries, seriesID)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "series modification time getting failed")
+		return
+	}
+
+	series, err := h.loadSeries(c.Request.Context(), seriesID, p)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "series getting failed")
+		return
+	}
+	h.projectedJSON(c, modified, p, series)
 }
 
 // @Summary Get a list of all series
-// @Description Retrieves a list of all series.
+// @Description Retrieves a list of all series. fields and expand shape them as for a single series, except that credits are never sent, and only the parts sent are loaded. With view=card, the compact cards of the series' projects are sent instead, which fields can limit too.
 // @Tags series
 // @Produce json
-// @Success 200 {array} models.Series "List of series"
-// @Failure 400 {object} error "Error getting series"
+// @Param view query string false "card for project cards" Enums(card)
+// @Param fields query string false "Comma separated fields to send, e.g. title,release_year,cover"
+// @Param expand query string false "Comma separated parts to send: genres, keywords, age_categories, cover, screenshots, seasons"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Param If-Modified-Since header string false "Last-Modified of a cached copy"
+// @Success 200 {array} models.Series "List of series, or of models.ProjectCard with view=card"
+// @Header 200 {string} ETag "Hash of the response"
+// @Header 200 {string} Last-Modified "When the resource was last modified"
+// @Success 304 "The cached copy is current"
+// @Failure 400 {object} Problem "Error getting series"
 // @Router /series [get]
 func (h *Handler) GetAllSeries(c *gin.Context) {
-	series, err := h.Service.Series.GetAll()
+	cards, p, err := parseListProjection(c, jsonFields(models.Series{}), seriesParts)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "series getting failed")
+		h.errorpage(c, http.StatusBadRequest, err, "invalid projection")
 		return
 	}
-	c.JSON(http.StatusOK, series)
-}
 
-// @Summary Delete a series
-// @Description Deletes a series based on the provided ID.
-// @Tags series
-// @Produce json
-// @Security ApiKeyAuth
-// @Param id path string true "Series ID"
-// @Success 200 "Series deleted successfully"
-// @Failure 400 {object} error "Invalid parameters"
-// @Failure 500 {object} error "Error deleting series"
-// @Router /series/{id} [delete]
-func (h *Handler) DeleteSeries(c *gin.Context) {
-	id := c.Param("id")
-	seriesID, err := strconv.Atoi(id)
+	modified, err := h.Service.Catalog.ListModifiedAt(c.Request.Context(), models.ResourceSeries)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "invalid parameters")
+		h.errorpage(c, http.StatusInternalServerError, err, "series modification time getting failed")
 		return
 	}
-	err = h.Service.Series.Remove(seriesID)
+
+	var series any
+	switch {
+	case cards:
+		series, err = h.Service.Catalog.GetCards(c.Request.Context(), "series")
+	case p.parts == nil:
+		series, err = h.Service.Series.GetAll(c.Request.Context())
+	default:
+		series, err = h.Service.Catalog.ListSeries(c.Request.Context(), p.parts)
+	}
 	if err != nil {
-		h.errorpage(c, http.StatusInternalServerError, err, "deleting series")
+		h.errorpage(c, http.StatusInternalServerError, err, "series getting failed")
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "Series deleted"})
+	h.projectedJSON(c, modified, p, series)
 }
 
 // @Summary Get episodes of a specific season of a series
@@ -199,10 +226,11 @@ func (h *Handler) DeleteSeries(c *gin.Context) {
 // @Produce json
 // @Param seriesID path string true "Series ID"
 // @Param seasonNumber path string true "Season Number"
+// @Param preview query bool false "Preview an unpublished series (admins only)"
 // @Success 200 {array} models.Episode "List of episodes"
-// @Failure 400 {object} error "Invalid parameters"
-// @Failure 404 {object} error "Season not found"
-// @Failure 500 {object} error "Error getting episodes"
+// @Failure 400 {object} Problem "Invalid parameters"
+// @Failure 404 {object} Problem "Season not found or series not published"
+// @Failure 500 {object} Problem "Error getting episodes"
 // @Router /series/{seriesID}/seasons/{seasonNumber}/episodes [get]
 func (h *Handler) GetSeason(c *gin.Context) {
 	// 1. Extract Parameters
@@ -218,14 +246,14 @@ func (h *Handler) GetSeason(c *gin.Context) {
 		return
 	}
 
+	if !h.published(c, "series", seriesID) {
+		return
+	}
+
 	// 2. Fetch from Database (Adapt based on your storage logic)
-	episodes, err := h.Service.Series.GetSeason(seriesID, seasonNumber)
+	episodes, err := h.Service.Series.GetSeason(c.Request.Context(), seriesID, seasonNumber)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			h.errorpage(c, http.StatusNotFound, err, "season not found")
-		} else {
-			h.errorpage(c, http.StatusInternalServerError, err, "season getting failed")
-		}
+		h.errorpage(c, http.StatusInternalServerError, err, "season getting failed")
 		return
 	}
 
@@ -239,9 +267,11 @@ func (h *Handler) GetSeason(c *gin.Context) {
 // @Param seriesID path string true "Series ID"
 // @Param seasonNumber path string true "Season Number"
 // @Param episodeID path string true "Episode ID"
+// @Param preview query bool false "Preview an unpublished series (admins only)"
 // @Success 200 {object} models.Episode "Episode details"
-// @Failure 400 {object} error "Invalid parameters"
-// @Failure 500 {object} error "Error getting episode"
+// @Failure 400 {object} Problem "Invalid parameters"
+// @Failure 404 {object} Problem "Series not published"
+// @Failure 500 {object} Problem "Error getting episode"
 // @Router /series/{seriesID}/seasons/{seasonNumber}/episodes/{episodeID} [get]
 func (h *Handler) GetEpisode(c *gin.Context) {
 	var err error
@@ -262,7 +292,11 @@ func (h *Handler) GetEpisode(c *gin.Context) {
 		return
 	}
 
-	episode, err := h.Service.Series.GetEpisode(seriesID, seasonNumber, episodeID)
+	if !h.published(c, "series", seriesID) {
+		return
+	}
+
+	episode, err := h.Service.Series.GetEpisode(c.Request.Context(), seriesID, seasonNumber, episodeID)
 	if err != nil {
 		h.errorpage(c, http.StatusInternalServerError, err, "episode getting failed")
 	}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultSyncLimit = 500
+	maxSyncLimit     = 1000
+)
+
+// @Summary Get the changes since the last sync
+// @Description Returns the projects, genres and age categories created, updated and deleted since the sync token, and the favorites of the current user added and removed, for clients keeping an offline copy. Omit since for a full sync. Store the returned token for the next sync, and sync again right away while has_more is true.
+// @Tags sync
+// @Produce json
+// @Param since query string false "Token returned by the previous sync"
+// @Param limit query int false "Changes to read at most (default 500, max 1000)"
+// @Success 200 {object} models.Sync "Changes since the token"
+// @Failure 400 {object} Problem "Invalid sync token"
+// @Failure 500 {object} Problem "Error getting changes"
+// @Router /sync [get]
+func (h *Handler) GetSync(c *gin.Context) {
+	data := c.MustGet("data").(*Data)
+
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultSyncLimit
+	}
+	limit = min(limit, maxSyncLimit)
+
+	var userID int
+	if data.IsAuthorized {
+		userID = data.User.ID
+	}
+
+	sync, err := h.Service.Sync.Changes(c.Request.Context(), c.Query("since"), userID, limit)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "sync failed")
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.JSON(http.StatusOK, sync)
+}
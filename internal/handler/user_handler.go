@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"errors"
 	"net/http"
 	"ozinshe/internal/models"
 	"strconv"
@@ -11,15 +10,15 @@ import (
 )
 
 type NewPassword struct {
-	CurrentPassword string `json:"current_password"`
-	NewPassword     string `json:"new_password"`
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,password"`
 }
 
 type ProfileData struct {
-	Name        string    `json:"name"`
-	Number      string    `json:"number"`
+	Name        string    `json:"name" validate:"required,min=2,max=50"`
+	Number      string    `json:"number" validate:"required,max=20"`
 	Password    string    `json:"password"`
-	DateOfBirth time.Time `json:"date_of_birth"`
+	DateOfBirth time.Time `json:"date_of_birth" validate:"required"`
 }
 
 // @Summary Get a list of all users
@@ -28,12 +27,12 @@ type ProfileData struct {
 // @Security CookieAuth
 // @Produce json
 // @Success 200 {array} models.User "List of users"
-// @Failure 400 {object} error "Error getting users"
+// @Failure 400 {object} Problem "Error getting users"
 // @Router /users [get]
 func (h *Handler) GetAllUsers(c *gin.Context) {
-	users, err := h.Service.User.GetAll()
+	users, err := h.Service.User.GetAll(c.Request.Context())
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "users getting failed")
+		h.errorpage(c, http.StatusInternalServerError, err, "users getting failed")
 		return
 	}
 	c.JSON(http.StatusOK, users)
@@ -46,13 +45,13 @@ func (h *Handler) GetAllUsers(c *gin.Context) {
 // @Produce json
 // @Param id path int true "User ID"
 // @Success 200 {object} models.User "User details"
-// @Failure 400 {object} error "Error getting user"
+// @Failure 400 {object} Problem "Error getting user"
 // @Router /user/{id} [get]
 func (h *Handler) GetUser(c *gin.Context) {
 	id := c.Param("id")
-	user, err := h.Service.User.GetById(id)
+	user, err := h.Service.User.GetById(c.Request.Context(), id)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "user getting failed")
+		h.errorpage(c, http.StatusInternalServerError, err, "user getting failed")
 		return
 	}
 	c.JSON(http.StatusOK, user)
@@ -66,7 +65,7 @@ func (h *Handler) GetUser(c *gin.Context) {
 // @Produce json
 // @Param request body NewPassword true "New password information"
 // @Success 200 "Password changed successfully"
-// @Failure 400 {object} error "Error changing password"
+// @Failure 400 {object} Problem "Error changing password"
 // @Router /change-password [post]
 func (h *Handler) ChangePassword(c *gin.Context) {
 	data := c.MustGet("data").(*Data)
@@ -80,9 +79,9 @@ func (h *Handler) ChangePassword(c *gin.Context) {
 		return
 	}
 
-	err := h.Service.User.UpdatePassword(data.User.Email, form.CurrentPassword, form.NewPassword)
+	err := h.Service.User.UpdatePassword(c.Request.Context(), data.User.Email, form.CurrentPassword, form.NewPassword)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "changing password")
+		h.errorpage(c, http.StatusInternalServerError, err, "changing password")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "password changed"})
@@ -96,7 +95,7 @@ func (h *Handler) ChangePassword(c *gin.Context) {
 // @Produce json
 // @Param request body ProfileData true "New profile information"
 // @Success 200 "Profile changed successfully"
-// @Failure 400 {object} error "Error changing profile"
+// @Failure 400 {object} Problem "Error changing profile"
 // @Router /change-profile [post]
 func (h *Handler) ChangeProfile(c *gin.Context) {
 	data := c.MustGet("data").(*Data)
@@ -110,11 +109,6 @@ func (h *Handler) ChangeProfile(c *gin.Context) {
 		return
 	}
 
-	if form.Name == "" || form.Number == "" || form.DateOfBirth.IsZero() {
-		h.errorpage(c, http.StatusBadRequest, errors.New("name, number and date of birth are required"), "changing profile")
-		return
-	}
-
 	user := models.User{
 		Email:       data.User.Email,
 		Password:    form.Password,
@@ -123,9 +117,9 @@ func (h *Handler) ChangeProfile(c *gin.Context) {
 		DateOfBirth: form.DateOfBirth,
 	}
 
-	err := h.Service.User.UpdateProfile(user)
+	err := h.Service.User.UpdateProfile(c.Request.Context(), user)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "changing profile")
+		h.errorpage(c, http.StatusInternalServerError, err, "changing profile")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "profile changed"})
@@ -134,11 +128,11 @@ func (h *Handler) ChangeProfile(c *gin.Context) {
 // @Summary Delete user
 // @Description Deletes a user based on the provided ID. Requires admin authorization.
 // @Tags users
-// @Security ApiKeyAuth
+// @Security CookieAuth
 // @Param id path int true "User ID"
 // @Produce json
 // @Success 200 "User deleted successfully"
-// @Failure 400 {object} error "Error deleting user"
+// @Failure 400 {object} Problem "Error deleting user"
 // @Router /user/{id} [delete]
 func (h *Handler) DeleteUser(c *gin.Context) {
 	data := c.MustGet("data").(*Data)
@@ -152,9 +146,9 @@ func (h *Handler) DeleteUser(c *gin.Context) {
 		return
 	}
 
-	err = h.Service.User.Remove(id)
+	err = h.Service.User.Remove(c.Request.Context(), id)
 	if err != nil {
-		h.errorpage(c, http.StatusBadRequest, err, "deleting user")
+		h.errorpage(c, http.StatusInternalServerError, err, "deleting user")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "user deleted"})
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"net/http"
+	"ozinshe/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type webhookForm struct {
+	URL    string   `json:"url" validate:"required,http_url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events" validate:"required,min=1,dive,required"`
+}
+
+// @Summary Get a list of all webhooks
+// @Description Retrieves the registered webhooks without their secrets. Requires admin authorization.
+// @Tags webhooks
+// @Security CookieAuth
+// @Produce json
+// @Success 200 {array} models.Webhook "List of webhooks"
+// @Failure 400 {object} Problem "Error getting webhooks"
+// @Router /admin/webhooks [get]
+func (h *Handler) GetAllWebhooks(c *gin.Context) {
+	hooks, err := h.Service.Webhook.GetAll(c.Request.Context())
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "webhooks getting failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, hooks)
+}
+
+// @Summary Register a webhook
+// @Description Registers an endpoint receiving the subscribed events. Deliveries are signed with the secret, which is generated when omitted and only returned here. Requires admin authorization.
+// @Tags webhooks
+// @Security CookieAuth
+// @Accept json
+// @Produce json
+// @Param webhook body webhookForm true "Webhook"
+// @Success 200 {object} models.Webhook "Registered webhook"
+// @Failure 400 {object} Problem "Error registering webhook"
+// @Router /admin/webhooks [post]
+func (h *Handler) CreateWebhook(c *gin.Context) {
+	var form webhookForm
+	if err := c.ShouldBindJSON(&form); err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "binding json in create webhook")
+		return
+	}
+
+	hook, err := h.Service.Webhook.Add(c.Request.Context(), models.Webhook{
+		URL:    form.URL,
+		Secret: form.Secret,
+		Events: form.Events,
+	})
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "webhook registration failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, hook)
+}
+
+// @Summary Delete a webhook
+// @Description Deletes a webhook with its delivery log. Requires admin authorization.
+// @Tags webhooks
+// @Security CookieAuth
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 "Webhook deleted"
+// @Failure 400 {object} Problem "Error deleting webhook"
+// @Router /admin/webhooks/{id} [delete]
+func (h *Handler) DeleteWebhook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid webhook id")
+		return
+	}
+
+	err = h.Service.Webhook.Remove(c.Request.Context(), id)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "webhook deleting failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted"})
+}
+
+// @Summary Ping a webhook
+// @Description Queues a signed ping event to the webhook to test its receiver. Requires admin authorization.
+// @Tags webhooks
+// @Security CookieAuth
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 "Ping queued"
+// @Failure 400 {object} Problem "Error pinging webhook"
+// @Failure 404 {object} Problem "Webhook not found"
+// @Router /admin/webhooks/{id}/ping [post]
+func (h *Handler) PingWebhook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid webhook id")
+		return
+	}
+
+	deliveryID, err := h.Service.Webhook.Ping(c.Request.Context(), id)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "webhook pinging failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Ping queued", "delivery_id": deliveryID})
+}
+
+// @Summary Get deliveries of a webhook
+// @Description Retrieves the delivery log of a webhook, newest first. Requires admin authorization.
+// @Tags webhooks
+// @Security CookieAuth
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param offset query int false "Items to skip"
+// @Param limit query int false "Items per page (default 10, max 50)"
+// @Success 200 {array} models.WebhookDelivery "Deliveries"
+// @Failure 400 {object} Problem "Error getting deliveries"
+// @Failure 404 {object} Problem "Webhook not found"
+// @Router /admin/webhooks/{id}/deliveries [get]
+func (h *Handler) GetWebhookDeliveries(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid webhook id")
+		return
+	}
+	offset, limit := parsePage(c)
+
+	deliveries, err := h.Service.Webhook.Deliveries(c.Request.Context(), id, offset, limit)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "webhook deliveries getting failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// @Summary Redeliver a webhook delivery
+// @Description Queues a delivery to be sent again right away with a fresh set of retries. Requires admin authorization.
+// @Tags webhooks
+// @Security CookieAuth
+// @Produce json
+// @Param id path int true "Delivery ID"
+// @Success 200 "Redelivery queued"
+// @Failure 400 {object} Problem "Error queuing redelivery"
+// @Failure 404 {object} Problem "Delivery not found"
+// @Router /admin/webhook-deliveries/{id}/redeliver [post]
+func (h *Handler) RedeliverWebhook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		h.errorpage(c, http.StatusBadRequest, err, "invalid delivery id")
+		return
+	}
+
+	err = h.Service.Webhook.Redeliver(c.Request.Context(), id)
+	if err != nil {
+		h.errorpage(c, http.StatusInternalServerError, err, "webhook redelivery failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Redelivery queued"})
+}
@@ -6,8 +6,23 @@ import (
 	"mime/multipart"
 	"os"
 	"path/filepath"
+	"strconv"
 )
 
+// uploadDir is where uploaded images are kept; see SetUploadDir.
+var uploadDir = "internal/uploads"
+
+// SetUploadDir sets the directory uploads are kept in, relative to the
+// working directory unless absolute. It must be called once on start.
+func SetUploadDir(dir string) {
+	uploadDir = dir
+}
+
+// UploadPath returns the path of elem inside the uploads directory.
+func UploadPath(elem ...string) string {
+	return filepath.Join(append([]string{uploadDir}, elem...)...)
+}
+
 func ProcessSaving(file *multipart.FileHeader, dst string) error {
 	src, err := file.Open()
 	if err != nil {
@@ -29,17 +44,49 @@ func ProcessSaving(file *multipart.FileHeader, dst string) error {
 	return err
 }
 
+// DeleteDirectory removes a directory of the uploads directory, such as
+// "movies/1", with everything in it.
 func DeleteDirectory(DirectoryPath string) error {
-	currentDir, err := os.Getwd()
+	err := os.RemoveAll(UploadPath(DirectoryPath))
+	if err != nil {
+		return fmt.Errorf("error deleting directory '%s': %v", DirectoryPath, err)
+	}
+	return nil
+}
+
+// UploadIDs returns the ids of the per-record directories in a directory of
+// the uploads directory, such as "movies". A missing directory has none.
+func UploadIDs(DirectoryPath string) ([]int, error) {
+	entries, err := os.ReadDir(UploadPath(DirectoryPath))
 	if err != nil {
-		return fmt.Errorf("error getting current dir: %v", err)
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading directory '%s': %v", DirectoryPath, err)
 	}
 
-	DirectoryPath = currentDir + "/internal/" + DirectoryPath
+	var ids []int
+	for _, entry := range entries {
+		if id, err := strconv.Atoi(entry.Name()); err == nil && entry.IsDir() {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
 
-	err = os.RemoveAll(DirectoryPath)
+// CheckWritable makes sure that files can be created in a directory of the
+// uploads directory, creating the directory if it is missing.
+func CheckWritable(DirectoryPath string) error {
+	dir := UploadPath(DirectoryPath)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("error creating directory '%s': %v", DirectoryPath, err)
+	}
+
+	file, err := os.CreateTemp(dir, ".writable-*")
 	if err != nil {
-		return fmt.Errorf("error deleting directory '%s': %v", DirectoryPath, err)
+		return fmt.Errorf("error writing to directory '%s': %v", DirectoryPath, err)
 	}
-	return nil
+	file.Close()
+
+	return os.Remove(file.Name())
 }
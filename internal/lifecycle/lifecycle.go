@@ -0,0 +1,173 @@
+// Package lifecycle starts the parts of the application together and stops
+// them in order when the process is signalled.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"ozinshe/internal/config"
+	"ozinshe/internal/config/lib/logger/sl"
+	"sync"
+	"syscall"
+	"time"
+)
+
+type server struct {
+	name     string
+	serve    func() error
+	shutdown func(context.Context) error
+}
+
+type worker struct {
+	name string
+	run  func(ctx context.Context)
+}
+
+type closer struct {
+	name  string
+	close func(context.Context) error
+}
+
+// Manager runs servers and background workers until SIGTERM or SIGINT, or
+// until a server fails. It then stops them in order:
+//
+//  1. the drain hooks run and the servers keep serving for the drain delay,
+//     so that load balancers see the server is not ready any more;
+//  2. the servers stop accepting connections and finish the requests in
+//     flight;
+//  3. the workers are asked to stop and waited for;
+//  4. the closers run, last added first.
+//
+// Steps 2 to 4 share the shutdown timeout. Rounds of work the workers have
+// not finished by then are abandoned; the job and delivery leases make sure
+// they are picked up again.
+type Manager struct {
+	cfg config.ShutdownConfig
+	log *slog.Logger
+
+	drain   []func()
+	servers []server
+	workers []worker
+	closers []closer
+}
+
+func New(cfg config.ShutdownConfig, log *slog.Logger) *Manager {
+	return &Manager{cfg: cfg, log: log}
+}
+
+// Serve adds a server. serve blocks until the server stops, and returning
+// http.ErrServerClosed after shutdown is not a failure.
+func (m *Manager) Serve(name string, serve func() error, shutdown func(context.Context) error) {
+	m.servers = append(m.servers, server{name: name, serve: serve, shutdown: shutdown})
+}
+
+// Go adds a background worker. run must return soon after ctx is done.
+func (m *Manager) Go(name string, run func(ctx context.Context)) {
+	m.workers = append(m.workers, worker{name: name, run: run})
+}
+
+// OnDrain adds a hook run as soon as shutdown starts.
+func (m *Manager) OnDrain(fn func()) {
+	m.drain = append(m.drain, fn)
+}
+
+// Close adds a resource released once the servers and workers stopped.
+func (m *Manager) Close(name string, close func(context.Context) error) {
+	m.closers = append(m.closers, closer{name: name, close: close})
+}
+
+// Run starts everything and blocks until it is all stopped again. It
+// returns the error of the failed server, or of a step of the shutdown
+// that failed or ran out of time, and nil after a clean shutdown.
+func (m *Manager) Run(ctx context.Context) error {
+	const op = "lifecycle.Run"
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(signals)
+
+	workCtx, stopWorkers := context.WithCancel(context.WithoutCancel(ctx))
+	defer stopWorkers()
+
+	var workers sync.WaitGroup
+	for _, w := range m.workers {
+		workers.Add(1)
+		go func(w worker) {
+			defer workers.Done()
+			w.run(workCtx)
+			m.log.Debug("worker stopped", slog.String("worker", w.name))
+		}(w)
+	}
+
+	failed := make(chan error, len(m.servers))
+	for _, s := range m.servers {
+		go func(s server) {
+			if err := s.serve(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				failed <- fmt.Errorf("%s: %w", s.name, err)
+			}
+		}(s)
+	}
+
+	m.log.Info("application started", slog.Int("servers", len(m.servers)), slog.Int("workers", len(m.workers)))
+
+	var runErr error
+	select {
+	case sig := <-signals:
+		m.log.Info("stopping application", slog.String("signal", sig.String()))
+	case <-ctx.Done():
+		m.log.Info("stopping application", sl.Err(ctx.Err()))
+	case runErr = <-failed:
+		m.log.Error("stopping application", sl.Err(runErr))
+	}
+	// A second signal kills the process without waiting for the shutdown.
+	signal.Stop(signals)
+
+	for _, fn := range m.drain {
+		fn()
+	}
+	if runErr == nil && m.cfg.DrainDelay > 0 {
+		time.Sleep(m.cfg.DrainDelay)
+	}
+
+	errs := []error{runErr}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), m.cfg.Timeout)
+	defer cancel()
+
+	for _, s := range m.servers {
+		if err := s.shutdown(shutdownCtx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: shutdown: %w", s.name, err))
+		}
+	}
+
+	stopWorkers()
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-shutdownCtx.Done():
+		errs = append(errs, errors.New("workers: still running at shutdown timeout"))
+	}
+
+	for i := len(m.closers) - 1; i >= 0; i-- {
+		if err := m.closers[i].close(shutdownCtx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: close: %w", m.closers[i].name, err))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	m.log.Info("application stopped")
+
+	return nil
+}
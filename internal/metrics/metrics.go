@@ -0,0 +1,96 @@
+// Package metrics holds the Prometheus metrics of the server and the
+// registry they are served from.
+package metrics
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "ozinshe"
+
+// Registry holds every metric served on /metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Duration of HTTP requests by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	StorageDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "storage_operation_duration_seconds",
+		Help:      "Duration of storage operations by op name.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"op"})
+
+	Signups = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "signups_total",
+		Help:      "Users registered.",
+	})
+
+	Logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Sign-in attempts by result (success or failure).",
+	}, []string{"result"})
+
+	Favorites = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "favorites_total",
+		Help:      "Projects added to Favorites lists.",
+	})
+
+	Uploads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "uploads_total",
+		Help:      "Images uploaded by kind (cover, screenshot, photo or banner).",
+	}, []string{"kind"})
+
+	CacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Reads of the catalog cache by cache (memory or redis) and result (hit or miss).",
+	}, []string{"cache", "result"})
+
+	CacheErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_errors_total",
+		Help:      "Failed operations of the catalog cache by cache and operation (get, set or delete).",
+	}, []string{"cache", "op"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestDuration,
+		StorageDuration,
+		Signups,
+		Logins,
+		Favorites,
+		Uploads,
+		CacheRequests,
+		CacheErrors,
+	)
+}
+
+// RegisterDB exports the connection pool stats of db.
+func RegisterDB(db *sql.DB) {
+	Registry.MustRegister(collectors.NewDBStatsCollector(db, "postgres"))
+}
+
+// ObserveStorage records the duration of the storage operation op started
+// at start. It is meant to be deferred:
+//
+//	defer metrics.ObserveStorage(op, time.Now())
+func ObserveStorage(op string, start time.Time) {
+	StorageDuration.WithLabelValues(op).Observe(time.Since(start).Seconds())
+}
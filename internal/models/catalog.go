@@ -0,0 +1,33 @@
+package models
+
+// Catalog resources whose modification times are tracked, for conditional
+// requests, and whose changes are logged, for sync. Movies and series are
+// logged as projects; favorites are logged for each user.
+const (
+	ResourceMovies        = "movies"
+	ResourceSeries        = "series"
+	ResourceProjects      = "projects"
+	ResourceGenres        = "genres"
+	ResourceAgeCategories = "age_categories"
+	ResourceFavorites     = "favorites"
+)
+
+// Parts of movies and series, named as in their JSON, that clients can
+// leave out to load and send less. Credits are only loaded for a single
+// movie or series.
+const (
+	PartGenres        = "genres"
+	PartKeywords      = "keywords"
+	PartAgeCategories = "age_categories"
+	PartCover         = "cover"
+	PartScreenshots   = "screenshots"
+	PartSeasons       = "seasons"
+	PartCredits       = "credits"
+)
+
+// Parts is a set of parts to load. The nil Parts has all of them.
+type Parts map[string]bool
+
+func (p Parts) Has(part string) bool {
+	return p == nil || p[part]
+}
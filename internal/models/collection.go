@@ -0,0 +1,52 @@
+package models
+
+const (
+	RowCollection       = "collection"
+	RowTrending         = "trending"
+	RowNewReleases      = "new-releases"
+	RowContinueWatching = "continue-watching"
+	RowFavoriteGenre    = "favorite-genre"
+)
+
+// ProjectCard is the compact shape of a project used by list screens.
+type ProjectCard struct {
+	ProjectID   int    `json:"project_id"`
+	ProjectType string `json:"project_type"`
+	Title       string `json:"title"`
+	ReleaseYear int    `json:"release_year"`
+	Popularity  int    `json:"popularity"`
+	Cover       string `json:"cover"`
+}
+
+type Collection struct {
+	ID       int           `json:"id"`
+	Title    string        `json:"title"`
+	Slug     string        `json:"slug"`
+	Banner   string        `json:"banner"`
+	Position int           `json:"position"`
+	Projects []ProjectCard `json:"projects,omitempty"`
+}
+
+type HomeRow struct {
+	Key     string        `json:"key"`
+	Kind    string        `json:"kind"`
+	Title   string        `json:"title"`
+	Banner  string        `json:"banner,omitempty"`
+	Items   []ProjectCard `json:"items"`
+	Offset  int           `json:"offset"`
+	Limit   int           `json:"limit"`
+	HasMore bool          `json:"has_more"`
+}
+
+type WatchProgress struct {
+	UserID          int  `json:"user_id"`
+	ProjectID       int  `json:"project_id"`
+	PositionSeconds int  `json:"position_seconds"`
+	Finished        bool `json:"finished"`
+}
+
+type Similarity struct {
+	ProjectID        int     `json:"project_id"`
+	SimilarProjectID int     `json:"similar_project_id"`
+	Score            float64 `json:"score"`
+}
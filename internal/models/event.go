@@ -0,0 +1,54 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Domain events recorded in the outbox.
+const (
+	EventProjectCreated   = "project.created"
+	EventProjectUpdated   = "project.updated"
+	EventProjectDeleted   = "project.deleted"
+	EventProjectPublished = "project.published"
+	EventEpisodesAdded    = "series.episodes_added"
+	EventUserRegistered   = "user.registered"
+	EventFavoriteAdded    = "favorite.added"
+	EventFavoriteRemoved  = "favorite.removed"
+)
+
+// Event is a domain event. Payload holds one of the *Event structs below,
+// depending on Type.
+type Event struct {
+	ID         int64           `json:"id"`
+	Type       string          `json:"type"`
+	Payload    json.RawMessage `json:"payload" swaggertype:"object"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+// Decode unmarshals the payload into v.
+func (e Event) Decode(v any) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+type ProjectEvent struct {
+	ProjectID   int    `json:"project_id"`
+	ProjectType string `json:"project_type"`
+	ContentID   int    `json:"content_id"`
+}
+
+type EpisodesEvent struct {
+	ProjectID int `json:"project_id"`
+	SeriesID  int `json:"series_id"`
+	Count     int `json:"count"`
+}
+
+type UserEvent struct {
+	UserID int    `json:"user_id"`
+	Email  string `json:"email"`
+}
+
+type FavoriteEvent struct {
+	UserID    int `json:"user_id"`
+	ProjectID int `json:"project_id"`
+}
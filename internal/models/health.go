@@ -0,0 +1,22 @@
+package models
+
+const (
+	Ready        = "ready"
+	NotReady     = "not_ready"
+	ShuttingDown = "shutting_down"
+
+	CheckOK   = "ok"
+	CheckFail = "fail"
+)
+
+// Readiness is the outcome of the readiness checks. Status is Ready only
+// when every check passed and the server is not shutting down.
+type Readiness struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+type CheckResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobDead      = "dead"
+)
+
+// Job is a unit of background work. Failed jobs are queued again with
+// backoff until MaxAttempts is reached, after which they are dead and only
+// run again when retried by an admin.
+type Job struct {
+	ID          int64           `json:"id"`
+	Kind        string          `json:"kind"`
+	Payload     json.RawMessage `json:"payload" swaggertype:"object"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	LastError   string          `json:"last_error"`
+	RunAt       time.Time       `json:"run_at"`
+	CreatedAt   time.Time       `json:"created_at"`
+	FinishedAt  *time.Time      `json:"finished_at"`
+}
+
+// JobSchedule enqueues a job of Kind whenever its cron Spec comes due.
+type JobSchedule struct {
+	Name      string          `json:"name"`
+	Spec      string          `json:"spec"`
+	Kind      string          `json:"kind"`
+	Payload   json.RawMessage `json:"payload" swaggertype:"object"`
+	NextRunAt time.Time       `json:"next_run_at"`
+	LastRunAt *time.Time      `json:"last_run_at"`
+}
+
+type JobFilter struct {
+	Status string
+	Kind   string
+	Offset int
+	Limit  int
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+const (
+	ListFavorites  = "favorites"
+	ListWatchLater = "watch_later"
+	ListCustom     = "custom"
+)
+
+// List is an ordered set of projects owned by a user. Every user has a
+// built-in Favorites and Watch Later list besides any number of custom ones.
+type List struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"user_id"`
+	Name       string     `json:"name"`
+	Kind       string     `json:"kind"`
+	IsPublic   bool       `json:"is_public"`
+	ShareToken string     `json:"share_token,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Items      []ListItem `json:"items,omitempty"`
+}
+
+type ListItem struct {
+	ProjectID int         `json:"project_id"`
+	Position  int         `json:"position"`
+	Note      string      `json:"note"`
+	AddedAt   time.Time   `json:"added_at"`
+	Project   ProjectCard `json:"project"`
+}
+
+// IsBuiltin reports whether the list is created by the system rather than the user.
+func (l List) IsBuiltin() bool {
+	return l.Kind != ListCustom
+}
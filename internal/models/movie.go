@@ -1,5 +1,7 @@
 package models
 
+// The Director and Producer of a Movie name the people credited as such,
+// comma separated in billing order. They are read only: Credits sets them.
 type Movie struct {
 	ID            int           `json:"id"`
 	Title         string        `json:"title"`
@@ -15,10 +17,5 @@ type Movie struct {
 	AgeCategories []AgeCategory `json:"age_categories"`
 	Screenshots   []Screenshot  `json:"screenshots"`
 	Cover         Cover         `json:"cover"`
-}
-
-type FavoriteMovie struct {
-	ID      int `json:"id"`
-	UserID  int `json:"user_id"`
-	MovieID int `json:"movie_id"`
+	Credits       []Credit      `json:"credits,omitempty"`
 }
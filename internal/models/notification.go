@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+const (
+	NotificationNewEpisodes = "new_episodes"
+	NotificationNewRelease  = "new_release"
+)
+
+type Notification struct {
+	ID        int        `json:"id"`
+	UserID    int        `json:"user_id"`
+	Kind      string     `json:"kind"`
+	ProjectID int        `json:"project_id,omitempty"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Inbox is one page of a user's notifications.
+type Inbox struct {
+	Unread        int            `json:"unread"`
+	Notifications []Notification `json:"notifications"`
+}
+
+type NotificationPreferences struct {
+	NewEpisodes bool `json:"new_episodes"`
+	NewReleases bool `json:"new_releases"`
+}
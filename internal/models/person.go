@@ -0,0 +1,44 @@
+package models
+
+const (
+	RoleDirector = "director"
+	RoleProducer = "producer"
+	RoleActor    = "actor"
+	RoleWriter   = "writer"
+)
+
+type Person struct {
+	ID          int           `json:"id"`
+	Name        string        `json:"name"`
+	Bio         string        `json:"bio"`
+	Photo       string        `json:"photo"`
+	Filmography []Filmography `json:"filmography,omitempty"`
+}
+
+type Credit struct {
+	ID          int    `json:"id"`
+	PersonID    int    `json:"person_id"`
+	PersonName  string `json:"person_name"`
+	PersonPhoto string `json:"person_photo"`
+	ProjectID   int    `json:"project_id"`
+	Role        string `json:"role"`
+	Character   string `json:"character,omitempty"`
+	Position    int    `json:"position"`
+}
+
+type Filmography struct {
+	ProjectID   int    `json:"project_id"`
+	ProjectType string `json:"project_type"`
+	Title       string `json:"title"`
+	ReleaseYear int    `json:"release_year"`
+	Role        string `json:"role"`
+	Character   string `json:"character,omitempty"`
+}
+
+func IsValidRole(role string) bool {
+	switch role {
+	case RoleDirector, RoleProducer, RoleActor, RoleWriter:
+		return true
+	}
+	return false
+}
@@ -46,17 +46,21 @@ type Project struct {
 	Id           int    `json:"id"`
 	Project_type string `json:"project_type"`
 	Project_id   int    `json:"project_id"`
-	Movies       []Movie
-	Series       []Series
+	Publication
+	Movies []Movie
+	Series []Series
+	// Credits are written along with a new project.
+	Credits []Credit `json:"-"`
 }
 
 type FilterParams struct {
-	Project_type    string   `form:"project_type"`
+	Project_type    string   `form:"project_type" validate:"omitempty,oneof=movie series"`
 	Genres          []string `form:"genres"`
-	Age             []string `form:"age"`
+	Age             []string `form:"age" validate:"dive,age_range"`
 	Title           string   `form:"title"`
-	YearStart       int      `form:"year_start"`
-	YearEnd         int      `form:"year_end"`
-	YearOrder       string   `form:"year_order"`
-	PopularityOrder string   `form:"popularity_order"`
+	YearStart       int      `form:"year_start" validate:"omitempty,release_year"`
+	YearEnd         int      `form:"year_end" validate:"omitempty,release_year"`
+	YearOrder       string   `form:"year_order" validate:"omitempty,oneof=asc desc"`
+	PopularityOrder string   `form:"popularity_order" validate:"omitempty,oneof=asc desc"`
+	PersonID        int      `form:"person_id" validate:"gte=0"`
 }
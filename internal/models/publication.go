@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Statuses of the publishing workflow. Only published projects, and
+// scheduled ones whose publish time has passed, are shown to the public.
+const (
+	ProjectDraft     = "draft"
+	ProjectInReview  = "review"
+	ProjectScheduled = "scheduled"
+	ProjectPublished = "published"
+)
+
+// Publication is where a project is in the publishing workflow. PublishAt is
+// when a scheduled project goes live; UnpublishAt, when set, is when the
+// project is hidden again, such as when its license expires.
+type Publication struct {
+	Status      string     `json:"status"`
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
+}
+
+// Visible reports whether the public can see the project at t.
+func (p Publication) Visible(t time.Time) bool {
+	live := p.Status == ProjectPublished ||
+		(p.Status == ProjectScheduled && p.PublishAt != nil && !p.PublishAt.After(t))
+
+	return live && (p.UnpublishAt == nil || p.UnpublishAt.After(t))
+}
+
+// PublicationCard is a project card along with its publication, as listed
+// to admins.
+type PublicationCard struct {
+	ProjectCard
+	Publication
+}
@@ -1,5 +1,7 @@
 package models
 
+// The Director and Producer of a Series name the people credited as such,
+// comma separated in billing order. They are read only: Credits sets them.
 type Series struct {
 	ID            int           `json:"id"`
 	Title         string        `json:"title"`
@@ -15,6 +17,7 @@ type Series struct {
 	Screenshots   []Screenshot  `json:"screenshots"`
 	AgeCategories []AgeCategory `json:"age_categories"`
 	Seasons       []Season      `json:"seasons"`
+	Credits       []Credit      `json:"credits,omitempty"`
 }
 
 type Season struct {
@@ -30,9 +33,3 @@ type Episode struct {
 	EpisodeNumber int    `json:"episode_number"`
 	Link          string `json:"link"`
 }
-
-type FavoriteSeries struct {
-	ID      int `json:"id"`
-	UserID  int `json:"user_id"`
-	SeriesID int `json:"movie_id"`
-}
@@ -0,0 +1,79 @@
+package models
+
+import "time"
+
+// Operations recorded in the sync log.
+const (
+	SyncInsert = "insert"
+	SyncUpdate = "update"
+	SyncDelete = "delete"
+)
+
+// SyncPosition is how far a client has synced: up to the log entry Seq of
+// the transaction Txid, and up to Time for the projects that go live or are
+// hidden when their publish window opens or closes, which is not logged.
+type SyncPosition struct {
+	Txid uint64
+	Seq  int64
+	Time time.Time
+}
+
+// SyncChange is an entry of the sync log: Op was applied to the resource
+// with ID.
+type SyncChange struct {
+	Resource string
+	ID       int
+	Op       string
+}
+
+// SyncLog is a page of the sync log. Next is where the following page
+// starts; More reports whether it already has entries.
+type SyncLog struct {
+	Changes []SyncChange
+	Next    SyncPosition
+	More    bool
+}
+
+// Sync is what changed since a sync token. Created and updated resources
+// are sent whole; deleted ones, including projects that are no longer
+// published, by ID only. Favorites are only sent to signed in users.
+type Sync struct {
+	Token         string             `json:"token"`
+	HasMore       bool               `json:"has_more"`
+	Projects      ProjectChanges     `json:"projects"`
+	Genres        GenreChanges       `json:"genres"`
+	AgeCategories AgeCategoryChanges `json:"age_categories"`
+	Favorites     *FavoriteChanges   `json:"favorites,omitempty"`
+}
+
+// SyncProject is a project along with the movie or series it is.
+type SyncProject struct {
+	ID     int     `json:"id"`
+	Type   string  `json:"type"`
+	Movie  *Movie  `json:"movie,omitempty"`
+	Series *Series `json:"series,omitempty"`
+}
+
+type ProjectChanges struct {
+	Created []SyncProject `json:"created"`
+	Updated []SyncProject `json:"updated"`
+	Deleted []int         `json:"deleted"`
+}
+
+type GenreChanges struct {
+	Created []Genre `json:"created"`
+	Updated []Genre `json:"updated"`
+	Deleted []int   `json:"deleted"`
+}
+
+type AgeCategoryChanges struct {
+	Created []AgeCategory `json:"created"`
+	Updated []AgeCategory `json:"updated"`
+	Deleted []int         `json:"deleted"`
+}
+
+// FavoriteChanges are the IDs of the projects favorited and unfavorited.
+type FavoriteChanges struct {
+	Created []int `json:"created"`
+	Deleted []int `json:"deleted"`
+}
@@ -4,12 +4,12 @@ import "time"
 
 type User struct {
 	ID            int       `json:"id,omitempty"`
-	Name          string    `json:"name,omitempty" validate:"required,min=2,max=50"`
-	Email         string    `json:"email,omitempty" validate:"required,email"`
-	Number        string    `json:"number,omitempty" validate:"required"`
-	DateOfBirth   time.Time `json:"date_of_birth,omitempty" validate:"required"`
-	UserType      string    `json:"user_type,omitempty" validate:"required"`
-	Password      string    `json:"password,omitempty" validate:"required,min=6"`
+	Name          string    `json:"name,omitempty"`
+	Email         string    `json:"email,omitempty"`
+	Number        string    `json:"number,omitempty"`
+	DateOfBirth   time.Time `json:"date_of_birth,omitempty"`
+	UserType      string    `json:"user_type,omitempty"`
+	Password      string    `json:"password,omitempty"`
 	Created_at    time.Time `json:"created_at,omitempty"`
 	Token         string    `json:"token,omitempty"`
 	Refresh_Token string    `json:"refresh_token,omitempty"`
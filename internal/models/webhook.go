@@ -0,0 +1,64 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventPing is only sent to test a webhook.
+const EventPing = "ping"
+
+// WebhookEvents are the domain events a webhook can subscribe to.
+var WebhookEvents = []string{
+	EventProjectCreated,
+	EventProjectUpdated,
+	EventProjectDeleted,
+	EventProjectPublished,
+	EventEpisodesAdded,
+	EventUserRegistered,
+	EventFavoriteAdded,
+	EventFavoriteRemoved,
+}
+
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+type Webhook struct {
+	ID        int       `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhookDelivery struct {
+	ID             int             `json:"id"`
+	WebhookID      int             `json:"webhook_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	LastStatusCode int             `json:"last_status_code"`
+	LastError      string          `json:"last_error"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+
+	// URL and Secret of the webhook, filled in for sending.
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
+
+// IsWebhookEvent reports whether webhooks can subscribe to the event.
+func IsWebhookEvent(event string) bool {
+	for _, e := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
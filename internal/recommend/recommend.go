@@ -0,0 +1,176 @@
+// Package recommend computes item-to-item similarity scores between projects.
+//
+// Content similarity is the weighted Jaccard index of the genres, keywords and
+// people two projects share. Collaborative similarity is the cosine of the
+// sets of users who favorited each project. The final score blends both.
+package recommend
+
+import (
+	"math"
+	"ozinshe/internal/models"
+	"sort"
+)
+
+// Item holds the features of one project.
+type Item struct {
+	ProjectID int
+	Genres    []int
+	Keywords  []int
+	People    []int
+}
+
+// Weights controls how much each signal contributes to a score.
+// Collaborative is the share of the collaborative signal in [0, 1]; the
+// content weights are relative to each other.
+type Weights struct {
+	Genre         float64
+	Keyword       float64
+	Person        float64
+	Collaborative float64
+}
+
+var DefaultWeights = Weights{
+	Genre:         0.5,
+	Keyword:       0.2,
+	Person:        0.3,
+	Collaborative: 0.4,
+}
+
+type feature int
+
+const (
+	featureGenre feature = iota
+	featureKeyword
+	featurePerson
+	featureCount
+)
+
+// Compute returns, for every project, at most topN similar projects with a
+// positive score, ordered by score. favorites maps a user ID to the projects
+// the user favorited.
+func Compute(items []Item, favorites map[int][]int, w Weights, topN int) []models.Similarity {
+	sets := make(map[int]*[featureCount]map[int]struct{}, len(items))
+	index := [featureCount]map[int][]int{{}, {}, {}}
+
+	for _, item := range items {
+		var s [featureCount]map[int]struct{}
+		for f, values := range [featureCount][]int{item.Genres, item.Keywords, item.People} {
+			s[f] = toSet(values)
+			for v := range s[f] {
+				index[f][v] = append(index[f][v], item.ProjectID)
+			}
+		}
+		sets[item.ProjectID] = &s
+	}
+
+	fans := make(map[int]map[int]struct{})
+	for userID, projects := range favorites {
+		for _, projectID := range projects {
+			if fans[projectID] == nil {
+				fans[projectID] = make(map[int]struct{})
+			}
+			fans[projectID][userID] = struct{}{}
+		}
+	}
+
+	// Projects favorited by each user, deduplicated, to walk co-occurrences.
+	liked := make(map[int][]int, len(favorites))
+	for userID, projects := range favorites {
+		for projectID := range toSet(projects) {
+			liked[userID] = append(liked[userID], projectID)
+		}
+	}
+
+	contentTotal := w.Genre + w.Keyword + w.Person
+	featureWeights := [featureCount]float64{w.Genre, w.Keyword, w.Person}
+
+	var result []models.Similarity
+	for _, item := range items {
+		a := sets[item.ProjectID]
+
+		// Count shared features and co-favorites with every candidate.
+		shared := make(map[int]*[featureCount + 1]int)
+		bump := func(other int, f int) {
+			if other == item.ProjectID {
+				return
+			}
+			if shared[other] == nil {
+				shared[other] = new([featureCount + 1]int)
+			}
+			shared[other][f]++
+		}
+
+		for f := feature(0); f < featureCount; f++ {
+			for v := range a[f] {
+				for _, other := range index[f][v] {
+					bump(other, int(f))
+				}
+			}
+		}
+		for userID := range fans[item.ProjectID] {
+			for _, other := range liked[userID] {
+				bump(other, int(featureCount))
+			}
+		}
+
+		candidates := make([]models.Similarity, 0, len(shared))
+		for other, counts := range shared {
+			var content float64
+			if contentTotal > 0 {
+				if b, ok := sets[other]; ok {
+					for f := feature(0); f < featureCount; f++ {
+						content += featureWeights[f] * jaccard(counts[f], len(a[f]), len(b[f]))
+					}
+				}
+				content /= contentTotal
+			}
+
+			var collaborative float64
+			if n, m := len(fans[item.ProjectID]), len(fans[other]); n > 0 && m > 0 {
+				collaborative = float64(counts[featureCount]) / math.Sqrt(float64(n*m))
+			}
+
+			score := (1-w.Collaborative)*content + w.Collaborative*collaborative
+			if score <= 0 {
+				continue
+			}
+
+			candidates = append(candidates, models.Similarity{
+				ProjectID:        item.ProjectID,
+				SimilarProjectID: other,
+				Score:            score,
+			})
+		}
+
+		sort.Slice(candidates, func(i, j int) bool {
+			if candidates[i].Score != candidates[j].Score {
+				return candidates[i].Score > candidates[j].Score
+			}
+			return candidates[i].SimilarProjectID < candidates[j].SimilarProjectID
+		})
+
+		if len(candidates) > topN {
+			candidates = candidates[:topN]
+		}
+
+		result = append(result, candidates...)
+	}
+
+	return result
+}
+
+func jaccard(shared, a, b int) float64 {
+	union := a + b - shared
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+func toSet(values []int) map[int]struct{} {
+	set := make(map[int]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,149 @@
+package recommend
+
+import (
+	"math"
+	"ozinshe/internal/models"
+	"slices"
+	"testing"
+)
+
+// Fixture catalog: 1-3 are thrillers sharing genres, keywords or people,
+// 4 and 5 are comedies, 6 shares nothing with anyone.
+var (
+	fixtureItems = []Item{
+		{ProjectID: 1, Genres: []int{1, 2}, Keywords: []int{10}, People: []int{100}},
+		{ProjectID: 2, Genres: []int{1, 2}, Keywords: []int{10}, People: []int{101}},
+		{ProjectID: 3, Genres: []int{1}, Keywords: []int{11}, People: []int{100}},
+		{ProjectID: 4, Genres: []int{3}, Keywords: []int{12}, People: []int{102}},
+		{ProjectID: 5, Genres: []int{3}, Keywords: []int{12}, People: []int{103}},
+		{ProjectID: 6, Genres: []int{4}, Keywords: []int{13}, People: []int{104}},
+	}
+	fixtureFavorites = map[int][]int{
+		10: {1, 2},
+		11: {1, 2, 3},
+		12: {4, 5},
+		13: {4, 5, 5}, // duplicates count once
+	}
+	// fixtureRelevant are the projects a viewer of each one should be
+	// offered, for precision@k.
+	fixtureRelevant = map[int][]int{
+		1: {2, 3},
+		2: {1, 3},
+		3: {1, 2},
+		4: {5},
+		5: {4},
+	}
+)
+
+func neighbours(similarities []models.Similarity) map[int][]models.Similarity {
+	byProject := make(map[int][]models.Similarity)
+	for _, s := range similarities {
+		byProject[s.ProjectID] = append(byProject[s.ProjectID], s)
+	}
+	return byProject
+}
+
+func TestComputeNeverRecommendsItself(t *testing.T) {
+	for _, s := range Compute(fixtureItems, fixtureFavorites, DefaultWeights, 10) {
+		if s.ProjectID == s.SimilarProjectID {
+			t.Errorf("project %d is similar to itself with score %v", s.ProjectID, s.Score)
+		}
+	}
+}
+
+func TestComputeNeighbours(t *testing.T) {
+	got := neighbours(Compute(fixtureItems, fixtureFavorites, DefaultWeights, 10))
+
+	tests := []struct {
+		project int
+		want    []int
+	}{
+		{1, []int{2, 3}},
+		{2, []int{1, 3}},
+		{3, []int{1, 2}},
+		{4, []int{5}},
+		{5, []int{4}},
+		{6, nil},
+	}
+	for _, tt := range tests {
+		var ids []int
+		for _, s := range got[tt.project] {
+			ids = append(ids, s.SimilarProjectID)
+		}
+		if !slices.Equal(ids, tt.want) {
+			t.Errorf("neighbours of %d = %v, want %v", tt.project, ids, tt.want)
+		}
+	}
+}
+
+func TestComputeOrdersByScore(t *testing.T) {
+	for project, list := range neighbours(Compute(fixtureItems, fixtureFavorites, DefaultWeights, 10)) {
+		for i := 1; i < len(list); i++ {
+			if list[i].Score > list[i-1].Score {
+				t.Errorf("neighbours of %d out of order: %v", project, list)
+			}
+		}
+		for _, s := range list {
+			if s.Score <= 0 || s.Score > 1 {
+				t.Errorf("score of %d -> %d = %v, want in (0, 1]", project, s.SimilarProjectID, s.Score)
+			}
+		}
+	}
+}
+
+func TestComputeScore(t *testing.T) {
+	// 1 and 2 share both genres and the keyword but no people, and have
+	// the same fans: content (0.5*1 + 0.2*1 + 0.3*0) / 1 = 0.7, cosine 1.
+	want := 0.6*0.7 + 0.4*1
+
+	for _, s := range Compute(fixtureItems, fixtureFavorites, DefaultWeights, 10) {
+		if s.ProjectID == 1 && s.SimilarProjectID == 2 {
+			if math.Abs(s.Score-want) > 1e-9 {
+				t.Errorf("score of 1 -> 2 = %v, want %v", s.Score, want)
+			}
+			return
+		}
+	}
+	t.Error("2 is not similar to 1")
+}
+
+func TestComputeTopN(t *testing.T) {
+	for project, list := range neighbours(Compute(fixtureItems, fixtureFavorites, DefaultWeights, 1)) {
+		if len(list) > 1 {
+			t.Errorf("%d neighbours of %d, want at most 1", len(list), project)
+		}
+	}
+}
+
+func TestComputePrecisionAtK(t *testing.T) {
+	const k = 2
+	got := neighbours(Compute(fixtureItems, fixtureFavorites, DefaultWeights, k))
+
+	var hits, total int
+	for project, relevant := range fixtureRelevant {
+		list := got[project]
+		for _, s := range list {
+			if slices.Contains(relevant, s.SimilarProjectID) {
+				hits++
+			}
+		}
+		total += min(k, len(relevant))
+	}
+
+	if precision := float64(hits) / float64(total); precision < 1 {
+		t.Errorf("precision@%d = %v, want 1", k, precision)
+	}
+}
+
+func TestComputeCollaborativeOnly(t *testing.T) {
+	w := Weights{Collaborative: 1}
+	got := neighbours(Compute(fixtureItems, fixtureFavorites, w, 10))
+
+	// 3 is only favorited along with 1 and 2 by one of their two fans.
+	if list := got[3]; len(list) != 2 || math.Abs(list[0].Score-1/math.Sqrt2) > 1e-9 {
+		t.Errorf("neighbours of 3 = %v, want 1 and 2 with score 1/sqrt(2)", list)
+	}
+	if list := got[6]; len(list) != 0 {
+		t.Errorf("neighbours of 6 = %v, want none without fans", list)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"ozinshe/internal/models"
 	psql "ozinshe/internal/storage/postgresql"
-	"time"
 )
 
 type AgeCategoryService struct {
@@ -15,26 +14,18 @@ func NewAgeCategoryService(ageCategory psql.AgeCategory) *AgeCategoryService {
 	return &AgeCategoryService{AgeCategory: ageCategory}
 }
 
-func (a *AgeCategoryService) Add(age_category []models.AgeCategory) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (a *AgeCategoryService) Add(ctx context.Context, age_category []models.AgeCategory) error {
 	return a.AgeCategory.Insert(ctx, age_category)
 }
 
-func (a *AgeCategoryService) Remove(id int) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (a *AgeCategoryService) Remove(ctx context.Context, id int) error {
 	return a.AgeCategory.Delete(ctx, id)
 }
 
-func (a *AgeCategoryService) GetById(id int) (models.AgeCategory, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (a *AgeCategoryService) GetById(ctx context.Context, id int) (models.AgeCategory, error) {
 	return a.AgeCategory.GetById(ctx, id)
 }
 
-func (a *AgeCategoryService) GetAll() ([]models.AgeCategory, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (a *AgeCategoryService) GetAll(ctx context.Context) ([]models.AgeCategory, error) {
 	return a.AgeCategory.GetAll(ctx)
 }
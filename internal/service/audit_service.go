@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"ozinshe/internal/models"
+	psql "ozinshe/internal/storage/postgresql"
+	"ozinshe/internal/tracing"
+)
+
+type AuditService struct {
+	Storage psql.Audit
+}
+
+func NewAuditService(storage psql.Audit) *AuditService {
+	return &AuditService{Storage: storage}
+}
+
+func (a *AuditService) GetAll(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	const op = "service.audit.GetAll"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	entries, err := a.Storage.GetAll(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"ozinshe/internal/cache"
+	"ozinshe/internal/events"
+	"ozinshe/internal/models"
+	"strconv"
+)
+
+// Keys of the catalog cache. Single movies and series are cached under
+// their prefix followed by their ID.
+const (
+	cacheMovies        = "movies"
+	cacheMovie         = "movie:"
+	cacheSeriesList    = "series"
+	cacheSeries        = "series:"
+	cacheGenres        = "genres"
+	cacheAgeCategories = "age_categories"
+)
+
+// contentKey is the cache key of a single movie or series.
+func contentKey(projectType string, id int) string {
+	if projectType == "movie" {
+		return cacheMovie + strconv.Itoa(id)
+	}
+	return cacheSeries + strconv.Itoa(id)
+}
+
+// contentKeys are the cache keys a change to a movie or series makes stale:
+// its own and the list of its type.
+func contentKeys(projectType string, id int) []string {
+	if projectType == "movie" {
+		return []string{contentKey(projectType, id), cacheMovies}
+	}
+	return []string{contentKey(projectType, id), cacheSeriesList}
+}
+
+// CachedMovieService caches GetAll and GetById of a Movie service and
+// invalidates them on its writes. The popularity of cached movies lags
+// behind the favorites for up to the TTL of the cache.
+type CachedMovieService struct {
+	Movie
+	cache *cache.Store
+}
+
+func NewCachedMovieService(movies Movie, store *cache.Store) *CachedMovieService {
+	return &CachedMovieService{Movie: movies, cache: store}
+}
+
+func (m *CachedMovieService) GetAll(ctx context.Context) ([]models.Movie, error) {
+	return cache.Get(ctx, m.cache, cacheMovies, m.Movie.GetAll)
+}
+
+func (m *CachedMovieService) GetById(ctx context.Context, id int) (models.Movie, error) {
+	return cache.Get(ctx, m.cache, contentKey("movie", id), func(ctx context.Context) (models.Movie, error) {
+		return m.Movie.GetById(ctx, id)
+	})
+}
+
+// Writes invalidate even when they fail, since they may have failed after
+// changing some of the movie.
+
+func (m *CachedMovieService) Add(ctx context.Context, movie models.Movie, image_data models.SavePhoto) (int, error) {
+	defer m.cache.Invalidate(ctx, cacheMovies)
+	return m.Movie.Add(ctx, movie, image_data)
+}
+
+func (m *CachedMovieService) Remove(ctx context.Context, id int) error {
+	defer m.cache.Invalidate(ctx, contentKeys("movie", id)...)
+	return m.Movie.Remove(ctx, id)
+}
+
+func (m *CachedMovieService) Update(ctx context.Context, id int, movie models.Movie) error {
+	defer m.cache.Invalidate(ctx, contentKeys("movie", id)...)
+	return m.Movie.Update(ctx, id, movie)
+}
+
+func (m *CachedMovieService) UpdateCover(ctx context.Context, id int, image_data models.SavePhoto) error {
+	defer m.cache.Invalidate(ctx, contentKeys("movie", id)...)
+	return m.Movie.UpdateCover(ctx, id, image_data)
+}
+
+func (m *CachedMovieService) UpdateScreenshots(ctx context.Context, id int, image_data models.SavePhoto) error {
+	defer m.cache.Invalidate(ctx, contentKeys("movie", id)...)
+	return m.Movie.UpdateScreenshots(ctx, id, image_data)
+}
+
+// CachedSeriesService is CachedMovieService for series.
+type CachedSeriesService struct {
+	Series
+	cache *cache.Store
+}
+
+func NewCachedSeriesService(series Series, store *cache.Store) *CachedSeriesService {
+	return &CachedSeriesService{Series: series, cache: store}
+}
+
+func (s *CachedSeriesService) GetAll(ctx context.Context) ([]models.Series, error) {
+	return cache.Get(ctx, s.cache, cacheSeriesList, s.Series.GetAll)
+}
+
+func (s *CachedSeriesService) GetById(ctx context.Context, id int) (models.Series, error) {
+	return cache.Get(ctx, s.cache, contentKey("series", id), func(ctx context.Context) (models.Series, error) {
+		return s.Series.GetById(ctx, id)
+	})
+}
+
+func (s *CachedSeriesService) Add(ctx context.Context, series models.Series, image_data models.SavePhoto) (int, error) {
+	defer s.cache.Invalidate(ctx, cacheSeriesList)
+	return s.Series.Add(ctx, series, image_data)
+}
+
+func (s *CachedSeriesService) Remove(ctx context.Context, id int) error {
+	defer s.cache.Invalidate(ctx, contentKeys("series", id)...)
+	return s.Series.Remove(ctx, id)
+}
+
+func (s *CachedSeriesService) Update(ctx context.Context, id int, series models.Series) error {
+	defer s.cache.Invalidate(ctx, contentKeys("series", id)...)
+	return s.Series.Update(ctx, id, series)
+}
+
+func (s *CachedSeriesService) UpdateCover(ctx context.Context, id int, image_data models.SavePhoto) error {
+	defer s.cache.Invalidate(ctx, contentKeys("series", id)...)
+	return s.Series.UpdateCover(ctx, id, image_data)
+}
+
+func (s *CachedSeriesService) UpdateScreenshots(ctx context.Context, id int, image_data models.SavePhoto) error {
+	defer s.cache.Invalidate(ctx, contentKeys("series", id)...)
+	return s.Series.UpdateScreenshots(ctx, id, image_data)
+}
+
+// CachedGenreService caches GetAll of a Genre service. Removing a genre
+// removes it from every movie and series too, so it invalidates all of them.
+type CachedGenreService struct {
+	Genre
+	cache *cache.Store
+}
+
+func NewCachedGenreService(genres Genre, store *cache.Store) *CachedGenreService {
+	return &CachedGenreService{Genre: genres, cache: store}
+}
+
+func (g *CachedGenreService) GetAll(ctx context.Context) ([]models.Genre, error) {
+	return cache.Get(ctx, g.cache, cacheGenres, g.Genre.GetAll)
+}
+
+func (g *CachedGenreService) Add(ctx context.Context, genres []models.Genre) error {
+	defer g.cache.Invalidate(ctx, cacheGenres)
+	return g.Genre.Add(ctx, genres)
+}
+
+func (g *CachedGenreService) Remove(ctx context.Context, id int) error {
+	defer g.cache.Invalidate(ctx, cacheGenres, cacheMovies, cacheMovie, cacheSeriesList, cacheSeries)
+	return g.Genre.Remove(ctx, id)
+}
+
+// CachedAgeCategoryService is CachedGenreService for age categories.
+type CachedAgeCategoryService struct {
+	AgeCategory
+	cache *cache.Store
+}
+
+func NewCachedAgeCategoryService(ageCategories AgeCategory, store *cache.Store) *CachedAgeCategoryService {
+	return &CachedAgeCategoryService{AgeCategory: ageCategories, cache: store}
+}
+
+func (a *CachedAgeCategoryService) GetAll(ctx context.Context) ([]models.AgeCategory, error) {
+	return cache.Get(ctx, a.cache, cacheAgeCategories, a.AgeCategory.GetAll)
+}
+
+func (a *CachedAgeCategoryService) Add(ctx context.Context, ageCategories []models.AgeCategory) error {
+	defer a.cache.Invalidate(ctx, cacheAgeCategories)
+	return a.AgeCategory.Add(ctx, ageCategories)
+}
+
+func (a *CachedAgeCategoryService) Remove(ctx context.Context, id int) error {
+	defer a.cache.Invalidate(ctx, cacheAgeCategories, cacheMovies, cacheMovie, cacheSeriesList, cacheSeries)
+	return a.AgeCategory.Remove(ctx, id)
+}
+
+// CachedProjectService invalidates the lists of movies and series, which
+// only hold published ones, when projects are added, removed or change
+// status. Projects published by the activator are invalidated by their
+// events instead.
+type CachedProjectService struct {
+	Project
+	cache *cache.Store
+}
+
+func NewCachedProjectService(projects Project, store *cache.Store) *CachedProjectService {
+	return &CachedProjectService{Project: projects, cache: store}
+}
+
+func (p *CachedProjectService) Add(ctx context.Context, project models.Project) (int, error) {
+	defer p.cache.Invalidate(ctx, cacheMovies, cacheSeriesList)
+	return p.Project.Add(ctx, project)
+}
+
+func (p *CachedProjectService) Remove(ctx context.Context, id int) error {
+	defer p.cache.Invalidate(ctx, cacheMovies, cacheSeriesList)
+	return p.Project.Remove(ctx, id)
+}
+
+func (p *CachedProjectService) SetStatus(ctx context.Context, id int, publication models.Publication) error {
+	defer p.cache.Invalidate(ctx, cacheMovies, cacheSeriesList)
+	return p.Project.SetStatus(ctx, id, publication)
+}
+
+// CachedPersonService invalidates the movies and series whose credits
+// change, along with the lists of movies and series, which name their
+// directors and producers: those of the project given new credits, or all
+// of them when a person is removed.
+type CachedPersonService struct {
+	Person
+	projects Project
+	cache    *cache.Store
+}
+
+func NewCachedPersonService(people Person, projects Project, store *cache.Store) *CachedPersonService {
+	return &CachedPersonService{Person: people, projects: projects, cache: store}
+}
+
+func (p *CachedPersonService) Remove(ctx context.Context, id int) error {
+	defer p.cache.Invalidate(ctx, cacheMovie, cacheMovies, cacheSeries, cacheSeriesList)
+	return p.Person.Remove(ctx, id)
+}
+
+func (p *CachedPersonService) SetCredits(ctx context.Context, projectID int, credits []models.Credit) error {
+	err := p.Person.SetCredits(ctx, projectID, credits)
+
+	project, perr := p.projects.GetById(ctx, projectID)
+	if perr != nil {
+		// Without the project, its movie or series is not known.
+		p.cache.Invalidate(ctx, cacheMovie, cacheMovies, cacheSeries, cacheSeriesList)
+	} else {
+		p.cache.Invalidate(ctx, contentKeys(project.Project_type, project.Project_id)...)
+	}
+
+	return err
+}
+
+// catalogEvents are the events of changes to movies and series, which are
+// recorded by the storage along with the change wherever it is made.
+var catalogEvents = []string{
+	models.EventProjectCreated,
+	models.EventProjectUpdated,
+	models.EventProjectDeleted,
+	models.EventProjectPublished,
+	models.EventEpisodesAdded,
+}
+
+// invalidateOnEvents invalidates the movies and series the catalog events
+// are about. The services invalidate their own writes right away; the
+// events catch the changes made elsewhere, such as the scheduled projects
+// the activator publishes, which may run in a worker process.
+func invalidateOnEvents(bus *events.Bus, store *cache.Store) {
+	for _, eventType := range catalogEvents {
+		bus.On(eventType, "cache", func(ctx context.Context, event models.Event) error {
+			if event.Type == models.EventEpisodesAdded {
+				var data models.EpisodesEvent
+				if err := event.Decode(&data); err != nil {
+					return err
+				}
+				store.Invalidate(ctx, contentKeys("series", data.SeriesID)...)
+				return nil
+			}
+
+			var data models.ProjectEvent
+			if err := event.Decode(&data); err != nil {
+				return err
+			}
+			store.Invalidate(ctx, contentKeys(data.ProjectType, data.ContentID)...)
+			return nil
+		})
+	}
+}
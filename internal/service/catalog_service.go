@@ -0,0 +1,458 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"ozinshe/internal/models"
+	psql "ozinshe/internal/storage/postgresql"
+	"ozinshe/internal/tracing"
+	"time"
+)
+
+// CatalogService loads projects, movies and series along with their parts
+// for many of them at once, for the loaders of the GraphQL API, sync and
+// the catalog lists. Movies and series are keyed by their own IDs, projects
+// by theirs. It also tells when the catalog was last modified, for
+// conditional requests.
+type CatalogService struct {
+	projects psql.Project
+	movies   psql.Movie
+	series   psql.Series
+	catalog  psql.Catalog
+}
+
+func NewCatalogService(projects psql.Project, movies psql.Movie, series psql.Series, catalog psql.Catalog) *CatalogService {
+	return &CatalogService{projects: projects, movies: movies, series: series, catalog: catalog}
+}
+
+func (c *CatalogService) GetProjects(ctx context.Context, ids []int) (map[int]models.Project, error) {
+	const op = "service.catalog.GetProjects"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	projects, err := c.projects.GetByIds(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	byID := make(map[int]models.Project, len(projects))
+	for _, project := range projects {
+		byID[project.Id] = project
+	}
+
+	return byID, nil
+}
+
+func (c *CatalogService) GetPublished(ctx context.Context, projectType string, offset, limit int) ([]models.Project, error) {
+	const op = "service.catalog.GetPublished"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	projects, err := c.projects.GetPublished(ctx, projectType, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return projects, nil
+}
+
+func (c *CatalogService) GetFavoriteIds(ctx context.Context, userID int) ([]int, error) {
+	const op = "service.catalog.GetFavoriteIds"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	ids, err := c.projects.GetFavoriteIds(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return ids, nil
+}
+
+func (c *CatalogService) GetMovies(ctx context.Context, ids []int) (map[int]models.Movie, error) {
+	const op = "service.catalog.GetMovies"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	movies, err := c.movies.GetByIds(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	byID := make(map[int]models.Movie, len(movies))
+	for _, movie := range movies {
+		byID[movie.ID] = movie
+	}
+
+	return byID, nil
+}
+
+func (c *CatalogService) GetSeries(ctx context.Context, ids []int) (map[int]models.Series, error) {
+	const op = "service.catalog.GetSeries"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	series, err := c.series.GetByIds(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	byID := make(map[int]models.Series, len(series))
+	for _, item := range series {
+		byID[item.ID] = item
+	}
+
+	return byID, nil
+}
+
+// The parts of movies and series are loaded for the ids of one project
+// type, "movie" or "series".
+
+func (c *CatalogService) GetGenres(ctx context.Context, projectType string, ids []int) (map[int][]models.Genre, error) {
+	return byProjectType(ctx, "service.catalog.GetGenres", projectType, ids, c.movies.GetGenresByIds, c.series.GetGenresByIds)
+}
+
+func (c *CatalogService) GetAgeCategories(ctx context.Context, projectType string, ids []int) (map[int][]models.AgeCategory, error) {
+	return byProjectType(ctx, "service.catalog.GetAgeCategories", projectType, ids, c.movies.GetAgeCategoriesByIds, c.series.GetAgeCategoriesByIds)
+}
+
+func (c *CatalogService) GetKeywords(ctx context.Context, projectType string, ids []int) (map[int][]models.Keyword, error) {
+	return byProjectType(ctx, "service.catalog.GetKeywords", projectType, ids, c.movies.GetKeywordsByIds, c.series.GetKeywordsByIds)
+}
+
+func (c *CatalogService) GetCovers(ctx context.Context, projectType string, ids []int) (map[int]models.Cover, error) {
+	return byProjectType(ctx, "service.catalog.GetCovers", projectType, ids, c.movies.GetCoversByIds, c.series.GetCoversByIds)
+}
+
+func (c *CatalogService) GetScreenshots(ctx context.Context, projectType string, ids []int) (map[int][]models.Screenshot, error) {
+	return byProjectType(ctx, "service.catalog.GetScreenshots", projectType, ids, c.movies.GetScreenshotsByIds, c.series.GetScreenshotsByIds)
+}
+
+func byProjectType[T any](ctx context.Context, op, projectType string, ids []int, movies, series func(context.Context, []int) (T, error)) (T, error) {
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	get := movies
+	switch projectType {
+	case "movie":
+	case "series":
+		get = series
+	default:
+		var zero T
+		return zero, fmt.Errorf("%s: unknown project type %q", op, projectType)
+	}
+
+	parts, err := get(ctx, ids)
+	if err != nil {
+		return parts, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return parts, nil
+}
+
+func (c *CatalogService) GetSeasons(ctx context.Context, seriesIDs []int) (map[int][]models.Season, error) {
+	const op = "service.catalog.GetSeasons"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	seasons, err := c.series.GetSeasonsByIds(ctx, seriesIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return seasons, nil
+}
+
+func (c *CatalogService) GetEpisodes(ctx context.Context, seasonIDs []int) (map[int][]models.Episode, error) {
+	const op = "service.catalog.GetEpisodes"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	episodes, err := c.series.GetEpisodesBySeasonIds(ctx, seasonIDs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return episodes, nil
+}
+
+func (c *CatalogService) GetCards(ctx context.Context, projectType string) ([]models.ProjectCard, error) {
+	const op = "service.catalog.GetCards"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	cards, err := c.projects.GetCards(ctx, projectType)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return cards, nil
+}
+
+// ListMovies returns the published movies with only the given parts, each
+// loaded for all of them with one query.
+func (c *CatalogService) ListMovies(ctx context.Context, parts models.Parts) ([]models.Movie, error) {
+	const op = "service.catalog.ListMovies"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	movies, err := c.movies.FetchMovieData(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := c.fillMovies(ctx, movies, parts); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return movies, nil
+}
+
+// ListSeries is ListMovies for series.
+func (c *CatalogService) ListSeries(ctx context.Context, parts models.Parts) ([]models.Series, error) {
+	const op = "service.catalog.ListSeries"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	series, err := c.series.FetchSeriesData(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := c.fillSeries(ctx, series, parts); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return series, nil
+}
+
+// LoadMovies returns the movies with the given IDs with the given parts,
+// credits only when parts names them. Missing movies are left out.
+func (c *CatalogService) LoadMovies(ctx context.Context, ids []int, parts models.Parts) (map[int]models.Movie, error) {
+	const op = "service.catalog.LoadMovies"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	movies, err := c.movies.GetByIds(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := c.fillMovies(ctx, movies, parts); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	byID := make(map[int]models.Movie, len(movies))
+	for _, movie := range movies {
+		byID[movie.ID] = movie
+	}
+
+	return byID, nil
+}
+
+// LoadSeries is LoadMovies for series.
+func (c *CatalogService) LoadSeries(ctx context.Context, ids []int, parts models.Parts) (map[int]models.Series, error) {
+	const op = "service.catalog.LoadSeries"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	series, err := c.series.GetByIds(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := c.fillSeries(ctx, series, parts); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	byID := make(map[int]models.Series, len(series))
+	for _, item := range series {
+		byID[item.ID] = item
+	}
+
+	return byID, nil
+}
+
+// fillMovies loads the given parts of movies in place.
+func (c *CatalogService) fillMovies(ctx context.Context, movies []models.Movie, parts models.Parts) error {
+	ids := make([]int, len(movies))
+	for i, movie := range movies {
+		ids[i] = movie.ID
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var (
+		genres        map[int][]models.Genre
+		ageCategories map[int][]models.AgeCategory
+		keywords      map[int][]models.Keyword
+		covers        map[int]models.Cover
+		screenshots   map[int][]models.Screenshot
+		credits       map[int][]models.Credit
+		err           error
+	)
+	if parts.Has(models.PartGenres) {
+		if genres, err = c.movies.GetGenresByIds(ctx, ids); err != nil {
+			return err
+		}
+	}
+	if parts.Has(models.PartAgeCategories) {
+		if ageCategories, err = c.movies.GetAgeCategoriesByIds(ctx, ids); err != nil {
+			return err
+		}
+	}
+	if parts.Has(models.PartKeywords) {
+		if keywords, err = c.movies.GetKeywordsByIds(ctx, ids); err != nil {
+			return err
+		}
+	}
+	if parts.Has(models.PartCover) {
+		if covers, err = c.movies.GetCoversByIds(ctx, ids); err != nil {
+			return err
+		}
+	}
+	if parts.Has(models.PartScreenshots) {
+		if screenshots, err = c.movies.GetScreenshotsByIds(ctx, ids); err != nil {
+			return err
+		}
+	}
+	// Credits are only loaded when parts names them, for a single movie.
+	if parts[models.PartCredits] {
+		if credits, err = c.movies.GetCreditsByIds(ctx, ids); err != nil {
+			return err
+		}
+	}
+
+	for i := range movies {
+		id := movies[i].ID
+		movies[i].Genres = genres[id]
+		movies[i].AgeCategories = ageCategories[id]
+		movies[i].Keywords = keywords[id]
+		movies[i].Cover = covers[id]
+		movies[i].Screenshots = screenshots[id]
+		movies[i].Credits = credits[id]
+	}
+
+	return nil
+}
+
+// fillSeries is fillMovies for series, which also have seasons of episodes.
+func (c *CatalogService) fillSeries(ctx context.Context, series []models.Series, parts models.Parts) error {
+	ids := make([]int, len(series))
+	for i, item := range series {
+		ids[i] = item.ID
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var (
+		genres        map[int][]models.Genre
+		ageCategories map[int][]models.AgeCategory
+		keywords      map[int][]models.Keyword
+		covers        map[int]models.Cover
+		screenshots   map[int][]models.Screenshot
+		credits       map[int][]models.Credit
+		seasons       map[int][]models.Season
+		episodes      map[int][]models.Episode
+		err           error
+	)
+	if parts.Has(models.PartGenres) {
+		if genres, err = c.series.GetGenresByIds(ctx, ids); err != nil {
+			return err
+		}
+	}
+	if parts.Has(models.PartAgeCategories) {
+		if ageCategories, err = c.series.GetAgeCategoriesByIds(ctx, ids); err != nil {
+			return err
+		}
+	}
+	if parts.Has(models.PartKeywords) {
+		if keywords, err = c.series.GetKeywordsByIds(ctx, ids); err != nil {
+			return err
+		}
+	}
+	if parts.Has(models.PartCover) {
+		if covers, err = c.series.GetCoversByIds(ctx, ids); err != nil {
+			return err
+		}
+	}
+	if parts.Has(models.PartScreenshots) {
+		if screenshots, err = c.series.GetScreenshotsByIds(ctx, ids); err != nil {
+			return err
+		}
+	}
+	if parts[models.PartCredits] {
+		if credits, err = c.series.GetCreditsByIds(ctx, ids); err != nil {
+			return err
+		}
+	}
+	if parts.Has(models.PartSeasons) {
+		if seasons, err = c.series.GetSeasonsByIds(ctx, ids); err != nil {
+			return err
+		}
+
+		var seasonIDs []int
+		for _, list := range seasons {
+			for _, season := range list {
+				seasonIDs = append(seasonIDs, season.ID)
+			}
+		}
+		if episodes, err = c.series.GetEpisodesBySeasonIds(ctx, seasonIDs); err != nil {
+			return err
+		}
+	}
+
+	for i := range series {
+		id := series[i].ID
+		series[i].Genres = genres[id]
+		series[i].AgeCategories = ageCategories[id]
+		series[i].Keywords = keywords[id]
+		series[i].Cover = covers[id]
+		series[i].Screenshots = screenshots[id]
+		series[i].Credits = credits[id]
+		series[i].Seasons = seasons[id]
+		for j := range series[i].Seasons {
+			series[i].Seasons[j].Episodes = episodes[series[i].Seasons[j].ID]
+		}
+	}
+
+	return nil
+}
+
+func (c *CatalogService) ModifiedAt(ctx context.Context, resource string, id int) (time.Time, error) {
+	const op = "service.catalog.ModifiedAt"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	modified, err := c.catalog.ModifiedAt(ctx, resource, id)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return modified, nil
+}
+
+func (c *CatalogService) ListModifiedAt(ctx context.Context, resource string) (time.Time, error) {
+	const op = "service.catalog.ListModifiedAt"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	modified, err := c.catalog.ListModifiedAt(ctx, resource)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return modified, nil
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"ozinshe/internal/helper"
+	"ozinshe/internal/metrics"
+	"ozinshe/internal/models"
+	psql "ozinshe/internal/storage/postgresql"
+	"ozinshe/internal/tracing"
+	"ozinshe/internal/validation"
+	"strconv"
+)
+
+type CollectionService struct {
+	Storage psql.Collection
+}
+
+func NewCollectionService(storage psql.Collection) *CollectionService {
+	return &CollectionService{Storage: storage}
+}
+
+func (c *CollectionService) Add(ctx context.Context, collection models.Collection, image_data models.SavePhoto) (int, error) {
+	var err error
+	const op = "service.collection.Add"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	banner := image_data.File_form.File["banner"]
+	if len(banner) > 0 {
+		if err := validation.ValidateImageFile(banner[0], image_data.MaxImageSize); err != nil {
+			return 0, err
+		}
+		collection.Banner = banner[0].Filename
+	}
+
+	collection.ID, err = c.Storage.Insert(ctx, collection)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(banner) > 0 {
+		uploadPath := image_data.UploadPath + strconv.Itoa(collection.ID) + "/" + banner[0].Filename
+		err = helper.ProcessSaving(banner[0], uploadPath)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+		metrics.Uploads.WithLabelValues("banner").Inc()
+	}
+
+	return collection.ID, nil
+}
+
+func (c *CollectionService) Remove(ctx context.Context, slug string) error {
+	const op = "service.collection.Remove"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	collection, err := c.Storage.GetBySlug(ctx, slug)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = c.Storage.Delete(ctx, collection.ID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = helper.DeleteDirectory("collections/" + strconv.Itoa(collection.ID))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (c *CollectionService) GetAll(ctx context.Context) ([]models.Collection, error) {
+	const op = "service.collection.GetAll"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	collections, err := c.Storage.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return collections, nil
+}
+
+func (c *CollectionService) SetProjects(ctx context.Context, slug string, projectIDs []int) error {
+	const op = "service.collection.SetProjects"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	collection, err := c.Storage.GetBySlug(ctx, slug)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = c.Storage.ReplaceProjects(ctx, collection.ID, projectIDs)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
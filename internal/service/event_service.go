@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"ozinshe/internal/events"
+	"ozinshe/internal/models"
+	psql "ozinshe/internal/storage/postgresql"
+	"ozinshe/internal/tracing"
+	"time"
+)
+
+const (
+	// relayBatch is how many outbox events are relayed per round.
+	relayBatch = 50
+	// relayTimeout must outlast handling a whole batch.
+	relayTimeout = 2 * time.Minute
+)
+
+type EventService struct {
+	Storage psql.Outbox
+	Bus     *events.Bus
+}
+
+func NewEventService(storage psql.Outbox, bus *events.Bus) *EventService {
+	return &EventService{Storage: storage, Bus: bus}
+}
+
+// RelayPending dispatches the outbox events that are due to the bus and
+// reports how many were relayed. Events a subscriber failed on are relayed
+// again later, to the subscribers that have not handled them yet.
+func (e *EventService) RelayPending(ctx context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, relayTimeout)
+	defer cancel()
+
+	const op = "service.event.RelayPending"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	relayed, err := e.Storage.Relay(ctx, relayBatch, e.Bus.Dispatch, relayBackoff)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return relayed, nil
+}
+
+// relayBackoff returns how long to wait before relaying an event again after
+// attempts failures: 5s, 10s, 20s... capped at 10m.
+func relayBackoff(attempts int) time.Duration {
+	backoff := 5 * time.Second
+	for i := 1; i < attempts && backoff < 10*time.Minute; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, 10*time.Minute)
+}
+
+// subscribe registers the in-process consumers of domain events. Every
+// handler may see an event more than once.
+func subscribe(bus *events.Bus, notification *NotificationService, webhook *WebhookService) {
+	bus.On(models.EventProjectPublished, "notifications", func(ctx context.Context, event models.Event) error {
+		var data models.ProjectEvent
+		if err := event.Decode(&data); err != nil {
+			return err
+		}
+		return notification.ProjectPublished(ctx, data.ProjectID)
+	})
+
+	bus.On(models.EventEpisodesAdded, "notifications", func(ctx context.Context, event models.Event) error {
+		var data models.EpisodesEvent
+		if err := event.Decode(&data); err != nil {
+			return err
+		}
+		return notification.EpisodesAdded(ctx, data.SeriesID, data.Count)
+	})
+
+	for _, eventType := range models.WebhookEvents {
+		bus.On(eventType, "webhooks", webhook.Publish)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"ozinshe/internal/models"
 	psql "ozinshe/internal/storage/postgresql"
-	"time"
 )
 
 type GenreService struct {
@@ -15,30 +14,18 @@ func NewGenreService(genre psql.Genre) *GenreService {
 	return &GenreService{Genre: genre}
 }
 
-func (g *GenreService) Add(genre []models.Genre) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (g *GenreService) Add(ctx context.Context, genre []models.Genre) error {
 	return g.Genre.Insert(ctx, genre)
 }
 
-func (g *GenreService) Remove(id int) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (g *GenreService) Remove(ctx context.Context, id int) error {
 	return g.Genre.Delete(ctx, id)
 }
 
-func (g *GenreService) GetById(id int) (models.Genre, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (g *GenreService) GetById(ctx context.Context, id int) (models.Genre, error) {
 	return g.Genre.GetById(ctx, id)
 }
 
-func (g *GenreService) GetAll() ([]models.Genre, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (g *GenreService) GetAll(ctx context.Context) ([]models.Genre, error) {
 	return g.Genre.GetAll(ctx)
 }
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ozinshe/internal/helper"
+	"ozinshe/internal/models"
+	psql "ozinshe/internal/storage/postgresql"
+	"ozinshe/migrations"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// checkTimeout bounds each readiness check.
+	checkTimeout = 2 * time.Second
+	// staleGrace is added to the allowed gap between two rounds of a
+	// background worker before it is reported stuck. A round may run for as
+	// long as a job lease.
+	staleGrace = jobLease
+)
+
+type heartbeat struct {
+	interval time.Duration
+	last     time.Time
+	err      error
+}
+
+type HealthService struct {
+	Storage psql.Health
+
+	draining atomic.Bool
+
+	mu         sync.Mutex
+	heartbeats map[string]heartbeat
+}
+
+func NewHealthService(storage psql.Health) *HealthService {
+	return &HealthService{
+		Storage:    storage,
+		heartbeats: make(map[string]heartbeat),
+	}
+}
+
+// Watch starts tracking a background worker that runs a round at least
+// every interval. Workers that are never watched are not checked.
+func (h *HealthService) Watch(name string, interval time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.heartbeats[name] = heartbeat{interval: interval, last: time.Now()}
+}
+
+// Beat records that the worker finished a round, with the error of the
+// round if it failed.
+func (h *HealthService) Beat(name string, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	beat := h.heartbeats[name]
+	beat.last, beat.err = time.Now(), err
+	h.heartbeats[name] = beat
+}
+
+// Drain marks the server as shutting down, so that load balancers stop
+// sending it requests while the ones in flight finish.
+func (h *HealthService) Drain() {
+	h.draining.Store(true)
+}
+
+// Ready runs the readiness checks concurrently.
+func (h *HealthService) Ready(ctx context.Context) models.Readiness {
+	checks := map[string]func(context.Context) error{
+		"database":   h.Storage.Ping,
+		"migrations": h.checkMigrations,
+		"uploads":    h.checkUploads,
+		"workers":    h.checkWorkers,
+	}
+
+	readiness := models.Readiness{
+		Status: models.Ready,
+		Checks: make(map[string]models.CheckResult, len(checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check func(context.Context) error) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+			defer cancel()
+
+			result := models.CheckResult{Status: models.CheckOK}
+			if err := check(ctx); err != nil {
+				result = models.CheckResult{Status: models.CheckFail, Error: err.Error()}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			readiness.Checks[name] = result
+			if result.Status == models.CheckFail {
+				readiness.Status = models.NotReady
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	if h.draining.Load() {
+		readiness.Status = models.ShuttingDown
+	}
+
+	return readiness
+}
+
+// checkMigrations fails unless the database schema is at the version of the
+// newest migration the server was built with.
+func (h *HealthService) checkMigrations(ctx context.Context) error {
+	want, err := migrations.Latest()
+	if err != nil {
+		return err
+	}
+
+	version, dirty, err := h.Storage.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case dirty:
+		return fmt.Errorf("migration %d failed halfway", version)
+	case version < want:
+		return fmt.Errorf("schema at version %d, %d pending", version, want-version)
+	case version > want:
+		return fmt.Errorf("schema at version %d, newer than %d", version, want)
+	}
+
+	return nil
+}
+
+func (h *HealthService) checkUploads(context.Context) error {
+	return helper.CheckWritable("")
+}
+
+// checkWorkers fails when a watched worker has not finished a round for
+// much longer than its interval.
+func (h *HealthService) checkWorkers(context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var stuck []string
+	for name, beat := range h.heartbeats {
+		since := time.Since(beat.last)
+		if since <= 2*beat.interval+staleGrace {
+			continue
+		}
+
+		msg := fmt.Sprintf("%s: no round for %s", name, since.Round(time.Second))
+		if beat.err != nil {
+			msg += fmt.Sprintf(" (last error: %v)", beat.err)
+		}
+		stuck = append(stuck, msg)
+	}
+	if len(stuck) == 0 {
+		return nil
+	}
+
+	sort.Strings(stuck)
+	return errors.New(strings.Join(stuck, "; "))
+}
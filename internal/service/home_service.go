@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"ozinshe/internal/apperr"
+	"ozinshe/internal/models"
+	psql "ozinshe/internal/storage/postgresql"
+	"ozinshe/internal/tracing"
+)
+
+var (
+	ErrUnknownRow = apperr.NotFound("unknown_home_row", "unknown home row")
+)
+
+type HomeService struct {
+	collections psql.Collection
+	projects    psql.Project
+}
+
+func NewHomeService(collections psql.Collection, projects psql.Project) *HomeService {
+	return &HomeService{collections: collections, projects: projects}
+}
+
+// Feed assembles the home screen: the viewer's continue watching row, curated
+// collections in admin order and the algorithmic rows. Empty rows are skipped.
+func (h *HomeService) Feed(ctx context.Context, userID, limit int) ([]models.HomeRow, error) {
+	const op = "service.home.Feed"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	feed := make([]models.HomeRow, 0)
+
+	if userID != 0 {
+		row, err := h.row(ctx, models.RowContinueWatching, userID, 0, limit)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		feed = appendRow(feed, row)
+	}
+
+	collections, err := h.collections.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, collection := range collections {
+		row, err := h.collectionRow(ctx, collection, 0, limit)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		feed = appendRow(feed, row)
+	}
+
+	keys := []string{models.RowTrending, models.RowNewReleases}
+	if userID != 0 {
+		keys = append(keys, models.RowFavoriteGenre)
+	}
+
+	for _, key := range keys {
+		row, err := h.row(ctx, key, userID, 0, limit)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		feed = appendRow(feed, row)
+	}
+
+	return feed, nil
+}
+
+// Row returns one page of an algorithmic home row.
+func (h *HomeService) Row(ctx context.Context, key string, userID, offset, limit int) (models.HomeRow, error) {
+	const op = "service.home.Row"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	row, err := h.row(ctx, key, userID, offset, limit)
+	if err != nil {
+		return models.HomeRow{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return row, nil
+}
+
+// CollectionRow returns one page of a curated collection.
+func (h *HomeService) CollectionRow(ctx context.Context, slug string, offset, limit int) (models.HomeRow, error) {
+	const op = "service.home.CollectionRow"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	collection, err := h.collections.GetBySlug(ctx, slug)
+	if err != nil {
+		return models.HomeRow{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	row, err := h.collectionRow(ctx, collection, offset, limit)
+	if err != nil {
+		return models.HomeRow{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return row, nil
+}
+
+func (h *HomeService) collectionRow(ctx context.Context, collection models.Collection, offset, limit int) (models.HomeRow, error) {
+	row := models.HomeRow{
+		Key:    models.RowCollection + "/" + collection.Slug,
+		Kind:   models.RowCollection,
+		Title:  collection.Title,
+		Banner: collection.Banner,
+		Offset: offset,
+		Limit:  limit,
+	}
+
+	items, err := h.collections.GetProjects(ctx, collection.ID, offset, limit+1)
+	if err != nil {
+		return models.HomeRow{}, err
+	}
+
+	row.Items, row.HasMore = page(items, limit)
+
+	return row, nil
+}
+
+func (h *HomeService) row(ctx context.Context, key string, userID, offset, limit int) (models.HomeRow, error) {
+	row := models.HomeRow{
+		Key:    key,
+		Kind:   key,
+		Offset: offset,
+		Limit:  limit,
+	}
+
+	var items []models.ProjectCard
+	var err error
+
+	switch key {
+	case models.RowTrending:
+		row.Title = "Trending"
+		items, err = h.projects.GetTrending(ctx, offset, limit+1)
+	case models.RowNewReleases:
+		row.Title = "New releases"
+		items, err = h.projects.GetNewReleases(ctx, offset, limit+1)
+	case models.RowContinueWatching:
+		row.Title = "Continue watching"
+		if userID != 0 {
+			items, err = h.projects.GetContinueWatching(ctx, userID, offset, limit+1)
+		}
+	case models.RowFavoriteGenre:
+		if userID == 0 {
+			break
+		}
+
+		genre, gerr := h.projects.GetFavoriteGenre(ctx, userID)
+		if errors.Is(gerr, sql.ErrNoRows) {
+			break
+		}
+		if gerr != nil {
+			return models.HomeRow{}, gerr
+		}
+
+		row.Title = "Because you like " + genre.Name
+		items, err = h.projects.GetByGenre(ctx, genre.ID, offset, limit+1)
+	default:
+		return models.HomeRow{}, ErrUnknownRow.Withf("%q", key)
+	}
+
+	if err != nil {
+		return models.HomeRow{}, err
+	}
+
+	row.Items, row.HasMore = page(items, limit)
+
+	return row, nil
+}
+
+// page trims a result fetched with limit+1 rows and reports whether more
+// rows are available.
+func page(items []models.ProjectCard, limit int) ([]models.ProjectCard, bool) {
+	if items == nil {
+		return []models.ProjectCard{}, false
+	}
+	if len(items) > limit {
+		return items[:limit], true
+	}
+	return items, false
+}
+
+func appendRow(feed []models.HomeRow, row models.HomeRow) []models.HomeRow {
+	if len(row.Items) == 0 {
+		return feed
+	}
+	return append(feed, row)
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ozinshe/internal/cron"
+	"ozinshe/internal/models"
+	"ozinshe/internal/storage"
+	psql "ozinshe/internal/storage/postgresql"
+	"ozinshe/internal/tracing"
+	"sort"
+	"time"
+)
+
+var ErrUnknownJobKind = errors.New("unknown job kind")
+
+const (
+	// jobBatch is how many jobs a worker claims per round.
+	jobBatch = 5
+	// jobLease must outlast running a whole batch; jobs still running after
+	// it are claimed again by another worker.
+	jobLease = 15 * time.Minute
+	// jobMaxAttempts is how many times a job is tried before it is dead.
+	jobMaxAttempts = 5
+
+	baseJobBackoff = 10 * time.Second
+	maxJobBackoff  = time.Hour
+)
+
+// JobHandler runs one job. It may be called more than once for the same job
+// when a worker dies mid-run.
+type JobHandler func(ctx context.Context, payload json.RawMessage) error
+
+type schedule struct {
+	spec cron.Schedule
+	job  models.JobSchedule
+}
+
+type JobService struct {
+	Storage psql.Job
+
+	handlers  map[string]JobHandler
+	schedules map[string]schedule
+}
+
+func NewJobService(storage psql.Job) *JobService {
+	return &JobService{
+		Storage:   storage,
+		handlers:  make(map[string]JobHandler),
+		schedules: make(map[string]schedule),
+	}
+}
+
+// HandleJob registers the handler of a job kind with a typed payload.
+func HandleJob[T any](j *JobService, kind string, handle func(ctx context.Context, payload T) error) {
+	j.handlers[kind] = func(ctx context.Context, raw json.RawMessage) error {
+		var payload T
+		if len(raw) > 0 {
+			if err := json.Unmarshal(raw, &payload); err != nil {
+				return fmt.Errorf("decode payload: %w", err)
+			}
+		}
+		return handle(ctx, payload)
+	}
+}
+
+// Schedule registers a recurring job. It panics on an invalid spec since
+// schedules are fixed at startup.
+func (j *JobService) Schedule(name, spec, kind string, payload any) {
+	parsed, err := cron.Parse(spec)
+	if err != nil {
+		panic(fmt.Sprintf("job schedule %s: %v", name, err))
+	}
+	if parsed.Next(time.Now()).IsZero() {
+		panic(fmt.Sprintf("job schedule %s: %q never runs", name, spec))
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		panic(fmt.Sprintf("job schedule %s: %v", name, err))
+	}
+
+	j.schedules[name] = schedule{
+		spec: parsed,
+		job:  models.JobSchedule{Name: name, Spec: spec, Kind: kind, Payload: data},
+	}
+}
+
+// Enqueue queues a job of a registered kind to run at runAt, or right away
+// when runAt is zero.
+func (j *JobService) Enqueue(ctx context.Context, kind string, payload any, runAt time.Time) (int64, error) {
+	const op = "service.job.Enqueue"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if _, ok := j.handlers[kind]; !ok {
+		return 0, fmt.Errorf("%s: %w: %s", op, ErrUnknownJobKind, kind)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if runAt.IsZero() {
+		runAt = time.Now()
+	}
+
+	id, err := j.Storage.Enqueue(ctx, kind, data, runAt, jobMaxAttempts)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (j *JobService) GetAll(ctx context.Context, filter models.JobFilter) ([]models.Job, error) {
+	const op = "service.job.GetAll"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	jobs, err := j.Storage.GetAll(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return jobs, nil
+}
+
+func (j *JobService) GetById(ctx context.Context, id int64) (models.Job, error) {
+	const op = "service.job.GetById"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	job, err := j.Storage.GetById(ctx, id)
+	if err != nil {
+		return models.Job{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return job, nil
+}
+
+func (j *JobService) Retry(ctx context.Context, id int64) error {
+	const op = "service.job.Retry"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	err := j.Storage.Retry(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (j *JobService) Schedules(ctx context.Context) ([]models.JobSchedule, error) {
+	const op = "service.job.Schedules"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	schedules, err := j.Storage.GetSchedules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return schedules, nil
+}
+
+// Kinds returns the registered job kinds.
+func (j *JobService) Kinds() []string {
+	kinds := make([]string, 0, len(j.handlers))
+	for kind := range j.handlers {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	return kinds
+}
+
+// SyncSchedules stores the registered schedules. Schedules that are new or
+// whose spec changed are due at the next matching time.
+func (j *JobService) SyncSchedules(ctx context.Context) error {
+	const op = "service.job.SyncSchedules"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	for _, s := range j.schedules {
+		job := s.job
+		job.NextRunAt = s.spec.Next(time.Now())
+
+		if err := j.Storage.SaveSchedule(ctx, job); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+// EnqueueScheduled queues the runs of the schedules that are due and reports
+// how many were queued. Runs missed while no worker was up are queued once.
+func (j *JobService) EnqueueScheduled(ctx context.Context) (int, error) {
+	const op = "service.job.EnqueueScheduled"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	queued, err := j.Storage.EnqueueScheduled(ctx, jobMaxAttempts, func(job models.JobSchedule) time.Time {
+		spec, ok := j.schedules[job.Name]
+		if !ok {
+			// Schedules registered by another build; parse the stored spec.
+			parsed, err := cron.Parse(job.Spec)
+			if err != nil {
+				return time.Now().Add(24 * time.Hour)
+			}
+			spec.spec = parsed
+		}
+		return spec.spec.Next(time.Now())
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return queued, nil
+}
+
+// RunDue claims due jobs and runs them one after another, reporting how many
+// were run. Failed jobs are queued again with exponential backoff until
+// jobMaxAttempts is reached; jobs without a handler are dead right away.
+func (j *JobService) RunDue(ctx context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, jobLease)
+	defer cancel()
+
+	const op = "service.job.RunDue"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	jobs, err := j.Storage.Claim(ctx, jobBatch, jobLease)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, job := range jobs {
+		runErr := j.run(ctx, job)
+		if runErr == nil {
+			err = j.Storage.Complete(ctx, job.ID, job.Attempts)
+		} else {
+			var retryAt *time.Time
+			if job.Attempts < job.MaxAttempts && !errors.Is(runErr, ErrUnknownJobKind) {
+				at := time.Now().Add(jobBackoff(job.Attempts))
+				retryAt = &at
+			}
+			err = j.Storage.Fail(ctx, job.ID, job.Attempts, runErr.Error(), retryAt)
+		}
+		// A job that outran its lease belongs to whichever worker claimed
+		// it again, which records how that run went instead.
+		if err != nil && !errors.Is(err, storage.ErrJobLeaseLost) {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return len(jobs), nil
+}
+
+// run calls the handler of a job, turning a panic into an error.
+func (j *JobService) run(ctx context.Context, job models.Job) (err error) {
+	handler, ok := j.handlers[job.Kind]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownJobKind, job.Kind)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return handler(ctx, job.Payload)
+}
+
+// jobBackoff returns how long to wait before retrying a job that failed
+// attempts times: 10s, 20s, 40s... capped at 1h.
+func jobBackoff(attempts int) time.Duration {
+	backoff := baseJobBackoff
+	for i := 1; i < attempts && backoff < maxJobBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, maxJobBackoff)
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"ozinshe/internal/models"
 	psql "ozinshe/internal/storage/postgresql"
-	"time"
 )
 
 type KeywordService struct {
@@ -15,9 +14,6 @@ func NewKeywordService(keyword psql.Keyword) *KeywordService {
 	return &KeywordService{Keyword: keyword}
 }
 
-func (k *KeywordService) Add(keywords []models.Keyword) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (k *KeywordService) Add(ctx context.Context, keywords []models.Keyword) error {
 	return k.Keyword.Insert(ctx, keywords)
 }
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ozinshe/internal/apperr"
+	"ozinshe/internal/metrics"
+	"ozinshe/internal/models"
+	"ozinshe/internal/storage"
+	psql "ozinshe/internal/storage/postgresql"
+	"ozinshe/internal/tracing"
+	"strings"
+)
+
+var (
+	ErrListNameRequired = apperr.Validation("list_name_required", "list name is required", map[string]string{"name": "is required"})
+	ErrBuiltinList      = apperr.Forbidden("builtin_list", "built-in lists can not be deleted")
+)
+
+type ListService struct {
+	Storage psql.List
+}
+
+func NewListService(storage psql.List) *ListService {
+	return &ListService{Storage: storage}
+}
+
+// owned returns the list if it belongs to the user. Lists of other users are
+// reported as missing so their IDs don't leak.
+func (l *ListService) owned(ctx context.Context, userID, listID int) (models.List, error) {
+	list, err := l.Storage.GetById(ctx, listID)
+	if err != nil {
+		return models.List{}, err
+	}
+
+	if list.UserID != userID {
+		return models.List{}, storage.ErrListNotFound
+	}
+
+	return list, nil
+}
+
+func (l *ListService) GetAll(ctx context.Context, userID int) ([]models.List, error) {
+	const op = "service.list.GetAll"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	err := l.Storage.EnsureBuiltins(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	lists, err := l.Storage.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return lists, nil
+}
+
+func (l *ListService) Create(ctx context.Context, list models.List) (int, error) {
+	const op = "service.list.Create"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	list.Name = strings.TrimSpace(list.Name)
+	if list.Name == "" {
+		return 0, fmt.Errorf("%s: %w", op, ErrListNameRequired)
+	}
+	list.Kind = models.ListCustom
+
+	id, err := l.Storage.Insert(ctx, list)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (l *ListService) Get(ctx context.Context, userID, listID, offset, limit int) (models.List, error) {
+	const op = "service.list.Get"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	list, err := l.owned(ctx, userID, listID)
+	if err != nil {
+		return models.List{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	list.Items, err = l.Storage.GetItems(ctx, list.ID, offset, limit)
+	if err != nil {
+		return models.List{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return list, nil
+}
+
+// GetShared returns a public list by its sharing link. Private lists are
+// reported as missing.
+func (l *ListService) GetShared(ctx context.Context, token string, offset, limit int) (models.List, error) {
+	const op = "service.list.GetShared"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	list, err := l.Storage.GetByShareToken(ctx, token)
+	if err != nil {
+		return models.List{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !list.IsPublic {
+		return models.List{}, fmt.Errorf("%s: %w", op, storage.ErrListNotFound)
+	}
+
+	list.Items, err = l.Storage.GetItems(ctx, list.ID, offset, limit)
+	if err != nil {
+		return models.List{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return list, nil
+}
+
+// Update renames a list and changes its visibility. Built-in lists keep
+// their names.
+func (l *ListService) Update(ctx context.Context, userID, listID int, name string, isPublic bool) error {
+	const op = "service.list.Update"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	list, err := l.owned(ctx, userID, listID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if name = strings.TrimSpace(name); name != "" && !list.IsBuiltin() {
+		list.Name = name
+	}
+	list.IsPublic = isPublic
+
+	err = l.Storage.Update(ctx, list)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (l *ListService) Remove(ctx context.Context, userID, listID int) error {
+	const op = "service.list.Remove"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	list, err := l.owned(ctx, userID, listID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if list.IsBuiltin() {
+		return fmt.Errorf("%s: %w", op, ErrBuiltinList)
+	}
+
+	err = l.Storage.Delete(ctx, list.ID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (l *ListService) AddItem(ctx context.Context, userID, listID, projectID int, note string) error {
+	const op = "service.list.AddItem"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	list, err := l.owned(ctx, userID, listID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = l.Storage.InsertItem(ctx, list.ID, projectID, note)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (l *ListService) MoveItem(ctx context.Context, userID, listID, projectID, position int) error {
+	const op = "service.list.MoveItem"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	list, err := l.owned(ctx, userID, listID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = l.Storage.MoveItem(ctx, list.ID, projectID, position)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (l *ListService) SetItemNote(ctx context.Context, userID, listID, projectID int, note string) error {
+	const op = "service.list.SetItemNote"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	list, err := l.owned(ctx, userID, listID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = l.Storage.UpdateItemNote(ctx, list.ID, projectID, note)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (l *ListService) RemoveItem(ctx context.Context, userID, listID, projectID int) error {
+	const op = "service.list.RemoveItem"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	list, err := l.owned(ctx, userID, listID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = l.Storage.DeleteItem(ctx, list.ID, projectID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (l *ListService) AddToFavorites(ctx context.Context, userID, projectID int) error {
+	const op = "service.list.AddToFavorites"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	favorites, err := l.favorites(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = l.Storage.InsertItem(ctx, favorites.ID, projectID, "")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	metrics.Favorites.Inc()
+
+	return nil
+}
+
+// RemoveFromFavorites is a no-op for projects that are not in the favorites.
+func (l *ListService) RemoveFromFavorites(ctx context.Context, userID, projectID int) error {
+	const op = "service.list.RemoveFromFavorites"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	favorites, err := l.favorites(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = l.Storage.DeleteItem(ctx, favorites.ID, projectID)
+	if err != nil && !errors.Is(err, storage.ErrListItemNotFound) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (l *ListService) favorites(ctx context.Context, userID int) (models.List, error) {
+	err := l.Storage.EnsureBuiltins(ctx, userID)
+	if err != nil {
+		return models.List{}, err
+	}
+
+	return l.Storage.GetBuiltin(ctx, userID, models.ListFavorites)
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ozinshe/internal/helper"
+	psql "ozinshe/internal/storage/postgresql"
+	"ozinshe/internal/tracing"
+	"ozinshe/util"
+	"strconv"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// Job kinds of the maintenance tasks.
+const (
+	JobRecomputePopularity = "popularity.recompute"
+	JobCleanupUploads      = "uploads.cleanup"
+	JobPurgeTokens         = "tokens.purge"
+)
+
+// uploadTables maps the upload directories with per-record subdirectories to
+// the tables of those records.
+var uploadTables = map[string]string{
+	"movies":      "movies",
+	"series":      "series",
+	"people":      "people",
+	"collections": "collections",
+}
+
+type MaintenanceService struct {
+	Storage psql.Maintenance
+}
+
+func NewMaintenanceService(storage psql.Maintenance) *MaintenanceService {
+	return &MaintenanceService{Storage: storage}
+}
+
+// register adds the maintenance jobs and their schedules.
+func (m *MaintenanceService) register(jobs *JobService) {
+	HandleJob(jobs, JobRecomputePopularity, func(ctx context.Context, _ struct{}) error {
+		return m.RecomputePopularity(ctx)
+	})
+	HandleJob(jobs, JobCleanupUploads, func(ctx context.Context, _ struct{}) error {
+		return m.CleanupUploads(ctx)
+	})
+	HandleJob(jobs, JobPurgeTokens, func(ctx context.Context, _ struct{}) error {
+		return m.PurgeExpiredTokens(ctx)
+	})
+
+	jobs.Schedule(JobRecomputePopularity, "*/30 * * * *", JobRecomputePopularity, struct{}{})
+	jobs.Schedule(JobCleanupUploads, "0 4 * * *", JobCleanupUploads, struct{}{})
+	jobs.Schedule(JobPurgeTokens, "@hourly", JobPurgeTokens, struct{}{})
+}
+
+func (m *MaintenanceService) RecomputePopularity(ctx context.Context) error {
+	const op = "service.maintenance.RecomputePopularity"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if err := m.Storage.RecomputePopularity(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// CleanupUploads removes the upload directories of movies, series, people
+// and collections that no longer exist.
+func (m *MaintenanceService) CleanupUploads(ctx context.Context) error {
+	const op = "service.maintenance.CleanupUploads"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	for dir, table := range uploadTables {
+		ids, err := helper.UploadIDs(dir)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		missing, err := m.Storage.MissingIDs(ctx, table, ids)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		for _, id := range missing {
+			if err := helper.DeleteDirectory(dir + "/" + strconv.Itoa(id)); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// PurgeExpiredTokens signs out the users whose refresh token has expired.
+// Tokens that fail validation for other reasons, such as a rotated key, are
+// left alone.
+func (m *MaintenanceService) PurgeExpiredTokens(ctx context.Context) error {
+	const op = "service.maintenance.PurgeExpiredTokens"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	tokens, err := m.Storage.GetRefreshTokens(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var expired []int
+	for userID, token := range tokens {
+		_, err := util.ValidateToken(token)
+
+		var validationErr *jwt.ValidationError
+		if errors.As(err, &validationErr) && validationErr.Errors&jwt.ValidationErrorExpired != 0 {
+			expired = append(expired, userID)
+		}
+	}
+
+	if len(expired) == 0 {
+		return nil
+	}
+
+	if err := m.Storage.ClearTokens(ctx, expired); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -1,76 +1,198 @@
 package service
 
 import (
+	"context"
+	"ozinshe/internal/cache"
+	"ozinshe/internal/events"
 	"ozinshe/internal/models"
 	psql "ozinshe/internal/storage/postgresql"
+	"time"
 )
 
 type User interface {
-	Register(user models.User) error
-	Login(user models.User) (string, string, error)
-	UpdateAllTokens(signedToken string, signedRefreshToken string, user_type string, id string) (string, string, error)
-	Remove(id int) error
-	DeleteTokensByEmail(email string) error
-	UpdatePassword(email string, current_password, new_password string) error
-	UpdateProfile(user models.User) error
-	GetById(id string) (models.User, error)
-	GetAll() ([]models.User, error)
+	Register(ctx context.Context, user models.User) error
+	Login(ctx context.Context, user models.User) (string, string, error)
+	UpdateAllTokens(ctx context.Context, signedToken string, signedRefreshToken string, user_type string, id string) (string, string, error)
+	Remove(ctx context.Context, id int) error
+	DeleteTokensByEmail(ctx context.Context, email string) error
+	UpdatePassword(ctx context.Context, email string, current_password, new_password string) error
+	UpdateProfile(ctx context.Context, user models.User) error
+	GetById(ctx context.Context, id string) (models.User, error)
+	GetAll(ctx context.Context) ([]models.User, error)
 }
 
 type Movie interface {
-	Add(movie models.Movie, image_data models.SavePhoto) (int, error)
-	AddToFavorites(movieID, userID int) error
-	RemoveFromFavorites(movieID, userID int) error
-	Remove(id int) error
-	Update(id int, movie models.Movie) error
-	UpdateCover(id int, image_data models.SavePhoto) error
-	UpdateScreenshots(id int, image_data models.SavePhoto) error
-	GetById(id int) (models.Movie, error)
-	GetAll() ([]models.Movie, error)
-	GetFavorites(userID int) ([]models.Movie, error)
-	GetFiltered(filter models.FilterParams) ([]models.Movie, error)
+	Add(ctx context.Context, movie models.Movie, image_data models.SavePhoto) (int, error)
+	Remove(ctx context.Context, id int) error
+	Update(ctx context.Context, id int, movie models.Movie) error
+	UpdateCover(ctx context.Context, id int, image_data models.SavePhoto) error
+	UpdateScreenshots(ctx context.Context, id int, image_data models.SavePhoto) error
+	GetById(ctx context.Context, id int) (models.Movie, error)
+	GetAll(ctx context.Context) ([]models.Movie, error)
+	GetFavorites(ctx context.Context, userID int) ([]models.Movie, error)
+	GetFiltered(ctx context.Context, filter models.FilterParams) ([]models.Movie, error)
 }
 
 type Series interface {
-	Add(series models.Series, image_data models.SavePhoto) (int, error)
-	AddToFavorites(movieID, userID int) error
-	RemoveFromFavorites(movieID, userID int) error
-	Remove(id int) error
-	GetById(id int) (models.Series, error)
-	GetAll() ([]models.Series, error)
-	GetFavorites(userID int) ([]models.Series, error)
-	GetSeason(seriesID, seasonNumber int) ([]models.Episode, error)
-	GetEpisode(seriesID, seasonNumber, episodeNumber int) (models.Episode, error)
-	Update(id int, series models.Series) error
-	UpdateCover(id int, image_data models.SavePhoto) error
-	UpdateScreenshots(id int, image_data models.SavePhoto) error
-	GetFiltered(filter models.FilterParams) ([]models.Series, error)
+	Add(ctx context.Context, series models.Series, image_data models.SavePhoto) (int, error)
+	Remove(ctx context.Context, id int) error
+	GetById(ctx context.Context, id int) (models.Series, error)
+	GetAll(ctx context.Context) ([]models.Series, error)
+	GetFavorites(ctx context.Context, userID int) ([]models.Series, error)
+	GetSeason(ctx context.Context, seriesID, seasonNumber int) ([]models.Episode, error)
+	GetEpisode(ctx context.Context, seriesID, seasonNumber, episodeNumber int) (models.Episode, error)
+	Update(ctx context.Context, id int, series models.Series) error
+	UpdateCover(ctx context.Context, id int, image_data models.SavePhoto) error
+	UpdateScreenshots(ctx context.Context, id int, image_data models.SavePhoto) error
+	GetFiltered(ctx context.Context, filter models.FilterParams) ([]models.Series, error)
 }
 
 type Genre interface {
-	Add(genres []models.Genre) error
-	Remove(id int) error
-	GetById(id int) (models.Genre, error)
-	GetAll() ([]models.Genre, error)
+	Add(ctx context.Context, genres []models.Genre) error
+	Remove(ctx context.Context, id int) error
+	GetById(ctx context.Context, id int) (models.Genre, error)
+	GetAll(ctx context.Context) ([]models.Genre, error)
 }
 
 type AgeCategory interface {
-	Add(age_category []models.AgeCategory) error
-	Remove(id int) error
-	GetById(id int) (models.AgeCategory, error)
-	GetAll() ([]models.AgeCategory, error)
+	Add(ctx context.Context, age_category []models.AgeCategory) error
+	Remove(ctx context.Context, id int) error
+	GetById(ctx context.Context, id int) (models.AgeCategory, error)
+	GetAll(ctx context.Context) ([]models.AgeCategory, error)
 }
 
 type Keyword interface {
-	Add(keywords []models.Keyword) error
+	Add(ctx context.Context, keywords []models.Keyword) error
 }
 
 type Project interface {
-	Add(project models.Project) (int, error)
-	Remove(id int) error
-	GetById(id int) (models.Project, error)
-	AddToFavorites(movieID, userID int) error
-	RemoveFromFavorites(movieID, userID int) error
+	Add(ctx context.Context, project models.Project) (int, error)
+	Remove(ctx context.Context, id int) error
+	GetById(ctx context.Context, id int) (models.Project, error)
+	IsPublished(ctx context.Context, projectType string, contentID int) (bool, error)
+	SetStatus(ctx context.Context, id int, publication models.Publication) error
+	Publications(ctx context.Context, status string, offset, limit int) ([]models.PublicationCard, error)
+	SaveProgress(ctx context.Context, progress models.WatchProgress) error
+}
+
+type Catalog interface {
+	GetProjects(ctx context.Context, ids []int) (map[int]models.Project, error)
+	GetPublished(ctx context.Context, projectType string, offset, limit int) ([]models.Project, error)
+	GetFavoriteIds(ctx context.Context, userID int) ([]int, error)
+	GetMovies(ctx context.Context, ids []int) (map[int]models.Movie, error)
+	GetSeries(ctx context.Context, ids []int) (map[int]models.Series, error)
+	GetGenres(ctx context.Context, projectType string, ids []int) (map[int][]models.Genre, error)
+	GetAgeCategories(ctx context.Context, projectType string, ids []int) (map[int][]models.AgeCategory, error)
+	GetKeywords(ctx context.Context, projectType string, ids []int) (map[int][]models.Keyword, error)
+	GetCovers(ctx context.Context, projectType string, ids []int) (map[int]models.Cover, error)
+	GetScreenshots(ctx context.Context, projectType string, ids []int) (map[int][]models.Screenshot, error)
+	GetSeasons(ctx context.Context, seriesIDs []int) (map[int][]models.Season, error)
+	GetEpisodes(ctx context.Context, seasonIDs []int) (map[int][]models.Episode, error)
+	GetCards(ctx context.Context, projectType string) ([]models.ProjectCard, error)
+	ListMovies(ctx context.Context, parts models.Parts) ([]models.Movie, error)
+	ListSeries(ctx context.Context, parts models.Parts) ([]models.Series, error)
+	LoadMovies(ctx context.Context, ids []int, parts models.Parts) (map[int]models.Movie, error)
+	LoadSeries(ctx context.Context, ids []int, parts models.Parts) (map[int]models.Series, error)
+	ModifiedAt(ctx context.Context, resource string, id int) (time.Time, error)
+	ListModifiedAt(ctx context.Context, resource string) (time.Time, error)
+}
+
+type Sync interface {
+	Changes(ctx context.Context, token string, userID, limit int) (models.Sync, error)
+	Compact(ctx context.Context) (int, error)
+}
+
+type Person interface {
+	Add(ctx context.Context, person models.Person, image_data models.SavePhoto) (int, error)
+	Remove(ctx context.Context, id int) error
+	GetById(ctx context.Context, id int) (models.Person, error)
+	GetAll(ctx context.Context, name string) ([]models.Person, error)
+	GetCredits(ctx context.Context, projectID int) ([]models.Credit, error)
+	SetCredits(ctx context.Context, projectID int, credits []models.Credit) error
+}
+
+type Collection interface {
+	Add(ctx context.Context, collection models.Collection, image_data models.SavePhoto) (int, error)
+	Remove(ctx context.Context, slug string) error
+	GetAll(ctx context.Context) ([]models.Collection, error)
+	SetProjects(ctx context.Context, slug string, projectIDs []int) error
+}
+
+type Home interface {
+	Feed(ctx context.Context, userID, limit int) ([]models.HomeRow, error)
+	Row(ctx context.Context, key string, userID, offset, limit int) (models.HomeRow, error)
+	CollectionRow(ctx context.Context, slug string, offset, limit int) (models.HomeRow, error)
+}
+
+type List interface {
+	GetAll(ctx context.Context, userID int) ([]models.List, error)
+	Create(ctx context.Context, list models.List) (int, error)
+	Get(ctx context.Context, userID, listID, offset, limit int) (models.List, error)
+	GetShared(ctx context.Context, token string, offset, limit int) (models.List, error)
+	Update(ctx context.Context, userID, listID int, name string, isPublic bool) error
+	Remove(ctx context.Context, userID, listID int) error
+	AddItem(ctx context.Context, userID, listID, projectID int, note string) error
+	MoveItem(ctx context.Context, userID, listID, projectID, position int) error
+	SetItemNote(ctx context.Context, userID, listID, projectID int, note string) error
+	RemoveItem(ctx context.Context, userID, listID, projectID int) error
+	AddToFavorites(ctx context.Context, userID, projectID int) error
+	RemoveFromFavorites(ctx context.Context, userID, projectID int) error
+}
+
+type Notification interface {
+	EpisodesAdded(ctx context.Context, seriesID, count int) error
+	ProjectPublished(ctx context.Context, projectID int) error
+	Inbox(ctx context.Context, userID int, unreadOnly bool, offset, limit int) (models.Inbox, error)
+	MarkRead(ctx context.Context, userID, id int) error
+	MarkAllRead(ctx context.Context, userID int) error
+	GetPreferences(ctx context.Context, userID int) (models.NotificationPreferences, error)
+	SetPreferences(ctx context.Context, userID int, prefs models.NotificationPreferences) error
+	Subscribe(userID int) (<-chan models.Notification, func())
+	Listen(ctx context.Context) error
+}
+
+type Webhook interface {
+	Add(ctx context.Context, hook models.Webhook) (models.Webhook, error)
+	Remove(ctx context.Context, id int) error
+	GetAll(ctx context.Context) ([]models.Webhook, error)
+	Deliveries(ctx context.Context, webhookID, offset, limit int) ([]models.WebhookDelivery, error)
+	Redeliver(ctx context.Context, deliveryID int) error
+	Ping(ctx context.Context, webhookID int) (int, error)
+	Publish(ctx context.Context, event models.Event) error
+	DeliverDue(ctx context.Context) (int, error)
+}
+
+type Event interface {
+	RelayPending(ctx context.Context) (int, error)
+}
+
+type Job interface {
+	Enqueue(ctx context.Context, kind string, payload any, runAt time.Time) (int64, error)
+	GetAll(ctx context.Context, filter models.JobFilter) ([]models.Job, error)
+	GetById(ctx context.Context, id int64) (models.Job, error)
+	Retry(ctx context.Context, id int64) error
+	Schedules(ctx context.Context) ([]models.JobSchedule, error)
+	Kinds() []string
+	SyncSchedules(ctx context.Context) error
+	EnqueueScheduled(ctx context.Context) (int, error)
+	RunDue(ctx context.Context) (int, error)
+}
+
+type Audit interface {
+	GetAll(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error)
+}
+
+type Recommendation interface {
+	Recompute(ctx context.Context) error
+	Similar(ctx context.Context, projectID, offset, limit int) ([]models.ProjectCard, error)
+	ForUser(ctx context.Context, userID, offset, limit int) ([]models.ProjectCard, error)
+}
+
+type Health interface {
+	Watch(name string, interval time.Duration)
+	Beat(name string, err error)
+	Drain()
+	Ready(ctx context.Context) models.Readiness
 }
 
 type Service struct {
@@ -81,16 +203,61 @@ type Service struct {
 	Keyword
 	AgeCategory
 	Project
+	Catalog
+	Sync
+	Person
+	Collection
+	Home
+	List
+	Notification
+	Webhook
+	Event
+	Job
+	Audit
+	Recommendation
+	Health
 }
 
-func New(storage *psql.Storage) *Service {
+// New wires the services over storage. store caches the catalog, or is nil
+// for no cache.
+func New(storage *psql.Storage, store *cache.Store) *Service {
+	notification := NewNotificationService(storage.Notification)
+	webhook := NewWebhookService(storage.Webhook)
+
+	bus := events.NewBus()
+	subscribe(bus, notification, webhook)
+	invalidateOnEvents(bus, store)
+
+	jobs := NewJobService(storage.Job)
+	NewMaintenanceService(storage.Maintenance).register(jobs)
+
+	project := NewProjectService(storage.Project)
+	project.register(jobs)
+
+	catalog := NewCatalogService(storage.Project, storage.Movie, storage.Series, storage.Catalog)
+	sync := NewSyncService(storage.Sync, catalog, storage.Genre, storage.AgeCategory)
+	sync.register(jobs)
+
 	return &Service{
-		User:        NewUserService(storage.User),
-		Movie:       NewMovieService(storage.Movie),
-		Series:      NewSeriesService(storage.Series),
-		Genre:       NewGenreService(storage.Genre),
-		Keyword:     NewKeywordService(storage.Keyword),
-		AgeCategory: NewAgeCategoryService(storage.AgeCategory),
-		Project:     NewProjectService(storage.Project),
+		User:           NewUserService(storage.User),
+		Movie:          NewCachedMovieService(NewMovieService(storage.Movie), store),
+		Series:         NewCachedSeriesService(NewSeriesService(storage.Series), store),
+		Genre:          NewCachedGenreService(NewGenreService(storage.Genre), store),
+		Keyword:        NewKeywordService(storage.Keyword),
+		AgeCategory:    NewCachedAgeCategoryService(NewAgeCategoryService(storage.AgeCategory), store),
+		Project:        NewCachedProjectService(project, store),
+		Catalog:        catalog,
+		Sync:           sync,
+		Person:         NewCachedPersonService(NewPersonService(storage.Person), project, store),
+		Collection:     NewCollectionService(storage.Collection),
+		Home:           NewHomeService(storage.Collection, storage.Project),
+		List:           NewListService(storage.List),
+		Notification:   notification,
+		Webhook:        webhook,
+		Event:          NewEventService(storage.Outbox, bus),
+		Job:            jobs,
+		Audit:          NewAuditService(storage.Audit),
+		Recommendation: NewRecommendationService(storage.Recommendation, storage.Project),
+		Health:         NewHealthService(storage.Health),
 	}
 }
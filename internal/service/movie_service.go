@@ -4,11 +4,12 @@ import (
 	"context"
 	"fmt"
 	"ozinshe/internal/helper"
+	"ozinshe/internal/metrics"
 	"ozinshe/internal/models"
 	psql "ozinshe/internal/storage/postgresql"
+	"ozinshe/internal/tracing"
 	"ozinshe/internal/validation"
 	"strconv"
-	"time"
 )
 
 type MovieService struct {
@@ -19,12 +20,11 @@ func NewMovieService(storage psql.Movie) *MovieService {
 	return &MovieService{Storage: storage}
 }
 
-func (movies *MovieService) Add(movie models.Movie, image_data models.SavePhoto) (int, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (movies *MovieService) Add(ctx context.Context, movie models.Movie, image_data models.SavePhoto) (int, error) {
 	var err error
 	const op = "service.movie.Add"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
 	var movie_screenshots []models.Screenshot
 
@@ -61,6 +61,7 @@ func (movies *MovieService) Add(movie models.Movie, image_data models.SavePhoto)
 	if err != nil {
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
+	metrics.Uploads.WithLabelValues("cover").Inc()
 
 	for _, fileHeader := range screenshots {
 		uploadPath := image_data.UploadPath + "/" + strconv.Itoa(movie.ID) + "/screenshots/" + fileHeader.Filename
@@ -69,23 +70,23 @@ func (movies *MovieService) Add(movie models.Movie, image_data models.SavePhoto)
 		if err != nil {
 			return 0, fmt.Errorf("%s: %w", op, err)
 		}
+		metrics.Uploads.WithLabelValues("screenshot").Inc()
 	}
 
 	return movie.ID, nil
 }
 
-func (movies *MovieService) Remove(id int) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (movies *MovieService) Remove(ctx context.Context, id int) error {
 	const op = "service.movie.Remove"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 	err := movies.Storage.Delete(ctx, id)
 
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = helper.DeleteDirectory("uploads/movies/" + strconv.Itoa(id))
+	err = helper.DeleteDirectory("movies/" + strconv.Itoa(id))
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -93,11 +94,14 @@ func (movies *MovieService) Remove(id int) error {
 	return nil
 }
 
-func (movies *MovieService) Update(id int, movie models.Movie) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (movies *MovieService) Update(ctx context.Context, id int, movie models.Movie) error {
 	const op = "service.movie.Update"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if err := checkCredits(movie.Credits); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
 
 	movie.ID = id
 	err := movies.Storage.Update(ctx, movie)
@@ -109,13 +113,12 @@ func (movies *MovieService) Update(id int, movie models.Movie) error {
 	return nil
 }
 
-func (movies *MovieService) UpdateCover(id int, image_data models.SavePhoto) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (movies *MovieService) UpdateCover(ctx context.Context, id int, image_data models.SavePhoto) error {
 	const op = "service.movie.UpdateCover"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
-	err := helper.DeleteDirectory("uploads/movies/" + strconv.Itoa(id) + "/covers")
+	err := helper.DeleteDirectory("movies/" + strconv.Itoa(id) + "/covers")
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -130,6 +133,7 @@ func (movies *MovieService) UpdateCover(id int, image_data models.SavePhoto) err
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
+	metrics.Uploads.WithLabelValues("cover").Inc()
 
 	err = movies.Storage.UpdateCover(ctx, id, models.Cover{
 		Filename: cover[0].Filename,
@@ -142,14 +146,13 @@ func (movies *MovieService) UpdateCover(id int, image_data models.SavePhoto) err
 	return nil
 }
 
-func (movies *MovieService) UpdateScreenshots(id int, image_data models.SavePhoto) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (movies *MovieService) UpdateScreenshots(ctx context.Context, id int, image_data models.SavePhoto) error {
 	const op = "service.movie.UpdateScreenshots"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 	var err error
 
-	err = helper.DeleteDirectory("uploads/movies/" + strconv.Itoa(id) + "/screenshots")
+	err = helper.DeleteDirectory("movies/" + strconv.Itoa(id) + "/screenshots")
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -171,6 +174,7 @@ func (movies *MovieService) UpdateScreenshots(id int, image_data models.SavePhot
 		if err != nil {
 			return fmt.Errorf("%s: %w", op, err)
 		}
+		metrics.Uploads.WithLabelValues("screenshot").Inc()
 
 		movie_screenshots = append(movie_screenshots, models.Screenshot{
 			Filename: fileHeader.Filename,
@@ -185,11 +189,10 @@ func (movies *MovieService) UpdateScreenshots(id int, image_data models.SavePhot
 	return nil
 }
 
-func (movies *MovieService) GetById(id int) (models.Movie, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (movies *MovieService) GetById(ctx context.Context, id int) (models.Movie, error) {
 	const op = "service.movie.GetById"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
 	movie, err := movies.Storage.GetById(ctx, id)
 	if err != nil {
@@ -199,11 +202,10 @@ func (movies *MovieService) GetById(id int) (models.Movie, error) {
 	return movie, nil
 }
 
-func (movies *MovieService) GetAll() ([]models.Movie, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (movies *MovieService) GetAll(ctx context.Context) ([]models.Movie, error) {
 	const op = "service.movie.GetAll"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
 	movies_list, err := movies.Storage.GetAll(ctx)
 	if err != nil {
@@ -213,41 +215,10 @@ func (movies *MovieService) GetAll() ([]models.Movie, error) {
 	return movies_list, nil
 }
 
-func (movies *MovieService) AddToFavorites(movieID, userID int) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	const op = "service.movie.AddToFavorites"
-
-	err := movies.Storage.InsertToFavorites(ctx, movieID, userID)
-
-	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
-	}
-
-	return nil
-}
-
-func (movies *MovieService) RemoveFromFavorites(movieID, userID int) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	const op = "service.movie.RemoveFromFavorites"
-
-	err := movies.Storage.DeleteFromFavorites(ctx, movieID, userID)
-
-	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
-	}
-
-	return nil
-}
-
-func (movies *MovieService) GetFavorites(userID int) ([]models.Movie, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (movies *MovieService) GetFavorites(ctx context.Context, userID int) ([]models.Movie, error) {
 	const op = "service.movie.GetFavorites"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
 	movies_list, err := movies.Storage.GetFavorites(ctx, userID)
 	if err != nil {
@@ -257,11 +228,10 @@ func (movies *MovieService) GetFavorites(userID int) ([]models.Movie, error) {
 	return movies_list, nil
 }
 
-func (movies *MovieService) GetFiltered(filter models.FilterParams) ([]models.Movie, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (movies *MovieService) GetFiltered(ctx context.Context, filter models.FilterParams) ([]models.Movie, error) {
 	const op = "service.movie.GetFiltered"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
 	var err error
 	var moviesWithTitle []models.Movie
@@ -300,5 +270,25 @@ func (movies *MovieService) GetFiltered(filter models.FilterParams) ([]models.Mo
 		}
 	}
 
+	if filter.PersonID != 0 {
+		moviesWithPerson, err := movies.Storage.GetByPerson(ctx, filter.PersonID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: error fetching movies by person: %w", op, err)
+		}
+
+		credited := make(map[int]struct{}, len(moviesWithPerson))
+		for _, movie := range moviesWithPerson {
+			credited[movie.ID] = struct{}{}
+		}
+
+		var moviesWithYearAndPerson []models.Movie
+		for _, movie := range moviesWithYear {
+			if _, ok := credited[movie.ID]; ok {
+				moviesWithYearAndPerson = append(moviesWithYearAndPerson, movie)
+			}
+		}
+		moviesWithYear = moviesWithYearAndPerson
+	}
+
 	return moviesWithYear, nil
 }
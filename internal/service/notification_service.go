@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"ozinshe/internal/models"
+	psql "ozinshe/internal/storage/postgresql"
+	"ozinshe/internal/tracing"
+	"sync"
+	"time"
+)
+
+// subscriberBuffer is how many notifications a live subscriber may lag behind
+// before new ones are dropped for it. Dropped notifications are still in the
+// inbox.
+const subscriberBuffer = 16
+
+type NotificationService struct {
+	Storage psql.Notification
+
+	mu          sync.Mutex
+	subscribers map[int]map[chan models.Notification]struct{}
+}
+
+func NewNotificationService(storage psql.Notification) *NotificationService {
+	return &NotificationService{
+		Storage:     storage,
+		subscribers: make(map[int]map[chan models.Notification]struct{}),
+	}
+}
+
+// EpisodesAdded notifies the users following a series that count episodes
+// were added to it.
+func (n *NotificationService) EpisodesAdded(ctx context.Context, seriesID, count int) error {
+	const op = "service.notification.EpisodesAdded"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	body := "A new episode is available"
+	if count > 1 {
+		body = fmt.Sprintf("%d new episodes are available", count)
+	}
+
+	if _, err := n.Storage.InsertNewEpisodes(ctx, seriesID, body); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ProjectPublished notifies the users about a newly published project.
+func (n *NotificationService) ProjectPublished(ctx context.Context, projectID int) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	const op = "service.notification.ProjectPublished"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if _, err := n.Storage.InsertNewRelease(ctx, projectID, "Now available to watch"); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (n *NotificationService) Inbox(ctx context.Context, userID int, unreadOnly bool, offset, limit int) (models.Inbox, error) {
+	const op = "service.notification.Inbox"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	var inbox models.Inbox
+	var err error
+
+	inbox.Notifications, err = n.Storage.GetByUser(ctx, userID, unreadOnly, offset, limit)
+	if err != nil {
+		return models.Inbox{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	inbox.Unread, err = n.Storage.CountUnread(ctx, userID)
+	if err != nil {
+		return models.Inbox{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return inbox, nil
+}
+
+func (n *NotificationService) MarkRead(ctx context.Context, userID, id int) error {
+	const op = "service.notification.MarkRead"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	err := n.Storage.MarkRead(ctx, userID, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (n *NotificationService) MarkAllRead(ctx context.Context, userID int) error {
+	const op = "service.notification.MarkAllRead"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	err := n.Storage.MarkAllRead(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (n *NotificationService) GetPreferences(ctx context.Context, userID int) (models.NotificationPreferences, error) {
+	const op = "service.notification.GetPreferences"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	prefs, err := n.Storage.GetPreferences(ctx, userID)
+	if err != nil {
+		return models.NotificationPreferences{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return prefs, nil
+}
+
+func (n *NotificationService) SetPreferences(ctx context.Context, userID int, prefs models.NotificationPreferences) error {
+	const op = "service.notification.SetPreferences"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	err := n.Storage.SavePreferences(ctx, userID, prefs)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Subscribe returns a channel receiving the user's new notifications as they
+// are created, by any process, and a function to stop receiving them. Only
+// a process running Listen receives them.
+func (n *NotificationService) Subscribe(userID int) (<-chan models.Notification, func()) {
+	ch := make(chan models.Notification, subscriberBuffer)
+
+	n.mu.Lock()
+	if n.subscribers[userID] == nil {
+		n.subscribers[userID] = make(map[chan models.Notification]struct{})
+	}
+	n.subscribers[userID][ch] = struct{}{}
+	n.mu.Unlock()
+
+	unsubscribe := func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+
+		delete(n.subscribers[userID], ch)
+		if len(n.subscribers[userID]) == 0 {
+			delete(n.subscribers, userID)
+		}
+	}
+
+	return ch, unsubscribe
+}
+
+// Listen delivers the notifications created by every process to the
+// subscribers of this one until ctx is done.
+func (n *NotificationService) Listen(ctx context.Context) error {
+	const op = "service.notification.Listen"
+
+	if err := n.Storage.Listen(ctx, n.publish); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (n *NotificationService) publish(notification models.Notification) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for ch := range n.subscribers[notification.UserID] {
+		select {
+		case ch <- notification:
+		default:
+		}
+	}
+}
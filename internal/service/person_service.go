@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"ozinshe/internal/apperr"
+	"ozinshe/internal/helper"
+	"ozinshe/internal/metrics"
+	"ozinshe/internal/models"
+	psql "ozinshe/internal/storage/postgresql"
+	"ozinshe/internal/tracing"
+	"ozinshe/internal/validation"
+	"strconv"
+)
+
+var (
+	ErrInvalidRole = apperr.Validation("invalid_credit_role", "invalid credit role", nil)
+)
+
+type PersonService struct {
+	Storage psql.Person
+}
+
+func NewPersonService(storage psql.Person) *PersonService {
+	return &PersonService{Storage: storage}
+}
+
+func (p *PersonService) Add(ctx context.Context, person models.Person, image_data models.SavePhoto) (int, error) {
+	var err error
+	const op = "service.person.Add"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	photo := image_data.File_form.File["photo"]
+	if len(photo) > 0 {
+		if err := validation.ValidateImageFile(photo[0], image_data.MaxImageSize); err != nil {
+			return 0, err
+		}
+		person.Photo = photo[0].Filename
+	}
+
+	person.ID, err = p.Storage.Insert(ctx, person)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(photo) > 0 {
+		uploadPath := image_data.UploadPath + strconv.Itoa(person.ID) + "/" + photo[0].Filename
+		err = helper.ProcessSaving(photo[0], uploadPath)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+		metrics.Uploads.WithLabelValues("photo").Inc()
+	}
+
+	return person.ID, nil
+}
+
+func (p *PersonService) Remove(ctx context.Context, id int) error {
+	const op = "service.person.Remove"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	err := p.Storage.Delete(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = helper.DeleteDirectory("people/" + strconv.Itoa(id))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (p *PersonService) GetById(ctx context.Context, id int) (models.Person, error) {
+	const op = "service.person.GetById"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	person, err := p.Storage.GetById(ctx, id)
+	if err != nil {
+		return models.Person{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return person, nil
+}
+
+func (p *PersonService) GetAll(ctx context.Context, name string) ([]models.Person, error) {
+	const op = "service.person.GetAll"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	people, err := p.Storage.GetAll(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return people, nil
+}
+
+func (p *PersonService) GetCredits(ctx context.Context, projectID int) ([]models.Credit, error) {
+	const op = "service.person.GetCredits"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	credits, err := p.Storage.GetCredits(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return credits, nil
+}
+
+// SetCredits replaces the credits of a project. The order of credits is kept
+// as their billing position.
+func (p *PersonService) SetCredits(ctx context.Context, projectID int, credits []models.Credit) error {
+	const op = "service.person.SetCredits"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if err := checkCredits(credits); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err := p.Storage.ReplaceCredits(ctx, projectID, credits)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// checkCredits checks the roles of credits and clears the character of
+// credits other than actors.
+func checkCredits(credits []models.Credit) error {
+	for i := range credits {
+		if !models.IsValidRole(credits[i].Role) {
+			return ErrInvalidRole.Withf("%q", credits[i].Role)
+		}
+		if credits[i].Role != models.RoleActor {
+			credits[i].Character = ""
+		}
+	}
+
+	return nil
+}
@@ -3,11 +3,32 @@ package service
 import (
 	"context"
 	"fmt"
+	"ozinshe/internal/apperr"
 	"ozinshe/internal/models"
 	psql "ozinshe/internal/storage/postgresql"
+	"ozinshe/internal/tracing"
+	"slices"
 	"time"
 )
 
+// JobPublishDue is the job kind of the activator publishing scheduled
+// projects.
+const JobPublishDue = "projects.publish_due"
+
+var (
+	ErrInvalidPublication = apperr.Validation("invalid_publication", "invalid publication", nil)
+	ErrStatusTransition   = apperr.Conflict("status_transition", "status transition not allowed")
+)
+
+// projectTransitions are the statuses a project can move to from each
+// status. Staying in a status changes its publish window.
+var projectTransitions = map[string][]string{
+	models.ProjectDraft:     {models.ProjectDraft, models.ProjectInReview},
+	models.ProjectInReview:  {models.ProjectDraft, models.ProjectScheduled, models.ProjectPublished},
+	models.ProjectScheduled: {models.ProjectDraft, models.ProjectInReview, models.ProjectScheduled, models.ProjectPublished},
+	models.ProjectPublished: {models.ProjectDraft, models.ProjectPublished},
+}
+
 type ProjectService struct {
 	storage psql.Project
 }
@@ -16,11 +37,14 @@ func NewProjectService(storage psql.Project) *ProjectService {
 	return &ProjectService{storage: storage}
 }
 
-func (p *ProjectService) Add(project models.Project) (int, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (p *ProjectService) Add(ctx context.Context, project models.Project) (int, error) {
 	const op = "service.project.Add"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if err := checkCredits(project.Credits); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
 
 	id, err := p.storage.Insert(ctx, project)
 	if err != nil {
@@ -30,11 +54,10 @@ func (p *ProjectService) Add(project models.Project) (int, error) {
 	return id, nil
 }
 
-func (p *ProjectService) Remove(id int) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (p *ProjectService) Remove(ctx context.Context, id int) error {
 	const op = "service.project.Remove"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
 	err := p.storage.Delete(ctx, id)
 	if err != nil {
@@ -44,11 +67,10 @@ func (p *ProjectService) Remove(id int) error {
 	return nil
 }
 
-func (p *ProjectService) GetById(id int) (models.Project, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (p *ProjectService) GetById(ctx context.Context, id int) (models.Project, error) {
 	const op = "service.project.GetById"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
 	project, err := p.storage.GetById(ctx, id)
 	if err != nil {
@@ -58,27 +80,113 @@ func (p *ProjectService) GetById(id int) (models.Project, error) {
 	return project, nil
 }
 
-func (p *ProjectService) AddToFavorites(movieID, userID int) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+// IsPublished reports whether the public can see the project of a movie or
+// series.
+func (p *ProjectService) IsPublished(ctx context.Context, projectType string, contentID int) (bool, error) {
+	const op = "service.project.IsPublished"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
-	const op = "service.project.AddToFavorites"
+	published, err := p.storage.IsPublished(ctx, projectType, contentID)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return published, nil
+}
+
+// SetStatus moves a project through the publishing workflow. Scheduled
+// projects need a publish time in the future; projects published right away
+// get the current time.
+func (p *ProjectService) SetStatus(ctx context.Context, id int, publication models.Publication) error {
+	const op = "service.project.SetStatus"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
-	err := p.storage.InsertToFavorites(ctx, movieID, userID)
+	project, err := p.storage.GetById(ctx, id)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	if _, ok := projectTransitions[publication.Status]; !ok {
+		return fmt.Errorf("%s: %w", op, ErrInvalidPublication.Withf("unknown status %q", publication.Status))
+	}
+	if !slices.Contains(projectTransitions[project.Status], publication.Status) {
+		return fmt.Errorf("%s: %w", op, ErrStatusTransition.Withf("%s to %s", project.Status, publication.Status))
+	}
+
+	now := time.Now()
+	switch publication.Status {
+	case models.ProjectScheduled:
+		if publication.PublishAt == nil || !publication.PublishAt.After(now) {
+			return fmt.Errorf("%s: %w", op, ErrInvalidPublication.Withf("scheduled projects need a publish time in the future"))
+		}
+	case models.ProjectPublished:
+		if publication.PublishAt == nil {
+			publication.PublishAt = project.PublishAt
+		}
+		if publication.PublishAt == nil || publication.PublishAt.After(now) {
+			publication.PublishAt = &now
+		}
+	}
+
+	if publication.UnpublishAt != nil && publication.PublishAt != nil && !publication.UnpublishAt.After(*publication.PublishAt) {
+		return fmt.Errorf("%s: %w", op, ErrInvalidPublication.Withf("unpublish time must be after the publish time"))
+	}
+
+	if err := p.storage.SetPublication(ctx, id, publication); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	return nil
 }
 
-func (p *ProjectService) RemoveFromFavorites(movieID, userID int) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+// PublishDue publishes the scheduled projects whose publish time has passed
+// and reports how many were published.
+func (p *ProjectService) PublishDue(ctx context.Context) (int, error) {
+	const op = "service.project.PublishDue"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	published, err := p.storage.PublishDue(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return published, nil
+}
+
+// Publications lists all projects with their publication, newest first.
+func (p *ProjectService) Publications(ctx context.Context, status string, offset, limit int) ([]models.PublicationCard, error) {
+	const op = "service.project.Publications"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	cards, err := p.storage.GetPublications(ctx, status, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return cards, nil
+}
+
+// register adds the activator job publishing scheduled projects and runs it
+// every minute.
+func (p *ProjectService) register(jobs *JobService) {
+	HandleJob(jobs, JobPublishDue, func(ctx context.Context, _ struct{}) error {
+		_, err := p.PublishDue(ctx)
+		return err
+	})
+
+	jobs.Schedule(JobPublishDue, "* * * * *", JobPublishDue, struct{}{})
+}
 
-	const op = "service.project.RemoveFromFavorites"
+func (p *ProjectService) SaveProgress(ctx context.Context, progress models.WatchProgress) error {
+	const op = "service.project.SaveProgress"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
-	err := p.storage.DeleteFromFavorites(ctx, movieID, userID)
+	err := p.storage.SaveProgress(ctx, progress)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"ozinshe/internal/models"
+	"ozinshe/internal/recommend"
+	psql "ozinshe/internal/storage/postgresql"
+	"ozinshe/internal/tracing"
+	"time"
+)
+
+// similarPerProject is how many similar projects are cached for each project.
+const similarPerProject = 50
+
+type RecommendationService struct {
+	Storage  psql.Recommendation
+	projects psql.Project
+}
+
+func NewRecommendationService(storage psql.Recommendation, projects psql.Project) *RecommendationService {
+	return &RecommendationService{Storage: storage, projects: projects}
+}
+
+// Recompute rebuilds the cached similarity scores of the whole catalog.
+func (r *RecommendationService) Recompute(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	const op = "service.recommendation.Recompute"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	items, err := r.Storage.LoadItems(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	favorites, err := r.Storage.LoadFavorites(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	similarities := recommend.Compute(items, favorites, recommend.DefaultWeights, similarPerProject)
+
+	err = r.Storage.ReplaceSimilarities(ctx, similarities)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *RecommendationService) Similar(ctx context.Context, projectID, offset, limit int) ([]models.ProjectCard, error) {
+	const op = "service.recommendation.Similar"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	cards, err := r.Storage.GetSimilar(ctx, projectID, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return cards, nil
+}
+
+// ForUser recommends projects similar to the user's favorites. Users without
+// favorites get the trending projects instead.
+func (r *RecommendationService) ForUser(ctx context.Context, userID, offset, limit int) ([]models.ProjectCard, error) {
+	const op = "service.recommendation.ForUser"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	cards, err := r.Storage.GetForUser(ctx, userID, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(cards) == 0 && offset == 0 {
+		cards, err = r.projects.GetTrending(ctx, offset, limit)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return cards, nil
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"ozinshe/internal/helper"
+	"ozinshe/internal/metrics"
 	"ozinshe/internal/models"
+	"ozinshe/internal/storage"
 	psql "ozinshe/internal/storage/postgresql"
+	"ozinshe/internal/tracing"
 	"ozinshe/internal/validation"
 	"strconv"
-	"time"
 )
 
 type SeriesService struct {
@@ -19,12 +21,11 @@ func NewSeriesService(series psql.Series) *SeriesService {
 	return &SeriesService{Storage: series}
 }
 
-func (s *SeriesService) Add(series models.Series, image_data models.SavePhoto) (int, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (s *SeriesService) Add(ctx context.Context, series models.Series, image_data models.SavePhoto) (int, error) {
 	var err error
 	const op = "service.series.Add"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
 	var series_screenshots []models.Screenshot
 
@@ -61,6 +62,7 @@ func (s *SeriesService) Add(series models.Series, image_data models.SavePhoto) (
 	if err != nil {
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
+	metrics.Uploads.WithLabelValues("cover").Inc()
 
 	for _, fileHeader := range screenshots {
 		uploadPath := image_data.UploadPath + strconv.Itoa(series.ID) + "/screenshots/" + fileHeader.Filename
@@ -69,16 +71,16 @@ func (s *SeriesService) Add(series models.Series, image_data models.SavePhoto) (
 		if err != nil {
 			return 0, fmt.Errorf("%s: %w", op, err)
 		}
+		metrics.Uploads.WithLabelValues("screenshot").Inc()
 	}
 
 	return series.ID, nil
 }
 
-func (s *SeriesService) GetAll() ([]models.Series, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (s *SeriesService) GetAll(ctx context.Context) ([]models.Series, error) {
 	const op = "service.series.GetAll"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
 	series, err := s.Storage.GetAll(ctx)
 	if err != nil {
@@ -88,11 +90,10 @@ func (s *SeriesService) GetAll() ([]models.Series, error) {
 	return series, nil
 }
 
-func (s *SeriesService) GetById(id int) (models.Series, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (s *SeriesService) GetById(ctx context.Context, id int) (models.Series, error) {
 	const op = "service.series.GetById"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
 	series, err := s.Storage.GetById(ctx, id)
 	if err != nil {
@@ -102,32 +103,33 @@ func (s *SeriesService) GetById(id int) (models.Series, error) {
 	return series, nil
 }
 
-func (s *SeriesService) GetSeason(seriesID, seasonNumber int) ([]models.Episode, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (s *SeriesService) GetSeason(ctx context.Context, seriesID, seasonNumber int) ([]models.Episode, error) {
 	const op = "service.series.GetSeason"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
 	episodes, err := s.Storage.FetchEpisodes(ctx, seriesID, seasonNumber)
 	if err != nil {
 		return []models.Episode{}, fmt.Errorf("%s: %w", op, err)
 	}
+	if len(episodes) == 0 {
+		return []models.Episode{}, fmt.Errorf("%s: %w", op, storage.ErrSeasonNotFound)
+	}
 
 	return episodes, nil
 }
 
-func (s *SeriesService) Remove(id int) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (s *SeriesService) Remove(ctx context.Context, id int) error {
 	const op = "service.series.Remove"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
 	err := s.Storage.Delete(ctx, id)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = helper.DeleteDirectory("uploads/series/" + strconv.Itoa(id))
+	err = helper.DeleteDirectory("series/" + strconv.Itoa(id))
 	if err != nil {
 		return fmt.Errorf("%s: delete directory: %w", op, err)
 	}
@@ -135,11 +137,14 @@ func (s *SeriesService) Remove(id int) error {
 	return nil
 }
 
-func (s *SeriesService) Update(id int, series models.Series) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (s *SeriesService) Update(ctx context.Context, id int, series models.Series) error {
 	const op = "service.series.Update"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if err := checkCredits(series.Credits); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
 
 	err := s.Storage.Update(ctx, series)
 	if err != nil {
@@ -149,13 +154,12 @@ func (s *SeriesService) Update(id int, series models.Series) error {
 	return nil
 }
 
-func (s *SeriesService) UpdateCover(id int, image_data models.SavePhoto) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (s *SeriesService) UpdateCover(ctx context.Context, id int, image_data models.SavePhoto) error {
 	const op = "service.series.UpdateCover"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
-	err := helper.DeleteDirectory("uploads/series/" + strconv.Itoa(id) + "/covers")
+	err := helper.DeleteDirectory("series/" + strconv.Itoa(id) + "/covers")
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -178,17 +182,17 @@ func (s *SeriesService) UpdateCover(id int, image_data models.SavePhoto) error {
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
+	metrics.Uploads.WithLabelValues("cover").Inc()
 
 	return nil
 }
 
-func (s *SeriesService) UpdateScreenshots(id int, image_data models.SavePhoto) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (s *SeriesService) UpdateScreenshots(ctx context.Context, id int, image_data models.SavePhoto) error {
 	const op = "service.series.UpdateScreenshots"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
-	err := helper.DeleteDirectory("uploads/series/" + strconv.Itoa(id) + "/screenshots")
+	err := helper.DeleteDirectory("series/" + strconv.Itoa(id) + "/screenshots")
 
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
@@ -219,44 +223,16 @@ func (s *SeriesService) UpdateScreenshots(id int, image_data models.SavePhoto) e
 		if err != nil {
 			return fmt.Errorf("%s: %w", op, err)
 		}
+		metrics.Uploads.WithLabelValues("screenshot").Inc()
 	}
 
 	return nil
 }
 
-func (s *SeriesService) AddToFavorites(seriesID, userID int) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	const op = "service.series.AddToFavorites"
-
-	err := s.Storage.InsertToFavorites(ctx, seriesID, userID)
-	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
-	}
-
-	return nil
-}
-
-func (s *SeriesService) RemoveFromFavorites(seriesID, userID int) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	const op = "service.series.RemoveFromFavorites"
-
-	err := s.Storage.DeleteFromFavorites(ctx, seriesID, userID)
-	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
-	}
-
-	return nil
-}
-
-func (s *SeriesService) GetFavorites(userID int) ([]models.Series, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (s *SeriesService) GetFavorites(ctx context.Context, userID int) ([]models.Series, error) {
 	const op = "service.series.GetFavorites"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
 	series_list, err := s.Storage.GetFavorites(ctx, userID)
 	if err != nil {
@@ -266,11 +242,10 @@ func (s *SeriesService) GetFavorites(userID int) ([]models.Series, error) {
 	return series_list, nil
 }
 
-func (series *SeriesService) GetFiltered(filter models.FilterParams) ([]models.Series, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (series *SeriesService) GetFiltered(ctx context.Context, filter models.FilterParams) ([]models.Series, error) {
 	const op = "service.series.GetFiltered"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
 	var err error
 	var seriesWithTitle []models.Series
@@ -295,25 +270,47 @@ func (series *SeriesService) GetFiltered(filter models.FilterParams) ([]models.S
 
 	var seriesWithYear []models.Series
 	if filter.YearStart != 0 || filter.YearEnd != 0 {
-		for _, series := range seriesWithGenres {
-			if series.ReleaseYear >= filter.YearStart && series.ReleaseYear <= filter.YearEnd {
-				seriesWithYear = append(seriesWithYear, series)
+		if len(seriesWithGenres) == 0 {
+			seriesWithYear, err = series.Storage.GetByYear(ctx, filter.YearStart, filter.YearEnd)
+			if err != nil {
+				return nil, fmt.Errorf("%s: error fetching series by year: %w", op, err)
+			}
+		} else {
+			for _, series := range seriesWithGenres {
+				if series.ReleaseYear >= filter.YearStart && series.ReleaseYear <= filter.YearEnd {
+					seriesWithYear = append(seriesWithYear, series)
+				}
 			}
 		}
 	}
 
-	if err != nil {
-		return nil, fmt.Errorf("%s: error during final filtering: %w", op, err)
+	if filter.PersonID != 0 {
+		seriesWithPerson, err := series.Storage.GetByPerson(ctx, filter.PersonID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: error fetching series by person: %w", op, err)
+		}
+
+		credited := make(map[int]struct{}, len(seriesWithPerson))
+		for _, series := range seriesWithPerson {
+			credited[series.ID] = struct{}{}
+		}
+
+		var seriesWithYearAndPerson []models.Series
+		for _, series := range seriesWithYear {
+			if _, ok := credited[series.ID]; ok {
+				seriesWithYearAndPerson = append(seriesWithYearAndPerson, series)
+			}
+		}
+		seriesWithYear = seriesWithYearAndPerson
 	}
 
 	return seriesWithYear, nil
 }
 
-func (s *SeriesService) GetEpisode(seriesID, seasonNumber, episodeNumber int) (models.Episode, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (s *SeriesService) GetEpisode(ctx context.Context, seriesID, seasonNumber, episodeNumber int) (models.Episode, error) {
 	const op = "service.series.GetEpisode"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
 	episode, err := s.Storage.GetEpisode(ctx, seriesID, seasonNumber, episodeNumber)
 	if err != nil {
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"ozinshe/internal/apperr"
+	"ozinshe/internal/models"
+	psql "ozinshe/internal/storage/postgresql"
+	"ozinshe/internal/tracing"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JobCompactSyncLog is the job kind of compacting the sync log.
+const JobCompactSyncLog = "sync.compact"
+
+var ErrInvalidSyncToken = apperr.Validation("invalid_sync_token", "invalid sync token", map[string]string{"since": "must be a token returned by sync"})
+
+// SyncService sends offline clients what changed in the catalog and in
+// their favorites since their last sync. Clients should treat created and
+// updated alike: a creation is reported as an update once the log is
+// compacted.
+type SyncService struct {
+	storage       psql.Sync
+	catalog       Catalog
+	genres        psql.Genre
+	ageCategories psql.AgeCategory
+}
+
+func NewSyncService(storage psql.Sync, catalog Catalog, genres psql.Genre, ageCategories psql.AgeCategory) *SyncService {
+	return &SyncService{storage: storage, catalog: catalog, genres: genres, ageCategories: ageCategories}
+}
+
+// register adds the job compacting the sync log and its schedule.
+func (s *SyncService) register(jobs *JobService) {
+	HandleJob(jobs, JobCompactSyncLog, func(ctx context.Context, _ struct{}) error {
+		_, err := s.Compact(ctx)
+		return err
+	})
+
+	jobs.Schedule(JobCompactSyncLog, "30 4 * * *", JobCompactSyncLog, struct{}{})
+}
+
+// Changes returns the changes since token, reading up to limit entries of
+// the sync log, and the token of the next sync. An empty token starts a
+// full sync. The resources are read after the log, so they may be newer
+// than the token; the next sync sends them again.
+func (s *SyncService) Changes(ctx context.Context, token string, userID, limit int) (models.Sync, error) {
+	const op = "service.sync.Changes"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	since, err := decodeSyncToken(token)
+	if err != nil {
+		return models.Sync{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log, err := s.storage.Changes(ctx, since, userID, limit)
+	if err != nil {
+		return models.Sync{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	changed := latestChanges(log.Changes)
+
+	sync := models.Sync{
+		Token:   encodeSyncToken(log.Next),
+		HasMore: log.More,
+	}
+
+	sync.Projects, err = s.projectChanges(ctx, changed[models.ResourceProjects], log.Next.Time)
+	if err != nil {
+		return models.Sync{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	sync.Genres = models.GenreChanges{Created: []models.Genre{}, Updated: []models.Genre{}, Deleted: []int{}}
+	if len(changed[models.ResourceGenres]) > 0 {
+		genres, err := s.genres.GetAll(ctx)
+		if err != nil {
+			return models.Sync{}, fmt.Errorf("%s: %w", op, err)
+		}
+		byID := make(map[int]models.Genre, len(genres))
+		for _, genre := range genres {
+			byID[genre.ID] = genre
+		}
+		sync.Genres.Created, sync.Genres.Updated, sync.Genres.Deleted = splitChanges(changed[models.ResourceGenres], byID)
+	}
+
+	sync.AgeCategories = models.AgeCategoryChanges{Created: []models.AgeCategory{}, Updated: []models.AgeCategory{}, Deleted: []int{}}
+	if len(changed[models.ResourceAgeCategories]) > 0 {
+		ageCategories, err := s.ageCategories.GetAll(ctx)
+		if err != nil {
+			return models.Sync{}, fmt.Errorf("%s: %w", op, err)
+		}
+		byID := make(map[int]models.AgeCategory, len(ageCategories))
+		for _, ageCategory := range ageCategories {
+			byID[ageCategory.ID] = ageCategory
+		}
+		sync.AgeCategories.Created, sync.AgeCategories.Updated, sync.AgeCategories.Deleted = splitChanges(changed[models.ResourceAgeCategories], byID)
+	}
+
+	if userID != 0 {
+		favorites := &models.FavoriteChanges{Created: []int{}, Deleted: []int{}}
+		for _, change := range changed[models.ResourceFavorites] {
+			if change.Op == models.SyncDelete {
+				favorites.Deleted = append(favorites.Deleted, change.ID)
+			} else {
+				favorites.Created = append(favorites.Created, change.ID)
+			}
+		}
+		sync.Favorites = favorites
+	}
+
+	return sync, nil
+}
+
+// projectChanges loads the changed projects that are published along with
+// their movies and series. The others are deleted for the client.
+func (s *SyncService) projectChanges(ctx context.Context, changes []models.SyncChange, now time.Time) (models.ProjectChanges, error) {
+	result := models.ProjectChanges{Created: []models.SyncProject{}, Updated: []models.SyncProject{}, Deleted: []int{}}
+	if len(changes) == 0 {
+		return result, nil
+	}
+
+	var ids []int
+	for _, change := range changes {
+		if change.Op != models.SyncDelete {
+			ids = append(ids, change.ID)
+		}
+	}
+
+	projects, err := s.catalog.GetProjects(ctx, ids)
+	if err != nil {
+		return result, err
+	}
+
+	var movieIDs, seriesIDs []int
+	for _, project := range projects {
+		if !project.Visible(now) {
+			continue
+		}
+		if project.Project_type == "movie" {
+			movieIDs = append(movieIDs, project.Project_id)
+		} else {
+			seriesIDs = append(seriesIDs, project.Project_id)
+		}
+	}
+
+	movies, err := s.catalog.LoadMovies(ctx, movieIDs, nil)
+	if err != nil {
+		return result, err
+	}
+	series, err := s.catalog.LoadSeries(ctx, seriesIDs, nil)
+	if err != nil {
+		return result, err
+	}
+
+	for _, change := range changes {
+		project, ok := projects[change.ID]
+		if change.Op == models.SyncDelete || !ok || !project.Visible(now) {
+			result.Deleted = append(result.Deleted, change.ID)
+			continue
+		}
+
+		synced := models.SyncProject{ID: project.Id, Type: project.Project_type}
+		if project.Project_type == "movie" {
+			movie, ok := movies[project.Project_id]
+			if !ok {
+				result.Deleted = append(result.Deleted, change.ID)
+				continue
+			}
+			synced.Movie = &movie
+		} else {
+			one, ok := series[project.Project_id]
+			if !ok {
+				result.Deleted = append(result.Deleted, change.ID)
+				continue
+			}
+			synced.Series = &one
+		}
+
+		if change.Op == models.SyncInsert {
+			result.Created = append(result.Created, synced)
+		} else {
+			result.Updated = append(result.Updated, synced)
+		}
+	}
+
+	return result, nil
+}
+
+func (s *SyncService) Compact(ctx context.Context) (int, error) {
+	const op = "service.sync.Compact"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	deleted, err := s.storage.Compact(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deleted, nil
+}
+
+// latestChanges groups changes by resource, keeping one for each ID in the
+// order they were first changed: a deletion when the last change deleted
+// it, otherwise an insertion when any change inserted it, otherwise an
+// update.
+func latestChanges(changes []models.SyncChange) map[string][]models.SyncChange {
+	type key struct {
+		resource string
+		id       int
+	}
+
+	index := make(map[key]int)
+	var latest []models.SyncChange
+	for _, change := range changes {
+		k := key{change.Resource, change.ID}
+		i, ok := index[k]
+		if !ok {
+			index[k] = len(latest)
+			latest = append(latest, change)
+			continue
+		}
+
+		switch {
+		case change.Op == models.SyncDelete:
+			latest[i].Op = models.SyncDelete
+		case latest[i].Op == models.SyncDelete || change.Op == models.SyncInsert:
+			latest[i].Op = change.Op
+		}
+	}
+
+	byResource := make(map[string][]models.SyncChange)
+	for _, change := range latest {
+		byResource[change.Resource] = append(byResource[change.Resource], change)
+	}
+
+	return byResource
+}
+
+// splitChanges sorts the changes of a resource whose current rows are byID
+// into created, updated and deleted ones. Rows that are gone are deleted
+// whatever their last change was.
+func splitChanges[T any](changes []models.SyncChange, byID map[int]T) (created, updated []T, deleted []int) {
+	created, updated, deleted = []T{}, []T{}, []int{}
+	for _, change := range changes {
+		row, ok := byID[change.ID]
+		switch {
+		case change.Op == models.SyncDelete || !ok:
+			deleted = append(deleted, change.ID)
+		case change.Op == models.SyncInsert:
+			created = append(created, row)
+		default:
+			updated = append(updated, row)
+		}
+	}
+	return created, updated, deleted
+}
+
+// Sync tokens are opaque to clients: the URL-safe base64 of the position
+// as "txid.seq.unix microseconds".
+
+func encodeSyncToken(position models.SyncPosition) string {
+	raw := fmt.Sprintf("%d.%d.%d", position.Txid, position.Seq, position.Time.UnixMicro())
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeSyncToken(token string) (models.SyncPosition, error) {
+	if token == "" {
+		return models.SyncPosition{}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return models.SyncPosition{}, ErrInvalidSyncToken.Wrap(err)
+	}
+
+	parts := strings.Split(string(raw), ".")
+	if len(parts) != 3 {
+		return models.SyncPosition{}, ErrInvalidSyncToken
+	}
+
+	txid, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return models.SyncPosition{}, ErrInvalidSyncToken.Wrap(err)
+	}
+	seq, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || seq < 0 {
+		return models.SyncPosition{}, ErrInvalidSyncToken.Wrap(err)
+	}
+	micros, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return models.SyncPosition{}, ErrInvalidSyncToken.Wrap(err)
+	}
+
+	return models.SyncPosition{Txid: txid, Seq: seq, Time: time.UnixMicro(micros)}, nil
+}
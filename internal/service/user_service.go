@@ -3,16 +3,18 @@ package service
 import (
 	"context"
 	"fmt"
+	"ozinshe/internal/apperr"
 	"ozinshe/internal/helper"
+	"ozinshe/internal/metrics"
 	"ozinshe/internal/models"
 	psql "ozinshe/internal/storage/postgresql"
+	"ozinshe/internal/tracing"
 	"ozinshe/util"
 	"strconv"
-	"time"
 )
 
 var (
-	ErrWrongPassword = fmt.Errorf("wrong password")
+	ErrWrongPassword = apperr.Unauthorized("wrong_password", "wrong password")
 )
 
 type UserService struct {
@@ -23,12 +25,11 @@ func NewUserService(storage psql.User) *UserService {
 	return &UserService{Storage: storage}
 }
 
-func (a *UserService) Register(user models.User) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (a *UserService) Register(ctx context.Context, user models.User) error {
 	var err error
 	const op = "service.user.Register"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
 	user.Password = helper.HashPassword(user.Password)
 	user.Token, user.Refresh_Token, err = util.GenerateAllTokens(user.Email, user.Name, user.UserType, strconv.Itoa(user.ID))
@@ -42,24 +43,26 @@ func (a *UserService) Register(user models.User) error {
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
+	metrics.Signups.Inc()
 
 	return nil
 }
 
-func (a *UserService) Login(user models.User) (string, string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (a *UserService) Login(ctx context.Context, user models.User) (string, string, error) {
 	const op = "service.user.Login"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 	var foundUser models.User
 
 	foundUser, err := a.Storage.GetByEmail(ctx, user.Email)
 	if err != nil {
+		metrics.Logins.WithLabelValues("failure").Inc()
 		return "", "", fmt.Errorf("%s: %w", op, err)
 	}
 
 	match, _ := helper.VerifyPassword(user.Password, foundUser.Password)
 	if !match {
+		metrics.Logins.WithLabelValues("failure").Inc()
 		return "", "", fmt.Errorf("%s: %w", op, ErrWrongPassword)
 	}
 
@@ -72,15 +75,16 @@ func (a *UserService) Login(user models.User) (string, string, error) {
 		return "", "", fmt.Errorf("%s: %w", op, err)
 	}
 
-	token, refreshToken, err := a.UpdateAllTokens(foundUser.Token, foundUser.Refresh_Token, foundUser.UserType, strconv.Itoa(foundUser.ID))
+	token, refreshToken, err := a.UpdateAllTokens(ctx, foundUser.Token, foundUser.Refresh_Token, foundUser.UserType, strconv.Itoa(foundUser.ID))
 	if err != nil {
 		return "", "", fmt.Errorf("%s: %w", op, err)
 	}
+	metrics.Logins.WithLabelValues("success").Inc()
 
 	return token, refreshToken, nil
 }
 
-func (a *UserService) UpdateAllTokens(signedToken string, signedRefreshToken string, user_type string, id string) (string, string, error) {
+func (a *UserService) UpdateAllTokens(ctx context.Context, signedToken string, signedRefreshToken string, user_type string, id string) (string, string, error) {
 	const op = "util.UpdateAllTokens"
 
 	_, err := util.ValidateToken(signedRefreshToken)
@@ -88,7 +92,7 @@ func (a *UserService) UpdateAllTokens(signedToken string, signedRefreshToken str
 		return "", "", fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = a.Storage.UpdateTokens(signedToken, signedRefreshToken, user_type)
+	err = a.Storage.UpdateTokens(ctx, signedToken, signedRefreshToken, user_type)
 	if err != nil {
 		return "", "", fmt.Errorf("%s: %w", op, err)
 	}
@@ -96,10 +100,10 @@ func (a *UserService) UpdateAllTokens(signedToken string, signedRefreshToken str
 	return signedToken, signedRefreshToken, nil
 }
 
-func (a *UserService) DeleteTokensByEmail(email string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+func (a *UserService) DeleteTokensByEmail(ctx context.Context, email string) error {
 	const op = "service.user.DeleteTokensByEmail"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
 	err := a.Storage.DeleteTokens(ctx, email)
 	if err != nil {
@@ -109,11 +113,10 @@ func (a *UserService) DeleteTokensByEmail(email string) error {
 	return nil
 }
 
-func (a *UserService) GetById(id string) (models.User, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (a *UserService) GetById(ctx context.Context, id string) (models.User, error) {
 	const op = "service.user.GetById"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
 	user, err := a.Storage.GetByEmail(ctx, id)
 	if err != nil {
@@ -123,11 +126,10 @@ func (a *UserService) GetById(id string) (models.User, error) {
 	return user, nil
 }
 
-func (a *UserService) GetAll() ([]models.User, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (a *UserService) GetAll(ctx context.Context) ([]models.User, error) {
 	const op = "service.user.GetAll"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
 	users, err := a.Storage.GetAll(ctx)
 	if err != nil {
@@ -137,11 +139,10 @@ func (a *UserService) GetAll() ([]models.User, error) {
 	return users, nil
 }
 
-func (a *UserService) UpdatePassword(email string, current_password, new_password string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (a *UserService) UpdatePassword(ctx context.Context, email string, current_password, new_password string) error {
 	const op = "service.user.UpdatePassword"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
 	user, err := a.Storage.GetByEmail(ctx, email)
 	if err != nil {
@@ -160,11 +161,10 @@ func (a *UserService) UpdatePassword(email string, current_password, new_passwor
 	return nil
 }
 
-func (a *UserService) UpdateProfile(user models.User) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (a *UserService) UpdateProfile(ctx context.Context, user models.User) error {
 	const op = "service.user.UpdateProfile"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
 	user, err := a.Storage.GetByEmail(ctx, user.Email)
 	if err != nil {
@@ -183,11 +183,10 @@ func (a *UserService) UpdateProfile(user models.User) error {
 	return nil
 }
 
-func (a *UserService) Remove(id int) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
+func (a *UserService) Remove(ctx context.Context, id int) error {
 	const op = "service.user.Remove"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
 	err := a.Storage.Delete(ctx, id)
 	if err != nil {
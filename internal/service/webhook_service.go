@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"ozinshe/internal/apperr"
+	"ozinshe/internal/models"
+	psql "ozinshe/internal/storage/postgresql"
+	"ozinshe/internal/tracing"
+	"ozinshe/internal/webhook"
+	"time"
+)
+
+var (
+	ErrInvalidWebhookURL   = apperr.Validation("invalid_webhook_url", "webhook url must be an absolute http or https url", map[string]string{"url": "must be an absolute http or https url"})
+	ErrInvalidWebhookEvent = apperr.Validation("invalid_webhook_event", "unknown webhook event", nil)
+	ErrNoWebhookEvents     = apperr.Validation("no_webhook_events", "webhook must subscribe to at least one event", map[string]string{"events": "must not be empty"})
+)
+
+const (
+	// deliveryBatch is how many deliveries are sent per round.
+	deliveryBatch = 20
+	// deliveryLease must outlast sending a whole batch.
+	deliveryLease = 5 * time.Minute
+	// deliveryTimeout is how long a receiver has to answer.
+	deliveryTimeout = 10 * time.Second
+)
+
+// event is the body of every webhook delivery. ID is the outbox ID of the
+// domain event, which receivers can use to drop duplicates.
+type event struct {
+	ID         int64     `json:"id,omitempty"`
+	Event      string    `json:"event"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+type WebhookService struct {
+	Storage psql.Webhook
+	sender  *webhook.Sender
+}
+
+func NewWebhookService(storage psql.Webhook) *WebhookService {
+	return &WebhookService{Storage: storage, sender: webhook.NewSender(deliveryTimeout)}
+}
+
+// Add registers a webhook. A random secret is generated unless one is given;
+// the returned webhook is the only place the secret is shown.
+func (w *WebhookService) Add(ctx context.Context, hook models.Webhook) (models.Webhook, error) {
+	const op = "service.webhook.Add"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	u, err := url.Parse(hook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return models.Webhook{}, fmt.Errorf("%s: %w", op, ErrInvalidWebhookURL)
+	}
+
+	if len(hook.Events) == 0 {
+		return models.Webhook{}, fmt.Errorf("%s: %w", op, ErrNoWebhookEvents)
+	}
+	for _, e := range hook.Events {
+		if !models.IsWebhookEvent(e) {
+			return models.Webhook{}, fmt.Errorf("%s: %w", op, ErrInvalidWebhookEvent.Withf("%s", e))
+		}
+	}
+
+	if hook.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return models.Webhook{}, fmt.Errorf("%s: generate secret: %w", op, err)
+		}
+		hook.Secret = hex.EncodeToString(secret)
+	}
+	hook.Active = true
+
+	hook.ID, err = w.Storage.Insert(ctx, hook)
+	if err != nil {
+		return models.Webhook{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return hook, nil
+}
+
+func (w *WebhookService) Remove(ctx context.Context, id int) error {
+	const op = "service.webhook.Remove"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	err := w.Storage.Delete(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetAll returns the webhooks without their secrets.
+func (w *WebhookService) GetAll(ctx context.Context) ([]models.Webhook, error) {
+	const op = "service.webhook.GetAll"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	hooks, err := w.Storage.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i := range hooks {
+		hooks[i].Secret = ""
+	}
+
+	return hooks, nil
+}
+
+func (w *WebhookService) Deliveries(ctx context.Context, webhookID, offset, limit int) ([]models.WebhookDelivery, error) {
+	const op = "service.webhook.Deliveries"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if _, err := w.Storage.GetById(ctx, webhookID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	deliveries, err := w.Storage.GetDeliveries(ctx, webhookID, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+func (w *WebhookService) Redeliver(ctx context.Context, deliveryID int) error {
+	const op = "service.webhook.Redeliver"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	err := w.Storage.Redeliver(ctx, deliveryID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Ping queues a ping event to one webhook so that receivers can be tested.
+func (w *WebhookService) Ping(ctx context.Context, webhookID int) (int, error) {
+	const op = "service.webhook.Ping"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	if _, err := w.Storage.GetById(ctx, webhookID); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	payload, err := json.Marshal(event{Event: models.EventPing, OccurredAt: time.Now().UTC(), Data: map[string]int{"webhook_id": webhookID}})
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := w.Storage.EnqueueFor(ctx, webhookID, models.EventPing, payload)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// Publish queues a domain event to every webhook subscribed to it.
+func (w *WebhookService) Publish(ctx context.Context, e models.Event) error {
+	const op = "service.webhook.Publish"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	payload, err := json.Marshal(event{ID: e.ID, Event: e.Type, OccurredAt: e.OccurredAt.UTC(), Data: e.Payload})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = w.Storage.Enqueue(ctx, e.Type, payload)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeliverDue sends the deliveries that are due and reports how many were
+// attempted. Failed deliveries are retried with exponential backoff until
+// webhook.MaxAttempts is reached.
+func (w *WebhookService) DeliverDue(ctx context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, deliveryLease)
+	defer cancel()
+
+	const op = "service.webhook.DeliverDue"
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	deliveries, err := w.Storage.ClaimDue(ctx, deliveryBatch, deliveryLease)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, delivery := range deliveries {
+		status, sendErr := w.sender.Send(ctx, delivery)
+
+		delivery.Attempts++
+		delivery.LastStatusCode = status
+		delivery.LastError = ""
+		delivery.NextAttemptAt = time.Now()
+
+		switch {
+		case sendErr == nil:
+			delivery.Status = models.DeliverySucceeded
+		case delivery.Attempts >= webhook.MaxAttempts:
+			delivery.Status = models.DeliveryFailed
+			delivery.LastError = sendErr.Error()
+		default:
+			delivery.Status = models.DeliveryPending
+			delivery.LastError = sendErr.Error()
+			delivery.NextAttemptAt = time.Now().Add(webhook.Backoff(delivery.Attempts))
+		}
+
+		if err := w.Storage.RecordAttempt(ctx, delivery); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return len(deliveries), nil
+}
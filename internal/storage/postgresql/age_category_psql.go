@@ -2,6 +2,8 @@ package storage
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"ozinshe/internal/models"
 )
@@ -16,25 +18,43 @@ func NewAgeCategoryStorage(db *Postgres) *AgeCategoryStorage {
 
 func (s *AgeCategoryStorage) Insert(ctx context.Context, ageCategories []models.AgeCategory) error {
 	const op = "storage.age_category.Insert"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := s.storage.db.Prepare(`
+	tx, err := s.storage.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
         INSERT INTO age_categories (min_age, max_age) 
         VALUES ($1, $2) 
         ON CONFLICT DO NOTHING 
+        RETURNING id
     `)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 	defer stmt.Close()
 
-	tx, err := s.storage.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
-	}
-	defer tx.Rollback()
-
 	for _, ac := range ageCategories {
-		stmt.ExecContext(ctx, ac.MinAge, ac.MaxAge) // Pass min_age and max_age
+		var id int
+		err := stmt.QueryRowContext(ctx, ac.MinAge, ac.MaxAge).Scan(&id) // Pass min_age and max_age
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		after, err := snapshot(ctx, tx, "age_categories", id)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if err := insertAudit(ctx, tx, "age_category.create", "age_category", id, nil, after); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -46,15 +66,10 @@ func (s *AgeCategoryStorage) Insert(ctx context.Context, ageCategories []models.
 
 func (s *AgeCategoryStorage) Delete(ctx context.Context, id int) error {
 	const op = "storage.age_category.Delete"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := s.storage.db.Prepare(`DELETE FROM age_categories WHERE id = $1`)
-	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
-	}
-	defer stmt.Close()
-
-	_, err = stmt.ExecContext(ctx, id)
-	if err != nil {
+	if err := deleteAudited(ctx, s.storage.db, "age_categories", "age_category", id); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
@@ -63,8 +78,10 @@ func (s *AgeCategoryStorage) Delete(ctx context.Context, id int) error {
 
 func (s *AgeCategoryStorage) GetById(ctx context.Context, id int) (models.AgeCategory, error) {
 	const op = "storage.age_category.GetById"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := s.storage.db.Prepare(`
+	stmt, err := s.storage.db.PrepareContext(ctx, `
 		SELECT id, min_age, max_age
 		FROM age_categories
 		WHERE id = $1
@@ -85,8 +102,10 @@ func (s *AgeCategoryStorage) GetById(ctx context.Context, id int) (models.AgeCat
 
 func (s *AgeCategoryStorage) GetAll(ctx context.Context) ([]models.AgeCategory, error) {
 	const op = "storage.age_category.GetAll"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := s.storage.db.Prepare(`
+	stmt, err := s.storage.db.PrepareContext(ctx, `
 		SELECT id, min_age, max_age
 		FROM age_categories
 		ORDER BY id
@@ -0,0 +1,195 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ozinshe/internal/audit"
+	"ozinshe/internal/models"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+type AuditStorage struct {
+	storage *Postgres
+}
+
+func NewAuditStorage(db *Postgres) *AuditStorage {
+	return &AuditStorage{storage: db}
+}
+
+// redactedColumns are left out of the snapshots of a table.
+var redactedColumns = map[string][]string{
+	"users":    {"password", "token", "refresh_token"},
+	"webhooks": {"secret"},
+}
+
+// snapshot returns the row of table with the given id as JSON, or nil when
+// there is no such row.
+func snapshot(ctx context.Context, tx *sql.Tx, table string, id any) (json.RawMessage, error) {
+	return snapshotQuery(ctx, tx, `SELECT to_jsonb(t) - $2::TEXT[] FROM `+table+` t WHERE t.id = $1`,
+		id, pq.Array(redactedColumns[table]))
+}
+
+// snapshotQuery returns the single JSON value selected by query, or nil when
+// it selects no row.
+func snapshotQuery(ctx context.Context, tx *sql.Tx, query string, args ...any) (json.RawMessage, error) {
+	var data []byte
+	err := tx.QueryRowContext(ctx, query, args...).Scan(&data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("snapshot: %w", err)
+	}
+
+	return data, nil
+}
+
+// contentSnapshot returns a movie or series as JSON along with the names of
+// its genres, keywords and age categories and its image filenames.
+func contentSnapshot(ctx context.Context, tx *sql.Tx, contentType string, id int) (json.RawMessage, error) {
+	table := map[string]string{"movie": "movies", "series": "series"}[contentType]
+
+	return snapshotQuery(ctx, tx, strings.NewReplacer("{t}", table, "{p}", contentType).Replace(`
+		SELECT to_jsonb(c) || jsonb_build_object(
+			'genres', COALESCE((SELECT jsonb_agg(g.name ORDER BY g.name) FROM {p}_genres x
+				JOIN genres g ON g.id = x.genre_id WHERE x.{p}_id = c.id), '[]'),
+			'keywords', COALESCE((SELECT jsonb_agg(k.name ORDER BY k.name) FROM {p}_key_words x
+				JOIN key_words k ON k.id = x.key_word_id WHERE x.{p}_id = c.id), '[]'),
+			'age_categories', COALESCE((SELECT jsonb_agg(a.min_age || '-' || a.max_age) FROM {p}_age_categories x
+				JOIN age_categories a ON a.id = x.age_category_id WHERE x.{p}_id = c.id), '[]'),
+			'cover', (SELECT filename FROM {p}_covers WHERE {p}_id = c.id LIMIT 1),
+			'screenshots', COALESCE((SELECT jsonb_agg(filename ORDER BY id) FROM {p}_screenshots
+				WHERE {p}_id = c.id), '[]'))
+		FROM {t} c WHERE c.id = $1`), id)
+}
+
+// auditContentChange records a change to a movie or series, snapshotting it
+// as it is after the change.
+func auditContentChange(ctx context.Context, tx *sql.Tx, action, contentType string, id int, before json.RawMessage) error {
+	after, err := contentSnapshot(ctx, tx, contentType, id)
+	if err != nil {
+		return err
+	}
+
+	return insertAudit(ctx, tx, action, contentType, id, before, after)
+}
+
+// auditCreated records the creation of the row of table with the given id
+// as "<targetType>.create".
+func auditCreated(ctx context.Context, tx *sql.Tx, table, targetType string, id any) error {
+	after, err := snapshot(ctx, tx, table, id)
+	if err != nil {
+		return err
+	}
+
+	return insertAudit(ctx, tx, targetType+".create", targetType, id, nil, after)
+}
+
+// deleteAudited deletes the row of table with the given id and records it
+// as "<targetType>.delete". Deleting a missing row is neither an error nor
+// recorded.
+func deleteAudited(ctx context.Context, db *sql.DB, table, targetType string, id any) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := snapshot(ctx, tx, table, id)
+	if err != nil || before == nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete %s: %w", targetType, err)
+	}
+
+	if err := insertAudit(ctx, tx, targetType+".delete", targetType, id, before, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// insertAudit records a change made in tx in the audit log, so that the
+// entry exists if and only if the change is committed. The actor comes from
+// ctx.
+func insertAudit(ctx context.Context, tx *sql.Tx, action, targetType string, targetID any, before, after json.RawMessage) error {
+	actor, ok := audit.ActorFrom(ctx)
+
+	var actorID *int
+	if ok && actor.UserID != 0 {
+		actorID = &actor.UserID
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO audit_log (actor_id, actor_email, action, target_type, target_id, before, after, ip, request_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		actorID, actor.Email, action, targetType, fmt.Sprint(targetID), jsonParam(before), jsonParam(after),
+		actor.IP, actor.RequestID)
+	if err != nil {
+		return fmt.Errorf("insert %s audit entry: %w", action, err)
+	}
+
+	return nil
+}
+
+// jsonParam passes JSON as a string since lib/pq sends []byte as bytea, and
+// nil as NULL.
+func jsonParam(data json.RawMessage) any {
+	if data == nil {
+		return nil
+	}
+	return string(data)
+}
+
+func (a *AuditStorage) GetAll(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	const op = "storage.audit.GetAll"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	from := sql.NullTime{Time: filter.From, Valid: !filter.From.IsZero()}
+	to := sql.NullTime{Time: filter.To, Valid: !filter.To.IsZero()}
+
+	rows, err := a.storage.db.QueryContext(ctx, `
+		SELECT id, actor_id, actor_email, action, target_type, target_id, before, after, ip, request_id, created_at
+		FROM audit_log
+		WHERE ($1 = 0 OR actor_id = $1) AND ($2 = '' OR action = $2) AND ($3 = '' OR target_type = $3)
+			AND ($4 = '' OR target_id = $4)
+			AND ($5::TIMESTAMPTZ IS NULL OR created_at >= $5) AND ($6::TIMESTAMPTZ IS NULL OR created_at < $6)
+		ORDER BY created_at DESC, id DESC
+		OFFSET $7 LIMIT $8`,
+		filter.ActorID, filter.Action, filter.TargetType, filter.TargetID, from, to, filter.Offset, filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	entries := make([]models.AuditEntry, 0)
+	for rows.Next() {
+		var e models.AuditEntry
+		var before, after []byte
+		err := rows.Scan(&e.ID, &e.ActorID, &e.ActorEmail, &e.Action, &e.TargetType, &e.TargetID, &before, &after,
+			&e.IP, &e.RequestID, &e.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		e.Before, e.After = before, after
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entries, nil
+}
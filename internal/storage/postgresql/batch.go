@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"ozinshe/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// groupByIds runs query with ids as its $1 array and groups the rows it
+// returns by the ID scan reads from each, for loading the parts of many
+// movies or series with one query.
+func groupByIds[T any](ctx context.Context, db *sql.DB, query string, ids []int, scan func(*sql.Rows) (int, T, error)) (map[int][]T, error) {
+	rows, err := db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	grouped := make(map[int][]T, len(ids))
+	for rows.Next() {
+		id, v, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		grouped[id] = append(grouped[id], v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return grouped, nil
+}
+
+func scanGenre(rows *sql.Rows) (id int, genre models.Genre, err error) {
+	err = rows.Scan(&id, &genre.ID, &genre.Name)
+	return id, genre, err
+}
+
+func scanAgeCategory(rows *sql.Rows) (id int, ageCategory models.AgeCategory, err error) {
+	err = rows.Scan(&id, &ageCategory.ID, &ageCategory.MinAge, &ageCategory.MaxAge)
+	return id, ageCategory, err
+}
+
+func scanKeyword(rows *sql.Rows) (id int, keyword models.Keyword, err error) {
+	err = rows.Scan(&id, &keyword.ID, &keyword.Name)
+	return id, keyword, err
+}
+
+func scanImage(rows *sql.Rows) (id int, screenshot models.Screenshot, err error) {
+	err = rows.Scan(&id, &screenshot.ID, &screenshot.Filename)
+	screenshot.ProjectID = id
+	return id, screenshot, err
+}
+
+func scanCredit(rows *sql.Rows) (id int, credit models.Credit, err error) {
+	err = rows.Scan(&id, &credit.ID, &credit.PersonID, &credit.PersonName, &credit.PersonPhoto, &credit.ProjectID, &credit.Role, &credit.Character, &credit.Position)
+	return id, credit, err
+}
+
+// creditsByIds selects the credits of the movies or series with the $1
+// IDs, each in billing order.
+func creditsByIds(projectType string) string {
+	return `SELECT p.project_id, c.id, c.person_id, pe.name, pe.photo, c.project_id, c.role, c.character_name, c.position
+		FROM credits c
+		JOIN people pe ON pe.id = c.person_id
+		JOIN projects p ON p.id = c.project_id
+		WHERE p.project_type = '` + projectType + `' AND p.project_id = ANY($1)
+		ORDER BY c.position`
+}
+
+// covers keeps the first cover of each movie or series; there is only ever
+// one.
+func covers(grouped map[int][]models.Screenshot) map[int]models.Cover {
+	covers := make(map[int]models.Cover, len(grouped))
+	for id, images := range grouped {
+		covers[id] = models.Cover(images[0])
+	}
+	return covers
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"ozinshe/internal/apperr"
+	"ozinshe/internal/models"
+	"ozinshe/internal/storage"
+	"time"
+)
+
+// CatalogStorage reads when the catalog was last modified. The updated_at
+// columns and the catalog_changes table it reads are kept by triggers, so
+// that no write can forget them.
+type CatalogStorage struct {
+	storage *Postgres
+}
+
+func NewCatalogStorage(db *Postgres) *CatalogStorage {
+	return &CatalogStorage{storage: db}
+}
+
+// ModifiedAt returns when a movie, series, genre or age category was last
+// modified. A movie or series is modified along with its parts, such as its
+// seasons or credits, but not by changes of its popularity.
+func (c *CatalogStorage) ModifiedAt(ctx context.Context, resource string, id int) (time.Time, error) {
+	const op = "storage.catalog.ModifiedAt"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	var (
+		query   string
+		missing *apperr.Error
+	)
+	switch resource {
+	case models.ResourceMovies:
+		query, missing = `SELECT updated_at FROM projects WHERE project_type = 'movie' AND project_id = $1`, storage.ErrMovieNotFound
+	case models.ResourceSeries:
+		query, missing = `SELECT updated_at FROM projects WHERE project_type = 'series' AND project_id = $1`, storage.ErrSeriesNotFound
+	case models.ResourceGenres:
+		query, missing = `SELECT updated_at FROM genres WHERE id = $1`, storage.ErrNotFound
+	case models.ResourceAgeCategories:
+		query, missing = `SELECT updated_at FROM age_categories WHERE id = $1`, storage.ErrNotFound
+	default:
+		return time.Time{}, fmt.Errorf("%s: unknown resource %q", op, resource)
+	}
+
+	var modified time.Time
+	if err := c.storage.db.QueryRowContext(ctx, query, id).Scan(&modified); err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, notFound(err, missing))
+	}
+
+	return modified, nil
+}
+
+// ListModifiedAt returns when the list of a resource was last modified,
+// including by deletions. The lists of movies and series only hold
+// published ones, so they also change when a publish or unpublish time
+// passes.
+func (c *CatalogStorage) ListModifiedAt(ctx context.Context, resource string) (time.Time, error) {
+	const op = "storage.catalog.ListModifiedAt"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	var (
+		modified time.Time
+		err      error
+	)
+	switch resource {
+	case models.ResourceMovies, models.ResourceSeries:
+		projectType := "movie"
+		if resource == models.ResourceSeries {
+			projectType = "series"
+		}
+		err = c.storage.db.QueryRowContext(ctx, `
+			SELECT GREATEST(c.changed_at,
+				(SELECT MAX(publish_at) FROM projects
+				WHERE project_type = $1 AND status = 'scheduled' AND publish_at <= NOW()),
+				(SELECT MAX(unpublish_at) FROM projects
+				WHERE project_type = $1 AND unpublish_at <= NOW()))
+			FROM catalog_changes c
+			WHERE c.name = 'projects'`, projectType).Scan(&modified)
+	case models.ResourceGenres, models.ResourceAgeCategories:
+		err = c.storage.db.QueryRowContext(ctx, `
+			SELECT changed_at FROM catalog_changes WHERE name = $1`, resource).Scan(&modified)
+	default:
+		return time.Time{}, fmt.Errorf("%s: unknown resource %q", op, resource)
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return modified, nil
+}
@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"ozinshe/internal/models"
+	"ozinshe/internal/storage"
+
+	"github.com/lib/pq"
+)
+
+type CollectionStorage struct {
+	storage *Postgres
+}
+
+func NewCollectionStorage(db *Postgres) *CollectionStorage {
+	return &CollectionStorage{storage: db}
+}
+
+func (c *CollectionStorage) Insert(ctx context.Context, collection models.Collection) (int, error) {
+	const op = "storage.collection.Insert"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	tx, err := c.storage.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRowContext(ctx, `INSERT INTO collections (title, slug, banner, position) VALUES ($1, $2, $3, $4) RETURNING id`,
+		collection.Title, collection.Slug, collection.Banner, collection.Position).Scan(&id)
+	if err != nil {
+		var pqErr *pq.Error
+
+		if errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation" {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrCollectionExists)
+		}
+
+		return 0, fmt.Errorf("%s: insert collection: %w", op, err)
+	}
+
+	if err := auditCreated(ctx, tx, "collections", "collection", id); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: commit transaction: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (c *CollectionStorage) Delete(ctx context.Context, id int) error {
+	const op = "storage.collection.Delete"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	if err := deleteAudited(ctx, c.storage.db, "collections", "collection", id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (c *CollectionStorage) GetAll(ctx context.Context) ([]models.Collection, error) {
+	const op = "storage.collection.GetAll"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	rows, err := c.storage.db.QueryContext(ctx, `SELECT id, title, slug, banner, position FROM collections ORDER BY position, id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	collections := make([]models.Collection, 0)
+	for rows.Next() {
+		var collection models.Collection
+		err := rows.Scan(&collection.ID, &collection.Title, &collection.Slug, &collection.Banner, &collection.Position)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		collections = append(collections, collection)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return collections, nil
+}
+
+func (c *CollectionStorage) GetBySlug(ctx context.Context, slug string) (models.Collection, error) {
+	const op = "storage.collection.GetBySlug"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	stmt, err := c.storage.db.PrepareContext(ctx, `SELECT id, title, slug, banner, position FROM collections WHERE slug = $1`)
+	if err != nil {
+		return models.Collection{}, fmt.Errorf("%s: prepare statement: %w", op, err)
+	}
+	defer stmt.Close()
+
+	var collection models.Collection
+	err = stmt.QueryRowContext(ctx, slug).Scan(&collection.ID, &collection.Title, &collection.Slug, &collection.Banner, &collection.Position)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Collection{}, fmt.Errorf("%s: %w", op, storage.ErrCollectionNotFound)
+		}
+		return models.Collection{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return collection, nil
+}
+
+func (c *CollectionStorage) GetProjects(ctx context.Context, collectionID, offset, limit int) ([]models.ProjectCard, error) {
+	const op = "storage.collection.GetProjects"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	query := projectCardQuery + `
+		JOIN collection_projects cp ON cp.project_id = p.id
+		WHERE cp.collection_id = $1
+		ORDER BY cp.position OFFSET $2 LIMIT $3`
+
+	rows, err := c.storage.db.QueryContext(ctx, query, collectionID, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	cards, err := scanProjectCards(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: scan card: %w", op, err)
+	}
+
+	return cards, nil
+}
+
+// collectionProjectsSnapshot selects the project ids of a collection in
+// order for the audit log.
+const collectionProjectsSnapshot = `SELECT COALESCE(jsonb_agg(project_id ORDER BY position), '[]')
+	FROM collection_projects WHERE collection_id = $1`
+
+func (c *CollectionStorage) ReplaceProjects(ctx context.Context, collectionID int, projectIDs []int) error {
+	const op = "storage.collection.ReplaceProjects"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	tx, err := c.storage.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	before, err := snapshotQuery(ctx, tx, collectionProjectsSnapshot, collectionID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// 1. Delete existing projects
+	_, err = tx.ExecContext(ctx, `DELETE FROM collection_projects WHERE collection_id = $1`, collectionID)
+	if err != nil {
+		return fmt.Errorf("%s: delete existing projects: %w", op, err)
+	}
+
+	// 2. Insert new projects in the given order
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO collection_projects (collection_id, project_id, position) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`)
+	if err != nil {
+		return fmt.Errorf("%s: prepare error: %w", op, err)
+	}
+	defer stmt.Close()
+
+	for i, projectID := range projectIDs {
+		_, err = stmt.ExecContext(ctx, collectionID, projectID, i)
+		if err != nil {
+			return fmt.Errorf("%s: insert project: %w", op, err)
+		}
+	}
+
+	after, err := snapshotQuery(ctx, tx, collectionProjectsSnapshot, collectionID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := insertAudit(ctx, tx, "collection.set_projects", "collection", collectionID, before, after); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit transaction: %w", op, err)
+	}
+
+	return nil
+}
@@ -2,30 +2,52 @@ package storage
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"ozinshe/internal/config"
+	"ozinshe/internal/metrics"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type Postgres struct {
 	db *sql.DB
+	// dsn is what Listen connects with, empty when the connections are
+	// opened some other way.
+	dsn string
 }
 
 func NewPostgres(cnf *config.Config) (*Postgres, error) {
 	const op = "storage.New"
 
-	db, err := sql.Open("postgres", "postgresql://"+cnf.DB.User+":"+cnf.DB.Password+"@"+cnf.Host+":"+cnf.DB.Port+"/"+cnf.DB.Dbname+"?sslmode="+cnf.DB.Sslmode)
+	dsn := "postgresql://" + cnf.DB.User + ":" + cnf.DB.Password + "@" + cnf.Host + ":" + cnf.DB.Port + "/" + cnf.DB.Dbname + "?sslmode=" + cnf.DB.Sslmode
+	connector, err := pq.NewConnector(dsn)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = db.Ping()
+	p := Open(connector)
+	p.dsn = dsn
+
+	err = p.db.Ping()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	fmt.Println(op, "connected successfully")
 
-	return &Postgres{db: db}, nil
+	metrics.RegisterDB(p.db)
+
+	return p, nil
+}
+
+// Open returns the Postgres of the connections connector opens, traced and
+// with their errors translated. It does not connect until the first query.
+func Open(connector driver.Connector) *Postgres {
+	return &Postgres{db: sql.OpenDB(tracedConnector{connector})}
+}
+
+// Close closes the connection pool once the queries in progress are done.
+func (p *Postgres) Close() error {
+	return p.db.Close()
 }
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"ozinshe/internal/apperr"
+	"ozinshe/internal/storage"
+
+	"github.com/lib/pq"
+)
+
+// translate turns the errors of the database clients should know about into
+// the errors of the storage package, keeping the original as their cause so
+// that the pq.Error can still be inspected. Other errors are returned as is.
+//
+// Postgres reports a query cancelled because ctx is done as any other
+// cancelled statement, so it also wraps the error of ctx, for it to read as
+// the timeout or cancellation it is rather than an unavailable database.
+func translate(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil {
+		return fmt.Errorf("%w: %w", ctx.Err(), err)
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	switch pqErr.Code.Class() {
+	// connection_exception, insufficient_resources, operator_intervention
+	case "08", "53", "57":
+		return storage.ErrUnavailable.Wrap(err)
+	}
+
+	switch pqErr.Code.Name() {
+	case "unique_violation", "exclusion_violation":
+		return storage.ErrConflict.Wrap(err)
+	case "foreign_key_violation":
+		return storage.ErrInvalidReference.Wrap(err)
+	case "check_violation", "not_null_violation", "invalid_text_representation",
+		"string_data_right_truncation", "numeric_value_out_of_range", "invalid_datetime_format":
+		return storage.ErrInvalidValue.Wrap(err)
+	}
+
+	return err
+}
+
+// notFound returns notFound with err as its cause when err is sql.ErrNoRows,
+// and err otherwise.
+func notFound(err error, notFound *apperr.Error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return notFound.Wrap(err)
+	}
+	return err
+}
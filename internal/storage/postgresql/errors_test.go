@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"ozinshe/internal/storage"
+	"ozinshe/internal/storage/storagetest"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestQueryTimesOut(t *testing.T) {
+	s := New(Open(storagetest.New().Block()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := s.Genre.GetAll(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GetAll past the deadline = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if errors.Is(err, storage.ErrUnavailable) {
+		t.Errorf("GetAll past the deadline = %v, want it not to be unavailable", err)
+	}
+}
+
+func TestQueryCanceled(t *testing.T) {
+	s := New(Open(storagetest.New().Block()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	_, err := s.Movie.GetById(ctx, 1)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetById of a canceled request = %v, want %v", err, context.Canceled)
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != "57014" {
+		t.Errorf("GetById of a canceled request = %v, want it to wrap the error of Postgres", err)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"unique violation", &pq.Error{Code: "23505"}, storage.ErrConflict},
+		{"foreign key violation", &pq.Error{Code: "23503"}, storage.ErrInvalidReference},
+		{"check violation", &pq.Error{Code: "23514"}, storage.ErrInvalidValue},
+		{"connection failure", &pq.Error{Code: "08006"}, storage.ErrUnavailable},
+		{"canceled by the server", storagetest.ErrCanceled, storage.ErrUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := translate(context.Background(), tt.err); !errors.Is(err, tt.want) {
+				t.Errorf("translate(%v) = %v, want %v", tt.err, err, tt.want)
+			}
+		})
+	}
+
+	if err := translate(context.Background(), nil); err != nil {
+		t.Errorf("translate(nil) = %v, want nil", err)
+	}
+}
@@ -2,6 +2,8 @@ package storage
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"ozinshe/internal/models"
 )
@@ -16,25 +18,43 @@ func NewGenreStorage(db *Postgres) *GenreStorage {
 
 func (s *GenreStorage) Insert(ctx context.Context, genres []models.Genre) error {
 	const op = "storage.genre.Insert"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := s.Storage.db.Prepare(`
+	tx, err := s.Storage.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
         INSERT INTO genres (name) 
         VALUES ($1) 
         ON CONFLICT DO NOTHING 
+        RETURNING id
     `)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 	defer stmt.Close()
 
-	tx, err := s.Storage.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
-	}
-	defer tx.Rollback()
-
 	for _, g := range genres {
-		stmt.ExecContext(ctx, g.Name)
+		var id int
+		err := stmt.QueryRowContext(ctx, g.Name).Scan(&id)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		after, err := snapshot(ctx, tx, "genres", id)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if err := insertAudit(ctx, tx, "genre.create", "genre", id, nil, after); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -46,15 +66,10 @@ func (s *GenreStorage) Insert(ctx context.Context, genres []models.Genre) error
 
 func (s *GenreStorage) Delete(ctx context.Context, id int) error {
 	const op = "storage.genre.Delete"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := s.Storage.db.Prepare(`DELETE FROM genres WHERE id = $1`)
-	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
-	}
-	defer stmt.Close()
-
-	_, err = stmt.ExecContext(ctx, id)
-	if err != nil {
+	if err := deleteAudited(ctx, s.Storage.db, "genres", "genre", id); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
@@ -63,8 +78,10 @@ func (s *GenreStorage) Delete(ctx context.Context, id int) error {
 
 func (s *GenreStorage) GetById(ctx context.Context, id int) (models.Genre, error) {
 	const op = "storage.genre.GetById"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := s.Storage.db.Prepare(`
+	stmt, err := s.Storage.db.PrepareContext(ctx, `
 		SELECT id, name
 		FROM genres
 		WHERE id = $1
@@ -85,8 +102,10 @@ func (s *GenreStorage) GetById(ctx context.Context, id int) (models.Genre, error
 
 func (s *GenreStorage) GetAll(ctx context.Context) ([]models.Genre, error) {
 	const op = "storage.genre.GetAll"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := s.Storage.db.Prepare(`
+	stmt, err := s.Storage.db.PrepareContext(ctx, `
 		SELECT id, name
 		FROM genres
 	`)
@@ -114,8 +133,10 @@ func (s *GenreStorage) GetAll(ctx context.Context) ([]models.Genre, error) {
 
 func (s *GenreStorage) GetByName(ctx context.Context, name string) (models.Genre, error) {
 	const op = "storage.genre.GetByName"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := s.Storage.db.Prepare(`
+	stmt, err := s.Storage.db.PrepareContext(ctx, `
 		SELECT id, name
 		FROM genres
 		WHERE name = $1
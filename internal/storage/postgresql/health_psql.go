@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+)
+
+type HealthStorage struct {
+	storage *Postgres
+}
+
+func NewHealthStorage(db *Postgres) *HealthStorage {
+	return &HealthStorage{storage: db}
+}
+
+func (h *HealthStorage) Ping(ctx context.Context) error {
+	const op = "storage.health.Ping"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	if err := h.storage.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SchemaVersion returns the version of the last migration applied by the
+// migrate CLI, and whether it failed halfway.
+func (h *HealthStorage) SchemaVersion(ctx context.Context) (uint, bool, error) {
+	const op = "storage.health.SchemaVersion"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	var version uint
+	var dirty bool
+
+	err := h.storage.db.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err != nil {
+		return 0, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return version, dirty, nil
+}
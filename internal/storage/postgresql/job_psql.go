@@ -0,0 +1,375 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"ozinshe/internal/models"
+	"ozinshe/internal/storage"
+	"time"
+)
+
+type JobStorage struct {
+	storage *Postgres
+}
+
+func NewJobStorage(db *Postgres) *JobStorage {
+	return &JobStorage{storage: db}
+}
+
+const jobColumns = `id, kind, payload, status, attempts, max_attempts, last_error, run_at, created_at, finished_at`
+
+func scanJob(row interface{ Scan(...any) error }) (models.Job, error) {
+	var j models.Job
+	err := row.Scan(&j.ID, &j.Kind, &j.Payload, &j.Status, &j.Attempts, &j.MaxAttempts, &j.LastError,
+		&j.RunAt, &j.CreatedAt, &j.FinishedAt)
+	return j, err
+}
+
+func scanJobs(rows *sql.Rows) ([]models.Job, error) {
+	defer rows.Close()
+
+	jobs := make([]models.Job, 0)
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+// Enqueue queues a job to run at runAt. Payloads are passed as strings since
+// lib/pq sends []byte as bytea.
+func (j *JobStorage) Enqueue(ctx context.Context, kind string, payload []byte, runAt time.Time, maxAttempts int) (int64, error) {
+	const op = "storage.job.Enqueue"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	tx, err := j.storage.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO jobs (kind, payload, run_at, max_attempts) VALUES ($1, $2, $3, $4) RETURNING id`,
+		kind, string(payload), runAt, maxAttempts).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := auditCreated(ctx, tx, "jobs", "job", id); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: commit transaction: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// Claim marks at most limit due jobs as running and returns them. Each claim
+// counts as an attempt and holds the job for the given lease; jobs whose
+// worker died are claimed again once their lease expires, unless that was
+// their last attempt, which makes them dead instead.
+func (j *JobStorage) Claim(ctx context.Context, limit int, lease time.Duration) ([]models.Job, error) {
+	const op = "storage.job.Claim"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	_, err := j.storage.db.ExecContext(ctx, `
+		UPDATE jobs SET status = 'dead', last_error = 'lease expired', locked_until = NULL, finished_at = NOW()
+		WHERE status = 'running' AND locked_until < NOW() AND attempts >= max_attempts`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: bury expired jobs: %w", op, err)
+	}
+
+	rows, err := j.storage.db.QueryContext(ctx, `
+		UPDATE jobs SET status = 'running', attempts = attempts + 1, locked_until = NOW() + $2 * INTERVAL '1 second'
+		WHERE id IN (
+			SELECT id FROM jobs
+			WHERE (status = 'queued' AND run_at <= NOW())
+				OR (status = 'running' AND locked_until < NOW() AND attempts < max_attempts)
+			ORDER BY run_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+jobColumns, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	jobs, err := scanJobs(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return jobs, nil
+}
+
+// Complete records that the given attempt at a job succeeded. It fails with
+// storage.ErrJobLeaseLost when that attempt no longer holds the job.
+func (j *JobStorage) Complete(ctx context.Context, id int64, attempt int) error {
+	const op = "storage.job.Complete"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	result, err := j.storage.db.ExecContext(ctx, `
+		UPDATE jobs SET status = 'succeeded', last_error = '', locked_until = NULL, finished_at = NOW()
+		WHERE id = $1 AND `+leaseHeld, id, attempt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := checkLease(result); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Fail records that the given attempt at a job failed. The job is queued
+// again at retryAt, or is dead when retryAt is nil. Like Complete, it fails
+// with storage.ErrJobLeaseLost when that attempt no longer holds the job.
+func (j *JobStorage) Fail(ctx context.Context, id int64, attempt int, lastError string, retryAt *time.Time) error {
+	const op = "storage.job.Fail"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	var (
+		result sql.Result
+		err    error
+	)
+	if retryAt != nil {
+		result, err = j.storage.db.ExecContext(ctx, `
+			UPDATE jobs SET status = 'queued', last_error = $3, run_at = $4, locked_until = NULL
+			WHERE id = $1 AND `+leaseHeld, id, attempt, lastError, *retryAt)
+	} else {
+		result, err = j.storage.db.ExecContext(ctx, `
+			UPDATE jobs SET status = 'dead', last_error = $3, locked_until = NULL, finished_at = NOW()
+			WHERE id = $1 AND `+leaseHeld, id, attempt, lastError)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := checkLease(result); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// leaseHeld matches a job still held by the claim that made attempt $2.
+// Every claim counts an attempt, so a job claimed again after its lease
+// expired no longer matches.
+const leaseHeld = `status = 'running' AND attempts = $2 AND locked_until > NOW()`
+
+func checkLease(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("check rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return storage.ErrJobLeaseLost
+	}
+
+	return nil
+}
+
+func (j *JobStorage) GetAll(ctx context.Context, filter models.JobFilter) ([]models.Job, error) {
+	const op = "storage.job.GetAll"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	rows, err := j.storage.db.QueryContext(ctx, `SELECT `+jobColumns+` FROM jobs
+		WHERE ($1 = '' OR status = $1) AND ($2 = '' OR kind = $2)
+		ORDER BY created_at DESC, id DESC OFFSET $3 LIMIT $4`,
+		filter.Status, filter.Kind, filter.Offset, filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	jobs, err := scanJobs(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return jobs, nil
+}
+
+func (j *JobStorage) GetById(ctx context.Context, id int64) (models.Job, error) {
+	const op = "storage.job.GetById"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	job, err := scanJob(j.storage.db.QueryRowContext(ctx, `SELECT `+jobColumns+` FROM jobs WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Job{}, fmt.Errorf("%s: %w", op, storage.ErrJobNotFound)
+		}
+		return models.Job{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return job, nil
+}
+
+// Retry queues a job that is not running to run again right away with a
+// fresh set of attempts.
+func (j *JobStorage) Retry(ctx context.Context, id int64) error {
+	const op = "storage.job.Retry"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	tx, err := j.storage.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	before, err := snapshot(ctx, tx, "jobs", id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE jobs SET status = 'queued', attempts = 0, last_error = '', run_at = NOW(), finished_at = NULL
+		WHERE id = $1 AND status <> 'running'`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: check rows affected: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrJobNotFound)
+	}
+
+	after, err := snapshot(ctx, tx, "jobs", id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := insertAudit(ctx, tx, "job.retry", "job", id, before, after); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit transaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// SaveSchedule creates or updates a schedule. The next run is only moved
+// when the spec changes, so restarts don't skip or repeat runs.
+func (j *JobStorage) SaveSchedule(ctx context.Context, schedule models.JobSchedule) error {
+	const op = "storage.job.SaveSchedule"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	_, err := j.storage.db.ExecContext(ctx, `
+		INSERT INTO job_schedules (name, spec, kind, payload, next_run_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (name) DO UPDATE SET spec = EXCLUDED.spec, kind = EXCLUDED.kind, payload = EXCLUDED.payload,
+			next_run_at = CASE WHEN job_schedules.spec = EXCLUDED.spec THEN job_schedules.next_run_at ELSE EXCLUDED.next_run_at END`,
+		schedule.Name, schedule.Spec, schedule.Kind, string(schedule.Payload), schedule.NextRunAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (j *JobStorage) GetSchedules(ctx context.Context) ([]models.JobSchedule, error) {
+	const op = "storage.job.GetSchedules"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	rows, err := j.storage.db.QueryContext(ctx, `
+		SELECT name, spec, kind, payload, next_run_at, last_run_at FROM job_schedules ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	schedules := make([]models.JobSchedule, 0)
+	for rows.Next() {
+		var s models.JobSchedule
+		if err := rows.Scan(&s.Name, &s.Spec, &s.Kind, &s.Payload, &s.NextRunAt, &s.LastRunAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		schedules = append(schedules, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return schedules, nil
+}
+
+// EnqueueScheduled queues a job for every schedule that is due and moves the
+// schedule to next(schedule). The schedules stay locked until it returns, so
+// concurrent schedulers never queue the same run twice.
+func (j *JobStorage) EnqueueScheduled(ctx context.Context, maxAttempts int, next func(models.JobSchedule) time.Time) (int, error) {
+	const op = "storage.job.EnqueueScheduled"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	tx, err := j.storage.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT name, spec, kind, payload, next_run_at, last_run_at FROM job_schedules
+		WHERE next_run_at <= NOW()
+		FOR UPDATE SKIP LOCKED`)
+	if err != nil {
+		return 0, fmt.Errorf("%s: query: %w", op, err)
+	}
+
+	var due []models.JobSchedule
+	for rows.Next() {
+		var s models.JobSchedule
+		if err := rows.Scan(&s.Name, &s.Spec, &s.Kind, &s.Payload, &s.NextRunAt, &s.LastRunAt); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("%s: scan schedule: %w", op, err)
+		}
+		due = append(due, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, s := range due {
+		_, err = tx.ExecContext(ctx, `INSERT INTO jobs (kind, payload, max_attempts) VALUES ($1, $2, $3)`,
+			s.Kind, string(s.Payload), maxAttempts)
+		if err != nil {
+			return 0, fmt.Errorf("%s: enqueue %s: %w", op, s.Name, err)
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE job_schedules SET next_run_at = $2, last_run_at = NOW() WHERE name = $1`,
+			s.Name, next(s))
+		if err != nil {
+			return 0, fmt.Errorf("%s: update schedule %s: %w", op, s.Name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: commit transaction: %w", op, err)
+	}
+
+	return len(due), nil
+}
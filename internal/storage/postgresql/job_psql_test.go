@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"ozinshe/internal/storage"
+	"ozinshe/internal/storage/storagetest"
+	"testing"
+	"time"
+)
+
+func TestJobFinishHoldingTheLease(t *testing.T) {
+	s := New(Open(storagetest.New().Affect("UPDATE jobs", 1)))
+	ctx := context.Background()
+	retryAt := time.Now().Add(time.Minute)
+
+	if err := s.Job.Complete(ctx, 1, 1); err != nil {
+		t.Errorf("Complete() = %v, want nil", err)
+	}
+	if err := s.Job.Fail(ctx, 1, 1, "boom", &retryAt); err != nil {
+		t.Errorf("Fail() with a retry = %v, want nil", err)
+	}
+	if err := s.Job.Fail(ctx, 1, 1, "boom", nil); err != nil {
+		t.Errorf("Fail() without a retry = %v, want nil", err)
+	}
+}
+
+func TestJobFinishAfterLosingTheLease(t *testing.T) {
+	s := New(Open(storagetest.New().Affect("UPDATE jobs", 0)))
+	ctx := context.Background()
+	retryAt := time.Now().Add(time.Minute)
+
+	if err := s.Job.Complete(ctx, 1, 1); !errors.Is(err, storage.ErrJobLeaseLost) {
+		t.Errorf("Complete() = %v, want %v", err, storage.ErrJobLeaseLost)
+	}
+	if err := s.Job.Fail(ctx, 1, 1, "boom", &retryAt); !errors.Is(err, storage.ErrJobLeaseLost) {
+		t.Errorf("Fail() with a retry = %v, want %v", err, storage.ErrJobLeaseLost)
+	}
+	if err := s.Job.Fail(ctx, 1, 1, "boom", nil); !errors.Is(err, storage.ErrJobLeaseLost) {
+		t.Errorf("Fail() without a retry = %v, want %v", err, storage.ErrJobLeaseLost)
+	}
+}
+
+func TestJobFinishWriteError(t *testing.T) {
+	errWrite := errors.New("connection reset")
+	s := New(Open(storagetest.New().Fail("UPDATE jobs", errWrite)))
+
+	err := s.Job.Complete(context.Background(), 1, 1)
+	if !errors.Is(err, errWrite) {
+		t.Errorf("Complete() = %v, want %v", err, errWrite)
+	}
+	if errors.Is(err, storage.ErrJobLeaseLost) {
+		t.Errorf("Complete() = %v, want it not to be a lost lease", err)
+	}
+}
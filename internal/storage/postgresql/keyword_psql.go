@@ -16,8 +16,10 @@ func NewKeywordStorage(db *Postgres) *KeywordStorage {
 
 func (s *KeywordStorage) Insert(ctx context.Context, keywords []models.Keyword) error {
 	const op = "storage.keyword.Insert"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := s.storage.db.Prepare(`
+	stmt, err := s.storage.db.PrepareContext(ctx, `
 		INSERT INTO key_words (name) 
 		VALUES ($1) 
 		ON CONFLICT DO NOTHING
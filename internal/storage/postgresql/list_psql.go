@@ -0,0 +1,438 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"ozinshe/internal/models"
+	"ozinshe/internal/storage"
+)
+
+type ListStorage struct {
+	storage *Postgres
+}
+
+func NewListStorage(db *Postgres) *ListStorage {
+	return &ListStorage{storage: db}
+}
+
+const listColumns = `id, user_id, name, kind, is_public, share_token, created_at`
+
+func scanList(row interface{ Scan(...any) error }) (models.List, error) {
+	var list models.List
+	err := row.Scan(&list.ID, &list.UserID, &list.Name, &list.Kind, &list.IsPublic, &list.ShareToken, &list.CreatedAt)
+	return list, err
+}
+
+// EnsureBuiltins creates the Favorites and Watch Later lists of a user unless
+// they already exist.
+func (l *ListStorage) EnsureBuiltins(ctx context.Context, userID int) error {
+	const op = "storage.list.EnsureBuiltins"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	_, err := l.storage.db.ExecContext(ctx, `
+		INSERT INTO lists (user_id, name, kind) VALUES ($1, 'Favorites', 'favorites'), ($1, 'Watch Later', 'watch_later')
+		ON CONFLICT (user_id, kind) WHERE kind <> 'custom' DO NOTHING`, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (l *ListStorage) GetBuiltin(ctx context.Context, userID int, kind string) (models.List, error) {
+	const op = "storage.list.GetBuiltin"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	row := l.storage.db.QueryRowContext(ctx, `SELECT `+listColumns+` FROM lists WHERE user_id = $1 AND kind = $2`, userID, kind)
+
+	list, err := scanList(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.List{}, fmt.Errorf("%s: %w", op, storage.ErrListNotFound)
+		}
+		return models.List{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return list, nil
+}
+
+func (l *ListStorage) GetByUser(ctx context.Context, userID int) ([]models.List, error) {
+	const op = "storage.list.GetByUser"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	rows, err := l.storage.db.QueryContext(ctx, `SELECT `+listColumns+` FROM lists WHERE user_id = $1
+		ORDER BY kind = 'custom', kind, created_at, id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	lists := make([]models.List, 0)
+	for rows.Next() {
+		list, err := scanList(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		lists = append(lists, list)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return lists, nil
+}
+
+func (l *ListStorage) GetById(ctx context.Context, id int) (models.List, error) {
+	const op = "storage.list.GetById"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	row := l.storage.db.QueryRowContext(ctx, `SELECT `+listColumns+` FROM lists WHERE id = $1`, id)
+
+	list, err := scanList(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.List{}, fmt.Errorf("%s: %w", op, storage.ErrListNotFound)
+		}
+		return models.List{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return list, nil
+}
+
+func (l *ListStorage) GetByShareToken(ctx context.Context, token string) (models.List, error) {
+	const op = "storage.list.GetByShareToken"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	row := l.storage.db.QueryRowContext(ctx, `SELECT `+listColumns+` FROM lists WHERE share_token = $1`, token)
+
+	list, err := scanList(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.List{}, fmt.Errorf("%s: %w", op, storage.ErrListNotFound)
+		}
+		return models.List{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return list, nil
+}
+
+func (l *ListStorage) Insert(ctx context.Context, list models.List) (int, error) {
+	const op = "storage.list.Insert"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	stmt, err := l.storage.db.PrepareContext(ctx, `INSERT INTO lists (user_id, name, kind, is_public) VALUES ($1, $2, $3, $4) RETURNING id`)
+	if err != nil {
+		return 0, fmt.Errorf("%s: prepare statement: %w", op, err)
+	}
+	defer stmt.Close()
+
+	var id int
+	err = stmt.QueryRowContext(ctx, list.UserID, list.Name, list.Kind, list.IsPublic).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: insert list: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (l *ListStorage) Update(ctx context.Context, list models.List) error {
+	const op = "storage.list.Update"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	stmt, err := l.storage.db.PrepareContext(ctx, `UPDATE lists SET name = $2, is_public = $3 WHERE id = $1`)
+	if err != nil {
+		return fmt.Errorf("%s: prepare statement: %w", op, err)
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, list.ID, list.Name, list.IsPublic)
+	if err != nil {
+		return fmt.Errorf("%s: update list: %w", op, err)
+	}
+
+	return nil
+}
+
+func (l *ListStorage) Delete(ctx context.Context, id int) error {
+	const op = "storage.list.Delete"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	stmt, err := l.storage.db.PrepareContext(ctx, `DELETE FROM lists WHERE id = $1`)
+	if err != nil {
+		return fmt.Errorf("%s: prepare statement: %w", op, err)
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%s: delete list: %w", op, err)
+	}
+
+	return nil
+}
+
+func (l *ListStorage) GetItems(ctx context.Context, listID, offset, limit int) ([]models.ListItem, error) {
+	const op = "storage.list.GetItems"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	query := `SELECT li.position, li.note, li.added_at, ` + projectCardColumns + projectCardFrom + `
+		JOIN list_items li ON li.project_id = p.id
+		WHERE li.list_id = $1
+		ORDER BY li.position, li.added_at OFFSET $2 LIMIT $3`
+
+	rows, err := l.storage.db.QueryContext(ctx, query, listID, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	items := make([]models.ListItem, 0)
+	for rows.Next() {
+		var item models.ListItem
+		card := &item.Project
+		err := rows.Scan(&item.Position, &item.Note, &item.AddedAt,
+			&card.ProjectID, &card.ProjectType, &card.Title, &card.ReleaseYear, &card.Popularity, &card.Cover)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan item: %w", op, err)
+		}
+		item.ProjectID = card.ProjectID
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return items, nil
+}
+
+// InsertItem appends a project to the end of a list. Adding a project to a
+// Favorites list also makes it more popular.
+func (l *ListStorage) InsertItem(ctx context.Context, listID, projectID int, note string) error {
+	const op = "storage.list.InsertItem"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	tx, err := l.storage.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	// Lock the whole list so concurrent inserts don't take the same
+	// position.
+	_, err = tx.ExecContext(ctx, `SELECT 1 FROM lists WHERE id = $1 FOR UPDATE`, listID)
+	if err != nil {
+		return fmt.Errorf("%s: lock list: %w", op, err)
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO list_items (list_id, project_id, position, note)
+		SELECT $1, $2, COALESCE(MAX(position) + 1, 0), $3 FROM list_items WHERE list_id = $1
+		ON CONFLICT DO NOTHING`, listID, projectID, note)
+	if err != nil {
+		return fmt.Errorf("%s: insert item: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: check rows affected: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return nil
+	}
+
+	if err := bumpPopularity(ctx, tx, listID, projectID, 1); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := insertFavoriteEvent(ctx, tx, models.EventFavoriteAdded, listID, projectID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit transaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteItem removes a project from a list and closes the gap it leaves in
+// the ordering.
+func (l *ListStorage) DeleteItem(ctx context.Context, listID, projectID int) error {
+	const op = "storage.list.DeleteItem"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	tx, err := l.storage.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	// Lock the whole list so the shift doesn't interleave with moves.
+	_, err = tx.ExecContext(ctx, `SELECT 1 FROM lists WHERE id = $1 FOR UPDATE`, listID)
+	if err != nil {
+		return fmt.Errorf("%s: lock list: %w", op, err)
+	}
+
+	var position int
+	err = tx.QueryRowContext(ctx, `DELETE FROM list_items WHERE list_id = $1 AND project_id = $2 RETURNING position`,
+		listID, projectID).Scan(&position)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, storage.ErrListItemNotFound)
+		}
+		return fmt.Errorf("%s: delete item: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE list_items SET position = position - 1 WHERE list_id = $1 AND position > $2`, listID, position)
+	if err != nil {
+		return fmt.Errorf("%s: shift items: %w", op, err)
+	}
+
+	if err := bumpPopularity(ctx, tx, listID, projectID, -1); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := insertFavoriteEvent(ctx, tx, models.EventFavoriteRemoved, listID, projectID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit transaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// MoveItem places a project at the given position, shifting the items in
+// between. Positions past the end of the list move the project last.
+func (l *ListStorage) MoveItem(ctx context.Context, listID, projectID, position int) error {
+	const op = "storage.list.MoveItem"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	tx, err := l.storage.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	// Lock the whole list so concurrent moves don't interleave.
+	_, err = tx.ExecContext(ctx, `SELECT 1 FROM lists WHERE id = $1 FOR UPDATE`, listID)
+	if err != nil {
+		return fmt.Errorf("%s: lock list: %w", op, err)
+	}
+
+	var current, count int
+	err = tx.QueryRowContext(ctx, `SELECT position, (SELECT COUNT(*) FROM list_items WHERE list_id = $1)
+		FROM list_items WHERE list_id = $1 AND project_id = $2`, listID, projectID).Scan(&current, &count)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, storage.ErrListItemNotFound)
+		}
+		return fmt.Errorf("%s: get item: %w", op, err)
+	}
+
+	if position < 0 {
+		position = 0
+	}
+	if position > count-1 {
+		position = count - 1
+	}
+	if position == current {
+		return nil
+	}
+
+	if position > current {
+		_, err = tx.ExecContext(ctx, `UPDATE list_items SET position = position - 1
+			WHERE list_id = $1 AND position > $2 AND position <= $3`, listID, current, position)
+	} else {
+		_, err = tx.ExecContext(ctx, `UPDATE list_items SET position = position + 1
+			WHERE list_id = $1 AND position >= $3 AND position < $2`, listID, current, position)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: shift items: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE list_items SET position = $3 WHERE list_id = $1 AND project_id = $2`, listID, projectID, position)
+	if err != nil {
+		return fmt.Errorf("%s: move item: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit transaction: %w", op, err)
+	}
+
+	return nil
+}
+
+func (l *ListStorage) UpdateItemNote(ctx context.Context, listID, projectID int, note string) error {
+	const op = "storage.list.UpdateItemNote"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	result, err := l.storage.db.ExecContext(ctx, `UPDATE list_items SET note = $3 WHERE list_id = $1 AND project_id = $2`, listID, projectID, note)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: check rows affected: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrListItemNotFound)
+	}
+
+	return nil
+}
+
+// bumpPopularity changes the popularity of the movie or series behind a
+// project by delta when the list is a Favorites list.
+func bumpPopularity(ctx context.Context, tx *sql.Tx, listID, projectID, delta int) error {
+	queries := []string{
+		`UPDATE movies SET popularity = popularity + $3
+			FROM projects p, lists l
+			WHERE l.id = $1 AND l.kind = 'favorites' AND p.id = $2 AND p.project_type = 'movie' AND movies.id = p.project_id`,
+		`UPDATE series SET popularity = popularity + $3
+			FROM projects p, lists l
+			WHERE l.id = $1 AND l.kind = 'favorites' AND p.id = $2 AND p.project_type = 'series' AND series.id = p.project_id`,
+	}
+
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, listID, projectID, delta); err != nil {
+			return fmt.Errorf("update popularity: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// insertFavoriteEvent records a favorite event when the list is a Favorites
+// list.
+func insertFavoriteEvent(ctx context.Context, tx *sql.Tx, eventType string, listID, projectID int) error {
+	var userID int
+	err := tx.QueryRowContext(ctx, `SELECT user_id FROM lists WHERE id = $1 AND kind = 'favorites'`, listID).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("get list owner: %w", err)
+	}
+
+	return insertEvent(ctx, tx, eventType, models.FavoriteEvent{UserID: userID, ProjectID: projectID})
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"ozinshe/internal/storage"
+	"ozinshe/internal/storage/storagetest"
+	"testing"
+)
+
+func TestInsertItem(t *testing.T) {
+	tests := []struct {
+		name        string
+		inserted    int64
+		wantCommits int
+	}{
+		{name: "new project", inserted: 1, wantCommits: 1},
+		{name: "project already in the list", inserted: 0, wantCommits: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := storagetest.New().Affect("INSERT INTO list_items", tt.inserted)
+
+			err := New(Open(db)).List.InsertItem(context.Background(), 1, 2, "")
+			if err != nil {
+				t.Fatalf("InsertItem() = %v, want nil", err)
+			}
+			if got := db.Commits(); got != tt.wantCommits {
+				t.Errorf("InsertItem() committed %d times, want %d", got, tt.wantCommits)
+			}
+		})
+	}
+}
+
+func TestListItemWriteErrors(t *testing.T) {
+	errWrite := errors.New("connection reset")
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		match string
+		write func(s *Storage) error
+	}{
+		{
+			name:  "insert",
+			match: "INSERT INTO list_items",
+			write: func(s *Storage) error { return s.List.InsertItem(ctx, 1, 2, "") },
+		},
+		{
+			name:  "lock before insert",
+			match: "FOR UPDATE",
+			write: func(s *Storage) error { return s.List.InsertItem(ctx, 1, 2, "") },
+		},
+		{
+			name:  "delete",
+			match: "DELETE FROM list_items",
+			write: func(s *Storage) error { return s.List.DeleteItem(ctx, 1, 2) },
+		},
+		{
+			name:  "note",
+			match: "UPDATE list_items SET note",
+			write: func(s *Storage) error { return s.List.UpdateItemNote(ctx, 1, 2, "later") },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := storagetest.New().Affect("INSERT INTO list_items", 1).Fail(tt.match, errWrite)
+
+			if err := tt.write(New(Open(db))); !errors.Is(err, errWrite) {
+				t.Errorf("write = %v, want %v", err, errWrite)
+			}
+			if got := db.Commits(); got != 0 {
+				t.Errorf("failed write committed %d times, want 0", got)
+			}
+		})
+	}
+}
+
+func TestDeleteItem(t *testing.T) {
+	db := storagetest.New().On("DELETE FROM list_items", []string{"position"}, []driver.Value{int64(3)})
+
+	if err := New(Open(db)).List.DeleteItem(context.Background(), 1, 2); err != nil {
+		t.Fatalf("DeleteItem() = %v, want nil", err)
+	}
+	if got := db.Commits(); got != 1 {
+		t.Errorf("DeleteItem() committed %d times, want 1", got)
+	}
+}
+
+func TestListItemNotFound(t *testing.T) {
+	s := New(Open(storagetest.New()))
+	ctx := context.Background()
+
+	if err := s.List.DeleteItem(ctx, 1, 2); !errors.Is(err, storage.ErrListItemNotFound) {
+		t.Errorf("DeleteItem() = %v, want %v", err, storage.ErrListItemNotFound)
+	}
+	if err := s.List.MoveItem(ctx, 1, 2, 0); !errors.Is(err, storage.ErrListItemNotFound) {
+		t.Errorf("MoveItem() = %v, want %v", err, storage.ErrListItemNotFound)
+	}
+	if err := s.List.UpdateItemNote(ctx, 1, 2, "later"); !errors.Is(err, storage.ErrListItemNotFound) {
+		t.Errorf("UpdateItemNote() = %v, want %v", err, storage.ErrListItemNotFound)
+	}
+}
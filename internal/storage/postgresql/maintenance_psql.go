@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+type MaintenanceStorage struct {
+	storage *Postgres
+}
+
+func NewMaintenanceStorage(db *Postgres) *MaintenanceStorage {
+	return &MaintenanceStorage{storage: db}
+}
+
+// RecomputePopularity resets the popularity of every movie and series to the
+// number of users who favorited it, fixing any drift of the running counts.
+func (m *MaintenanceStorage) RecomputePopularity(ctx context.Context) error {
+	const op = "storage.maintenance.RecomputePopularity"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	tx, err := m.storage.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	tables := []struct{ table, projectType string }{{"movies", "movie"}, {"series", "series"}}
+	for _, t := range tables {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE `+t.table+` t SET popularity = s.count
+			FROM (
+				SELECT c.id, COUNT(uf.user_id) AS count FROM `+t.table+` c
+				LEFT JOIN projects p ON p.project_type = $1 AND p.project_id = c.id
+				LEFT JOIN user_favorites uf ON uf.project_id = p.id
+				GROUP BY c.id
+			) s
+			WHERE t.id = s.id AND t.popularity IS DISTINCT FROM s.count`, t.projectType)
+		if err != nil {
+			return fmt.Errorf("%s: update %s: %w", op, t.table, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit transaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// MissingIDs returns the ids that have no row in table. Only tables with an
+// integer id column are supported.
+func (m *MaintenanceStorage) MissingIDs(ctx context.Context, table string, ids []int) ([]int, error) {
+	const op = "storage.maintenance.MissingIDs"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	switch table {
+	case "movies", "series", "people", "collections":
+	default:
+		return nil, fmt.Errorf("%s: unsupported table %q", op, table)
+	}
+
+	rows, err := m.storage.db.QueryContext(ctx, `
+		SELECT u.id FROM UNNEST($1::INTEGER[]) AS u(id)
+		WHERE NOT EXISTS (SELECT 1 FROM `+table+` t WHERE t.id = u.id)`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	missing := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		missing = append(missing, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return missing, nil
+}
+
+// GetRefreshTokens returns the refresh token of every signed in user by user
+// id.
+func (m *MaintenanceStorage) GetRefreshTokens(ctx context.Context) (map[int]string, error) {
+	const op = "storage.maintenance.GetRefreshTokens"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	rows, err := m.storage.db.QueryContext(ctx, `SELECT id, refresh_token FROM users WHERE refresh_token IS NOT NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	tokens := make(map[int]string)
+	for rows.Next() {
+		var id int
+		var token string
+		if err := rows.Scan(&id, &token); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		tokens[id] = token
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tokens, nil
+}
+
+func (m *MaintenanceStorage) ClearTokens(ctx context.Context, userIDs []int) error {
+	const op = "storage.maintenance.ClearTokens"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	_, err := m.storage.db.ExecContext(ctx, `UPDATE users SET token = NULL, refresh_token = NULL WHERE id = ANY($1)`,
+		pq.Array(userIDs))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -3,13 +3,15 @@ package storage
 import (
 	"context"
 	"ozinshe/internal/models"
+	"ozinshe/internal/recommend"
+	"time"
 )
 
 type User interface {
 	SaveUser(ctx context.Context, user models.User) error
 	Delete(ctx context.Context, id int) error
 	GetByEmail(ctx context.Context, email string) (models.User, error)
-	UpdateTokens(signedToken string, signedRefreshToken string, user_type string) error
+	UpdateTokens(ctx context.Context, signedToken string, signedRefreshToken string, user_type string) error
 	DeleteTokens(ctx context.Context, email string) error
 	GetAll(ctx context.Context) ([]models.User, error)
 	GetById(ctx context.Context, id int) (models.User, error)
@@ -22,11 +24,10 @@ type Movie interface {
 	GetByTitle(ctx context.Context, title string) ([]models.Movie, error)
 	GetByYear(ctx context.Context, year_start, year_end int) ([]models.Movie, error)
 	GetByGenres(ctx context.Context, genres []string) ([]models.Movie, error)
+	GetByPerson(ctx context.Context, personID int) ([]models.Movie, error)
 	GetAll(ctx context.Context) ([]models.Movie, error)
 	GetFavorites(ctx context.Context, userID int) ([]models.Movie, error)
 	Insert(ctx context.Context, movie models.Movie) (int, error)
-	InsertToFavorites(ctx context.Context, movieID, userID int) error
-	DeleteFromFavorites(ctx context.Context, movieID, userID int) error
 	Delete(ctx context.Context, id int) error
 	Update(ctx context.Context, movie models.Movie) error
 	UpdateCover(ctx context.Context, movieID int, cover models.Cover) error
@@ -37,25 +38,44 @@ type Movie interface {
 	FetchCover(ctx context.Context, movie *models.Movie) error
 	FetchScreenshots(ctx context.Context, movie *models.Movie) error
 	FetchAgeCategories(ctx context.Context, movie *models.Movie) error
+	FetchCredits(ctx context.Context, movie *models.Movie) error
+	GetByIds(ctx context.Context, ids []int) ([]models.Movie, error)
+	GetGenresByIds(ctx context.Context, ids []int) (map[int][]models.Genre, error)
+	GetAgeCategoriesByIds(ctx context.Context, ids []int) (map[int][]models.AgeCategory, error)
+	GetKeywordsByIds(ctx context.Context, ids []int) (map[int][]models.Keyword, error)
+	GetCoversByIds(ctx context.Context, ids []int) (map[int]models.Cover, error)
+	GetScreenshotsByIds(ctx context.Context, ids []int) (map[int][]models.Screenshot, error)
+	GetCreditsByIds(ctx context.Context, ids []int) (map[int][]models.Credit, error)
 }
 
 type Project interface {
 	Insert(ctx context.Context, project models.Project) (int, error)
-	InsertToFavorites(ctx context.Context, movieID, userID int) error
-	DeleteFromFavorites(ctx context.Context, movieID, userID int) error
 	Delete(ctx context.Context, id int) error
 	GetById(ctx context.Context, id int) (models.Project, error)
+	IsPublished(ctx context.Context, projectType string, contentID int) (bool, error)
+	SetPublication(ctx context.Context, id int, publication models.Publication) error
+	PublishDue(ctx context.Context) (int, error)
+	GetPublications(ctx context.Context, status string, offset, limit int) ([]models.PublicationCard, error)
+	GetTrending(ctx context.Context, offset, limit int) ([]models.ProjectCard, error)
+	GetNewReleases(ctx context.Context, offset, limit int) ([]models.ProjectCard, error)
+	GetByGenre(ctx context.Context, genreID, offset, limit int) ([]models.ProjectCard, error)
+	GetContinueWatching(ctx context.Context, userID, offset, limit int) ([]models.ProjectCard, error)
+	GetCards(ctx context.Context, projectType string) ([]models.ProjectCard, error)
+	GetFavoriteGenre(ctx context.Context, userID int) (models.Genre, error)
+	SaveProgress(ctx context.Context, progress models.WatchProgress) error
+	GetByIds(ctx context.Context, ids []int) ([]models.Project, error)
+	GetPublished(ctx context.Context, projectType string, offset, limit int) ([]models.Project, error)
+	GetFavoriteIds(ctx context.Context, userID int) ([]int, error)
 }
 
 type Series interface {
 	Insert(ctx context.Context, series models.Series) (int, error)
-	InsertToFavorites(ctx context.Context, movieID, userID int) error
 	Delete(ctx context.Context, id int) error
-	DeleteFromFavorites(ctx context.Context, movieID, userID int) error
 	GetById(ctx context.Context, id int) (models.Series, error)
 	GetByYear(ctx context.Context, year_start, year_end int) ([]models.Series, error)
 	GetByTitle(ctx context.Context, title string) ([]models.Series, error)
 	GetByGenres(ctx context.Context, genres []string) ([]models.Series, error)
+	GetByPerson(ctx context.Context, personID int) ([]models.Series, error)
 	GetAll(ctx context.Context) ([]models.Series, error)
 	GetFavorites(ctx context.Context, userID int) ([]models.Series, error)
 	GetEpisode(ctx context.Context, seriesID, seasonNumber, episodeNumber int) (models.Episode, error)
@@ -69,6 +89,16 @@ type Series interface {
 	FetchScreenshots(ctx context.Context, series *models.Series) error
 	FetchAgeCategories(ctx context.Context, series *models.Series) error
 	FetchEpisodes(ctx context.Context, seriesID, seasonID int) ([]models.Episode, error)
+	FetchCredits(ctx context.Context, series *models.Series) error
+	GetByIds(ctx context.Context, ids []int) ([]models.Series, error)
+	GetGenresByIds(ctx context.Context, ids []int) (map[int][]models.Genre, error)
+	GetAgeCategoriesByIds(ctx context.Context, ids []int) (map[int][]models.AgeCategory, error)
+	GetKeywordsByIds(ctx context.Context, ids []int) (map[int][]models.Keyword, error)
+	GetCoversByIds(ctx context.Context, ids []int) (map[int]models.Cover, error)
+	GetScreenshotsByIds(ctx context.Context, ids []int) (map[int][]models.Screenshot, error)
+	GetCreditsByIds(ctx context.Context, ids []int) (map[int][]models.Credit, error)
+	GetSeasonsByIds(ctx context.Context, ids []int) (map[int][]models.Season, error)
+	GetEpisodesBySeasonIds(ctx context.Context, seasonIDs []int) (map[int][]models.Episode, error)
 }
 
 type Genre interface {
@@ -85,10 +115,121 @@ type AgeCategory interface {
 	GetAll(ctx context.Context) ([]models.AgeCategory, error)
 }
 
+type Catalog interface {
+	ModifiedAt(ctx context.Context, resource string, id int) (time.Time, error)
+	ListModifiedAt(ctx context.Context, resource string) (time.Time, error)
+}
+
+type Sync interface {
+	Changes(ctx context.Context, since models.SyncPosition, userID, limit int) (models.SyncLog, error)
+	Compact(ctx context.Context) (int, error)
+}
+
 type Keyword interface {
 	Insert(ctx context.Context, keywords []models.Keyword) error
 }
 
+type Person interface {
+	Insert(ctx context.Context, person models.Person) (int, error)
+	Delete(ctx context.Context, id int) error
+	GetById(ctx context.Context, id int) (models.Person, error)
+	GetAll(ctx context.Context, name string) ([]models.Person, error)
+	FetchFilmography(ctx context.Context, person *models.Person) error
+	GetCredits(ctx context.Context, projectID int) ([]models.Credit, error)
+	ReplaceCredits(ctx context.Context, projectID int, credits []models.Credit) error
+}
+
+type Collection interface {
+	Insert(ctx context.Context, collection models.Collection) (int, error)
+	Delete(ctx context.Context, id int) error
+	GetAll(ctx context.Context) ([]models.Collection, error)
+	GetBySlug(ctx context.Context, slug string) (models.Collection, error)
+	GetProjects(ctx context.Context, collectionID, offset, limit int) ([]models.ProjectCard, error)
+	ReplaceProjects(ctx context.Context, collectionID int, projectIDs []int) error
+}
+
+type List interface {
+	EnsureBuiltins(ctx context.Context, userID int) error
+	GetBuiltin(ctx context.Context, userID int, kind string) (models.List, error)
+	GetByUser(ctx context.Context, userID int) ([]models.List, error)
+	GetById(ctx context.Context, id int) (models.List, error)
+	GetByShareToken(ctx context.Context, token string) (models.List, error)
+	Insert(ctx context.Context, list models.List) (int, error)
+	Update(ctx context.Context, list models.List) error
+	Delete(ctx context.Context, id int) error
+	GetItems(ctx context.Context, listID, offset, limit int) ([]models.ListItem, error)
+	InsertItem(ctx context.Context, listID, projectID int, note string) error
+	DeleteItem(ctx context.Context, listID, projectID int) error
+	MoveItem(ctx context.Context, listID, projectID, position int) error
+	UpdateItemNote(ctx context.Context, listID, projectID int, note string) error
+}
+
+type Notification interface {
+	InsertNewEpisodes(ctx context.Context, seriesID int, body string) ([]models.Notification, error)
+	InsertNewRelease(ctx context.Context, projectID int, body string) ([]models.Notification, error)
+	GetByUser(ctx context.Context, userID int, unreadOnly bool, offset, limit int) ([]models.Notification, error)
+	CountUnread(ctx context.Context, userID int) (int, error)
+	MarkRead(ctx context.Context, userID, id int) error
+	MarkAllRead(ctx context.Context, userID int) error
+	GetPreferences(ctx context.Context, userID int) (models.NotificationPreferences, error)
+	SavePreferences(ctx context.Context, userID int, prefs models.NotificationPreferences) error
+	Listen(ctx context.Context, deliver func(models.Notification)) error
+}
+
+type Webhook interface {
+	Insert(ctx context.Context, webhook models.Webhook) (int, error)
+	Delete(ctx context.Context, id int) error
+	GetAll(ctx context.Context) ([]models.Webhook, error)
+	GetById(ctx context.Context, id int) (models.Webhook, error)
+	Enqueue(ctx context.Context, event string, payload []byte) error
+	EnqueueFor(ctx context.Context, webhookID int, event string, payload []byte) (int, error)
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error)
+	RecordAttempt(ctx context.Context, delivery models.WebhookDelivery) error
+	GetDeliveries(ctx context.Context, webhookID, offset, limit int) ([]models.WebhookDelivery, error)
+	Redeliver(ctx context.Context, deliveryID int) error
+}
+
+type Outbox interface {
+	Relay(ctx context.Context, limit int, dispatch func(ctx context.Context, event models.Event, handled []string) ([]string, error), retryAfter func(attempts int) time.Duration) (int, error)
+}
+
+type Job interface {
+	Enqueue(ctx context.Context, kind string, payload []byte, runAt time.Time, maxAttempts int) (int64, error)
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]models.Job, error)
+	Complete(ctx context.Context, id int64, attempt int) error
+	Fail(ctx context.Context, id int64, attempt int, lastError string, retryAt *time.Time) error
+	GetAll(ctx context.Context, filter models.JobFilter) ([]models.Job, error)
+	GetById(ctx context.Context, id int64) (models.Job, error)
+	Retry(ctx context.Context, id int64) error
+	SaveSchedule(ctx context.Context, schedule models.JobSchedule) error
+	GetSchedules(ctx context.Context) ([]models.JobSchedule, error)
+	EnqueueScheduled(ctx context.Context, maxAttempts int, next func(models.JobSchedule) time.Time) (int, error)
+}
+
+type Maintenance interface {
+	RecomputePopularity(ctx context.Context) error
+	MissingIDs(ctx context.Context, table string, ids []int) ([]int, error)
+	GetRefreshTokens(ctx context.Context) (map[int]string, error)
+	ClearTokens(ctx context.Context, userIDs []int) error
+}
+
+type Audit interface {
+	GetAll(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error)
+}
+
+type Recommendation interface {
+	LoadItems(ctx context.Context) ([]recommend.Item, error)
+	LoadFavorites(ctx context.Context) (map[int][]int, error)
+	ReplaceSimilarities(ctx context.Context, similarities []models.Similarity) error
+	GetSimilar(ctx context.Context, projectID, offset, limit int) ([]models.ProjectCard, error)
+	GetForUser(ctx context.Context, userID, offset, limit int) ([]models.ProjectCard, error)
+}
+
+type Health interface {
+	Ping(ctx context.Context) error
+	SchemaVersion(ctx context.Context) (uint, bool, error)
+}
+
 type Storage struct {
 	User
 	Movie
@@ -97,16 +238,42 @@ type Storage struct {
 	AgeCategory
 	Keyword
 	Project
+	Catalog
+	Sync
+	Person
+	Collection
+	List
+	Notification
+	Webhook
+	Outbox
+	Job
+	Maintenance
+	Audit
+	Recommendation
+	Health
 }
 
 func New(storage *Postgres) *Storage {
 	return &Storage{
-		User:        NewUserStorage(storage),
-		Genre:       NewGenreStorage(storage),
-		Movie:       NewMovieStorage(storage),
-		Series:      NewSeriesStorage(storage),
-		AgeCategory: NewAgeCategoryStorage(storage),
-		Keyword:     NewKeywordStorage(storage),
-		Project:     NewProjectStorage(storage),
+		User:           NewUserStorage(storage),
+		Genre:          NewGenreStorage(storage),
+		Movie:          NewMovieStorage(storage),
+		Series:         NewSeriesStorage(storage),
+		AgeCategory:    NewAgeCategoryStorage(storage),
+		Keyword:        NewKeywordStorage(storage),
+		Project:        NewProjectStorage(storage),
+		Catalog:        NewCatalogStorage(storage),
+		Sync:           NewSyncStorage(storage),
+		Person:         NewPersonStorage(storage),
+		Collection:     NewCollectionStorage(storage),
+		List:           NewListStorage(storage),
+		Notification:   NewNotificationStorage(storage),
+		Webhook:        NewWebhookStorage(storage),
+		Outbox:         NewOutboxStorage(storage),
+		Job:            NewJobStorage(storage),
+		Maintenance:    NewMaintenanceStorage(storage),
+		Audit:          NewAuditStorage(storage),
+		Recommendation: NewRecommendationStorage(storage),
+		Health:         NewHealthStorage(storage),
 	}
 }
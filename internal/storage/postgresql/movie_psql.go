@@ -12,6 +12,11 @@ import (
 	"github.com/lib/pq"
 )
 
+// movieColumns selects a movie of movies m in the order of the fields of
+// models.Movie.
+var movieColumns = `m.id, m.title, m.release_year, m.description, m.popularity, m.youtube_id, m.duration, ` +
+	crewColumns("movie", "m.id")
+
 type MovieStorage struct {
 	storage *Postgres
 }
@@ -22,6 +27,8 @@ func NewMovieStorage(db *Postgres) *MovieStorage {
 
 func (m *MovieStorage) Insert(ctx context.Context, movie models.Movie) (int, error) {
 	const op = "storage.movie.Insert"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	tx, err := m.storage.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -31,11 +38,11 @@ func (m *MovieStorage) Insert(ctx context.Context, movie models.Movie) (int, err
 	// Insert Movie
 	var movieID int
 	err = tx.QueryRowContext(ctx,
-		`INSERT INTO movies (title, release_year, description, popularity, youtube_id, duration, director, producer)
-         VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`INSERT INTO movies (title, release_year, description, popularity, youtube_id, duration)
+         VALUES ($1, $2, $3, $4, $5, $6)
          RETURNING id`,
 		movie.Title, movie.ReleaseYear, movie.Description, movie.Popularity,
-		movie.YoutubeID, movie.Duration, movie.Director, movie.Producer,
+		movie.YoutubeID, movie.Duration,
 	).Scan(&movieID)
 	if err != nil {
 		var pqErr *pq.Error
@@ -181,6 +188,12 @@ func (m *MovieStorage) Insert(ctx context.Context, movie models.Movie) (int, err
 		return 0, fmt.Errorf("%s: insert cover: %w", op, err)
 	}
 
+	err = auditContentChange(ctx, tx, "movie.create", "movie", movieID, nil)
+	if err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
 	// Commit the transaction
 	err = tx.Commit()
 	if err != nil {
@@ -192,16 +205,11 @@ func (m *MovieStorage) Insert(ctx context.Context, movie models.Movie) (int, err
 
 func (m *MovieStorage) Delete(ctx context.Context, id int) error {
 	const op = "storage.movie.Delete"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := m.storage.db.Prepare("DELETE FROM movies WHERE id = $1")
-	if err != nil {
-		return fmt.Errorf("%s: movie prepare statement: %w", op, err)
-	}
-	defer stmt.Close()
-
-	_, err = stmt.ExecContext(ctx, id)
-	if err != nil {
-		return fmt.Errorf("%s: delete movie: %w", op, err)
+	if err := deleteAudited(ctx, m.storage.db, "movies", "movie", id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
 
 	return nil
@@ -209,25 +217,31 @@ func (m *MovieStorage) Delete(ctx context.Context, id int) error {
 
 func (m *MovieStorage) Update(ctx context.Context, movie models.Movie) error {
 	const op = "storage.movie.Update"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	tx, err := m.storage.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("%s: begin transaction: %w", op, err)
 	}
 
+	before, err := contentSnapshot(ctx, tx, "movie", movie.ID)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	// Update Movie
 	if movie.Title != "" || movie.ReleaseYear != 0 || movie.Description != "" ||
-		movie.Popularity != 0 || movie.YoutubeID != "" || movie.Duration != 0 ||
-		movie.Director != "" || movie.Producer != "" {
+		movie.Popularity != 0 || movie.YoutubeID != "" || movie.Duration != 0 {
 		_, err := tx.ExecContext(ctx,
 			`UPDATE movies 
 			SET title = COALESCE($1, title), release_year = COALESCE($2, release_year), 
 			description = COALESCE($3, description), popularity = COALESCE($4, popularity), 
-			youtube_id = COALESCE($5, youtube_id), duration = COALESCE($6, duration), 
-			director = COALESCE($7, director), producer = COALESCE($8, producer)
-			WHERE id = $9`,
+			youtube_id = COALESCE($5, youtube_id), duration = COALESCE($6, duration)
+			WHERE id = $7`,
 			movie.Title, movie.ReleaseYear, movie.Description, movie.Popularity,
-			movie.YoutubeID, movie.Duration, movie.Director, movie.Producer,
+			movie.YoutubeID, movie.Duration,
 			movie.ID,
 		)
 		if err != nil {
@@ -330,6 +344,27 @@ func (m *MovieStorage) Update(ctx context.Context, movie models.Movie) error {
 		}
 	}
 
+	// Update Credits, leaving them as they are when none are given
+	if movie.Credits != nil {
+		err = writeContentCredits(ctx, tx, "movie", movie.ID, movie.Credits)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	err = insertProjectEvent(ctx, tx, models.EventProjectUpdated, "movie", movie.ID)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = auditContentChange(ctx, tx, "movie.update", "movie", movie.ID, before)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	// Commit the transaction
 	err = tx.Commit()
 	if err != nil {
@@ -341,12 +376,20 @@ func (m *MovieStorage) Update(ctx context.Context, movie models.Movie) error {
 
 func (m *MovieStorage) UpdateCover(ctx context.Context, movieID int, cover models.Cover) error {
 	const op = "storage.movie.UpdateCover"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	tx, err := m.storage.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	before, err := contentSnapshot(ctx, tx, "movie", movieID)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	// 1. Delete existing cover (if any)
 	_, err = tx.ExecContext(ctx, `DELETE FROM movie_covers WHERE movie_id = $1`, movieID)
 	if err != nil {
@@ -363,17 +406,31 @@ func (m *MovieStorage) UpdateCover(ctx context.Context, movieID int, cover model
 		return fmt.Errorf("%s: insert error: %w", op, err)
 	}
 
+	err = auditContentChange(ctx, tx, "movie.update_cover", "movie", movieID, before)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	return tx.Commit()
 }
 
 func (m *MovieStorage) UpdateScreenshots(ctx context.Context, movieID int, screenshots []models.Screenshot) error {
 	const op = "storage.movie.UpdateScreenshots"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	tx, err := m.storage.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	before, err := contentSnapshot(ctx, tx, "movie", movieID)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	// 1. Delete existing screenshots
 	_, err = tx.ExecContext(ctx, `DELETE FROM movie_screenshots WHERE movie_id = $1`, movieID)
 	if err != nil {
@@ -397,11 +454,19 @@ func (m *MovieStorage) UpdateScreenshots(ctx context.Context, movieID int, scree
 		}
 	}
 
+	err = auditContentChange(ctx, tx, "movie.update_screenshots", "movie", movieID, before)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	return tx.Commit()
 }
 
 func (m *MovieStorage) GetAll(ctx context.Context) ([]models.Movie, error) {
 	const op = "storage.movie.GetAll"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	movies, err := m.FetchMovieData(ctx)
 	if err != nil {
@@ -440,6 +505,8 @@ func (m *MovieStorage) GetAll(ctx context.Context) ([]models.Movie, error) {
 
 func (m *MovieStorage) FetchGenres(ctx context.Context, movie *models.Movie) error {
 	const op = "storage.movie.FetchGenres"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	query := `SELECT g.id, g.name 
 				FROM genres g 
@@ -466,6 +533,8 @@ func (m *MovieStorage) FetchGenres(ctx context.Context, movie *models.Movie) err
 
 func (m *MovieStorage) FetchKeywords(ctx context.Context, movie *models.Movie) error {
 	const op = "storage.movie.FetchKeywords"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	query := `SELECT k.id, k.name 
 				FROM key_words k 
@@ -493,10 +562,12 @@ func (m *MovieStorage) FetchKeywords(ctx context.Context, movie *models.Movie) e
 
 func (m *MovieStorage) FetchMovieData(ctx context.Context) ([]models.Movie, error) {
 	const op = "storage.movie.FetchMovieData"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	movies := make([]models.Movie, 0)
 
-	rows, err := m.storage.db.QueryContext(ctx, `SELECT * FROM movies`)
+	rows, err := m.storage.db.QueryContext(ctx, `SELECT `+movieColumns+` FROM movies m WHERE id IN `+publishedMovies)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -519,6 +590,8 @@ func (m *MovieStorage) FetchMovieData(ctx context.Context) ([]models.Movie, erro
 
 func (m *MovieStorage) FetchCover(ctx context.Context, movie *models.Movie) error {
 	const op = "storage.movie.FetchCover"
+	ctx, end := startOp(ctx, op)
+	defer end()
 	var filename string
 	err := m.storage.db.QueryRowContext(ctx, `SELECT filename FROM movie_covers WHERE movie_id = $1`, movie.ID).Scan(&filename)
 	if err != nil {
@@ -530,6 +603,8 @@ func (m *MovieStorage) FetchCover(ctx context.Context, movie *models.Movie) erro
 
 func (m *MovieStorage) FetchScreenshots(ctx context.Context, movie *models.Movie) error {
 	const op = "storage.movie.FetchScreenshots"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	query := "SELECT filename FROM movie_screenshots WHERE movie_id = $1"
 	rows, err := m.storage.db.QueryContext(ctx, query, movie.ID)
@@ -552,6 +627,8 @@ func (m *MovieStorage) FetchScreenshots(ctx context.Context, movie *models.Movie
 
 func (m *MovieStorage) FetchAgeCategories(ctx context.Context, movie *models.Movie) error {
 	const op = "storage.movie.FetchAgeCategories"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	query := `SELECT ac.id, ac.min_age, ac.max_age
               FROM age_categories ac 
@@ -576,10 +653,42 @@ func (m *MovieStorage) FetchAgeCategories(ctx context.Context, movie *models.Mov
 	return nil
 }
 
+func (m *MovieStorage) FetchCredits(ctx context.Context, movie *models.Movie) error {
+	const op = "storage.movie.FetchCredits"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	query := `SELECT c.id, c.person_id, pe.name, pe.photo, c.project_id, c.role, c.character_name, c.position
+				FROM credits c
+				JOIN people pe ON pe.id = c.person_id
+				JOIN projects p ON p.id = c.project_id
+				WHERE p.project_type = 'movie' AND p.project_id = $1
+				ORDER BY c.position`
+
+	rows, err := m.storage.db.QueryContext(ctx, query, movie.ID)
+	if err != nil {
+		return fmt.Errorf("%s: query movie credits: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var credit models.Credit
+		err := rows.Scan(&credit.ID, &credit.PersonID, &credit.PersonName, &credit.PersonPhoto, &credit.ProjectID, &credit.Role, &credit.Character, &credit.Position)
+		if err != nil {
+			return fmt.Errorf("%s: scan credit row: %w", op, err)
+		}
+		movie.Credits = append(movie.Credits, credit)
+	}
+
+	return nil
+}
+
 func (m *MovieStorage) GetById(ctx context.Context, id int) (models.Movie, error) {
 	const op = "storage.movie.GetById"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := m.storage.db.Prepare(`SELECT * FROM movies WHERE id = $1`)
+	stmt, err := m.storage.db.PrepareContext(ctx, `SELECT `+movieColumns+` FROM movies m WHERE id = $1`)
 	if err != nil {
 		return models.Movie{}, fmt.Errorf("%s: prepare statement: %w", op, err)
 	}
@@ -588,7 +697,7 @@ func (m *MovieStorage) GetById(ctx context.Context, id int) (models.Movie, error
 	var movie models.Movie
 	err = stmt.QueryRowContext(ctx, id).Scan(&movie.ID, &movie.Title, &movie.ReleaseYear, &movie.Description, &movie.Popularity, &movie.YoutubeID, &movie.Duration, &movie.Director, &movie.Producer)
 	if err != nil {
-		return models.Movie{}, fmt.Errorf("%s: get movie: %w", op, err)
+		return models.Movie{}, fmt.Errorf("%s: get movie: %w", op, notFound(err, storage.ErrMovieNotFound))
 	}
 
 	if err = m.FetchCover(ctx, &movie); err != nil {
@@ -611,128 +720,20 @@ func (m *MovieStorage) GetById(ctx context.Context, id int) (models.Movie, error
 		return models.Movie{}, fmt.Errorf("%s: Fetch genres: %w", op, err)
 	}
 
-	return movie, nil
-}
-
-func (m *MovieStorage) InsertToFavorites(ctx context.Context, movieID, userID int) error {
-	const op = "storage.movie.InsertToFavorites"
-
-	tx, err := m.storage.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("%s: begin transaction: %w", op, err)
-	}
-
-	// Insert into favorites (handling potential conflicts)
-	stmt, err := tx.Prepare(`INSERT INTO favorite_movies (user_id, movie_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("%s: prepare insert statement: %w", op, err)
-	}
-	defer stmt.Close()
-
-	result, err := stmt.ExecContext(ctx, userID, movieID)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("%s: insert into favorites: %w", op, err)
-	}
-
-	// Check if anything was actually inserted
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("%s: check rows affected: %w", op, err)
-	}
-
-	if rowsAffected == 0 {
-		tx.Rollback() // No need to increment popularity if nothing was inserted
-		return nil    // Or you might want to return a specific "already exists" error
-	}
-
-	// Increment popularity (only if a row was inserted)
-	stmt, err = tx.Prepare(`UPDATE movies SET popularity = popularity + 1 WHERE id = $1`)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("%s: prepare update statement: %w", op, err)
-	}
-	defer stmt.Close()
-
-	_, err = stmt.ExecContext(ctx, movieID)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("%s: increment popularity: %w", op, err)
-	}
-
-	// Commit if all operations succeeded
-	err = tx.Commit()
-	if err != nil {
-		return fmt.Errorf("%s: commit transaction: %w", op, err)
-	}
-
-	return nil
-}
-
-func (m *MovieStorage) DeleteFromFavorites(ctx context.Context, movieID, userID int) error {
-	const op = "storage.movie.DeleteFromFavorites"
-
-	tx, err := m.storage.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("%s: begin transaction: %w", op, err)
-	}
-
-	// Attempt to delete from favorites
-	stmt, err := tx.Prepare(`DELETE FROM favorite_movies WHERE user_id = $1 AND movie_id = $2`)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("%s: prepare delete statement: %w", op, err)
-	}
-	defer stmt.Close()
-
-	result, err := stmt.ExecContext(ctx, userID, movieID)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("%s: delete from favorites: %w", op, err)
+	if err = m.FetchCredits(ctx, &movie); err != nil {
+		return models.Movie{}, fmt.Errorf("%s: Fetch credits: %w", op, err)
 	}
 
-	// Check if anything was actually deleted
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("%s: check rows affected: %w", op, err)
-	}
-
-	if rowsAffected == 0 {
-		tx.Rollback() // No need to decrement popularity if nothing was deleted
-		return nil    // Or you might want to return a specific "not found" error
-	}
-
-	// Decrement popularity (only if a row was deleted)
-	stmt, err = tx.Prepare(`UPDATE movies SET popularity = popularity - 1 WHERE id = $1`)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("%s: prepare update statement: %w", op, err)
-	}
-	defer stmt.Close()
-
-	_, err = stmt.ExecContext(ctx, movieID)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("%s: decrement popularity: %w", op, err)
-	}
-
-	// Commit if all operations succeeded
-	err = tx.Commit()
-	if err != nil {
-		return fmt.Errorf("%s: commit transaction: %w", op, err)
-	}
-
-	return nil
+	return movie, nil
 }
 
 func (m *MovieStorage) GetFavorites(ctx context.Context, userID int) ([]models.Movie, error) {
 	const op = "storage.movie.GetFavorites"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	var movieIDs []int
-	stmt, err := m.storage.db.Prepare(`SELECT movie_id FROM favorite_movies WHERE user_id = $1`)
+	stmt, err := m.storage.db.PrepareContext(ctx, `SELECT p.project_id FROM user_favorites f JOIN published_projects p ON p.id = f.project_id WHERE f.user_id = $1 AND p.project_type = 'movie'`)
 	if err != nil {
 		return nil, fmt.Errorf("%s: prepare statement (phase 1): %w", op, err)
 	}
@@ -764,7 +765,7 @@ func (m *MovieStorage) GetFavorites(ctx context.Context, userID int) ([]models.M
 		args[i] = id
 	}
 
-	query := fmt.Sprintf(`SELECT * FROM movies WHERE id IN (%s)`, strings.Join(placeholders, ", "))
+	query := fmt.Sprintf(`SELECT %s FROM movies m WHERE id IN (%s)`, movieColumns, strings.Join(placeholders, ", "))
 
 	rows, err = m.storage.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -797,8 +798,10 @@ func (m *MovieStorage) GetFavorites(ctx context.Context, userID int) ([]models.M
 
 func (m *MovieStorage) GetByTitle(ctx context.Context, title string) ([]models.Movie, error) {
 	const op = "storage.movie.GetByTitle"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := m.storage.db.Prepare(`SELECT * FROM movies WHERE title ILIKE $1`)
+	stmt, err := m.storage.db.PrepareContext(ctx, `SELECT `+movieColumns+` FROM movies m WHERE title ILIKE $1 AND id IN `+publishedMovies)
 	if err != nil {
 		return nil, fmt.Errorf("%s: prepare statement: %w", op, err)
 	}
@@ -837,15 +840,16 @@ func (m *MovieStorage) GetByTitle(ctx context.Context, title string) ([]models.M
 
 func (m *MovieStorage) GetByGenres(ctx context.Context, genres []string) ([]models.Movie, error) {
 	const op = "storage.movie.GetByGenres"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	// Start building the SQL query
 	query := `
-        SELECT m.id, m.title, m.release_year, m.description, m.popularity, m.youtube_id, 
-               m.duration, m.director, m.producer
+        SELECT ` + movieColumns + `
         FROM movies m
         JOIN movie_genres mg ON m.id = mg.movie_id
         JOIN genres g ON mg.genre_id = g.id
-        WHERE g.name IN (`
+        WHERE m.id IN ` + publishedMovies + ` AND g.name IN (`
 
 	// Add placeholders for genre names
 	placeholders := make([]string, len(genres))
@@ -888,8 +892,10 @@ func (m *MovieStorage) GetByGenres(ctx context.Context, genres []string) ([]mode
 
 func (m *MovieStorage) GetByYear(ctx context.Context, yearStart, yearEnd int) ([]models.Movie, error) {
 	const op = "storage.movie.GetByYear"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := m.storage.db.Prepare(`SELECT * FROM movies WHERE release_year BETWEEN $1 AND $2`)
+	stmt, err := m.storage.db.PrepareContext(ctx, `SELECT `+movieColumns+` FROM movies m WHERE release_year BETWEEN $1 AND $2 AND id IN `+publishedMovies)
 	if err != nil {
 		return nil, fmt.Errorf("%s: prepare statement: %w", op, err)
 	}
@@ -913,3 +919,159 @@ func (m *MovieStorage) GetByYear(ctx context.Context, yearStart, yearEnd int) ([
 
 	return movies, nil
 }
+
+func (m *MovieStorage) GetByPerson(ctx context.Context, personID int) ([]models.Movie, error) {
+	const op = "storage.movie.GetByPerson"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	stmt, err := m.storage.db.PrepareContext(ctx, `
+		SELECT DISTINCT `+movieColumns+`
+		FROM movies m
+		JOIN published_projects p ON p.project_type = 'movie' AND p.project_id = m.id
+		JOIN credits c ON c.project_id = p.id
+		WHERE c.person_id = $1`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: prepare statement: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, personID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	var movies []models.Movie
+	for rows.Next() {
+		var movie models.Movie
+		err = rows.Scan(&movie.ID, &movie.Title, &movie.ReleaseYear, &movie.Description, &movie.Popularity, &movie.YoutubeID, &movie.Duration, &movie.Director, &movie.Producer)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan movie: %w", op, err)
+		}
+		movies = append(movies, movie)
+	}
+
+	return movies, nil
+}
+
+// GetByIds returns the movies with the given IDs, without their genres,
+// images and other parts, which the Get*ByIds methods load for many movies
+// at once. Missing movies are left out.
+func (m *MovieStorage) GetByIds(ctx context.Context, ids []int) ([]models.Movie, error) {
+	const op = "storage.movie.GetByIds"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	rows, err := m.storage.db.QueryContext(ctx, `
+		SELECT m.id, m.title, m.release_year, m.description, COALESCE(m.popularity, 0), COALESCE(m.youtube_id, ''), m.duration, `+crewColumns("movie", "m.id")+`
+		FROM movies m WHERE id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	movies := make([]models.Movie, 0, len(ids))
+	for rows.Next() {
+		var movie models.Movie
+		err = rows.Scan(&movie.ID, &movie.Title, &movie.ReleaseYear, &movie.Description, &movie.Popularity, &movie.YoutubeID, &movie.Duration, &movie.Director, &movie.Producer)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		movies = append(movies, movie)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return movies, nil
+}
+
+func (m *MovieStorage) GetGenresByIds(ctx context.Context, ids []int) (map[int][]models.Genre, error) {
+	const op = "storage.movie.GetGenresByIds"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	genres, err := groupByIds(ctx, m.storage.db, `
+		SELECT mg.movie_id, g.id, g.name
+		FROM genres g JOIN movie_genres mg ON g.id = mg.genre_id
+		WHERE mg.movie_id = ANY($1) ORDER BY g.name`, ids, scanGenre)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return genres, nil
+}
+
+func (m *MovieStorage) GetAgeCategoriesByIds(ctx context.Context, ids []int) (map[int][]models.AgeCategory, error) {
+	const op = "storage.movie.GetAgeCategoriesByIds"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	ageCategories, err := groupByIds(ctx, m.storage.db, `
+		SELECT mac.movie_id, ac.id, ac.min_age, ac.max_age
+		FROM age_categories ac JOIN movie_age_categories mac ON ac.id = mac.age_category_id
+		WHERE mac.movie_id = ANY($1) ORDER BY ac.min_age, ac.max_age`, ids, scanAgeCategory)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return ageCategories, nil
+}
+
+func (m *MovieStorage) GetKeywordsByIds(ctx context.Context, ids []int) (map[int][]models.Keyword, error) {
+	const op = "storage.movie.GetKeywordsByIds"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	keywords, err := groupByIds(ctx, m.storage.db, `
+		SELECT mk.movie_id, k.id, k.name
+		FROM key_words k JOIN movie_key_words mk ON k.id = mk.key_word_id
+		WHERE mk.movie_id = ANY($1) ORDER BY k.name`, ids, scanKeyword)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keywords, nil
+}
+
+func (m *MovieStorage) GetCoversByIds(ctx context.Context, ids []int) (map[int]models.Cover, error) {
+	const op = "storage.movie.GetCoversByIds"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	images, err := groupByIds(ctx, m.storage.db, `
+		SELECT movie_id, id, filename FROM movie_covers WHERE movie_id = ANY($1) ORDER BY id`, ids, scanImage)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return covers(images), nil
+}
+
+func (m *MovieStorage) GetScreenshotsByIds(ctx context.Context, ids []int) (map[int][]models.Screenshot, error) {
+	const op = "storage.movie.GetScreenshotsByIds"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	screenshots, err := groupByIds(ctx, m.storage.db, `
+		SELECT movie_id, id, filename FROM movie_screenshots WHERE movie_id = ANY($1) ORDER BY id`, ids, scanImage)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return screenshots, nil
+}
+
+func (m *MovieStorage) GetCreditsByIds(ctx context.Context, ids []int) (map[int][]models.Credit, error) {
+	const op = "storage.movie.GetCreditsByIds"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	credits, err := groupByIds(ctx, m.storage.db, creditsByIds("movie"), ids, scanCredit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return credits, nil
+}
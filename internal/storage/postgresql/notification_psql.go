@@ -0,0 +1,249 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ozinshe/internal/models"
+	"ozinshe/internal/storage"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type NotificationStorage struct {
+	storage *Postgres
+}
+
+func NewNotificationStorage(db *Postgres) *NotificationStorage {
+	return &NotificationStorage{storage: db}
+}
+
+const notificationColumns = `id, user_id, kind, COALESCE(project_id, 0), title, body, read_at, created_at`
+
+func scanNotifications(rows *sql.Rows) ([]models.Notification, error) {
+	notifications := make([]models.Notification, 0)
+	for rows.Next() {
+		var n models.Notification
+		err := rows.Scan(&n.ID, &n.UserID, &n.Kind, &n.ProjectID, &n.Title, &n.Body, &n.ReadAt, &n.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func (n *NotificationStorage) insert(ctx context.Context, op, query string, args ...any) ([]models.Notification, error) {
+	rows, err := n.storage.db.QueryContext(ctx, query+` RETURNING `+notificationColumns, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: insert notifications: %w", op, err)
+	}
+	defer rows.Close()
+
+	notifications, err := scanNotifications(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: scan notification: %w", op, err)
+	}
+
+	return notifications, nil
+}
+
+// InsertNewEpisodes notifies every user having the series in one of their
+// lists, unless they turned new episode notifications off.
+func (n *NotificationStorage) InsertNewEpisodes(ctx context.Context, seriesID int, body string) ([]models.Notification, error) {
+	const op = "storage.notification.InsertNewEpisodes"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	query := `INSERT INTO notifications (user_id, kind, project_id, title, body)
+		SELECT DISTINCT l.user_id, 'new_episodes', p.id, s.title, $2
+		FROM published_projects p
+		JOIN series s ON s.id = p.project_id
+		JOIN list_items li ON li.project_id = p.id
+		JOIN lists l ON l.id = li.list_id
+		LEFT JOIN notification_preferences np ON np.user_id = l.user_id
+		WHERE p.project_type = 'series' AND p.project_id = $1 AND COALESCE(np.new_episodes, TRUE)`
+
+	return n.insert(ctx, op, query, seriesID, body)
+}
+
+// InsertNewRelease notifies every user about a published project, unless they
+// turned new release notifications off.
+func (n *NotificationStorage) InsertNewRelease(ctx context.Context, projectID int, body string) ([]models.Notification, error) {
+	const op = "storage.notification.InsertNewRelease"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	query := `INSERT INTO notifications (user_id, kind, project_id, title, body)
+		SELECT u.id, 'new_release', p.id, COALESCE(m.title, s.title, ''), $2
+		FROM projects p
+		LEFT JOIN movies m ON p.project_type = 'movie' AND m.id = p.project_id
+		LEFT JOIN series s ON p.project_type = 'series' AND s.id = p.project_id
+		CROSS JOIN users u
+		LEFT JOIN notification_preferences np ON np.user_id = u.id
+		WHERE p.id = $1 AND COALESCE(np.new_releases, TRUE)`
+
+	return n.insert(ctx, op, query, projectID, body)
+}
+
+func (n *NotificationStorage) GetByUser(ctx context.Context, userID int, unreadOnly bool, offset, limit int) ([]models.Notification, error) {
+	const op = "storage.notification.GetByUser"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	query := `SELECT ` + notificationColumns + ` FROM notifications
+		WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+		ORDER BY created_at DESC, id DESC OFFSET $3 LIMIT $4`
+
+	rows, err := n.storage.db.QueryContext(ctx, query, userID, unreadOnly, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	notifications, err := scanNotifications(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: scan notification: %w", op, err)
+	}
+
+	return notifications, nil
+}
+
+func (n *NotificationStorage) CountUnread(ctx context.Context, userID int) (int, error) {
+	const op = "storage.notification.CountUnread"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	var count int
+	err := n.storage.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+func (n *NotificationStorage) MarkRead(ctx context.Context, userID, id int) error {
+	const op = "storage.notification.MarkRead"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	result, err := n.storage.db.ExecContext(ctx, `UPDATE notifications SET read_at = COALESCE(read_at, NOW()) WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: check rows affected: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrNotificationNotFound)
+	}
+
+	return nil
+}
+
+func (n *NotificationStorage) MarkAllRead(ctx context.Context, userID int) error {
+	const op = "storage.notification.MarkAllRead"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	_, err := n.storage.db.ExecContext(ctx, `UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL`, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetPreferences returns the user's preferences, everything enabled when the
+// user never saved any.
+func (n *NotificationStorage) GetPreferences(ctx context.Context, userID int) (models.NotificationPreferences, error) {
+	const op = "storage.notification.GetPreferences"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	prefs := models.NotificationPreferences{NewEpisodes: true, NewReleases: true}
+
+	err := n.storage.db.QueryRowContext(ctx, `SELECT new_episodes, new_releases FROM notification_preferences WHERE user_id = $1`, userID).
+		Scan(&prefs.NewEpisodes, &prefs.NewReleases)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return models.NotificationPreferences{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return prefs, nil
+}
+
+func (n *NotificationStorage) SavePreferences(ctx context.Context, userID int, prefs models.NotificationPreferences) error {
+	const op = "storage.notification.SavePreferences"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	stmt, err := n.storage.db.PrepareContext(ctx, `
+		INSERT INTO notification_preferences (user_id, new_episodes, new_releases) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET new_episodes = EXCLUDED.new_episodes, new_releases = EXCLUDED.new_releases`)
+	if err != nil {
+		return fmt.Errorf("%s: prepare statement: %w", op, err)
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, userID, prefs.NewEpisodes, prefs.NewReleases)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// notificationsChannel is where a trigger sends every new notification, as
+// JSON.
+const notificationsChannel = "notifications"
+
+// Listen calls deliver with every notification created from now on, by any
+// process, until ctx is done. The listening connection reconnects on its
+// own; notifications created while it is down are not delivered, but are
+// still in the inboxes.
+func (n *NotificationStorage) Listen(ctx context.Context, deliver func(models.Notification)) error {
+	const op = "storage.notification.Listen"
+
+	if n.storage.dsn == "" {
+		return fmt.Errorf("%s: no connection string to listen with", op)
+	}
+
+	listener := pq.NewListener(n.storage.dsn, time.Second, time.Minute, nil)
+	defer listener.Close()
+
+	if err := listener.Listen(notificationsChannel); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-listener.Notify:
+			// A nil event tells that the connection was reestablished.
+			if event == nil {
+				continue
+			}
+
+			var notification models.Notification
+			if err := json.Unmarshal([]byte(event.Extra), &notification); err != nil {
+				return fmt.Errorf("%s: decode notification: %w", op, err)
+			}
+			deliver(notification)
+		case <-time.After(90 * time.Second):
+			// Notice a dead connection while no notifications come.
+			go listener.Ping()
+		}
+	}
+}
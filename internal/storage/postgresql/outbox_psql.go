@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"ozinshe/internal/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type OutboxStorage struct {
+	storage *Postgres
+}
+
+func NewOutboxStorage(db *Postgres) *OutboxStorage {
+	return &OutboxStorage{storage: db}
+}
+
+// insertEvent records a domain event in the outbox as part of tx, so that the
+// event exists if and only if the change it describes is committed.
+func insertEvent(ctx context.Context, tx *sql.Tx, eventType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal %s event: %w", eventType, err)
+	}
+
+	// Passed as a string since lib/pq sends []byte as bytea.
+	_, err = tx.ExecContext(ctx, `INSERT INTO outbox (event_type, payload) VALUES ($1, $2)`, eventType, string(data))
+	if err != nil {
+		return fmt.Errorf("insert %s event: %w", eventType, err)
+	}
+
+	return nil
+}
+
+// projectOf returns the project of a movie or series, or 0 while it has none.
+func projectOf(ctx context.Context, tx *sql.Tx, projectType string, contentID int) (int, error) {
+	var projectID int
+	err := tx.QueryRowContext(ctx, `SELECT id FROM projects WHERE project_type = $1 AND project_id = $2`, projectType, contentID).Scan(&projectID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("get project of %s %d: %w", projectType, contentID, err)
+	}
+
+	return projectID, nil
+}
+
+// insertProjectEvent records a project event for a movie or series. Nothing
+// is recorded while the movie or series has no project yet.
+func insertProjectEvent(ctx context.Context, tx *sql.Tx, eventType, projectType string, contentID int) error {
+	projectID, err := projectOf(ctx, tx, projectType, contentID)
+	if err != nil || projectID == 0 {
+		return err
+	}
+
+	return insertEvent(ctx, tx, eventType, models.ProjectEvent{
+		ProjectID:   projectID,
+		ProjectType: projectType,
+		ContentID:   contentID,
+	})
+}
+
+// Relay hands at most limit pending events to dispatch in the order they
+// were recorded, along with the subscribers that already handled them.
+// The subscribers dispatch reports are recorded in outbox_dispatches.
+// Dispatched events are marked as such; events dispatch fails for are
+// retried after retryAfter(attempts). The events stay locked until Relay
+// returns, so concurrent relays never dispatch the same event at once.
+func (o *OutboxStorage) Relay(ctx context.Context, limit int, dispatch func(ctx context.Context, event models.Event, handled []string) ([]string, error), retryAfter func(attempts int) time.Duration) (int, error) {
+	const op = "storage.outbox.Relay"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	tx, err := o.storage.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, event_type, payload, occurred_at, attempts FROM outbox
+		WHERE dispatched_at IS NULL AND next_attempt_at <= NOW()
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`, limit)
+	if err != nil {
+		return 0, fmt.Errorf("%s: query: %w", op, err)
+	}
+
+	var events []models.Event
+	var attempts []int
+	for rows.Next() {
+		var event models.Event
+		var n int
+		if err := rows.Scan(&event.ID, &event.Type, &event.Payload, &event.OccurredAt, &n); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("%s: scan event: %w", op, err)
+		}
+		events = append(events, event)
+		attempts = append(attempts, n)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	handled, err := handledBy(ctx, tx, events)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i, event := range events {
+		succeeded, dispatchErr := dispatch(ctx, event, handled[event.ID])
+
+		if len(succeeded) > 0 {
+			_, err = tx.ExecContext(ctx, `INSERT INTO outbox_dispatches (event_id, subscriber)
+				SELECT $1, unnest($2::text[]) ON CONFLICT DO NOTHING`, event.ID, pq.Array(succeeded))
+			if err != nil {
+				return 0, fmt.Errorf("%s: record dispatches: %w", op, err)
+			}
+		}
+
+		if dispatchErr == nil {
+			_, err = tx.ExecContext(ctx, `UPDATE outbox SET dispatched_at = NOW(), attempts = attempts + 1, last_error = '' WHERE id = $1`, event.ID)
+		} else {
+			_, err = tx.ExecContext(ctx, `UPDATE outbox SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3 WHERE id = $1`,
+				event.ID, dispatchErr.Error(), time.Now().Add(retryAfter(attempts[i]+1)))
+		}
+		if err != nil {
+			return 0, fmt.Errorf("%s: update event: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: commit transaction: %w", op, err)
+	}
+
+	return len(events), nil
+}
+
+// handledBy returns the subscribers that handled each of the events.
+func handledBy(ctx context.Context, tx *sql.Tx, events []models.Event) (map[int64][]string, error) {
+	ids := make([]int64, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT event_id, subscriber FROM outbox_dispatches WHERE event_id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("query dispatches: %w", err)
+	}
+	defer rows.Close()
+
+	handled := make(map[int64][]string)
+	for rows.Next() {
+		var id int64
+		var subscriber string
+		if err := rows.Scan(&id, &subscriber); err != nil {
+			return nil, fmt.Errorf("scan dispatch: %w", err)
+		}
+		handled[id] = append(handled[id], subscriber)
+	}
+
+	return handled, rows.Err()
+}
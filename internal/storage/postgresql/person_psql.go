@@ -0,0 +1,273 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"ozinshe/internal/models"
+	"ozinshe/internal/storage"
+)
+
+type PersonStorage struct {
+	storage *Postgres
+}
+
+func NewPersonStorage(db *Postgres) *PersonStorage {
+	return &PersonStorage{storage: db}
+}
+
+func (p *PersonStorage) Insert(ctx context.Context, person models.Person) (int, error) {
+	const op = "storage.person.Insert"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	tx, err := p.storage.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRowContext(ctx, `INSERT INTO people (name, bio, photo) VALUES ($1, $2, $3) RETURNING id`,
+		person.Name, person.Bio, person.Photo).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: insert person: %w", op, err)
+	}
+
+	if err := auditCreated(ctx, tx, "people", "person", id); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: commit transaction: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (p *PersonStorage) Delete(ctx context.Context, id int) error {
+	const op = "storage.person.Delete"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	if err := deleteAudited(ctx, p.storage.db, "people", "person", id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (p *PersonStorage) GetById(ctx context.Context, id int) (models.Person, error) {
+	const op = "storage.person.GetById"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	stmt, err := p.storage.db.PrepareContext(ctx, `SELECT id, name, bio, photo FROM people WHERE id = $1`)
+	if err != nil {
+		return models.Person{}, fmt.Errorf("%s: prepare statement: %w", op, err)
+	}
+	defer stmt.Close()
+
+	var person models.Person
+	err = stmt.QueryRowContext(ctx, id).Scan(&person.ID, &person.Name, &person.Bio, &person.Photo)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Person{}, fmt.Errorf("%s: %w", op, storage.ErrPersonNotFound)
+		}
+		return models.Person{}, fmt.Errorf("%s: get person: %w", op, err)
+	}
+
+	if err = p.FetchFilmography(ctx, &person); err != nil {
+		return models.Person{}, fmt.Errorf("%s: Fetch filmography: %w", op, err)
+	}
+
+	return person, nil
+}
+
+func (p *PersonStorage) GetAll(ctx context.Context, name string) ([]models.Person, error) {
+	const op = "storage.person.GetAll"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	stmt, err := p.storage.db.PrepareContext(ctx, `SELECT id, name, bio, photo FROM people WHERE name ILIKE $1 ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: prepare statement: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, "%"+name+"%")
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	people := make([]models.Person, 0)
+	for rows.Next() {
+		var person models.Person
+		if err := rows.Scan(&person.ID, &person.Name, &person.Bio, &person.Photo); err != nil {
+			return nil, fmt.Errorf("%s: scan person: %w", op, err)
+		}
+		people = append(people, person)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return people, nil
+}
+
+func (p *PersonStorage) FetchFilmography(ctx context.Context, person *models.Person) error {
+	const op = "storage.person.FetchFilmography"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	query := `SELECT p.id, p.project_type, COALESCE(m.title, s.title), COALESCE(m.release_year, s.release_year),
+				c.role, c.character_name
+				FROM credits c
+				JOIN published_projects p ON p.id = c.project_id
+				LEFT JOIN movies m ON p.project_type = 'movie' AND m.id = p.project_id
+				LEFT JOIN series s ON p.project_type = 'series' AND s.id = p.project_id
+				WHERE c.person_id = $1
+				ORDER BY 4 DESC, c.position`
+
+	rows, err := p.storage.db.QueryContext(ctx, query, person.ID)
+	if err != nil {
+		return fmt.Errorf("%s: query filmography: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry models.Filmography
+		err := rows.Scan(&entry.ProjectID, &entry.ProjectType, &entry.Title, &entry.ReleaseYear, &entry.Role, &entry.Character)
+		if err != nil {
+			return fmt.Errorf("%s: scan filmography row: %w", op, err)
+		}
+		person.Filmography = append(person.Filmography, entry)
+	}
+
+	return rows.Err()
+}
+
+func (p *PersonStorage) GetCredits(ctx context.Context, projectID int) ([]models.Credit, error) {
+	const op = "storage.person.GetCredits"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	query := `SELECT c.id, c.person_id, pe.name, pe.photo, c.project_id, c.role, c.character_name, c.position
+				FROM credits c
+				JOIN people pe ON pe.id = c.person_id
+				WHERE c.project_id = $1
+				ORDER BY c.position`
+
+	rows, err := p.storage.db.QueryContext(ctx, query, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query credits: %w", op, err)
+	}
+	defer rows.Close()
+
+	credits := make([]models.Credit, 0)
+	for rows.Next() {
+		var credit models.Credit
+		err := rows.Scan(&credit.ID, &credit.PersonID, &credit.PersonName, &credit.PersonPhoto, &credit.ProjectID, &credit.Role, &credit.Character, &credit.Position)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan credit row: %w", op, err)
+		}
+		credits = append(credits, credit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return credits, nil
+}
+
+// crewColumns selects the director and producer of the movie or series with
+// the ID in column id: the names of the people credited as such, comma
+// separated in billing order, where free-text columns used to be.
+func crewColumns(projectType, id string) string {
+	return crewNames(projectType, id, models.RoleDirector) + ", " + crewNames(projectType, id, models.RoleProducer)
+}
+
+func crewNames(projectType, id, role string) string {
+	return `COALESCE((SELECT string_agg(pe.name, ', ' ORDER BY c.position)
+		FROM credits c
+		JOIN people pe ON pe.id = c.person_id
+		JOIN projects p ON p.id = c.project_id
+		WHERE p.project_type = '` + projectType + `' AND p.project_id = ` + id + ` AND c.role = '` + role + `'), '')`
+}
+
+// creditsSnapshot selects the credits of a project in order for the audit
+// log.
+const creditsSnapshot = `SELECT COALESCE(jsonb_agg(jsonb_build_object('person_id', person_id, 'role', role,
+	'character_name', character_name) ORDER BY position), '[]') FROM credits WHERE project_id = $1`
+
+func (p *PersonStorage) ReplaceCredits(ctx context.Context, projectID int, credits []models.Credit) error {
+	const op = "storage.person.ReplaceCredits"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	tx, err := p.storage.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err := writeCredits(ctx, tx, projectID, credits); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit transaction: %w", op, err)
+	}
+
+	return nil
+}
+
+// writeCredits replaces the credits of a project within tx, keeping their
+// order as the billing position, and records the change in the audit log.
+// It lets credits be written in the same transaction as the project itself.
+func writeCredits(ctx context.Context, tx *sql.Tx, projectID int, credits []models.Credit) error {
+	before, err := snapshotQuery(ctx, tx, creditsSnapshot, projectID)
+	if err != nil {
+		return err
+	}
+
+	// 1. Delete existing credits
+	_, err = tx.ExecContext(ctx, `DELETE FROM credits WHERE project_id = $1`, projectID)
+	if err != nil {
+		return fmt.Errorf("delete existing credits: %w", err)
+	}
+
+	// 2. Insert new credits in the given order
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO credits (person_id, project_id, role, character_name, position) VALUES ($1, $2, $3, $4, $5)`)
+	if err != nil {
+		return fmt.Errorf("prepare error: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, credit := range credits {
+		_, err = stmt.ExecContext(ctx, credit.PersonID, projectID, credit.Role, credit.Character, i)
+		if err != nil {
+			return fmt.Errorf("insert credit: %w", err)
+		}
+	}
+
+	after, err := snapshotQuery(ctx, tx, creditsSnapshot, projectID)
+	if err != nil {
+		return err
+	}
+
+	return insertAudit(ctx, tx, "project.set_credits", "project", projectID, before, after)
+}
+
+// writeContentCredits replaces the credits of the project of a movie or
+// series within tx. Nothing is written while it has no project yet.
+func writeContentCredits(ctx context.Context, tx *sql.Tx, projectType string, contentID int, credits []models.Credit) error {
+	projectID, err := projectOf(ctx, tx, projectType, contentID)
+	if err != nil || projectID == 0 {
+		return err
+	}
+
+	return writeCredits(ctx, tx, projectID, credits)
+}
@@ -2,8 +2,13 @@ package storage
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"ozinshe/internal/models"
+	"ozinshe/internal/storage"
+
+	"github.com/lib/pq"
 )
 
 type ProjectStorage struct {
@@ -16,87 +21,551 @@ func NewProjectStorage(db *Postgres) *ProjectStorage {
 
 func (p *ProjectStorage) Insert(ctx context.Context, project models.Project) (int, error) {
 	const op = "storage.project.Insert"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := p.storage.db.Prepare(`INSERT INTO projects (project_type, project_id) VALUES ($1, $2) RETURNING id`)
+	tx, err := p.storage.db.BeginTx(ctx, nil)
 	if err != nil {
-		return 0, fmt.Errorf("%s: prepare statement: %w", op, err)
+		return 0, fmt.Errorf("%s: begin transaction: %w", op, err)
 	}
-	defer stmt.Close()
+	defer tx.Rollback()
 
 	var id int
-	err = stmt.QueryRowContext(ctx, project.Project_type, project.Project_id).Scan(&id)
+	err = tx.QueryRowContext(ctx, `INSERT INTO projects (project_type, project_id) VALUES ($1, $2) RETURNING id`,
+		project.Project_type, project.Project_id).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("%s: insert project: %w", op, err)
 	}
 
+	if len(project.Credits) > 0 {
+		if err := writeCredits(ctx, tx, id, project.Credits); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	err = insertEvent(ctx, tx, models.EventProjectCreated, models.ProjectEvent{
+		ProjectID:   id,
+		ProjectType: project.Project_type,
+		ContentID:   project.Project_id,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := auditCreated(ctx, tx, "projects", "project", id); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: commit transaction: %w", op, err)
+	}
+
 	return id, nil
 }
 
 func (p *ProjectStorage) Delete(ctx context.Context, id int) error {
 	const op = "storage.project.Delete"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := p.storage.db.Prepare(`DELETE FROM projects WHERE id = $1`)
+	tx, err := p.storage.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("%s: prepare statement: %w", op, err)
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	before, err := snapshot(ctx, tx, "projects", id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
-	defer stmt.Close()
 
-	_, err = stmt.ExecContext(ctx, id)
+	event := models.ProjectEvent{ProjectID: id}
+	err = tx.QueryRowContext(ctx, `DELETE FROM projects WHERE id = $1 RETURNING project_type, project_id`, id).
+		Scan(&event.ProjectType, &event.ContentID)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
 		return fmt.Errorf("%s: delete project: %w", op, err)
 	}
 
+	if err := insertEvent(ctx, tx, models.EventProjectDeleted, event); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := insertAudit(ctx, tx, "project.delete", "project", id, before, nil); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit transaction: %w", op, err)
+	}
+
 	return nil
 }
 
 func (p *ProjectStorage) GetById(ctx context.Context, id int) (models.Project, error) {
 	const op = "storage.project.GetById"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := p.storage.db.Prepare(`SELECT project_type, project_id FROM projects WHERE id = $1`)
+	stmt, err := p.storage.db.PrepareContext(ctx, `SELECT project_type, project_id, status, publish_at, unpublish_at FROM projects WHERE id = $1`)
 	if err != nil {
 		return models.Project{}, fmt.Errorf("%s: prepare statement: %w", op, err)
 	}
 	defer stmt.Close()
 
-	var project models.Project
-	err = stmt.QueryRowContext(ctx, id).Scan(&project.Project_type, &project.Project_id)
+	project := models.Project{Id: id}
+	err = stmt.QueryRowContext(ctx, id).Scan(&project.Project_type, &project.Project_id,
+		&project.Status, &project.PublishAt, &project.UnpublishAt)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Project{}, fmt.Errorf("%s: %w", op, storage.ErrProjectNotFound)
+		}
 		return models.Project{}, fmt.Errorf("%s: get project: %w", op, err)
 	}
 
 	return project, nil
 }
 
-func (p *ProjectStorage) InsertToFavorites(ctx context.Context, movieID, userID int) error {
-	const op = "storage.project.InsertToFavorites"
+// IsPublished reports whether the project of a movie or series is visible
+// to the public.
+func (p *ProjectStorage) IsPublished(ctx context.Context, projectType string, contentID int) (bool, error) {
+	const op = "storage.project.IsPublished"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := p.storage.db.Prepare(`INSERT INTO favorite_projects (user_id, project_id) VALUES ($1, $2)	ON CONFLICT DO NOTHING`)
+	var published bool
+	err := p.storage.db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM published_projects WHERE project_type = $1 AND project_id = $2)`,
+		projectType, contentID).Scan(&published)
 	if err != nil {
-		return fmt.Errorf("%s: prepare statement: %w", op, err)
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return published, nil
+}
+
+// SetPublication moves a project through the publishing workflow. Moving it
+// to published records a project.published event.
+func (p *ProjectStorage) SetPublication(ctx context.Context, id int, publication models.Publication) error {
+	const op = "storage.project.SetPublication"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	tx, err := p.storage.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var previous string
+	err = tx.QueryRowContext(ctx, `SELECT status FROM projects WHERE id = $1 FOR UPDATE`, id).Scan(&previous)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, storage.ErrProjectNotFound)
+		}
+		return fmt.Errorf("%s: lock project: %w", op, err)
+	}
+
+	before, err := snapshot(ctx, tx, "projects", id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
-	defer stmt.Close()
 
-	_, err = stmt.ExecContext(ctx, userID, movieID)
+	event := models.ProjectEvent{ProjectID: id}
+	err = tx.QueryRowContext(ctx, `
+		UPDATE projects SET status = $2, publish_at = $3, unpublish_at = $4
+		WHERE id = $1
+		RETURNING project_type, project_id`,
+		id, publication.Status, publication.PublishAt, publication.UnpublishAt).
+		Scan(&event.ProjectType, &event.ContentID)
+	if err != nil {
+		return fmt.Errorf("%s: update project: %w", op, err)
+	}
+
+	if publication.Status == models.ProjectPublished && previous != models.ProjectPublished {
+		if err := insertEvent(ctx, tx, models.EventProjectPublished, event); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	after, err := snapshot(ctx, tx, "projects", id)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	if err := insertAudit(ctx, tx, "project.set_status", "project", id, before, after); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit transaction: %w", op, err)
+	}
+
 	return nil
 }
 
-func (p *ProjectStorage) DeleteFromFavorites(ctx context.Context, movieID, userID int) error {
-	const op = "storage.project.DeleteFromFavorites"
+// PublishDue publishes the scheduled projects whose publish time has passed
+// and reports how many were published. Projects locked by a concurrent call
+// are left to it.
+func (p *ProjectStorage) PublishDue(ctx context.Context) (int, error) {
+	const op = "storage.project.PublishDue"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := p.storage.db.Prepare(`DELETE FROM favorite_projects WHERE user_id = $1 AND project_id = $2`)
+	tx, err := p.storage.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return 0, fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id FROM projects WHERE status = 'scheduled' AND publish_at <= NOW()
+		ORDER BY publish_at
+		FOR UPDATE SKIP LOCKED`)
+	if err != nil {
+		return 0, fmt.Errorf("%s: query: %w", op, err)
+	}
+
+	var due []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("%s: scan project: %w", op, err)
+		}
+		due = append(due, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, id := range due {
+		before, err := snapshot(ctx, tx, "projects", id)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		event := models.ProjectEvent{ProjectID: id}
+		err = tx.QueryRowContext(ctx, `UPDATE projects SET status = 'published' WHERE id = $1 RETURNING project_type, project_id`, id).
+			Scan(&event.ProjectType, &event.ContentID)
+		if err != nil {
+			return 0, fmt.Errorf("%s: publish project %d: %w", op, id, err)
+		}
+
+		if err := insertEvent(ctx, tx, models.EventProjectPublished, event); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		after, err := snapshot(ctx, tx, "projects", id)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := insertAudit(ctx, tx, "project.publish", "project", id, before, after); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: commit transaction: %w", op, err)
+	}
+
+	return len(due), nil
+}
+
+// GetPublications lists the cards of all projects, published or not, with
+// their publication, newest first. An empty status lists every status.
+func (p *ProjectStorage) GetPublications(ctx context.Context, status string, offset, limit int) ([]models.PublicationCard, error) {
+	const op = "storage.project.GetPublications"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	rows, err := p.storage.db.QueryContext(ctx, `SELECT `+projectCardColumns+`, p.status, p.publish_at, p.unpublish_at
+		FROM projects p`+projectCardJoins+`
+		WHERE $1 = '' OR p.status = $1
+		ORDER BY p.id DESC OFFSET $2 LIMIT $3`, status, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	cards := make([]models.PublicationCard, 0)
+	for rows.Next() {
+		var card models.PublicationCard
+		err := rows.Scan(&card.ProjectID, &card.ProjectType, &card.Title, &card.ReleaseYear, &card.Popularity, &card.Cover,
+			&card.Status, &card.PublishAt, &card.UnpublishAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan card: %w", op, err)
+		}
+		cards = append(cards, card)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return cards, nil
+}
+
+// projectCardColumns and projectCardFrom select the compact card of every
+// published project. Callers needing extra columns join them on their own.
+const projectCardColumns = `p.id, p.project_type, COALESCE(m.title, s.title, ''), COALESCE(m.release_year, s.release_year, 0),
+		COALESCE(m.popularity, s.popularity, 0), COALESCE(mc.filename, sc.filename, '')`
+
+// projectCardQuery is meant to be extended with WHERE/ORDER BY clauses by the
+// callers.
+const projectCardQuery = `SELECT ` + projectCardColumns + projectCardFrom
+
+const projectCardFrom = `
+		FROM published_projects p` + projectCardJoins
+
+const projectCardJoins = `
+		LEFT JOIN movies m ON p.project_type = 'movie' AND m.id = p.project_id
+		LEFT JOIN series s ON p.project_type = 'series' AND s.id = p.project_id
+		LEFT JOIN movie_covers mc ON mc.movie_id = m.id
+		LEFT JOIN series_covers sc ON sc.series_id = s.id`
+
+func scanProjectCards(rows *sql.Rows) ([]models.ProjectCard, error) {
+	cards := make([]models.ProjectCard, 0)
+	for rows.Next() {
+		var card models.ProjectCard
+		err := rows.Scan(&card.ProjectID, &card.ProjectType, &card.Title, &card.ReleaseYear, &card.Popularity, &card.Cover)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return cards, nil
+}
+
+func (p *ProjectStorage) queryCards(ctx context.Context, op, query string, args ...any) ([]models.ProjectCard, error) {
+	rows, err := p.storage.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	cards, err := scanProjectCards(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: scan card: %w", op, err)
+	}
+
+	return cards, nil
+}
+
+func (p *ProjectStorage) GetTrending(ctx context.Context, offset, limit int) ([]models.ProjectCard, error) {
+	const op = "storage.project.GetTrending"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	query := projectCardQuery + ` ORDER BY 5 DESC, p.id DESC OFFSET $1 LIMIT $2`
+
+	return p.queryCards(ctx, op, query, offset, limit)
+}
+
+func (p *ProjectStorage) GetNewReleases(ctx context.Context, offset, limit int) ([]models.ProjectCard, error) {
+	const op = "storage.project.GetNewReleases"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	query := projectCardQuery + ` ORDER BY 4 DESC, p.id DESC OFFSET $1 LIMIT $2`
+
+	return p.queryCards(ctx, op, query, offset, limit)
+}
+
+func (p *ProjectStorage) GetByGenre(ctx context.Context, genreID, offset, limit int) ([]models.ProjectCard, error) {
+	const op = "storage.project.GetByGenre"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	query := projectCardQuery + `
+		WHERE EXISTS (SELECT 1 FROM movie_genres mg WHERE mg.movie_id = m.id AND mg.genre_id = $1)
+		OR EXISTS (SELECT 1 FROM series_genres sg WHERE sg.series_id = s.id AND sg.genre_id = $1)
+		ORDER BY 5 DESC, p.id DESC OFFSET $2 LIMIT $3`
+
+	return p.queryCards(ctx, op, query, genreID, offset, limit)
+}
+
+func (p *ProjectStorage) GetContinueWatching(ctx context.Context, userID, offset, limit int) ([]models.ProjectCard, error) {
+	const op = "storage.project.GetContinueWatching"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	query := projectCardQuery + `
+		JOIN watch_progress wp ON wp.project_id = p.id
+		WHERE wp.user_id = $1 AND NOT wp.finished
+		ORDER BY wp.updated_at DESC OFFSET $2 LIMIT $3`
+
+	return p.queryCards(ctx, op, query, userID, offset, limit)
+}
+
+// GetCards returns the cards of all published projects of projectType, for
+// list views that need no more than a card, with one query.
+func (p *ProjectStorage) GetCards(ctx context.Context, projectType string) ([]models.ProjectCard, error) {
+	const op = "storage.project.GetCards"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	query := projectCardQuery + ` WHERE p.project_type = $1 ORDER BY p.id`
+
+	return p.queryCards(ctx, op, query, projectType)
+}
+
+func (p *ProjectStorage) GetFavoriteGenre(ctx context.Context, userID int) (models.Genre, error) {
+	const op = "storage.project.GetFavoriteGenre"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	query := `SELECT g.id, g.name
+				FROM user_favorites f
+				JOIN projects p ON p.id = f.project_id
+				LEFT JOIN movie_genres mg ON p.project_type = 'movie' AND mg.movie_id = p.project_id
+				LEFT JOIN series_genres sg ON p.project_type = 'series' AND sg.series_id = p.project_id
+				JOIN genres g ON g.id = COALESCE(mg.genre_id, sg.genre_id)
+				WHERE f.user_id = $1
+				GROUP BY g.id, g.name
+				ORDER BY COUNT(*) DESC, g.id
+				LIMIT 1`
+
+	var genre models.Genre
+	err := p.storage.db.QueryRowContext(ctx, query, userID).Scan(&genre.ID, &genre.Name)
+	if err != nil {
+		return models.Genre{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return genre, nil
+}
+
+func (p *ProjectStorage) SaveProgress(ctx context.Context, progress models.WatchProgress) error {
+	const op = "storage.project.SaveProgress"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	stmt, err := p.storage.db.PrepareContext(ctx, `
+		INSERT INTO watch_progress (user_id, project_id, position_seconds, finished, updated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (user_id, project_id) DO UPDATE
+		SET position_seconds = EXCLUDED.position_seconds, finished = EXCLUDED.finished, updated_at = NOW()`)
+	if err != nil {
+		return fmt.Errorf("%s: prepare statement: %w", op, err)
 	}
 	defer stmt.Close()
 
-	_, err = stmt.ExecContext(ctx, userID, movieID)
+	_, err = stmt.ExecContext(ctx, progress.UserID, progress.ProjectID, progress.PositionSeconds, progress.Finished)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
 	return nil
 }
+
+// GetByIds returns the projects with the given IDs, published or not.
+// Missing projects are left out.
+func (p *ProjectStorage) GetByIds(ctx context.Context, ids []int) ([]models.Project, error) {
+	const op = "storage.project.GetByIds"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	rows, err := p.storage.db.QueryContext(ctx, `
+		SELECT id, project_type, project_id, status, publish_at, unpublish_at
+		FROM projects WHERE id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	projects, err := scanProjects(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return projects, nil
+}
+
+// GetPublished returns a page of the published projects of projectType, or
+// of either type when it is empty, newest first.
+func (p *ProjectStorage) GetPublished(ctx context.Context, projectType string, offset, limit int) ([]models.Project, error) {
+	const op = "storage.project.GetPublished"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	rows, err := p.storage.db.QueryContext(ctx, `
+		SELECT id, project_type, project_id, status, publish_at, unpublish_at
+		FROM published_projects
+		WHERE $1 = '' OR project_type = $1
+		ORDER BY id DESC OFFSET $2 LIMIT $3`, projectType, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	projects, err := scanProjects(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return projects, nil
+}
+
+// GetFavoriteIds returns the IDs of the published projects the user
+// favorited, the latest first.
+func (p *ProjectStorage) GetFavoriteIds(ctx context.Context, userID int) ([]int, error) {
+	const op = "storage.project.GetFavoriteIds"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	rows, err := p.storage.db.QueryContext(ctx, `
+		SELECT f.project_id FROM user_favorites f
+		JOIN published_projects p ON p.id = f.project_id
+		WHERE f.user_id = $1
+		ORDER BY f.added_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	ids := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("%s: scan: %w", op, err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return ids, nil
+}
+
+func scanProjects(rows *sql.Rows) ([]models.Project, error) {
+	projects := make([]models.Project, 0)
+	for rows.Next() {
+		var project models.Project
+		err := rows.Scan(&project.Id, &project.Project_type, &project.Project_id,
+			&project.Status, &project.PublishAt, &project.UnpublishAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan project: %w", err)
+		}
+		projects = append(projects, project)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+// publishedMovies and publishedSeries select the ids of the movies and
+// series the public can see.
+const (
+	publishedMovies = `(SELECT project_id FROM published_projects WHERE project_type = 'movie')`
+	publishedSeries = `(SELECT project_id FROM published_projects WHERE project_type = 'series')`
+)
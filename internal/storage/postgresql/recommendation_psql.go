@@ -0,0 +1,196 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"ozinshe/internal/models"
+	"ozinshe/internal/recommend"
+)
+
+type RecommendationStorage struct {
+	storage *Postgres
+}
+
+func NewRecommendationStorage(db *Postgres) *RecommendationStorage {
+	return &RecommendationStorage{storage: db}
+}
+
+// LoadItems returns the genres, keywords and people of every project.
+func (r *RecommendationStorage) LoadItems(ctx context.Context) ([]recommend.Item, error) {
+	const op = "storage.recommendation.LoadItems"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	query := `SELECT p.id, 'genre', mg.genre_id FROM projects p
+				JOIN movie_genres mg ON p.project_type = 'movie' AND mg.movie_id = p.project_id
+			UNION ALL
+			SELECT p.id, 'genre', sg.genre_id FROM projects p
+				JOIN series_genres sg ON p.project_type = 'series' AND sg.series_id = p.project_id
+			UNION ALL
+			SELECT p.id, 'keyword', mk.key_word_id FROM projects p
+				JOIN movie_key_words mk ON p.project_type = 'movie' AND mk.movie_id = p.project_id
+			UNION ALL
+			SELECT p.id, 'keyword', sk.key_word_id FROM projects p
+				JOIN series_key_words sk ON p.project_type = 'series' AND sk.series_id = p.project_id
+			UNION ALL
+			SELECT c.project_id, 'person', c.person_id FROM credits c`
+
+	rows, err := r.storage.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query features: %w", op, err)
+	}
+	defer rows.Close()
+
+	items := make(map[int]*recommend.Item)
+	for rows.Next() {
+		var projectID, value int
+		var kind string
+		if err := rows.Scan(&projectID, &kind, &value); err != nil {
+			return nil, fmt.Errorf("%s: scan feature row: %w", op, err)
+		}
+
+		item, ok := items[projectID]
+		if !ok {
+			item = &recommend.Item{ProjectID: projectID}
+			items[projectID] = item
+		}
+
+		switch kind {
+		case "genre":
+			item.Genres = append(item.Genres, value)
+		case "keyword":
+			item.Keywords = append(item.Keywords, value)
+		case "person":
+			item.People = append(item.People, value)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	result := make([]recommend.Item, 0, len(items))
+	for _, item := range items {
+		result = append(result, *item)
+	}
+
+	return result, nil
+}
+
+// LoadFavorites returns the favorited projects of every user.
+func (r *RecommendationStorage) LoadFavorites(ctx context.Context) (map[int][]int, error) {
+	const op = "storage.recommendation.LoadFavorites"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	rows, err := r.storage.db.QueryContext(ctx, `SELECT user_id, project_id FROM user_favorites`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	favorites := make(map[int][]int)
+	for rows.Next() {
+		var userID, projectID int
+		if err := rows.Scan(&userID, &projectID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		favorites[userID] = append(favorites[userID], projectID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return favorites, nil
+}
+
+// ReplaceSimilarities swaps the cached scores for a freshly computed set.
+func (r *RecommendationStorage) ReplaceSimilarities(ctx context.Context, similarities []models.Similarity) error {
+	const op = "storage.recommendation.ReplaceSimilarities"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	tx, err := r.storage.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM project_similarities`)
+	if err != nil {
+		return fmt.Errorf("%s: delete existing scores: %w", op, err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO project_similarities (project_id, similar_project_id, score) VALUES ($1, $2, $3)`)
+	if err != nil {
+		return fmt.Errorf("%s: prepare error: %w", op, err)
+	}
+	defer stmt.Close()
+
+	for _, s := range similarities {
+		_, err = stmt.ExecContext(ctx, s.ProjectID, s.SimilarProjectID, s.Score)
+		if err != nil {
+			return fmt.Errorf("%s: insert score: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit transaction: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *RecommendationStorage) GetSimilar(ctx context.Context, projectID, offset, limit int) ([]models.ProjectCard, error) {
+	const op = "storage.recommendation.GetSimilar"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	query := projectCardQuery + `
+		JOIN project_similarities ps ON ps.similar_project_id = p.id
+		WHERE ps.project_id = $1
+		ORDER BY ps.score DESC, p.id OFFSET $2 LIMIT $3`
+
+	rows, err := r.storage.db.QueryContext(ctx, query, projectID, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	cards, err := scanProjectCards(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: scan card: %w", op, err)
+	}
+
+	return cards, nil
+}
+
+// GetForUser sums the cached scores of projects similar to the user's
+// favorites, leaving out the favorites themselves.
+func (r *RecommendationStorage) GetForUser(ctx context.Context, userID, offset, limit int) ([]models.ProjectCard, error) {
+	const op = "storage.recommendation.GetForUser"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	query := projectCardQuery + `
+		JOIN (
+			SELECT ps.similar_project_id, SUM(ps.score) AS score
+			FROM project_similarities ps
+			JOIN user_favorites f ON f.project_id = ps.project_id AND f.user_id = $1
+			WHERE ps.similar_project_id NOT IN (SELECT project_id FROM user_favorites WHERE user_id = $1)
+			GROUP BY ps.similar_project_id
+		) r ON r.similar_project_id = p.id
+		ORDER BY r.score DESC, p.id OFFSET $2 LIMIT $3`
+
+	rows, err := r.storage.db.QueryContext(ctx, query, userID, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	cards, err := scanProjectCards(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: scan card: %w", op, err)
+	}
+
+	return cards, nil
+}
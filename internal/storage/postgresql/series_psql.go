@@ -13,6 +13,11 @@ import (
 	"github.com/lib/pq"
 )
 
+// seriesColumns selects a series of series s in the order of the fields of
+// models.Series.
+var seriesColumns = `s.id, s.title, s.release_year, s.description, s.popularity, s.duration, ` +
+	crewColumns("series", "s.id")
+
 type SeriesStorage struct {
 	storage *Postgres
 }
@@ -23,6 +28,8 @@ func NewSeriesStorage(db *Postgres) *SeriesStorage {
 
 func (s *SeriesStorage) Insert(ctx context.Context, series models.Series) (int, error) {
 	const op = "storage.series.Insert"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	tx, err := s.storage.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -31,11 +38,11 @@ func (s *SeriesStorage) Insert(ctx context.Context, series models.Series) (int,
 
 	var seriesID int
 	err = tx.QueryRowContext(ctx,
-		`INSERT INTO series (title, release_year, description, popularity, duration, director, producer) 
-         VALUES ($1, $2, $3, $4, $5, $6, $7) 
+		`INSERT INTO series (title, release_year, description, popularity, duration) 
+         VALUES ($1, $2, $3, $4, $5) 
          RETURNING id`,
 		series.Title, series.ReleaseYear, series.Description, series.Popularity,
-		series.Duration, series.Director, series.Producer,
+		series.Duration,
 	).Scan(&seriesID)
 	if err != nil {
 		tx.Rollback()
@@ -207,6 +214,12 @@ func (s *SeriesStorage) Insert(ctx context.Context, series models.Series) (int,
 		}
 	}
 
+	err = auditContentChange(ctx, tx, "series.create", "series", seriesID, nil)
+	if err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
 	// Commit the transaction
 	err = tx.Commit()
 	if err != nil {
@@ -218,25 +231,31 @@ func (s *SeriesStorage) Insert(ctx context.Context, series models.Series) (int,
 
 func (s *SeriesStorage) Update(ctx context.Context, series models.Series) error {
 	const op = "storage.series.Update"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	tx, err := s.storage.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("%s: begin transaction: %w", op, err)
 	}
 
+	before, err := contentSnapshot(ctx, tx, "series", series.ID)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	// Update Series (Similar to Series Update)
 	if series.Title != "" || series.ReleaseYear != 0 || series.Description != "" ||
-		series.Popularity != 0 || series.Duration != 0 ||
-		series.Director != "" || series.Producer != "" {
+		series.Popularity != 0 || series.Duration != 0 {
 		_, err := tx.ExecContext(ctx,
 			`UPDATE series 
              SET title = COALESCE($1, title), release_year = COALESCE($2, release_year), 
              description = COALESCE($3, description), popularity = COALESCE($4, popularity), 
-             duration = COALESCE($5, duration), director = COALESCE($6, director), 
-             producer = COALESCE($7, producer)
-             WHERE id = $8`,
+             duration = COALESCE($5, duration)
+             WHERE id = $6`,
 			series.Title, series.ReleaseYear, series.Description, series.Popularity,
-			series.Duration, series.Director, series.Producer,
+			series.Duration,
 			series.ID,
 		)
 		if err != nil {
@@ -377,7 +396,8 @@ func (s *SeriesStorage) Update(ctx context.Context, series models.Series) error
 		}
 	}
 
-	// Update Seasons and Episodes
+	// Update Seasons and Episodes, counting the new episodes
+	added := 0
 	for _, season := range series.Seasons {
 		row := tx.QueryRowContext(ctx,
 			`SELECT id, season_number FROM seasons WHERE series_id = $1 AND season_number = $2`,
@@ -386,38 +406,24 @@ func (s *SeriesStorage) Update(ctx context.Context, series models.Series) error
 
 		err := row.Scan(&season.ID, &season.SeasonNumber)
 		if err != nil {
-			fmt.Println("err: ", err)
-		}
-		// if err != nil {
-		// 	if err == sql.ErrNoRows {
-		// 		// Insert new Season
-		// 		_, err = tx.ExecContext(ctx,
-		// 			`INSERT INTO seasons (series_id, season_number)
-		// 			VALUES ($1, $2)`,
-		// 			series.ID, season.SeasonNumber,
-		// 		)
-		// 		if err != nil {
-		// 			tx.Rollback()
-		// 			return fmt.Errorf("%s: insert season: %w", op, err)
-		// 		}
-		// 	} else {
-		// 		tx.Rollback()
-		// 		return fmt.Errorf("%s: get season id: %w", op, err)
-		// 	}
-		// }
+			if !errors.Is(err, sql.ErrNoRows) {
+				tx.Rollback()
+				return fmt.Errorf("%s: get season id: %w", op, err)
+			}
 
-		_, err = tx.ExecContext(ctx,
-			`UPDATE seasons
-			SET season_number = $1
-			WHERE id = $2`,
-			season.SeasonNumber, season.ID,
-		)
-		if err != nil {
-			tx.Rollback()
-			return fmt.Errorf("%s: update season: %w", op, err)
+			// Insert new Season
+			err = tx.QueryRowContext(ctx,
+				`INSERT INTO seasons (series_id, season_number)
+				VALUES ($1, $2) RETURNING id`,
+				series.ID, season.SeasonNumber,
+			).Scan(&season.ID)
+			if err != nil {
+				tx.Rollback()
+				return fmt.Errorf("%s: insert season: %w", op, err)
+			}
 		}
 
-		// Update Episodes for this Season
+		// Update Episodes for this Season, adding the new ones
 		for _, episode := range season.Episodes {
 
 			row := tx.QueryRowContext(ctx,
@@ -426,6 +432,19 @@ func (s *SeriesStorage) Update(ctx context.Context, series models.Series) error
 			)
 
 			err := row.Scan(&episode.ID, &episode.EpisodeNumber)
+			if errors.Is(err, sql.ErrNoRows) {
+				_, err = tx.ExecContext(ctx,
+					`INSERT INTO episodes (season_id, episode_number, youtube_id)
+					VALUES ($1, $2, $3)`,
+					season.ID, episode.EpisodeNumber, episode.Link,
+				)
+				if err != nil {
+					tx.Rollback()
+					return fmt.Errorf("%s: insert episode: %w", op, err)
+				}
+				added++
+				continue
+			}
 			if err != nil {
 				tx.Rollback()
 				return fmt.Errorf("%s: get episode id: %w", op, err)
@@ -443,6 +462,45 @@ func (s *SeriesStorage) Update(ctx context.Context, series models.Series) error
 		}
 	}
 
+	// Update Credits, leaving them as they are when none are given
+	if series.Credits != nil {
+		err = writeContentCredits(ctx, tx, "series", series.ID, series.Credits)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	err = insertProjectEvent(ctx, tx, models.EventProjectUpdated, "series", series.ID)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if added > 0 {
+		projectID, err := projectOf(ctx, tx, "series", series.ID)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		err = insertEvent(ctx, tx, models.EventEpisodesAdded, models.EpisodesEvent{
+			ProjectID: projectID,
+			SeriesID:  series.ID,
+			Count:     added,
+		})
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	err = auditContentChange(ctx, tx, "series.update", "series", series.ID, before)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	// Commit the transaction
 	err = tx.Commit()
 	if err != nil {
@@ -454,12 +512,20 @@ func (s *SeriesStorage) Update(ctx context.Context, series models.Series) error
 
 func (s *SeriesStorage) UpdateCover(ctx context.Context, seriesID int, cover models.Cover) error {
 	const op = "storage.series.UpdateCover"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	tx, err := s.storage.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	before, err := contentSnapshot(ctx, tx, "series", seriesID)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	// 1. Delete existing cover (if any)
 	_, err = tx.ExecContext(ctx, `DELETE FROM series_covers WHERE series_id = $1`, seriesID)
 	if err != nil {
@@ -476,17 +542,31 @@ func (s *SeriesStorage) UpdateCover(ctx context.Context, seriesID int, cover mod
 		return fmt.Errorf("%s: insert error: %w", op, err)
 	}
 
+	err = auditContentChange(ctx, tx, "series.update_cover", "series", seriesID, before)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	return tx.Commit()
 }
 
 func (s *SeriesStorage) UpdateScreenshots(ctx context.Context, seriesID int, screenshots []models.Screenshot) error {
 	const op = "storage.series.UpdateScreenshots"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	tx, err := s.storage.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	before, err := contentSnapshot(ctx, tx, "series", seriesID)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	// 1. Delete existing screenshots
 	_, err = tx.ExecContext(ctx, `DELETE FROM series_screenshots WHERE series_id = $1`, seriesID)
 	if err != nil {
@@ -510,20 +590,21 @@ func (s *SeriesStorage) UpdateScreenshots(ctx context.Context, seriesID int, scr
 		}
 	}
 
+	err = auditContentChange(ctx, tx, "series.update_screenshots", "series", seriesID, before)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	return tx.Commit()
 }
 
 func (s *SeriesStorage) Delete(ctx context.Context, id int) error {
 	const op = "storage.series.Delete"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := s.storage.db.Prepare(`DELETE FROM series WHERE id = $1`)
-	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
-	}
-	defer stmt.Close()
-
-	_, err = stmt.ExecContext(ctx, id)
-	if err != nil {
+	if err := deleteAudited(ctx, s.storage.db, "series", "series", id); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
@@ -532,8 +613,10 @@ func (s *SeriesStorage) Delete(ctx context.Context, id int) error {
 
 func (s *SeriesStorage) GetById(ctx context.Context, id int) (models.Series, error) {
 	const op = "storage.series.GetById"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := s.storage.db.Prepare(`SELECT * FROM series WHERE id = $1`)
+	stmt, err := s.storage.db.PrepareContext(ctx, `SELECT `+seriesColumns+` FROM series s WHERE id = $1`)
 	if err != nil {
 		return models.Series{}, fmt.Errorf("%s: %w", op, err)
 	}
@@ -543,7 +626,7 @@ func (s *SeriesStorage) GetById(ctx context.Context, id int) (models.Series, err
 
 	err = stmt.QueryRowContext(ctx, id).Scan(&series.ID, &series.Title, &series.ReleaseYear, &series.Description, &series.Popularity, &series.Duration, &series.Director, &series.Producer)
 	if err != nil {
-		return models.Series{}, fmt.Errorf("%s: %w", op, err)
+		return models.Series{}, fmt.Errorf("%s: %w", op, notFound(err, storage.ErrSeriesNotFound))
 	}
 
 	err = s.FetchGenres(ctx, &series)
@@ -576,11 +659,18 @@ func (s *SeriesStorage) GetById(ctx context.Context, id int) (models.Series, err
 		return models.Series{}, fmt.Errorf("%s: Fetch age categories for series %d: %w", op, series.ID, err)
 	}
 
+	err = s.FetchCredits(ctx, &series)
+	if err != nil {
+		return models.Series{}, fmt.Errorf("%s: Fetch credits for series %d: %w", op, series.ID, err)
+	}
+
 	return series, nil
 }
 
 func (s *SeriesStorage) GetAll(ctx context.Context) ([]models.Series, error) {
 	const op = "storage.series.GetAll"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	series, err := s.FetchSeriesData(ctx)
 	if err != nil {
@@ -642,6 +732,8 @@ func (s *SeriesStorage) GetAll(ctx context.Context) ([]models.Series, error) {
 
 func (s *SeriesStorage) FetchSeasons(ctx context.Context, series *models.Series) error {
 	const op = "storage.series.FetchSeasons"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	query := `SELECT s.id, s.season_number
 				FROM seasons s
@@ -679,6 +771,8 @@ func (s *SeriesStorage) FetchSeasons(ctx context.Context, series *models.Series)
 
 func (s *SeriesStorage) FetchEpisodes(ctx context.Context, seriesID, seasonID int) ([]models.Episode, error) {
 	const op = "storage.series.FetchEpisodes"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	query := `SELECT id, episode_number, youtube_id
               FROM episodes 
@@ -706,6 +800,8 @@ func (s *SeriesStorage) FetchEpisodes(ctx context.Context, seriesID, seasonID in
 
 func (s *SeriesStorage) FetchGenres(ctx context.Context, series *models.Series) error {
 	const op = "storage.series.FetchGenres"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	query := `SELECT g.id, g.name 
 				FROM genres g 
@@ -736,6 +832,8 @@ func (s *SeriesStorage) FetchGenres(ctx context.Context, series *models.Series)
 
 func (s *SeriesStorage) FetchKeywords(ctx context.Context, series *models.Series) error {
 	const op = "storage.series.FetchKeywords"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	query := `SELECT k.id, k.name
 				FROM key_words k
@@ -766,10 +864,12 @@ func (s *SeriesStorage) FetchKeywords(ctx context.Context, series *models.Series
 
 func (s *SeriesStorage) FetchSeriesData(ctx context.Context) ([]models.Series, error) {
 	const op = "storage.series.FetchSeriesData"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	series := make([]models.Series, 0)
 
-	rows, err := s.storage.db.QueryContext(ctx, `SELECT * FROM series`)
+	rows, err := s.storage.db.QueryContext(ctx, `SELECT `+seriesColumns+` FROM series s WHERE id IN `+publishedSeries)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -792,6 +892,8 @@ func (s *SeriesStorage) FetchSeriesData(ctx context.Context) ([]models.Series, e
 
 func (s *SeriesStorage) FetchCover(ctx context.Context, series *models.Series) error {
 	const op = "storage.series.FetchCover"
+	ctx, end := startOp(ctx, op)
+	defer end()
 	var filename string
 	err := s.storage.db.QueryRowContext(ctx, `SELECT filename FROM series_covers WHERE series_id = $1`, series.ID).Scan(&filename)
 	if err != nil {
@@ -803,6 +905,8 @@ func (s *SeriesStorage) FetchCover(ctx context.Context, series *models.Series) e
 
 func (s *SeriesStorage) FetchScreenshots(ctx context.Context, series *models.Series) error {
 	const op = "storage.series.FetchScreenshots"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	query := "SELECT filename FROM series_screenshots WHERE series_id = $1"
 	rows, err := s.storage.db.QueryContext(ctx, query, series.ID)
@@ -825,6 +929,8 @@ func (s *SeriesStorage) FetchScreenshots(ctx context.Context, series *models.Ser
 
 func (s *SeriesStorage) FetchAgeCategories(ctx context.Context, series *models.Series) error {
 	const op = "storage.series.FetchAgeCategories"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	query := `SELECT ac.id, ac.min_age, ac.max_age
               FROM age_categories ac 
@@ -849,114 +955,31 @@ func (s *SeriesStorage) FetchAgeCategories(ctx context.Context, series *models.S
 	return nil
 }
 
-func (s *SeriesStorage) InsertToFavorites(ctx context.Context, seriesID, userID int) error {
-	const op = "storage.series.InsertToFavorites"
-
-	tx, err := s.storage.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("%s: begin transaction: %w", op, err)
-	}
-
-	// Insert into favorites (handling potential conflicts)
-	stmt, err := tx.Prepare(`INSERT INTO favorite_series (series_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("%s: prepare insert statement: %w", op, err)
-	}
-	defer stmt.Close()
-
-	result, err := stmt.ExecContext(ctx, seriesID, userID)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("%s: insert into favorites: %w", op, err)
-	}
-
-	// Check if anything was actually inserted
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("%s: check rows affected: %w", op, err)
-	}
-
-	if rowsAffected == 0 {
-		tx.Rollback()
-		return nil
-	}
-
-	// Increment popularity (only if a row was inserted)
-	stmt, err = tx.Prepare(`UPDATE series SET popularity = popularity + 1 WHERE id = $1`)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("%s: prepare update statement: %w", op, err)
-	}
-	defer stmt.Close()
-
-	_, err = stmt.ExecContext(ctx, seriesID)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("%s: increment popularity: %w", op, err)
-	}
-
-	err = tx.Commit()
-	if err != nil {
-		return fmt.Errorf("%s: commit transaction: %w", op, err)
-	}
-
-	return nil
-}
-
-func (s *SeriesStorage) DeleteFromFavorites(ctx context.Context, seriesID, userID int) error {
-	const op = "storage.series.DeleteFromFavorites"
-
-	tx, err := s.storage.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("%s: begin transaction: %w", op, err)
-	}
-
-	// Attempt to delete from favorites
-	stmt, err := tx.Prepare(`DELETE FROM favorite_series WHERE series_id = $1 AND user_id = $2`)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("%s: prepare delete statement: %w", op, err)
-	}
-	defer stmt.Close()
-
-	result, err := stmt.ExecContext(ctx, seriesID, userID)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("%s: delete from favorites: %w", op, err)
-	}
+func (s *SeriesStorage) FetchCredits(ctx context.Context, series *models.Series) error {
+	const op = "storage.series.FetchCredits"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	// Check if anything was actually deleted
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("%s: check rows affected: %w", op, err)
-	}
-
-	if rowsAffected == 0 {
-		tx.Rollback()
-		return nil // Or a specific "not found" error if desired
-	}
-
-	// Decrement popularity (only if a row was deleted)
-	stmt, err = tx.Prepare(`UPDATE series SET popularity = popularity - 1 WHERE id = $1`)
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("%s: prepare update statement: %w", op, err)
-	}
-	defer stmt.Close()
+	query := `SELECT c.id, c.person_id, pe.name, pe.photo, c.project_id, c.role, c.character_name, c.position
+				FROM credits c
+				JOIN people pe ON pe.id = c.person_id
+				JOIN projects p ON p.id = c.project_id
+				WHERE p.project_type = 'series' AND p.project_id = $1
+				ORDER BY c.position`
 
-	_, err = stmt.ExecContext(ctx, seriesID)
+	rows, err := s.storage.db.QueryContext(ctx, query, series.ID)
 	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("%s: decrement popularity: %w", op, err)
+		return fmt.Errorf("%s: %w", op, err)
 	}
+	defer rows.Close()
 
-	// Commit if a favorite was deleted
-	err = tx.Commit()
-	if err != nil {
-		return fmt.Errorf("%s: commit transaction: %w", op, err)
+	for rows.Next() {
+		var credit models.Credit
+		err := rows.Scan(&credit.ID, &credit.PersonID, &credit.PersonName, &credit.PersonPhoto, &credit.ProjectID, &credit.Role, &credit.Character, &credit.Position)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		series.Credits = append(series.Credits, credit)
 	}
 
 	return nil
@@ -964,10 +987,12 @@ func (s *SeriesStorage) DeleteFromFavorites(ctx context.Context, seriesID, userI
 
 func (s *SeriesStorage) GetFavorites(ctx context.Context, userID int) ([]models.Series, error) {
 	const op = "storage.series.GetFavorites"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	// Phase 1: Get favorite series IDs
 	var seriesIDs []int
-	stmt, err := s.storage.db.Prepare(`SELECT series_id FROM favorite_series WHERE user_id = $1`)
+	stmt, err := s.storage.db.PrepareContext(ctx, `SELECT p.project_id FROM user_favorites f JOIN published_projects p ON p.id = f.project_id WHERE f.user_id = $1 AND p.project_type = 'series'`)
 	if err != nil {
 		return nil, fmt.Errorf("%s: prepare statement (phase 1): %w", op, err)
 	}
@@ -1000,7 +1025,7 @@ func (s *SeriesStorage) GetFavorites(ctx context.Context, userID int) ([]models.
 		args[i] = id
 	}
 
-	query := fmt.Sprintf(`SELECT * FROM series WHERE id IN (%s)`, strings.Join(placeholders, ", "))
+	query := fmt.Sprintf(`SELECT %s FROM series s WHERE id IN (%s)`, seriesColumns, strings.Join(placeholders, ", "))
 
 	rows, err = s.storage.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -1033,8 +1058,10 @@ func (s *SeriesStorage) GetFavorites(ctx context.Context, userID int) ([]models.
 
 func (s *SeriesStorage) GetByTitle(ctx context.Context, title string) ([]models.Series, error) {
 	const op = "storage.series.GetByTitle"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := s.storage.db.Prepare(`SELECT * FROM series WHERE title ILIKE $1`)
+	stmt, err := s.storage.db.PrepareContext(ctx, `SELECT `+seriesColumns+` FROM series s WHERE title ILIKE $1 AND id IN `+publishedSeries)
 	if err != nil {
 		return nil, fmt.Errorf("%s: prepare statement: %w", op, err)
 	}
@@ -1061,15 +1088,16 @@ func (s *SeriesStorage) GetByTitle(ctx context.Context, title string) ([]models.
 
 func (s *SeriesStorage) GetByGenres(ctx context.Context, genres []string) ([]models.Series, error) {
 	const op = "storage.series.GetByGenres"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	// Start building the SQL query
 	query := `
-        SELECT s.id, s.title, s.release_year, s.description, s.popularity, 
-               s.duration, s.director, s.producer
+        SELECT ` + seriesColumns + `
         FROM series s
         JOIN series_genres sg ON s.id = sg.series_id
         JOIN genres g ON sg.genre_id = g.id
-        WHERE g.name IN (`
+        WHERE s.id IN ` + publishedSeries + ` AND g.name IN (`
 
 	// Add placeholders for genre names
 	placeholders := make([]string, len(genres))
@@ -1112,8 +1140,10 @@ func (s *SeriesStorage) GetByGenres(ctx context.Context, genres []string) ([]mod
 
 func (s *SeriesStorage) GetByYear(ctx context.Context, yearStart, yearEnd int) ([]models.Series, error) {
 	const op = "storage.series.GetByYear"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := s.storage.db.Prepare(`SELECT * FROM series WHERE release_year BETWEEN $1 AND $2`)
+	stmt, err := s.storage.db.PrepareContext(ctx, `SELECT `+seriesColumns+` FROM series s WHERE release_year BETWEEN $1 AND $2 AND id IN `+publishedSeries)
 	if err != nil {
 		return nil, fmt.Errorf("%s: prepare statement: %w", op, err)
 	}
@@ -1138,10 +1168,47 @@ func (s *SeriesStorage) GetByYear(ctx context.Context, yearStart, yearEnd int) (
 	return series, nil
 }
 
+func (s *SeriesStorage) GetByPerson(ctx context.Context, personID int) ([]models.Series, error) {
+	const op = "storage.series.GetByPerson"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	stmt, err := s.storage.db.PrepareContext(ctx, `
+		SELECT DISTINCT `+seriesColumns+`
+		FROM series s
+		JOIN published_projects p ON p.project_type = 'series' AND p.project_id = s.id
+		JOIN credits c ON c.project_id = p.id
+		WHERE c.person_id = $1`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: prepare statement: %w", op, err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, personID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	var series []models.Series
+	for rows.Next() {
+		var s models.Series
+		err = rows.Scan(&s.ID, &s.Title, &s.ReleaseYear, &s.Description, &s.Popularity, &s.Duration, &s.Director, &s.Producer)
+		if err != nil {
+			return nil, fmt.Errorf("%s: scan series: %w", op, err)
+		}
+		series = append(series, s)
+	}
+
+	return series, nil
+}
+
 func (s *SeriesStorage) GetEpisode(ctx context.Context, seriesID, seasonNumber, episodeNumber int) (models.Episode, error) {
 	const op = "storage.series.GetEpisode"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := s.storage.db.Prepare(`SELECT * FROM episodes WHERE series_id = $1 AND season_number = $2 AND episode_number = $3`)
+	stmt, err := s.storage.db.PrepareContext(ctx, `SELECT * FROM episodes WHERE series_id = $1 AND season_number = $2 AND episode_number = $3`)
 	if err != nil {
 		return models.Episode{}, fmt.Errorf("%s: prepare statement: %w", op, err)
 	}
@@ -1153,10 +1220,172 @@ func (s *SeriesStorage) GetEpisode(ctx context.Context, seriesID, seasonNumber,
 	err = row.Scan(&e.ID, &e.EpisodeNumber)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return models.Episode{}, fmt.Errorf("%s: no rows in episode: %w", op, err)
+			return models.Episode{}, fmt.Errorf("%s: %w", op, storage.ErrEpisodeNotFound.Wrap(err))
 		}
 		return models.Episode{}, fmt.Errorf("%s: scan episode: %w", op, err)
 	}
 
 	return e, nil
 }
+
+// GetByIds returns the series with the given IDs, without their genres,
+// images, seasons and other parts, which the Get*ByIds methods load for many
+// series at once. Missing series are left out.
+func (s *SeriesStorage) GetByIds(ctx context.Context, ids []int) ([]models.Series, error) {
+	const op = "storage.series.GetByIds"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	rows, err := s.storage.db.QueryContext(ctx, `
+		SELECT s.id, s.title, s.release_year, s.description, COALESCE(s.popularity, 0), s.duration, `+crewColumns("series", "s.id")+`
+		FROM series s WHERE id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	series := make([]models.Series, 0, len(ids))
+	for rows.Next() {
+		var item models.Series
+		err = rows.Scan(&item.ID, &item.Title, &item.ReleaseYear, &item.Description, &item.Popularity, &item.Duration, &item.Director, &item.Producer)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		series = append(series, item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return series, nil
+}
+
+func (s *SeriesStorage) GetGenresByIds(ctx context.Context, ids []int) (map[int][]models.Genre, error) {
+	const op = "storage.series.GetGenresByIds"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	genres, err := groupByIds(ctx, s.storage.db, `
+		SELECT sg.series_id, g.id, g.name
+		FROM genres g JOIN series_genres sg ON g.id = sg.genre_id
+		WHERE sg.series_id = ANY($1) ORDER BY g.name`, ids, scanGenre)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return genres, nil
+}
+
+func (s *SeriesStorage) GetAgeCategoriesByIds(ctx context.Context, ids []int) (map[int][]models.AgeCategory, error) {
+	const op = "storage.series.GetAgeCategoriesByIds"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	ageCategories, err := groupByIds(ctx, s.storage.db, `
+		SELECT sac.series_id, ac.id, ac.min_age, ac.max_age
+		FROM age_categories ac JOIN series_age_categories sac ON ac.id = sac.age_category_id
+		WHERE sac.series_id = ANY($1) ORDER BY ac.min_age, ac.max_age`, ids, scanAgeCategory)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return ageCategories, nil
+}
+
+func (s *SeriesStorage) GetKeywordsByIds(ctx context.Context, ids []int) (map[int][]models.Keyword, error) {
+	const op = "storage.series.GetKeywordsByIds"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	keywords, err := groupByIds(ctx, s.storage.db, `
+		SELECT sk.series_id, k.id, k.name
+		FROM key_words k JOIN series_key_words sk ON k.id = sk.key_word_id
+		WHERE sk.series_id = ANY($1) ORDER BY k.name`, ids, scanKeyword)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keywords, nil
+}
+
+func (s *SeriesStorage) GetCoversByIds(ctx context.Context, ids []int) (map[int]models.Cover, error) {
+	const op = "storage.series.GetCoversByIds"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	images, err := groupByIds(ctx, s.storage.db, `
+		SELECT series_id, id, filename FROM series_covers WHERE series_id = ANY($1) ORDER BY id`, ids, scanImage)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return covers(images), nil
+}
+
+func (s *SeriesStorage) GetScreenshotsByIds(ctx context.Context, ids []int) (map[int][]models.Screenshot, error) {
+	const op = "storage.series.GetScreenshotsByIds"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	screenshots, err := groupByIds(ctx, s.storage.db, `
+		SELECT series_id, id, filename FROM series_screenshots WHERE series_id = ANY($1) ORDER BY id`, ids, scanImage)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return screenshots, nil
+}
+
+func (s *SeriesStorage) GetCreditsByIds(ctx context.Context, ids []int) (map[int][]models.Credit, error) {
+	const op = "storage.series.GetCreditsByIds"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	credits, err := groupByIds(ctx, s.storage.db, creditsByIds("series"), ids, scanCredit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return credits, nil
+}
+
+// GetSeasonsByIds returns the seasons of each series in order, without
+// their episodes.
+func (s *SeriesStorage) GetSeasonsByIds(ctx context.Context, ids []int) (map[int][]models.Season, error) {
+	const op = "storage.series.GetSeasonsByIds"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	seasons, err := groupByIds(ctx, s.storage.db, `
+		SELECT series_id, id, season_number FROM seasons WHERE series_id = ANY($1) ORDER BY season_number`, ids,
+		func(rows *sql.Rows) (int, models.Season, error) {
+			var season models.Season
+			err := rows.Scan(&season.SeriesID, &season.ID, &season.SeasonNumber)
+			return season.SeriesID, season, err
+		})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return seasons, nil
+}
+
+// GetEpisodesBySeasonIds returns the episodes of each season in order.
+func (s *SeriesStorage) GetEpisodesBySeasonIds(ctx context.Context, seasonIDs []int) (map[int][]models.Episode, error) {
+	const op = "storage.series.GetEpisodesBySeasonIds"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	episodes, err := groupByIds(ctx, s.storage.db, `
+		SELECT season_id, id, episode_number, youtube_id FROM episodes WHERE season_id = ANY($1) ORDER BY episode_number`, seasonIDs,
+		func(rows *sql.Rows) (int, models.Episode, error) {
+			var episode models.Episode
+			err := rows.Scan(&episode.SeasonID, &episode.ID, &episode.EpisodeNumber, &episode.Link)
+			return episode.SeasonID, episode, err
+		})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return episodes, nil
+}
@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"ozinshe/internal/models"
+	"strconv"
+)
+
+type SyncStorage struct {
+	storage *Postgres
+}
+
+func NewSyncStorage(db *Postgres) *SyncStorage {
+	return &SyncStorage{storage: db}
+}
+
+// Changes returns up to limit entries of the sync log after since, skipping
+// the favorites of users other than userID, along with the projects whose
+// publish window opened or closed since since.Time.
+//
+// Only entries of transactions older than every running one are returned:
+// a running transaction may still add entries before those of transactions
+// that already committed.
+func (s *SyncStorage) Changes(ctx context.Context, since models.SyncPosition, userID, limit int) (models.SyncLog, error) {
+	const op = "storage.sync.Changes"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	// One snapshot for the horizon, the log and the publish windows.
+	tx, err := s.storage.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return models.SyncLog{}, fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var (
+		log     models.SyncLog
+		horizon uint64
+	)
+	err = tx.QueryRowContext(ctx, `
+		SELECT pg_snapshot_xmin(pg_current_snapshot())::text, NOW()`).Scan(&horizon, &log.Next.Time)
+	if err != nil {
+		return models.SyncLog{}, fmt.Errorf("%s: horizon: %w", op, err)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT txid::text, seq, resource, resource_id, op FROM sync_log
+		WHERE (txid, seq) > ($1::xid8, $2) AND txid < $3::xid8
+			AND (resource <> 'favorites' OR user_id = $4)
+		ORDER BY txid, seq
+		LIMIT $5`,
+		strconv.FormatUint(since.Txid, 10), since.Seq, strconv.FormatUint(horizon, 10), userID, limit)
+	if err != nil {
+		return models.SyncLog{}, fmt.Errorf("%s: query: %w", op, err)
+	}
+	defer rows.Close()
+
+	var last models.SyncPosition
+	for rows.Next() {
+		var change models.SyncChange
+		if err := rows.Scan(&last.Txid, &last.Seq, &change.Resource, &change.ID, &change.Op); err != nil {
+			return models.SyncLog{}, fmt.Errorf("%s: scan change: %w", op, err)
+		}
+		log.Changes = append(log.Changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return models.SyncLog{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// A full page continues after its last entry; otherwise every entry
+	// before the horizon has been read.
+	switch {
+	case len(log.Changes) == limit:
+		log.More = true
+		log.Next.Txid, log.Next.Seq = last.Txid, last.Seq
+	case horizon > since.Txid:
+		log.Next.Txid = horizon
+	default:
+		log.Next.Txid, log.Next.Seq = since.Txid, since.Seq
+	}
+
+	// A first sync gets every published project from the log.
+	if since.Time.IsZero() {
+		return log, nil
+	}
+
+	rows, err = tx.QueryContext(ctx, `
+		SELECT id FROM projects
+		WHERE (status = 'scheduled' AND publish_at > $1 AND publish_at <= $2)
+			OR (unpublish_at > $1 AND unpublish_at <= $2)`, since.Time, log.Next.Time)
+	if err != nil {
+		return models.SyncLog{}, fmt.Errorf("%s: query publish windows: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		change := models.SyncChange{Resource: models.ResourceProjects, Op: models.SyncUpdate}
+		if err := rows.Scan(&change.ID); err != nil {
+			return models.SyncLog{}, fmt.Errorf("%s: scan project: %w", op, err)
+		}
+		log.Changes = append(log.Changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return models.SyncLog{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return log, nil
+}
+
+// Compact deletes the log entries superseded by a later entry for the same
+// resource, which every client reading the earlier one also reads, and
+// reports how many were deleted. The latest entry of each resource stays,
+// so tombstones are kept.
+func (s *SyncStorage) Compact(ctx context.Context) (int, error) {
+	const op = "storage.sync.Compact"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	res, err := s.storage.db.ExecContext(ctx, `
+		DELETE FROM sync_log l USING sync_log n
+		WHERE n.resource = l.resource AND n.resource_id = l.resource_id AND n.user_id = l.user_id
+			AND (n.txid, n.seq) > (l.txid, l.seq)`)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(deleted), nil
+}
@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"ozinshe/internal/metrics"
+	"ozinshe/internal/storage"
+	"ozinshe/internal/tracing"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// pqConn is the part of the lib/pq connection the traced connections wrap.
+type pqConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.QueryerContext
+	driver.ExecerContext
+	driver.Pinger
+	driver.SessionResetter
+	driver.Validator
+}
+
+// pqStmt is the part of the lib/pq statement the traced statements wrap.
+type pqStmt interface {
+	driver.Stmt
+	driver.StmtQueryContext
+	driver.StmtExecContext
+}
+
+// tracedConnector opens connections that record a span with the SQL
+// statement of every query run on behalf of a traced request. The errors of
+// the database are translated into the errors of the storage package.
+type tracedConnector struct {
+	driver.Connector
+}
+
+func (c tracedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, storage.ErrUnavailable.Wrap(err)
+	}
+
+	pq, ok := conn.(pqConn)
+	if !ok {
+		conn.Close()
+		return nil, fmt.Errorf("storage: unsupported driver connection %T", conn)
+	}
+
+	return &tracedConn{pqConn: pq}, nil
+}
+
+type tracedConn struct {
+	pqConn
+}
+
+func (c *tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	ctx, span := startQuery(ctx, "sql.Query", query)
+	rows, err := c.pqConn.QueryContext(ctx, query, args)
+	endQuery(span, err)
+	return rows, translate(ctx, err)
+}
+
+func (c *tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ctx, span := startQuery(ctx, "sql.Exec", query)
+	res, err := c.pqConn.ExecContext(ctx, query, args)
+	endQuery(span, err)
+	return res, translate(ctx, err)
+}
+
+func (c *tracedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	ctx, span := startQuery(ctx, "sql.Prepare", query)
+	stmt, err := c.pqConn.PrepareContext(ctx, query)
+	endQuery(span, err)
+	if err != nil {
+		return nil, translate(ctx, err)
+	}
+
+	// COPY statements come back as a copy-in, which is not a pqStmt.
+	if pq, ok := stmt.(pqStmt); ok {
+		return &tracedStmt{pqStmt: pq, query: query}, nil
+	}
+	return stmt, nil
+}
+
+func (c *tracedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+type tracedStmt struct {
+	pqStmt
+	query string
+}
+
+func (s *tracedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	ctx, span := startQuery(ctx, "sql.Query", s.query)
+	rows, err := s.pqStmt.QueryContext(ctx, args)
+	endQuery(span, err)
+	return rows, translate(ctx, err)
+}
+
+func (s *tracedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	ctx, span := startQuery(ctx, "sql.Exec", s.query)
+	res, err := s.pqStmt.ExecContext(ctx, args)
+	endQuery(span, err)
+	return res, translate(ctx, err)
+}
+
+// startQuery starts the span of a query when ctx is traced. The span is
+// not recording otherwise.
+func startQuery(ctx context.Context, name, query string) (context.Context, trace.Span) {
+	if !tracing.Traced(ctx) {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+
+	return tracing.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBStatement(query)),
+	)
+}
+
+func endQuery(span trace.Span, err error) {
+	if !span.IsRecording() {
+		return
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// startOp starts the span of the storage operation op. The returned
+// function ends it and records the latency of the operation.
+func startOp(ctx context.Context, op string) (context.Context, func()) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, op)
+
+	return ctx, func() {
+		span.End()
+		metrics.ObserveStorage(op, start)
+	}
+}
@@ -22,18 +22,23 @@ func NewUserStorage(db *Postgres) *UserStorage {
 
 func (u *UserStorage) SaveUser(ctx context.Context, user models.User) (err error) {
 	const op = "storage.user.SaveUser"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
 	user.Name = ""
 	user.Number = ""
 	user.DateOfBirth = time.Time{}
 	user.UserType = ""
 
-	stmt, err := u.storage.db.Prepare("INSERT INTO users (name, email, number, date_of_birth, user_type, password, token, refresh_token) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id")
+	tx, err := u.storage.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
 	}
+	defer tx.Rollback()
 
-	_, err = stmt.ExecContext(ctx, user.Name, user.Email, user.Number, user.DateOfBirth, user.UserType, user.Password, user.Token, user.Refresh_Token)
+	var id int
+	err = tx.QueryRowContext(ctx, "INSERT INTO users (name, email, number, date_of_birth, user_type, password, token, refresh_token) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id",
+		user.Name, user.Email, user.Number, user.DateOfBirth, user.UserType, user.Password, user.Token, user.Refresh_Token).Scan(&id)
 
 	if err != nil {
 		var pqErr *pq.Error
@@ -45,13 +50,24 @@ func (u *UserStorage) SaveUser(ctx context.Context, user models.User) (err error
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	err = insertEvent(ctx, tx, models.EventUserRegistered, models.UserEvent{UserID: id, Email: user.Email})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit transaction: %w", op, err)
+	}
+
 	return
 }
 
 func (u *UserStorage) GetByEmail(ctx context.Context, email string) (models.User, error) {
 	const op = "storage.user.GetByEmail"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := u.storage.db.Prepare("SELECT id, name, email, number, date_of_birth, user_type, password FROM users WHERE email = $1")
+	stmt, err := u.storage.db.PrepareContext(ctx, "SELECT id, name, email, number, date_of_birth, user_type, password FROM users WHERE email = $1")
 	if err != nil {
 		return models.User{}, fmt.Errorf("%s: %w", op, err)
 	}
@@ -69,13 +85,12 @@ func (u *UserStorage) GetByEmail(ctx context.Context, email string) (models.User
 	return user, nil
 }
 
-func (u *UserStorage) UpdateTokens(signedToken string, signedRefreshToken string, user_type string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
-	defer cancel()
-
+func (u *UserStorage) UpdateTokens(ctx context.Context, signedToken string, signedRefreshToken string, user_type string) error {
 	const op = "storage.user.UpdateTokens"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := u.storage.db.Prepare(`UPDATE users SET token = $1, refresh_token = $2 WHERE user_type = $3`)
+	stmt, err := u.storage.db.PrepareContext(ctx, `UPDATE users SET token = $1, refresh_token = $2 WHERE user_type = $3`)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -91,8 +106,10 @@ func (u *UserStorage) UpdateTokens(signedToken string, signedRefreshToken string
 
 func (u *UserStorage) DeleteTokens(ctx context.Context, email string) error {
 	const op = "storage.user.GetByToken"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := u.storage.db.Prepare("UPDATE users SET token = NULL, refresh_token = NULL WHERE email = $1")
+	stmt, err := u.storage.db.PrepareContext(ctx, "UPDATE users SET token = NULL, refresh_token = NULL WHERE email = $1")
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -107,8 +124,10 @@ func (u *UserStorage) DeleteTokens(ctx context.Context, email string) error {
 
 func (u *UserStorage) GetAll(ctx context.Context) ([]models.User, error) {
 	const op = "storage.user.GetAll"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := u.storage.db.Prepare("SELECT id, name, email, number, date_of_birth, user_type, password FROM users")
+	stmt, err := u.storage.db.PrepareContext(ctx, "SELECT id, name, email, number, date_of_birth, user_type, password FROM users")
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -132,8 +151,10 @@ func (u *UserStorage) GetAll(ctx context.Context) ([]models.User, error) {
 
 func (u *UserStorage) GetById(ctx context.Context, id int) (models.User, error) {
 	const op = "storage.user.GetById"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := u.storage.db.Prepare("SELECT id, name, email, number, date_of_birth, user_type, password FROM users WHERE id = $1")
+	stmt, err := u.storage.db.PrepareContext(ctx, "SELECT id, name, email, number, date_of_birth, user_type, password FROM users WHERE id = $1")
 	if err != nil {
 		return models.User{}, fmt.Errorf("%s: %w", op, err)
 	}
@@ -153,8 +174,10 @@ func (u *UserStorage) GetById(ctx context.Context, id int) (models.User, error)
 
 func (u *UserStorage) ChangePassword(ctx context.Context, user models.User) error {
 	const op = "storage.user.ChangePassword"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := u.storage.db.Prepare("UPDATE users SET password = $1 WHERE email = $2")
+	stmt, err := u.storage.db.PrepareContext(ctx, "UPDATE users SET password = $1 WHERE email = $2")
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -169,8 +192,10 @@ func (u *UserStorage) ChangePassword(ctx context.Context, user models.User) erro
 
 func (u *UserStorage) ChangeProfileData(ctx context.Context, user models.User) error {
 	const op = "storage.user.ChangeProfileData"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := u.storage.db.Prepare("UPDATE users SET name = $1, number = $2, date_of_birth = $3 WHERE email = $4")
+	stmt, err := u.storage.db.PrepareContext(ctx, "UPDATE users SET name = $1, number = $2, date_of_birth = $3 WHERE email = $4")
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -185,14 +210,10 @@ func (u *UserStorage) ChangeProfileData(ctx context.Context, user models.User) e
 
 func (u *UserStorage) Delete(ctx context.Context, id int) error {
 	const op = "storage.user.Delete"
+	ctx, end := startOp(ctx, op)
+	defer end()
 
-	stmt, err := u.storage.db.Prepare("DELETE FROM users WHERE id = $1")
-	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
-	}
-
-	_, err = stmt.ExecContext(ctx, id)
-	if err != nil {
+	if err := deleteAudited(ctx, u.storage.db, "users", "user", id); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
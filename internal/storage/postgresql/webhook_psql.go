@@ -0,0 +1,288 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"ozinshe/internal/models"
+	"ozinshe/internal/storage"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type WebhookStorage struct {
+	storage *Postgres
+}
+
+func NewWebhookStorage(db *Postgres) *WebhookStorage {
+	return &WebhookStorage{storage: db}
+}
+
+const deliveryColumns = `d.id, d.webhook_id, d.event, d.payload, d.status, d.attempts, d.last_status_code, d.last_error,
+		d.next_attempt_at, d.delivered_at, d.created_at`
+
+func scanDelivery(row interface{ Scan(...any) error }, extra ...any) (models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	dest := []any{&d.ID, &d.WebhookID, &d.Event, &d.Payload, &d.Status, &d.Attempts, &d.LastStatusCode, &d.LastError,
+		&d.NextAttemptAt, &d.DeliveredAt, &d.CreatedAt}
+	err := row.Scan(append(dest, extra...)...)
+	return d, err
+}
+
+func (w *WebhookStorage) Insert(ctx context.Context, webhook models.Webhook) (int, error) {
+	const op = "storage.webhook.Insert"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	tx, err := w.storage.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRowContext(ctx, `INSERT INTO webhooks (url, secret, events, active) VALUES ($1, $2, $3, $4) RETURNING id`,
+		webhook.URL, webhook.Secret, pq.Array(webhook.Events), webhook.Active).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: insert webhook: %w", op, err)
+	}
+
+	if err := auditCreated(ctx, tx, "webhooks", "webhook", id); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: commit transaction: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (w *WebhookStorage) Delete(ctx context.Context, id int) error {
+	const op = "storage.webhook.Delete"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	if err := deleteAudited(ctx, w.storage.db, "webhooks", "webhook", id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (w *WebhookStorage) GetAll(ctx context.Context) ([]models.Webhook, error) {
+	const op = "storage.webhook.GetAll"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	rows, err := w.storage.db.QueryContext(ctx, `SELECT id, url, secret, events, active, created_at FROM webhooks ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	webhooks := make([]models.Webhook, 0)
+	for rows.Next() {
+		var webhook models.Webhook
+		err := rows.Scan(&webhook.ID, &webhook.URL, &webhook.Secret, pq.Array(&webhook.Events), &webhook.Active, &webhook.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return webhooks, nil
+}
+
+func (w *WebhookStorage) GetById(ctx context.Context, id int) (models.Webhook, error) {
+	const op = "storage.webhook.GetById"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	var webhook models.Webhook
+	err := w.storage.db.QueryRowContext(ctx, `SELECT id, url, secret, events, active, created_at FROM webhooks WHERE id = $1`, id).
+		Scan(&webhook.ID, &webhook.URL, &webhook.Secret, pq.Array(&webhook.Events), &webhook.Active, &webhook.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Webhook{}, fmt.Errorf("%s: %w", op, storage.ErrWebhookNotFound)
+		}
+		return models.Webhook{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return webhook, nil
+}
+
+// Enqueue creates a pending delivery of the payload for every active webhook
+// subscribed to the event. Payloads are passed as strings since lib/pq sends
+// []byte as bytea.
+func (w *WebhookStorage) Enqueue(ctx context.Context, event string, payload []byte) error {
+	const op = "storage.webhook.Enqueue"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	_, err := w.storage.db.ExecContext(ctx, `
+		INSERT INTO webhook_deliveries (webhook_id, event, payload)
+		SELECT id, $1, $2 FROM webhooks WHERE active AND $1 = ANY(events)`, event, string(payload))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// EnqueueFor creates a pending delivery for one webhook regardless of its
+// subscriptions.
+func (w *WebhookStorage) EnqueueFor(ctx context.Context, webhookID int, event string, payload []byte) (int, error) {
+	const op = "storage.webhook.EnqueueFor"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	var id int
+	err := w.storage.db.QueryRowContext(ctx, `
+		INSERT INTO webhook_deliveries (webhook_id, event, payload) VALUES ($1, $2, $3) RETURNING id`,
+		webhookID, event, string(payload)).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// ClaimDue returns at most limit pending deliveries that are due, leasing them
+// for the given duration so that other instances skip them meanwhile. A
+// delivery whose sender dies is picked up again once the lease expires.
+func (w *WebhookStorage) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	const op = "storage.webhook.ClaimDue"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	query := `UPDATE webhook_deliveries d SET next_attempt_at = NOW() + $2 * INTERVAL '1 second'
+		FROM webhooks w
+		WHERE w.id = d.webhook_id AND d.id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + deliveryColumns + `, w.url, w.secret`
+
+	rows, err := w.storage.db.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]models.WebhookDelivery, 0)
+	for rows.Next() {
+		var url, secret string
+		delivery, err := scanDelivery(rows, &url, &secret)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		delivery.URL, delivery.Secret = url, secret
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+// RecordAttempt stores the outcome of sending a delivery. The delivery and
+// its next attempt time must already carry the new status.
+func (w *WebhookStorage) RecordAttempt(ctx context.Context, delivery models.WebhookDelivery) error {
+	const op = "storage.webhook.RecordAttempt"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	_, err := w.storage.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = attempts + 1, last_status_code = $3, last_error = $4, next_attempt_at = $5,
+			delivered_at = CASE WHEN $2 = 'succeeded' THEN NOW() ELSE delivered_at END
+		WHERE id = $1`,
+		delivery.ID, delivery.Status, delivery.LastStatusCode, delivery.LastError, delivery.NextAttemptAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (w *WebhookStorage) GetDeliveries(ctx context.Context, webhookID, offset, limit int) ([]models.WebhookDelivery, error) {
+	const op = "storage.webhook.GetDeliveries"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	rows, err := w.storage.db.QueryContext(ctx, `SELECT `+deliveryColumns+` FROM webhook_deliveries d
+		WHERE d.webhook_id = $1 ORDER BY d.created_at DESC, d.id DESC OFFSET $2 LIMIT $3`, webhookID, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]models.WebhookDelivery, 0)
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+// Redeliver queues a delivery to be sent again right away with a fresh set
+// of attempts.
+func (w *WebhookStorage) Redeliver(ctx context.Context, deliveryID int) error {
+	const op = "storage.webhook.Redeliver"
+	ctx, end := startOp(ctx, op)
+	defer end()
+
+	tx, err := w.storage.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	before, err := snapshot(ctx, tx, "webhook_deliveries", deliveryID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if before == nil {
+		return fmt.Errorf("%s: %w", op, storage.ErrDeliveryNotFound)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE webhook_deliveries SET status = 'pending', attempts = 0, next_attempt_at = NOW()
+		WHERE id = $1`, deliveryID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	after, err := snapshot(ctx, tx, "webhook_deliveries", deliveryID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := insertAudit(ctx, tx, "webhook_delivery.redeliver", "webhook_delivery", deliveryID, before, after); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: commit transaction: %w", op, err)
+	}
+
+	return nil
+}
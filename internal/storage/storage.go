@@ -1,10 +1,41 @@
 package storage
 
-import "fmt"
+import "ozinshe/internal/apperr"
 
 var (
-	ErrUserNotFound = fmt.Errorf("user not found")
-	ErrUserExists   = fmt.Errorf("user already exists")
-	ErrMovieExists  = fmt.Errorf("movie already exists")
-	ErrSeriesExists = fmt.Errorf("series already exists")
+	// ErrNotFound and ErrConflict are returned when a row is missing or a
+	// unique constraint is violated and no more specific error applies.
+	ErrNotFound = apperr.NotFound("not_found", "not found")
+	ErrConflict = apperr.Conflict("already_exists", "already exists")
+
+	// ErrInvalidReference and ErrInvalidValue are returned when a write is
+	// rejected by a foreign key or by a check on the values of a row.
+	ErrInvalidReference = apperr.Validation("invalid_reference", "references a row that does not exist", nil)
+	ErrInvalidValue     = apperr.Validation("invalid_value", "invalid value", nil)
+
+	// ErrUnavailable is returned when the database can not be reached.
+	ErrUnavailable = apperr.Unavailable("database_unavailable", "database unavailable")
+
+	ErrUserNotFound         = apperr.NotFound("user_not_found", "user not found")
+	ErrUserExists           = apperr.Conflict("user_exists", "user already exists")
+	ErrMovieNotFound        = apperr.NotFound("movie_not_found", "movie not found")
+	ErrMovieExists          = apperr.Conflict("movie_exists", "movie already exists")
+	ErrSeriesNotFound       = apperr.NotFound("series_not_found", "series not found")
+	ErrSeriesExists         = apperr.Conflict("series_exists", "series already exists")
+	ErrSeasonNotFound       = apperr.NotFound("season_not_found", "season not found")
+	ErrEpisodeNotFound      = apperr.NotFound("episode_not_found", "episode not found")
+	ErrProjectNotFound      = apperr.NotFound("project_not_found", "project not found")
+	ErrPersonNotFound       = apperr.NotFound("person_not_found", "person not found")
+	ErrCollectionExists     = apperr.Conflict("collection_exists", "collection already exists")
+	ErrCollectionNotFound   = apperr.NotFound("collection_not_found", "collection not found")
+	ErrListNotFound         = apperr.NotFound("list_not_found", "list not found")
+	ErrListItemNotFound     = apperr.NotFound("list_item_not_found", "list item not found")
+	ErrNotificationNotFound = apperr.NotFound("notification_not_found", "notification not found")
+	ErrWebhookNotFound      = apperr.NotFound("webhook_not_found", "webhook not found")
+	ErrDeliveryNotFound     = apperr.NotFound("webhook_delivery_not_found", "webhook delivery not found")
+	ErrJobNotFound          = apperr.NotFound("job_not_found", "job not found")
+
+	// ErrJobLeaseLost is returned when a worker finishes a job whose lease
+	// expired, which another worker may have claimed since.
+	ErrJobLeaseLost = apperr.Conflict("job_lease_lost", "job lease lost")
 )
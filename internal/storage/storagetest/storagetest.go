@@ -0,0 +1,269 @@
+// Package storagetest provides a fake database for the tests of the storage
+// and of the services and handlers above it, which run without Postgres.
+//
+//	db := storagetest.New().On("FROM genres", []string{"id", "name"}, []driver.Value{int64(1), "Drama"})
+//	storage := psql.New(psql.Open(db))
+package storagetest
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/lib/pq"
+)
+
+// ErrCanceled is the error Postgres fails a statement with when the client
+// cancels it, as lib/pq does when the context of a query is done.
+var ErrCanceled = &pq.Error{Severity: "ERROR", Code: "57014", Message: "canceling statement due to user request"}
+
+// DB is a fake database and the driver.Connector of its connections.
+// Queries return the rows of the first result whose pattern they contain,
+// and no rows otherwise. Statements other than queries change nothing and
+// report the rows affected of the first pattern they contain, 0 otherwise.
+type DB struct {
+	mu       sync.Mutex
+	results  []result
+	affected []affected
+	failures []failure
+	block    bool
+	queries  []string
+	commits  int
+}
+
+type result struct {
+	match   string
+	columns []string
+	rows    [][]driver.Value
+}
+
+type affected struct {
+	match string
+	rows  int64
+}
+
+type failure struct {
+	match string
+	err   error
+}
+
+func New() *DB {
+	return &DB{}
+}
+
+// On makes the queries containing match return rows of columns.
+func (db *DB) On(match string, columns []string, rows ...[]driver.Value) *DB {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.results = append(db.results, result{match: match, columns: columns, rows: rows})
+	return db
+}
+
+// Affect makes the statements containing match report n rows affected.
+func (db *DB) Affect(match string, n int64) *DB {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.affected = append(db.affected, affected{match: match, rows: n})
+	return db
+}
+
+// Fail makes the statements and queries containing match fail with err.
+func (db *DB) Fail(match string, err error) *DB {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.failures = append(db.failures, failure{match: match, err: err})
+	return db
+}
+
+// Block makes every statement wait until its context is done and then fail
+// with ErrCanceled, like a slow query the client gives up on.
+func (db *DB) Block() *DB {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.block = true
+	return db
+}
+
+// Queries returns the statements run so far, in order.
+func (db *DB) Queries() []string {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return append([]string(nil), db.queries...)
+}
+
+// Commits returns how many transactions were committed so far.
+func (db *DB) Commits() int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return db.commits
+}
+
+func (db *DB) Connect(context.Context) (driver.Conn, error) {
+	return &conn{db: db}, nil
+}
+
+func (db *DB) Driver() driver.Driver {
+	return fakeDriver{db: db}
+}
+
+func (db *DB) run(ctx context.Context, query string) error {
+	db.mu.Lock()
+	db.queries = append(db.queries, query)
+	block := db.block
+	var err error
+	for _, f := range db.failures {
+		if strings.Contains(query, f.match) {
+			err = f.err
+			break
+		}
+	}
+	db.mu.Unlock()
+
+	if block {
+		<-ctx.Done()
+		return ErrCanceled
+	}
+	return err
+}
+
+func (db *DB) query(ctx context.Context, query string) (driver.Rows, error) {
+	if err := db.run(ctx, query); err != nil {
+		return nil, err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	for _, r := range db.results {
+		if strings.Contains(query, r.match) {
+			return &rows{columns: r.columns, rows: r.rows}, nil
+		}
+	}
+	return &rows{}, nil
+}
+
+func (db *DB) exec(ctx context.Context, query string) (driver.Result, error) {
+	if err := db.run(ctx, query); err != nil {
+		return nil, err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	for _, a := range db.affected {
+		if strings.Contains(query, a.match) {
+			return driver.RowsAffected(a.rows), nil
+		}
+	}
+	return driver.RowsAffected(0), nil
+}
+
+type fakeDriver struct {
+	db *DB
+}
+
+func (d fakeDriver) Open(string) (driver.Conn, error) {
+	return &conn{db: d.db}, nil
+}
+
+// conn implements the interfaces of the lib/pq connections, which the
+// storage expects of its driver.
+type conn struct {
+	db *DB
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{db: c.db, query: query}, nil
+}
+
+func (c *conn) PrepareContext(_ context.Context, query string) (driver.Stmt, error) {
+	return &stmt{db: c.db, query: query}, nil
+}
+
+func (c *conn) Close() error { return nil }
+
+func (c *conn) Begin() (driver.Tx, error) { return tx{db: c.db}, nil }
+
+func (c *conn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	return tx{db: c.db}, nil
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	return c.db.query(ctx, query)
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	return c.db.exec(ctx, query)
+}
+
+func (c *conn) Ping(context.Context) error { return nil }
+
+func (c *conn) ResetSession(context.Context) error { return nil }
+
+func (c *conn) IsValid() bool { return true }
+
+type stmt struct {
+	db    *DB
+	query string
+}
+
+func (s *stmt) Close() error { return nil }
+
+func (s *stmt) NumInput() int { return -1 }
+
+func (s *stmt) Exec([]driver.Value) (driver.Result, error) {
+	return s.db.exec(context.Background(), s.query)
+}
+
+func (s *stmt) Query([]driver.Value) (driver.Rows, error) {
+	return s.db.query(context.Background(), s.query)
+}
+
+func (s *stmt) ExecContext(ctx context.Context, _ []driver.NamedValue) (driver.Result, error) {
+	return s.db.exec(ctx, s.query)
+}
+
+func (s *stmt) QueryContext(ctx context.Context, _ []driver.NamedValue) (driver.Rows, error) {
+	return s.db.query(ctx, s.query)
+}
+
+type tx struct {
+	db *DB
+}
+
+func (t tx) Commit() error {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+
+	t.db.commits++
+	return nil
+}
+
+func (tx) Rollback() error { return nil }
+
+type rows struct {
+	columns []string
+	rows    [][]driver.Value
+	next    int
+}
+
+func (r *rows) Columns() []string { return r.columns }
+
+func (r *rows) Close() error { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.next])
+	r.next++
+	return nil
+}
@@ -1,8 +1,13 @@
 package validation
 
 import (
-	"errors"
 	"mime/multipart"
+	"ozinshe/internal/apperr"
+)
+
+var (
+	ErrImageType = apperr.Validation("invalid_image_type", "invalid image type. Only JPEG and PNG are supported", nil)
+	ErrImageSize = apperr.Validation("image_too_large", "image exceeds maximum allowed size", nil)
 )
 
 func ValidateImageFile(fileHeader *multipart.FileHeader, maxImageSize int64) error {
@@ -15,12 +20,12 @@ func ValidateImageFile(fileHeader *multipart.FileHeader, maxImageSize int64) err
 
 	// 1. Check File Type
 	if !allowedTypes[fileHeader.Header.Get("Content-Type")] {
-		return errors.New("invalid image type. Only JPEG and PNG are supported")
+		return ErrImageType
 	}
 
 	// 2. Check File Size
 	if fileHeader.Size > maxImageSize {
-		return errors.New("image exceeds maximum allowed size")
+		return ErrImageSize
 	}
 
 	// If all checks pass, there's no error
@@ -1,9 +1,7 @@
 package validation
 
 import (
-	"errors"
 	"net/mail"
-	"ozinshe/internal/models"
 	"regexp"
 )
 
@@ -12,16 +10,6 @@ const (
 	MsgInvalidPass  = "password must contain letters, numbers and must be at least 6 characters"
 )
 
-func GetErrMsg(m models.User) error {
-	if !IsValidEmail(m.Email) {
-		return errors.New(MsgInvalidEmail)
-	}
-	if !IsValidPassword(m.Password) {
-		return errors.New(MsgInvalidPass)
-	}
-	return nil
-}
-
 func IsValidEmail(email string) bool {
 	rxEmail := regexp.MustCompile(`^[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,4}$`)
 	if len(email) > 254 || !rxEmail.MatchString(email) {
@@ -0,0 +1,188 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"ozinshe/internal/apperr"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	// MaxAge bounds the ages of age categories.
+	MaxAge = 99
+	// FirstReleaseYear is the year of the first film.
+	FirstReleaseYear = 1888
+)
+
+var rxYoutubeID = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+
+// Validate checks structs against their validate tags. Besides the built-in
+// validators it knows:
+//
+//	age_range     an age category such as "6-12"
+//	youtube_id    the 11 character ID of a YouTube video
+//	release_year  a year films were released in, up to five years ahead
+//	password      letters and numbers, at least 6 characters
+var Validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+
+	// Errors name fields the way clients send them.
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return field.Name
+	})
+
+	v.RegisterValidation("age_range", func(fl validator.FieldLevel) bool {
+		_, _, ok := ParseAgeRange(fl.Field().String())
+		return ok
+	})
+	v.RegisterValidation("youtube_id", func(fl validator.FieldLevel) bool {
+		return rxYoutubeID.MatchString(fl.Field().String())
+	})
+	v.RegisterValidation("release_year", func(fl validator.FieldLevel) bool {
+		year := fl.Field().Int()
+		return year >= FirstReleaseYear && year <= int64(time.Now().Year()+5)
+	})
+	v.RegisterValidation("password", func(fl validator.FieldLevel) bool {
+		return IsValidPassword(fl.Field().String())
+	})
+
+	return v
+}
+
+// ParseAgeRange parses an age category such as "6-12", reporting whether it
+// is one: two ages up to MaxAge, the first not above the second.
+func ParseAgeRange(s string) (min, max int, ok bool) {
+	minAge, maxAge, found := strings.Cut(s, "-")
+	if !found {
+		return 0, 0, false
+	}
+
+	min, err := strconv.Atoi(strings.TrimSpace(minAge))
+	if err != nil {
+		return 0, 0, false
+	}
+	max, err = strconv.Atoi(strings.TrimSpace(maxAge))
+	if err != nil {
+		return 0, 0, false
+	}
+
+	if min < 0 || max > MaxAge || min > max {
+		return 0, 0, false
+	}
+
+	return min, max, true
+}
+
+// Struct validates v, a struct or a pointer to one. Slices and arrays have
+// each of their elements validated; other values are always valid. When v
+// is not valid, the error is an apperr validation error naming what is
+// wrong with each field.
+func Struct(v any) error {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		return fieldErrors(Validate.Struct(value.Interface()))
+	case reflect.Slice, reflect.Array:
+		fields := map[string]string{}
+		for i := 0; i < value.Len(); i++ {
+			err := Struct(value.Index(i).Interface())
+
+			var e *apperr.Error
+			if !errors.As(err, &e) {
+				continue
+			}
+			for field, msg := range e.Fields {
+				fields[fmt.Sprintf("[%d].%s", i, field)] = msg
+			}
+		}
+		if len(fields) > 0 {
+			return invalid(fields)
+		}
+	}
+
+	return nil
+}
+
+func fieldErrors(err error) error {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return err
+	}
+
+	fields := make(map[string]string, len(errs))
+	for _, fe := range errs {
+		// The namespace starts with the name of the struct validated.
+		_, field, _ := strings.Cut(fe.Namespace(), ".")
+		fields[field] = message(fe)
+	}
+
+	return invalid(fields)
+}
+
+func invalid(fields map[string]string) error {
+	return apperr.Validation("invalid_request", "request is not valid", fields)
+}
+
+// message describes the failed check of fe to clients.
+func message(fe validator.FieldError) string {
+	unit := ""
+	switch fe.Kind() {
+	case reflect.String:
+		unit = " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		unit = " items"
+	}
+
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return MsgInvalidEmail
+	case "password":
+		return MsgInvalidPass
+	case "url", "http_url":
+		return "must be an absolute http or https url"
+	case "min", "gte":
+		return "must be at least " + fe.Param() + unit
+	case "max", "lte":
+		return "must be at most " + fe.Param() + unit
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "eqfield":
+		return "must match " + fe.Param()
+	case "age_range":
+		return fmt.Sprintf("must be an age range such as 6-12, with ages up to %d", MaxAge)
+	case "youtube_id":
+		return "must be the 11 character ID of a YouTube video"
+	case "release_year":
+		return fmt.Sprintf("must be a year from %d to %d", FirstReleaseYear, time.Now().Year()+5)
+	default:
+		return "is not valid"
+	}
+}
@@ -0,0 +1,39 @@
+-- Bring the director and producer columns back from credits.
+ALTER TABLE movies ADD COLUMN director VARCHAR(100) NOT NULL DEFAULT '', ADD COLUMN producer VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE series ADD COLUMN director VARCHAR(100) NOT NULL DEFAULT '', ADD COLUMN producer VARCHAR(100) NOT NULL DEFAULT '';
+
+UPDATE movies m SET
+    director = COALESCE(LEFT(c.director, 100), ''),
+    producer = COALESCE(LEFT(c.producer, 100), '')
+FROM (
+    SELECT p.project_id,
+        string_agg(pe.name, ', ' ORDER BY cr.position) FILTER (WHERE cr.role = 'director') AS director,
+        string_agg(pe.name, ', ' ORDER BY cr.position) FILTER (WHERE cr.role = 'producer') AS producer
+    FROM credits cr
+    JOIN people pe ON pe.id = cr.person_id
+    JOIN projects p ON p.id = cr.project_id
+    WHERE p.project_type = 'movie'
+    GROUP BY p.project_id
+) c
+WHERE c.project_id = m.id;
+
+UPDATE series s SET
+    director = COALESCE(LEFT(c.director, 100), ''),
+    producer = COALESCE(LEFT(c.producer, 100), '')
+FROM (
+    SELECT p.project_id,
+        string_agg(pe.name, ', ' ORDER BY cr.position) FILTER (WHERE cr.role = 'director') AS director,
+        string_agg(pe.name, ', ' ORDER BY cr.position) FILTER (WHERE cr.role = 'producer') AS producer
+    FROM credits cr
+    JOIN people pe ON pe.id = cr.person_id
+    JOIN projects p ON p.id = cr.project_id
+    WHERE p.project_type = 'series'
+    GROUP BY p.project_id
+) c
+WHERE c.project_id = s.id;
+
+ALTER TABLE movies ALTER COLUMN director DROP DEFAULT, ALTER COLUMN producer DROP DEFAULT;
+ALTER TABLE series ALTER COLUMN director DROP DEFAULT, ALTER COLUMN producer DROP DEFAULT;
+
+DROP TABLE IF EXISTS credits;
+DROP TABLE IF EXISTS people;
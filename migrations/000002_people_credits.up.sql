@@ -0,0 +1,49 @@
+CREATE TABLE IF NOT EXISTS people (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    bio TEXT NOT NULL DEFAULT '',
+    photo VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS credits (
+    id SERIAL PRIMARY KEY,
+    person_id INTEGER NOT NULL,
+    project_id INTEGER NOT NULL,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('director', 'producer', 'actor', 'writer')),
+    character_name VARCHAR(255) NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (person_id, project_id, role, character_name),
+    FOREIGN KEY (person_id) REFERENCES people(id) ON DELETE CASCADE,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_people_name ON people (name);
+CREATE INDEX idx_credits_person_id ON credits (person_id);
+CREATE INDEX idx_credits_project_id ON credits (project_id);
+
+-- Move the free-text director and producer columns into people and credits.
+INSERT INTO people (name)
+SELECT DISTINCT name FROM (
+    SELECT director AS name FROM movies
+    UNION SELECT producer FROM movies
+    UNION SELECT director FROM series
+    UNION SELECT producer FROM series
+) names
+WHERE name <> '';
+
+INSERT INTO credits (person_id, project_id, role)
+SELECT pe.id, p.id, c.role
+FROM projects p
+JOIN (
+    SELECT 'movie' AS project_type, id, director AS name, 'director' AS role FROM movies
+    UNION ALL SELECT 'movie', id, producer, 'producer' FROM movies
+    UNION ALL SELECT 'series', id, director, 'director' FROM series
+    UNION ALL SELECT 'series', id, producer, 'producer' FROM series
+) c ON c.project_type = p.project_type AND c.id = p.project_id
+JOIN people pe ON pe.name = c.name
+ON CONFLICT DO NOTHING;
+
+-- Credits are the only record of them from now on.
+ALTER TABLE movies DROP COLUMN director, DROP COLUMN producer;
+ALTER TABLE series DROP COLUMN director, DROP COLUMN producer;
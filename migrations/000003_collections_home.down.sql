@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS watch_progress;
+DROP TABLE IF EXISTS collection_projects;
+DROP TABLE IF EXISTS collections;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS project_similarities (
+    project_id INTEGER NOT NULL,
+    similar_project_id INTEGER NOT NULL,
+    score DOUBLE PRECISION NOT NULL,
+    computed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (project_id, similar_project_id),
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (similar_project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_project_similarities_score ON project_similarities (project_id, score DESC);
@@ -0,0 +1,65 @@
+CREATE TABLE IF NOT EXISTS lists (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    kind VARCHAR(20) NOT NULL DEFAULT 'custom' CHECK (kind IN ('favorites', 'watch_later', 'custom')),
+    is_public BOOLEAN NOT NULL DEFAULT FALSE,
+    share_token VARCHAR(64) NOT NULL UNIQUE DEFAULT md5(random()::text || clock_timestamp()::text),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS list_items (
+    list_id INTEGER NOT NULL,
+    project_id INTEGER NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    note TEXT NOT NULL DEFAULT '',
+    added_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (list_id, project_id),
+    FOREIGN KEY (list_id) REFERENCES lists(id) ON DELETE CASCADE,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+-- Every user has at most one built-in Favorites and Watch Later list.
+CREATE UNIQUE INDEX idx_lists_builtin ON lists (user_id, kind) WHERE kind <> 'custom';
+CREATE INDEX idx_lists_user_id ON lists (user_id);
+CREATE INDEX idx_list_items_position ON list_items (list_id, position);
+CREATE INDEX idx_list_items_project_id ON list_items (project_id);
+
+-- Favorites were kept by project, and before that by movie and by series.
+-- All of them move to the Favorites list of their user, each project once:
+-- project favorites first, then movies, then series, each oldest first.
+CREATE TEMPORARY TABLE old_favorites AS
+SELECT DISTINCT ON (user_id, project_id) user_id, project_id, source, id
+FROM (
+    SELECT user_id, project_id, 0 AS source, id FROM favorite_projects
+    UNION ALL
+    SELECT f.user_id, p.id, 1, f.id
+    FROM favorite_movies f
+    JOIN projects p ON p.project_type = 'movie' AND p.project_id = f.movie_id
+    UNION ALL
+    SELECT f.user_id, p.id, 2, f.id
+    FROM favorite_series f
+    JOIN projects p ON p.project_type = 'series' AND p.project_id = f.series_id
+) favorites
+ORDER BY user_id, project_id, source, id;
+
+INSERT INTO lists (user_id, name, kind)
+SELECT DISTINCT user_id, 'Favorites', 'favorites' FROM old_favorites;
+
+INSERT INTO list_items (list_id, project_id, position)
+SELECT l.id, f.project_id, ROW_NUMBER() OVER (PARTITION BY f.user_id ORDER BY f.source, f.id) - 1
+FROM old_favorites f
+JOIN lists l ON l.user_id = f.user_id AND l.kind = 'favorites';
+
+DROP TABLE old_favorites;
+
+CREATE VIEW user_favorites AS
+SELECT l.user_id, li.project_id, li.added_at
+FROM list_items li
+JOIN lists l ON l.id = li.list_id
+WHERE l.kind = 'favorites';
+
+DROP TABLE IF EXISTS favorite_movies;
+DROP TABLE IF EXISTS favorite_series;
+DROP TABLE IF EXISTS favorite_projects;
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS notifications_notify ON notifications;
+DROP FUNCTION IF EXISTS notify_notification();
+DROP TABLE IF EXISTS notification_preferences;
+DROP TABLE IF EXISTS notifications;
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('new_episodes', 'new_release')),
+    project_id INTEGER,
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    read_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_notifications_user_id ON notifications (user_id, created_at DESC);
+CREATE INDEX idx_notifications_unread ON notifications (user_id) WHERE read_at IS NULL;
+
+-- Users without a row get every notification.
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id INTEGER PRIMARY KEY,
+    new_episodes BOOLEAN NOT NULL DEFAULT TRUE,
+    new_releases BOOLEAN NOT NULL DEFAULT TRUE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Every new notification is sent on the notifications channel as JSON, so
+-- that each server can stream it to the user's subscribers whichever
+-- process created it. Listeners only get the notifications of committed
+-- transactions.
+CREATE OR REPLACE FUNCTION notify_notification() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('notifications', row_to_json(NEW)::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notifications_notify AFTER INSERT ON notifications
+    FOR EACH ROW EXECUTE PROCEDURE notify_notification();
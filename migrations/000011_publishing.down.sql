@@ -0,0 +1,8 @@
+DROP VIEW IF EXISTS published_projects;
+
+DROP INDEX IF EXISTS idx_projects_scheduled;
+
+ALTER TABLE projects
+    DROP COLUMN IF EXISTS unpublish_at,
+    DROP COLUMN IF EXISTS publish_at,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,18 @@
+ALTER TABLE projects
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'published' CHECK (status IN ('draft', 'review', 'scheduled', 'published')),
+    ADD COLUMN publish_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN unpublish_at TIMESTAMP WITH TIME ZONE,
+    ADD CONSTRAINT projects_scheduled_publish_at CHECK (status <> 'scheduled' OR publish_at IS NOT NULL),
+    ADD CONSTRAINT projects_publish_window CHECK (publish_at IS NULL OR unpublish_at IS NULL OR unpublish_at > publish_at);
+
+-- Existing projects stay published; new ones start as drafts.
+ALTER TABLE projects ALTER COLUMN status SET DEFAULT 'draft';
+
+CREATE INDEX idx_projects_scheduled ON projects (publish_at) WHERE status = 'scheduled';
+
+-- published_projects are the projects the public can see: published ones and
+-- scheduled ones whose publish time has passed, until their unpublish time.
+CREATE VIEW published_projects AS
+SELECT * FROM projects
+WHERE (status = 'published' OR (status = 'scheduled' AND publish_at <= NOW()))
+    AND (unpublish_at IS NULL OR unpublish_at > NOW());
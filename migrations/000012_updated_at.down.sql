@@ -0,0 +1,32 @@
+DROP TRIGGER IF EXISTS age_categories_record_change ON age_categories;
+DROP TRIGGER IF EXISTS genres_record_change ON genres;
+DROP TRIGGER IF EXISTS projects_record_change ON projects;
+DROP FUNCTION IF EXISTS record_catalog_change();
+DROP TABLE IF EXISTS catalog_changes;
+
+DROP TRIGGER IF EXISTS credits_touch_project ON credits;
+DROP TRIGGER IF EXISTS episodes_touch_project ON episodes;
+DROP TRIGGER IF EXISTS seasons_touch_project ON seasons;
+DROP TRIGGER IF EXISTS series_key_words_touch_project ON series_key_words;
+DROP TRIGGER IF EXISTS series_age_categories_touch_project ON series_age_categories;
+DROP TRIGGER IF EXISTS series_genres_touch_project ON series_genres;
+DROP TRIGGER IF EXISTS series_screenshots_touch_project ON series_screenshots;
+DROP TRIGGER IF EXISTS series_covers_touch_project ON series_covers;
+DROP TRIGGER IF EXISTS movie_key_words_touch_project ON movie_key_words;
+DROP TRIGGER IF EXISTS movie_age_categories_touch_project ON movie_age_categories;
+DROP TRIGGER IF EXISTS movie_genres_touch_project ON movie_genres;
+DROP TRIGGER IF EXISTS movie_screenshots_touch_project ON movie_screenshots;
+DROP TRIGGER IF EXISTS movie_covers_touch_project ON movie_covers;
+DROP TRIGGER IF EXISTS series_touch_project ON series;
+DROP TRIGGER IF EXISTS movies_touch_project ON movies;
+DROP FUNCTION IF EXISTS touch_episode_project();
+DROP FUNCTION IF EXISTS touch_project();
+
+DROP TRIGGER IF EXISTS age_categories_set_updated_at ON age_categories;
+DROP TRIGGER IF EXISTS genres_set_updated_at ON genres;
+DROP TRIGGER IF EXISTS projects_set_updated_at ON projects;
+DROP FUNCTION IF EXISTS set_updated_at();
+
+ALTER TABLE age_categories DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE genres DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE projects DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,123 @@
+ALTER TABLE projects ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+ALTER TABLE genres ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+ALTER TABLE age_categories ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+
+CREATE OR REPLACE FUNCTION set_updated_at() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at := NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER projects_set_updated_at
+    BEFORE UPDATE ON projects
+    FOR EACH ROW WHEN (OLD.* IS DISTINCT FROM NEW.*) EXECUTE PROCEDURE set_updated_at();
+CREATE TRIGGER genres_set_updated_at
+    BEFORE UPDATE ON genres
+    FOR EACH ROW WHEN (OLD.* IS DISTINCT FROM NEW.*) EXECUTE PROCEDURE set_updated_at();
+CREATE TRIGGER age_categories_set_updated_at
+    BEFORE UPDATE ON age_categories
+    FOR EACH ROW WHEN (OLD.* IS DISTINCT FROM NEW.*) EXECUTE PROCEDURE set_updated_at();
+
+-- touch_project marks the project of a changed movie, series or part of one
+-- as updated. Its arguments are the project type and the column of the row
+-- holding the movie or series ID, or only the column when it holds the
+-- project ID.
+CREATE OR REPLACE FUNCTION touch_project() RETURNS TRIGGER AS $$
+DECLARE
+    changed_id INTEGER;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed_id := to_jsonb(OLD) ->> TG_ARGV[TG_NARGS - 1];
+    ELSE
+        changed_id := to_jsonb(NEW) ->> TG_ARGV[TG_NARGS - 1];
+    END IF;
+
+    IF TG_NARGS = 1 THEN
+        UPDATE projects SET updated_at = NOW() WHERE id = changed_id;
+    ELSE
+        UPDATE projects SET updated_at = NOW() WHERE project_type = TG_ARGV[0] AND project_id = changed_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Episodes only know their season.
+CREATE OR REPLACE FUNCTION touch_episode_project() RETURNS TRIGGER AS $$
+DECLARE
+    changed_season INTEGER;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed_season := OLD.season_id;
+    ELSE
+        changed_season := NEW.season_id;
+    END IF;
+
+    UPDATE projects SET updated_at = NOW()
+    WHERE project_type = 'series' AND project_id = (SELECT series_id FROM seasons WHERE id = changed_season);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Popularity follows the favorites and is not an update of the movie or
+-- series itself.
+CREATE TRIGGER movies_touch_project
+    AFTER UPDATE ON movies
+    FOR EACH ROW WHEN ((to_jsonb(OLD) - 'popularity') IS DISTINCT FROM (to_jsonb(NEW) - 'popularity'))
+    EXECUTE PROCEDURE touch_project('movie', 'id');
+CREATE TRIGGER series_touch_project
+    AFTER UPDATE ON series
+    FOR EACH ROW WHEN ((to_jsonb(OLD) - 'popularity') IS DISTINCT FROM (to_jsonb(NEW) - 'popularity'))
+    EXECUTE PROCEDURE touch_project('series', 'id');
+
+CREATE TRIGGER movie_covers_touch_project AFTER INSERT OR UPDATE OR DELETE ON movie_covers
+    FOR EACH ROW EXECUTE PROCEDURE touch_project('movie', 'movie_id');
+CREATE TRIGGER movie_screenshots_touch_project AFTER INSERT OR UPDATE OR DELETE ON movie_screenshots
+    FOR EACH ROW EXECUTE PROCEDURE touch_project('movie', 'movie_id');
+CREATE TRIGGER movie_genres_touch_project AFTER INSERT OR UPDATE OR DELETE ON movie_genres
+    FOR EACH ROW EXECUTE PROCEDURE touch_project('movie', 'movie_id');
+CREATE TRIGGER movie_age_categories_touch_project AFTER INSERT OR UPDATE OR DELETE ON movie_age_categories
+    FOR EACH ROW EXECUTE PROCEDURE touch_project('movie', 'movie_id');
+CREATE TRIGGER movie_key_words_touch_project AFTER INSERT OR UPDATE OR DELETE ON movie_key_words
+    FOR EACH ROW EXECUTE PROCEDURE touch_project('movie', 'movie_id');
+
+CREATE TRIGGER series_covers_touch_project AFTER INSERT OR UPDATE OR DELETE ON series_covers
+    FOR EACH ROW EXECUTE PROCEDURE touch_project('series', 'series_id');
+CREATE TRIGGER series_screenshots_touch_project AFTER INSERT OR UPDATE OR DELETE ON series_screenshots
+    FOR EACH ROW EXECUTE PROCEDURE touch_project('series', 'series_id');
+CREATE TRIGGER series_genres_touch_project AFTER INSERT OR UPDATE OR DELETE ON series_genres
+    FOR EACH ROW EXECUTE PROCEDURE touch_project('series', 'series_id');
+CREATE TRIGGER series_age_categories_touch_project AFTER INSERT OR UPDATE OR DELETE ON series_age_categories
+    FOR EACH ROW EXECUTE PROCEDURE touch_project('series', 'series_id');
+CREATE TRIGGER series_key_words_touch_project AFTER INSERT OR UPDATE OR DELETE ON series_key_words
+    FOR EACH ROW EXECUTE PROCEDURE touch_project('series', 'series_id');
+CREATE TRIGGER seasons_touch_project AFTER INSERT OR UPDATE OR DELETE ON seasons
+    FOR EACH ROW EXECUTE PROCEDURE touch_project('series', 'series_id');
+CREATE TRIGGER episodes_touch_project AFTER INSERT OR UPDATE OR DELETE ON episodes
+    FOR EACH ROW EXECUTE PROCEDURE touch_episode_project();
+
+CREATE TRIGGER credits_touch_project AFTER INSERT OR UPDATE OR DELETE ON credits
+    FOR EACH ROW EXECUTE PROCEDURE touch_project('project_id');
+
+-- catalog_changes records when each catalog table last changed, so that a
+-- list has a modification time even when its newest row was deleted.
+CREATE TABLE IF NOT EXISTS catalog_changes (
+    name VARCHAR(50) PRIMARY KEY,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO catalog_changes (name) VALUES ('projects'), ('genres'), ('age_categories');
+
+CREATE OR REPLACE FUNCTION record_catalog_change() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE catalog_changes SET changed_at = NOW() WHERE name = TG_TABLE_NAME;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER projects_record_change AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON projects
+    FOR EACH STATEMENT EXECUTE PROCEDURE record_catalog_change();
+CREATE TRIGGER genres_record_change AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON genres
+    FOR EACH STATEMENT EXECUTE PROCEDURE record_catalog_change();
+CREATE TRIGGER age_categories_record_change AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON age_categories
+    FOR EACH STATEMENT EXECUTE PROCEDURE record_catalog_change();
@@ -0,0 +1,12 @@
+DROP TRIGGER IF EXISTS list_items_log_sync ON list_items;
+DROP FUNCTION IF EXISTS log_sync_favorite();
+
+DROP TRIGGER IF EXISTS age_categories_log_sync_update ON age_categories;
+DROP TRIGGER IF EXISTS age_categories_log_sync ON age_categories;
+DROP TRIGGER IF EXISTS genres_log_sync_update ON genres;
+DROP TRIGGER IF EXISTS genres_log_sync ON genres;
+DROP TRIGGER IF EXISTS projects_log_sync_update ON projects;
+DROP TRIGGER IF EXISTS projects_log_sync ON projects;
+DROP FUNCTION IF EXISTS log_sync_change();
+
+DROP TABLE IF EXISTS sync_log;
@@ -0,0 +1,73 @@
+-- sync_log records every change to the projects, genres and age categories
+-- and to the favorites of each user, for the delta sync of offline clients.
+-- Deletions stay in the log as tombstones. Entries are read in (txid, seq)
+-- order and only once their transaction is older than every running one,
+-- so that a reader never passes an entry that commits later.
+CREATE TABLE IF NOT EXISTS sync_log (
+    seq BIGSERIAL PRIMARY KEY,
+    txid XID8 NOT NULL DEFAULT pg_current_xact_id(),
+    resource VARCHAR(20) NOT NULL CHECK (resource IN ('projects', 'genres', 'age_categories', 'favorites')),
+    resource_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL DEFAULT 0,
+    op VARCHAR(10) NOT NULL CHECK (op IN ('insert', 'update', 'delete'))
+);
+
+CREATE INDEX idx_sync_log_position ON sync_log (txid, seq);
+CREATE INDEX idx_sync_log_key ON sync_log (resource, resource_id, user_id);
+
+CREATE OR REPLACE FUNCTION log_sync_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO sync_log (resource, resource_id, op) VALUES (TG_TABLE_NAME, OLD.id, 'delete');
+    ELSE
+        INSERT INTO sync_log (resource, resource_id, op) VALUES (TG_TABLE_NAME, NEW.id, lower(TG_OP));
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER projects_log_sync AFTER INSERT OR DELETE ON projects
+    FOR EACH ROW EXECUTE PROCEDURE log_sync_change();
+CREATE TRIGGER projects_log_sync_update AFTER UPDATE ON projects
+    FOR EACH ROW WHEN (OLD.* IS DISTINCT FROM NEW.*) EXECUTE PROCEDURE log_sync_change();
+CREATE TRIGGER genres_log_sync AFTER INSERT OR DELETE ON genres
+    FOR EACH ROW EXECUTE PROCEDURE log_sync_change();
+CREATE TRIGGER genres_log_sync_update AFTER UPDATE ON genres
+    FOR EACH ROW WHEN (OLD.* IS DISTINCT FROM NEW.*) EXECUTE PROCEDURE log_sync_change();
+CREATE TRIGGER age_categories_log_sync AFTER INSERT OR DELETE ON age_categories
+    FOR EACH ROW EXECUTE PROCEDURE log_sync_change();
+CREATE TRIGGER age_categories_log_sync_update AFTER UPDATE ON age_categories
+    FOR EACH ROW WHEN (OLD.* IS DISTINCT FROM NEW.*) EXECUTE PROCEDURE log_sync_change();
+
+-- Favorites are the items of the built-in Favorites lists.
+CREATE OR REPLACE FUNCTION log_sync_favorite() RETURNS TRIGGER AS $$
+DECLARE
+    item list_items;
+    owner INTEGER;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        item := OLD;
+    ELSE
+        item := NEW;
+    END IF;
+
+    SELECT user_id INTO owner FROM lists WHERE id = item.list_id AND kind = 'favorites';
+    IF FOUND THEN
+        INSERT INTO sync_log (resource, resource_id, user_id, op)
+        VALUES ('favorites', item.project_id, owner, lower(TG_OP));
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER list_items_log_sync AFTER INSERT OR DELETE ON list_items
+    FOR EACH ROW EXECUTE PROCEDURE log_sync_favorite();
+
+-- Everything that exists now is where a first sync starts.
+INSERT INTO sync_log (resource, resource_id, op)
+SELECT 'projects', id, 'insert' FROM projects
+UNION ALL SELECT 'genres', id, 'insert' FROM genres
+UNION ALL SELECT 'age_categories', id, 'insert' FROM age_categories;
+
+INSERT INTO sync_log (resource, resource_id, user_id, op)
+SELECT 'favorites', project_id, user_id, 'insert' FROM user_favorites;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS outbox_dispatches (
+    event_id BIGINT NOT NULL,
+    subscriber VARCHAR(50) NOT NULL,
+    dispatched_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (event_id, subscriber),
+    FOREIGN KEY (event_id) REFERENCES outbox(id) ON DELETE CASCADE
+);
@@ -0,0 +1,37 @@
+// Package migrations embeds the schema migrations, so that the server knows
+// which schema version it was built for. The files themselves are applied
+// with the migrate CLI.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+//go:embed *.up.sql
+var files embed.FS
+
+// Latest returns the version of the newest migration.
+func Latest() (uint, error) {
+	const op = "migrations.Latest"
+
+	names, err := fs.Glob(files, "*.up.sql")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var latest uint
+	for _, name := range names {
+		prefix, _, _ := strings.Cut(name, "_")
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %s: %w", op, name, err)
+		}
+		latest = max(latest, uint(version))
+	}
+
+	return latest, nil
+}
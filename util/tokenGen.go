@@ -1,24 +1,43 @@
 package util
 
 import (
+	"errors"
 	"fmt"
-	"os"
+	"ozinshe/internal/config"
 	"time"
 
 	"github.com/golang-jwt/jwt"
 )
 
-var SECRET_KEY string = os.Getenv("SECRET_KEY")
+var ErrNoSecret = errors.New("token secret is not set")
+
+var (
+	secretKey  []byte
+	tokenTTL   time.Duration
+	refreshTTL time.Duration
+)
+
+// SetupTokens sets the secret and lifetimes of the tokens. It must be called
+// once on start, before any token is generated or validated.
+func SetupTokens(cfg config.AuthConfig) {
+	secretKey = []byte(cfg.Secret)
+	tokenTTL = cfg.TokenTTL
+	refreshTTL = cfg.RefreshTTL
+}
 
 func GenerateAllTokens(email string, name string, user_type string, uid string) (signedToken, signedRefreshToken string, err error) {
 	const op = "util.GenerateAllTokens"
 
+	if len(secretKey) == 0 {
+		return "", "", fmt.Errorf("%s: %w", op, ErrNoSecret)
+	}
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"email":     email,
 		"name":      name,
 		"user_type": user_type,
 		"uid":       uid,
-		"exp":       time.Now().Add(time.Hour * time.Duration(24)).Unix(),
+		"exp":       time.Now().Add(tokenTTL).Unix(),
 	})
 
 	refresh := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
@@ -26,15 +45,15 @@ func GenerateAllTokens(email string, name string, user_type string, uid string)
 		"name":      name,
 		"user_type": user_type,
 		"uid":       uid,
-		"exp":       time.Now().Local().Add(time.Hour * time.Duration(169)).Unix(),
+		"exp":       time.Now().Add(refreshTTL).Unix(),
 	})
 
-	tokenString, err := token.SignedString([]byte(SECRET_KEY))
+	tokenString, err := token.SignedString(secretKey)
 	if err != nil {
 		return "", "", fmt.Errorf("%s: %w", op, err)
 	}
 
-	refreshString, err := refresh.SignedString([]byte(SECRET_KEY))
+	refreshString, err := refresh.SignedString(secretKey)
 	if err != nil {
 		return "", "", fmt.Errorf("%s: %w", op, err)
 	}
@@ -45,12 +64,16 @@ func GenerateAllTokens(email string, name string, user_type string, uid string)
 func ValidateToken(tokenString string) (jwt.MapClaims, error) {
 	const op = "util.ValidateToken"
 
+	if len(secretKey) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrNoSecret)
+	}
+
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("%s: unexpected signing method: %v", op, token.Header["alg"])
 		}
 
-		return []byte(SECRET_KEY), nil
+		return secretKey, nil
 	})
 
 	if err != nil {